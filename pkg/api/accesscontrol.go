@@ -422,6 +422,32 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{"Admin"},
 	}
 
+	liveChannelsPublisherRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:live.channels:publisher",
+			DisplayName: "Live channels publisher",
+			Description: "Publish to all Live channels which require live.channels:publish permission.",
+			Group:       "Live",
+			Permissions: []ac.Permission{
+				{Action: ac.ActionLiveChannelsPublish, Scope: ac.ScopeLiveChannelsAll},
+			},
+		},
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	liveChannelsSubscriberRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:live.channels:subscriber",
+			DisplayName: "Live channels subscriber",
+			Description: "Subscribe to all Live channels which require live.channels:subscribe permission.",
+			Group:       "Live",
+			Permissions: []ac.Permission{
+				{Action: ac.ActionLiveChannelsSubscribe, Scope: ac.ScopeLiveChannelsAll},
+			},
+		},
+		Grants: []string{string(org.RoleViewer)},
+	}
+
 	return hs.accesscontrolService.DeclareFixedRoles(
 		provisioningWriterRole, datasourcesReaderRole, builtInDatasourceReader, datasourcesWriterRole,
 		datasourcesIdReaderRole, orgReaderRole, orgWriterRole,
@@ -429,7 +455,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
 		dashboardsCreatorRole, dashboardsReaderRole, dashboardsWriterRole,
 		foldersCreatorRole, foldersReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
-		publicDashboardsWriterRole,
+		publicDashboardsWriterRole, liveChannelsPublisherRole, liveChannelsSubscriberRole,
 	)
 }
 
@@ -35,6 +35,11 @@ func ToDashboardErrorResponse(ctx context.Context, pluginStore plugins.Store, er
 		return response.Error(http.StatusUnprocessableEntity, validationErr.Error(), err)
 	}
 
+	var lintErr dashboards.LintError
+	if ok := errors.As(err, &lintErr); ok {
+		return response.JSON(http.StatusUnprocessableEntity, util.DynMap{"status": "lint-failed", "message": lintErr.Error(), "problems": lintErr.Problems})
+	}
+
 	var pluginErr dashboards.UpdatePluginDashboardError
 	if ok := errors.As(err, &pluginErr); ok {
 		message := fmt.Sprintf("The dashboard belongs to plugin %s.", pluginErr.PluginId)
@@ -25,6 +25,7 @@ import (
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/gitsync"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
 	pref "github.com/grafana/grafana/pkg/services/preference"
@@ -210,7 +211,7 @@ func (hs *HTTPServer) GetDashboard(c *models.ReqContext) response.Response {
 	}
 
 	if provisioningData != nil {
-		allowUIUpdate := hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
+		allowUIUpdate := hs.allowProvisionedUIUpdates(provisioningData.Name)
 		if !allowUIUpdate {
 			meta.Provisioned = true
 		}
@@ -446,7 +447,10 @@ func (hs *HTTPServer) postDashboard(c *models.ReqContext, cmd models.SaveDashboa
 
 	allowUiUpdate := true
 	if provisioningData != nil {
-		allowUiUpdate = hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
+		allowUiUpdate = hs.allowProvisionedUIUpdates(provisioningData.Name)
+		if allowUiUpdate && provisioningData.Name == gitsync.ProvisionerName {
+			return hs.saveGitSyncDashboard(c, dash, cmd.Message, cmd.Overwrite, provisioningData)
+		}
 	}
 
 	dashItem := &dashboards.SaveDashboardDTO{
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/gitsync"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// allowProvisionedUIUpdates returns whether dashboards of a provisioner can be changed in the UI.
+func (hs *HTTPServer) allowProvisionedUIUpdates(name string) bool {
+	if name == gitsync.ProvisionerName {
+		return hs.GitSync != nil && hs.GitSync.AllowsUIChanges()
+	}
+	return hs.ProvisioningService.GetAllowUIUpdatesFromConfig(name)
+}
+
+// saveGitSyncDashboard commits a dashboard synchronized from git to the repository instead of saving it to the database.
+func (hs *HTTPServer) saveGitSyncDashboard(c *models.ReqContext, dash *models.Dashboard, message string, overwrite bool, provisioningData *models.DashboardProvisioning) response.Response {
+	ctx := c.Req.Context()
+	query := models.GetDashboardQuery{Id: provisioningData.DashboardId, OrgId: c.OrgID}
+	if err := hs.DashboardService.GetDashboard(ctx, &query); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard", err)
+	}
+	g, err := guardian.NewByDashboard(ctx, query.Result, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	if !overwrite && dash.Version != query.Result.Version {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, dashboards.ErrDashboardVersionMismatch)
+	}
+
+	// The repository defines where the dashboard is stored.
+	dash.SetUid(query.Result.Uid)
+	result, err := hs.GitSync.SaveDashboard(ctx, &gitsync.SaveDashboardCommand{
+		User:      c.SignedInUser,
+		Dashboard: dash,
+		Path:      provisioningData.ExternalId,
+		Message:   message,
+		CheckSum:  provisioningData.CheckSum,
+		Overwrite: overwrite,
+	})
+	if err != nil {
+		if errors.Is(err, gitsync.ErrRemoteChanged) {
+			return response.Error(http.StatusConflict, err.Error(), err)
+		}
+		if errors.Is(err, dashboards.ErrDashboardVersionMismatch) {
+			return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to commit dashboard to the repository", err)
+	}
+
+	if result.Dashboard == nil {
+		return response.JSON(http.StatusAccepted, util.DynMap{
+			"status":  "pending",
+			"message": "changes were pushed to branch " + result.Branch,
+			"branch":  result.Branch,
+			"commit":  result.Commit,
+		})
+	}
+
+	dashboard := result.Dashboard
+	if hs.Live != nil {
+		if err := hs.Live.GrafanaScope.Dashboards.DashboardSaved(c.OrgID, c.SignedInUser.ToUserDisplayDTO(), message, dashboard, nil); err != nil {
+			hs.log.Warn("unable to broadcast save event", "uid", dashboard.Uid, "error", err)
+		}
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"status":  "success",
+		"slug":    dashboard.Slug,
+		"version": dashboard.Version,
+		"id":      dashboard.Id,
+		"uid":     dashboard.Uid,
+		"url":     dashboard.GetUrl(),
+		"commit":  result.Commit,
+	})
+}
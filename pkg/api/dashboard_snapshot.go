@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -48,7 +50,7 @@ type CreateExternalSnapshotResponse struct {
 	DeleteUrl string `json:"deleteUrl"`
 }
 
-func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnapshotCommand) (*CreateExternalSnapshotResponse, error) {
+func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnapshotCommand, token string) (*CreateExternalSnapshotResponse, error) {
 	var createSnapshotResponse CreateExternalSnapshotResponse
 	message := map[string]interface{}{
 		"name":      cmd.Name,
@@ -63,7 +65,16 @@ func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnaps
 		return nil, err
 	}
 
-	response, err := client.Post(setting.ExternalSnapshotUrl+"/api/snapshots", "application/json", bytes.NewBuffer(messageBytes))
+	req, err := http.NewRequest(http.MethodPost, setting.ExternalSnapshotUrl+"/api/snapshots", bytes.NewBuffer(messageBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(dashboardsnapshots.TokenHeader, token)
+	}
+
+	response, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +104,26 @@ func createOriginalDashboardURL(appURL string, cmd *dashboardsnapshots.CreateDas
 	return fmt.Sprintf("/d/%v", dashUID), nil
 }
 
+// isExternalOriginalURL returns true if the original URL of a snapshot published by another
+// Grafana instance is an absolute HTTP URL.
+func isExternalOriginalURL(originalURL string) bool {
+	u, err := url.Parse(originalURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// setOriginalDashboardURL sets the link of the snapshot to its dashboard, the link is removed
+// if the URL is empty.
+func setOriginalDashboardURL(dashboard *simplejson.Json, originalURL string) {
+	if originalURL != "" {
+		dashboard.SetPath([]string{"snapshot", "originalUrl"}, originalURL)
+		return
+	}
+	dashboard.Get("snapshot").Del("originalUrl")
+}
+
 // swagger:route POST /snapshots snapshots createDashboardSnapshot
 //
 // When creating a snapshot using the API, you have to provide the full dashboard payload including the snapshot data. This endpoint is designed for the Grafana UI.
@@ -109,6 +140,85 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *models.ReqContext) response.Res
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
+
+	if !c.IsSignedIn {
+		// The request was allowed by public mode, so it was most likely sent by another Grafana
+		// instance using this one as external snapshot server. Its dashboard doesn't exist here,
+		// the link to the original dashboard is kept only if the instance is trusted by a token.
+		originalURL := cmd.Dashboard.GetPath("snapshot", "originalUrl").MustString()
+		if hs.Cfg.SnapshotPublicModeToken == "" || !isExternalOriginalURL(originalURL) {
+			originalURL = ""
+		}
+		return hs.createDashboardSnapshot(c, &cmd, originalURL)
+	}
+
+	originalDashboardURL, err := createOriginalDashboardURL(hs.Cfg.AppURL, &cmd)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Invalid app URL", err)
+	}
+	return hs.createDashboardSnapshot(c, &cmd, originalDashboardURL)
+}
+
+// swagger:route POST /dashboards/uid/{uid}/snapshots snapshots createDashboardSnapshotByUID
+//
+// Create a snapshot of a dashboard.
+//
+// Runs the queries of all panels of the dashboard for the given time range, or the time range of the dashboard, and
+// stores the results in a new snapshot.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreateDashboardSnapshotByUID(c *models.ReqContext) response.Response {
+	body := dashboardsnapshots.CreateDashboardSnapshotByUIDCommand{}
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := guardian.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	snapshot, err := hs.dashboardsnapshotsRenderer.RenderSnapshot(c.Req.Context(), &dashboardsnapshots.RenderSnapshotCommand{
+		Dashboard: dash.Data,
+		From:      body.From,
+		To:        body.To,
+		User:      c.SignedInUser,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to render snapshot", err)
+	}
+
+	name := body.Name
+	if name == "" {
+		name = dash.Title
+	}
+	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Dashboard: snapshot,
+		Name:      name,
+		Expires:   body.Expires,
+		External:  body.External,
+	}
+	return hs.createDashboardSnapshot(c, &cmd, fmt.Sprintf("/d/%v", dash.Uid))
+}
+
+// createDashboardSnapshot stores the snapshot locally or on the external snapshot server.
+// The original URL is the path of the dashboard the snapshot was taken from, or an absolute
+// URL for snapshots published by other Grafana instances.
+func (hs *HTTPServer) createDashboardSnapshot(c *models.ReqContext, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand, originalDashboardURL string) response.Response {
 	if cmd.Name == "" {
 		cmd.Name = "Unnamed snapshot"
 	}
@@ -117,40 +227,39 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *models.ReqContext) response.Res
 	cmd.ExternalUrl = ""
 	cmd.OrgId = c.OrgID
 	cmd.UserId = c.UserID
-	originalDashboardURL, err := createOriginalDashboardURL(hs.Cfg.AppURL, &cmd)
-	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Invalid app URL", err)
-	}
 
 	if cmd.External {
 		if !setting.ExternalEnabled {
-			c.JsonApiErr(http.StatusForbidden, "External dashboard creation is disabled", nil)
-			return nil
+			return response.Error(http.StatusForbidden, "External dashboard creation is disabled", nil)
+		}
+
+		// The external snapshot server can't resolve the path of the dashboard.
+		if strings.HasPrefix(originalDashboardURL, "/") {
+			originalDashboardURL = hs.Cfg.AppURL + strings.TrimPrefix(originalDashboardURL, "/")
 		}
+		setOriginalDashboardURL(cmd.Dashboard, originalDashboardURL)
 
-		response, err := createExternalDashboardSnapshot(cmd)
+		externalSnapshot, err := createExternalDashboardSnapshot(*cmd, hs.Cfg.ExternalSnapshotToken)
 		if err != nil {
-			c.JsonApiErr(http.StatusInternalServerError, "Failed to create external snapshot", err)
-			return nil
+			return response.Error(http.StatusInternalServerError, "Failed to create external snapshot", err)
 		}
 
-		snapshotUrl = response.Url
-		cmd.Key = response.Key
-		cmd.DeleteKey = response.DeleteKey
-		cmd.ExternalUrl = response.Url
-		cmd.ExternalDeleteUrl = response.DeleteUrl
+		snapshotUrl = externalSnapshot.Url
+		cmd.Key = externalSnapshot.Key
+		cmd.DeleteKey = externalSnapshot.DeleteKey
+		cmd.ExternalUrl = externalSnapshot.Url
+		cmd.ExternalDeleteUrl = externalSnapshot.DeleteUrl
 		cmd.Dashboard = simplejson.New()
 
 		metrics.MApiDashboardSnapshotExternal.Inc()
 	} else {
-		cmd.Dashboard.SetPath([]string{"snapshot", "originalUrl"}, originalDashboardURL)
+		setOriginalDashboardURL(cmd.Dashboard, originalDashboardURL)
 
 		if cmd.Key == "" {
 			var err error
 			cmd.Key, err = util.GetRandomString(32)
 			if err != nil {
-				c.JsonApiErr(http.StatusInternalServerError, "Could not generate random string", err)
-				return nil
+				return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
 			}
 		}
 
@@ -158,8 +267,7 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *models.ReqContext) response.Res
 			var err error
 			cmd.DeleteKey, err = util.GetRandomString(32)
 			if err != nil {
-				c.JsonApiErr(http.StatusInternalServerError, "Could not generate random string", err)
-				return nil
+				return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
 			}
 		}
 
@@ -168,19 +276,17 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *models.ReqContext) response.Res
 		metrics.MApiDashboardSnapshotCreate.Inc()
 	}
 
-	if err := hs.dashboardsnapshotsService.CreateDashboardSnapshot(c.Req.Context(), &cmd); err != nil {
-		c.JsonApiErr(http.StatusInternalServerError, "Failed to create snapshot", err)
-		return nil
+	if err := hs.dashboardsnapshotsService.CreateDashboardSnapshot(c.Req.Context(), cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create snapshot", err)
 	}
 
-	c.JSON(http.StatusOK, util.DynMap{
+	return response.JSON(http.StatusOK, util.DynMap{
 		"key":       cmd.Key,
 		"deleteKey": cmd.DeleteKey,
 		"url":       snapshotUrl,
 		"deleteUrl": setting.ToAbsUrl("api/snapshots-delete/" + cmd.DeleteKey),
 		"id":        cmd.Result.Id,
 	})
-	return nil
 }
 
 // GET /api/snapshots/:key
@@ -228,8 +334,16 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *models.ReqContext) response.Respon
 	return response.JSON(http.StatusOK, dto).SetHeader("Cache-Control", "public, max-age=3600")
 }
 
-func deleteExternalDashboardSnapshot(externalUrl string) error {
-	response, err := client.Get(externalUrl)
+func deleteExternalDashboardSnapshot(externalUrl string, token string) error {
+	req, err := http.NewRequest(http.MethodGet, externalUrl, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set(dashboardsnapshots.TokenHeader, token)
+	}
+
+	response, err := client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -257,6 +371,14 @@ func deleteExternalDashboardSnapshot(externalUrl string) error {
 		}
 	}
 
+	// Grafana instances acting as external snapshot server respond with not found.
+	if response.StatusCode == 404 {
+		var respJson map[string]interface{}
+		if err := json.NewDecoder(response.Body).Decode(&respJson); err == nil && respJson["messageId"] == "dashboardsnapshots.not-found" {
+			return nil
+		}
+	}
+
 	return fmt.Errorf("unexpected response when deleting external snapshot, status code: %d", response.StatusCode)
 }
 
@@ -285,7 +407,7 @@ func (hs *HTTPServer) DeleteDashboardSnapshotByDeleteKey(c *models.ReqContext) r
 	}
 
 	if query.Result.External {
-		err := deleteExternalDashboardSnapshot(query.Result.ExternalDeleteUrl)
+		err := deleteExternalDashboardSnapshot(query.Result.ExternalDeleteUrl, hs.Cfg.ExternalSnapshotToken)
 		if err != nil {
 			return response.Error(500, "Failed to delete external dashboard", err)
 		}
@@ -329,7 +451,7 @@ func (hs *HTTPServer) DeleteDashboardSnapshot(c *models.ReqContext) response.Res
 	}
 
 	if query.Result.External {
-		err := deleteExternalDashboardSnapshot(query.Result.ExternalDeleteUrl)
+		err := deleteExternalDashboardSnapshot(query.Result.ExternalDeleteUrl, hs.Cfg.ExternalSnapshotToken)
 		if err != nil {
 			return response.Error(http.StatusInternalServerError, "Failed to delete external dashboard", err)
 		}
@@ -423,6 +545,16 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters createDashboardSnapshotByUID
+type CreateSnapshotByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dashboardsnapshots.CreateDashboardSnapshotByUIDCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -21,6 +22,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/sqlstore/mockstore"
 	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
@@ -68,7 +72,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 	t.Run("When user has editor role and is not in the ACL", func(t *testing.T) {
 		loggedInUserScenarioWithRole(t, "Should not be able to delete snapshot when calling DELETE on",
 			"DELETE", "/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, 0, "")}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, 0, "")}
 				sc.handlerFunc = hs.DeleteDashboardSnapshot
 
 				teamSvc := &teamtest.FakeService{}
@@ -98,7 +102,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 					rw.WriteHeader(200)
 					externalRequest = req
 				})
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, 0, ts.URL)}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, 0, ts.URL)}
 
 				sc.handlerFunc = hs.DeleteDashboardSnapshotByDeleteKey
 				sc.fakeReqWithParams("GET", sc.url, map[string]string{"deleteKey": "12345"}).exec()
@@ -150,7 +154,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 					}
 				}).Return(nil)
 				guardian.InitLegacyGuardian(sc.sqlStore, dashSvc, teamSvc)
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, 0, ts.URL), DashboardService: dashSvc}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, 0, ts.URL), DashboardService: dashSvc}
 				sc.handlerFunc = hs.DeleteDashboardSnapshot
 				sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -171,7 +175,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 				d := setUpSnapshotTest(t, testUserID, "")
 
 				dashSvc := dashboards.NewFakeDashboardService(t)
-				hs := &HTTPServer{dashboardsnapshotsService: d, DashboardService: dashSvc}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d, DashboardService: dashSvc}
 				sc.handlerFunc = hs.DeleteDashboardSnapshot
 				sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -195,7 +199,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 				})
 
 				dashSvc := dashboards.NewFakeDashboardService(t)
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL), DashboardService: dashSvc}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL), DashboardService: dashSvc}
 				sc.handlerFunc = hs.DeleteDashboardSnapshot
 				sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -208,6 +212,24 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 				assert.Equal(t, 1, respJSON.Get("id").MustInt())
 			}, sqlmock)
 
+		loggedInUserScenarioWithRole(t,
+			"Should gracefully delete local snapshot when remote Grafana snapshot server has already removed it when calling DELETE on",
+			"DELETE", "/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
+				var writeErr error
+				ts := setupRemoteServer(func(rw http.ResponseWriter, req *http.Request) {
+					rw.WriteHeader(404)
+					_, writeErr = rw.Write([]byte(`{"statusCode":404,"messageId":"dashboardsnapshots.not-found","message":"Snapshot not found"}`))
+				})
+
+				dashSvc := dashboards.NewFakeDashboardService(t)
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL), DashboardService: dashSvc}
+				sc.handlerFunc = hs.DeleteDashboardSnapshot
+				sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
+
+				require.NoError(t, writeErr)
+				assert.Equal(t, 200, sc.resp.Code)
+			}, sqlmock)
+
 		loggedInUserScenarioWithRole(t,
 			"Should fail to delete local snapshot when an unexpected 500 error occurs when calling DELETE on", "DELETE",
 			"/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
@@ -216,7 +238,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 					rw.WriteHeader(500)
 					_, writeErr = rw.Write([]byte(`{"message":"Unexpected"}`))
 				})
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL)}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL)}
 				sc.handlerFunc = hs.DeleteDashboardSnapshot
 				sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -230,7 +252,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 				ts := setupRemoteServer(func(rw http.ResponseWriter, req *http.Request) {
 					rw.WriteHeader(404)
 				})
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL)}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, testUserID, ts.URL)}
 				sc.handlerFunc = hs.DeleteDashboardSnapshot
 				sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -239,7 +261,7 @@ func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 
 		loggedInUserScenarioWithRole(t, "Should be able to read a snapshot's unencrypted data when calling GET on",
 			"GET", "/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
-				hs := &HTTPServer{dashboardsnapshotsService: setUpSnapshotTest(t, 0, "")}
+				hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: setUpSnapshotTest(t, 0, "")}
 				sc.handlerFunc = hs.GetDashboardSnapshot
 				sc.fakeReqWithParams("GET", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -275,7 +297,7 @@ func TestGetDashboardSnapshotNotFound(t *testing.T) {
 		"GET /snapshots/{key} should return 404 when the snapshot does not exist", "GET",
 		"/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
 			d := setUpSnapshotTest(t)
-			hs := &HTTPServer{dashboardsnapshotsService: d}
+			hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d}
 			sc.handlerFunc = hs.GetDashboardSnapshot
 			sc.fakeReqWithParams("GET", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -286,7 +308,7 @@ func TestGetDashboardSnapshotNotFound(t *testing.T) {
 		"DELETE /snapshots/{key} should return 404 when the snapshot does not exist", "DELETE",
 		"/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
 			d := setUpSnapshotTest(t)
-			hs := &HTTPServer{dashboardsnapshotsService: d}
+			hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d}
 			sc.handlerFunc = hs.DeleteDashboardSnapshot
 			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -297,7 +319,7 @@ func TestGetDashboardSnapshotNotFound(t *testing.T) {
 		"GET /snapshots-delete/{deleteKey} should return 404 when the snapshot does not exist", "DELETE",
 		"/api/snapshots-delete/12345", "/api/snapshots-delete/:deleteKey", org.RoleEditor, func(sc *scenarioContext) {
 			d := setUpSnapshotTest(t)
-			hs := &HTTPServer{dashboardsnapshotsService: d}
+			hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d}
 			sc.handlerFunc = hs.DeleteDashboardSnapshotByDeleteKey
 			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"deleteKey": "12345"}).exec()
 
@@ -325,7 +347,7 @@ func TestGetDashboardSnapshotFailure(t *testing.T) {
 		"GET /snapshots/{key} should return 404 when the snapshot does not exist", "GET",
 		"/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
 			d := setUpSnapshotTest(t)
-			hs := &HTTPServer{dashboardsnapshotsService: d}
+			hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d}
 			sc.handlerFunc = hs.GetDashboardSnapshot
 			sc.fakeReqWithParams("GET", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -336,7 +358,7 @@ func TestGetDashboardSnapshotFailure(t *testing.T) {
 		"DELETE /snapshots/{key} should return 404 when the snapshot does not exist", "DELETE",
 		"/api/snapshots/12345", "/api/snapshots/:key", org.RoleEditor, func(sc *scenarioContext) {
 			d := setUpSnapshotTest(t)
-			hs := &HTTPServer{dashboardsnapshotsService: d}
+			hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d}
 			sc.handlerFunc = hs.DeleteDashboardSnapshot
 			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"key": "12345"}).exec()
 
@@ -347,10 +369,85 @@ func TestGetDashboardSnapshotFailure(t *testing.T) {
 		"GET /snapshots-delete/{deleteKey} should return 404 when the snapshot does not exist", "DELETE",
 		"/api/snapshots-delete/12345", "/api/snapshots-delete/:deleteKey", org.RoleEditor, func(sc *scenarioContext) {
 			d := setUpSnapshotTest(t)
-			hs := &HTTPServer{dashboardsnapshotsService: d}
+			hs := &HTTPServer{Cfg: setting.NewCfg(), dashboardsnapshotsService: d}
 			sc.handlerFunc = hs.DeleteDashboardSnapshotByDeleteKey
 			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{"deleteKey": "12345"}).exec()
 
 			assert.Equal(t, http.StatusInternalServerError, sc.resp.Code)
 		}, sqlmock)
 }
+
+type fakeSnapshotRenderer struct {
+	cmd *dashboardsnapshots.RenderSnapshotCommand
+}
+
+func (r *fakeSnapshotRenderer) RenderSnapshot(_ context.Context, cmd *dashboardsnapshots.RenderSnapshotCommand) (*simplejson.Json, error) {
+	r.cmd = cmd
+	return simplejson.NewFromAny(map[string]interface{}{"uid": "dash", "panels": []interface{}{}}), nil
+}
+
+func TestCreateDashboardSnapshotByUID(t *testing.T) {
+	origNew, origNewByUID, origNewByDashboard := guardian.New, guardian.NewByUID, guardian.NewByDashboard
+	t.Cleanup(func() {
+		guardian.New, guardian.NewByUID, guardian.NewByDashboard = origNew, origNewByUID, origNewByDashboard
+	})
+
+	setUp := func(t *testing.T, canView bool) (*webtest.Server, *fakeSnapshotRenderer, *dashboardsnapshots.CreateDashboardSnapshotCommand) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: canView})
+
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			q := args.Get(1).(*models.GetDashboardQuery)
+			q.Result = &models.Dashboard{Id: 1, Uid: q.Uid, OrgId: 1, Title: "Dash", Data: simplejson.New()}
+		}).Return(nil)
+
+		created := &dashboardsnapshots.CreateDashboardSnapshotCommand{}
+		dashSnapSvc := dashboardsnapshots.NewMockService(t)
+		dashSnapSvc.On("CreateDashboardSnapshot", mock.Anything, mock.AnythingOfType("*dashboardsnapshots.CreateDashboardSnapshotCommand")).Run(func(args mock.Arguments) {
+			cmd := args.Get(1).(*dashboardsnapshots.CreateDashboardSnapshotCommand)
+			cmd.Result = &dashboardsnapshots.DashboardSnapshot{Id: 1, Key: cmd.Key}
+			*created = *cmd
+		}).Return(nil).Maybe()
+
+		renderer := &fakeSnapshotRenderer{}
+		s := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.DashboardService = dashSvc
+			hs.dashboardsnapshotsService = dashSnapSvc
+			hs.dashboardsnapshotsRenderer = renderer
+		})
+		return s, renderer, created
+	}
+
+	t.Run("Should render and store the snapshot of the dashboard", func(t *testing.T) {
+		s, renderer, created := setUp(t, true)
+
+		req := s.NewPostRequest("/api/dashboards/uid/dash/snapshots", strings.NewReader(`{"from":"now-1h","to":"now","expires":3600}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+		resp, err := s.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		require.NotNil(t, renderer.cmd)
+		assert.Equal(t, "now-1h", renderer.cmd.From)
+		assert.Equal(t, "now", renderer.cmd.To)
+		assert.Equal(t, "Dash", created.Name)
+		assert.Equal(t, int64(3600), created.Expires)
+		assert.NotEmpty(t, created.Key)
+		assert.Equal(t, "/d/dash", created.Dashboard.GetPath("snapshot", "originalUrl").MustString())
+	})
+
+	t.Run("Should return 403 when the user can't view the dashboard", func(t *testing.T) {
+		s, renderer, _ := setUp(t, false)
+
+		req := s.NewPostRequest("/api/dashboards/uid/dash/snapshots", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+		resp, err := s.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Nil(t, renderer.cmd)
+	})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/trash dashboards getTrashedDashboards
+//
+// Get deleted dashboards and folders.
+//
+// Returns dashboards and folders which were deleted and can still be restored.
+//
+// Responses:
+// 200: getTrashedDashboardsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetTrashedDashboards(c *models.ReqContext) response.Response {
+	result, err := hs.DashboardService.GetTrashedDashboards(c.Req.Context(), &dashboards.GetTrashedDashboardsQuery{OrgID: c.OrgID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get deleted dashboards", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /dashboards/trash/{uid}/restore dashboards restoreTrashedDashboard
+//
+// Restore a deleted dashboard or folder.
+//
+// Restores a dashboard or a folder with its dashboards, permissions and version history.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreTrashedDashboard(c *models.ReqContext) response.Response {
+	restored, err := hs.DashboardService.RestoreDashboard(c.Req.Context(), &dashboards.RestoreDashboardCommand{
+		OrgID: c.OrgID,
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return trashedDashboardErrorResponse(err, "Failed to restore dashboard")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{
+		"title":   restored.Title,
+		"uid":     restored.DashboardUID,
+		"message": "Dashboard " + restored.Title + " restored",
+	})
+}
+
+// swagger:route DELETE /dashboards/trash/{uid} dashboards deleteTrashedDashboard
+//
+// Permanently delete a deleted dashboard or folder.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteTrashedDashboard(c *models.ReqContext) response.Response {
+	err := hs.DashboardService.DeleteTrashedDashboard(c.Req.Context(), &dashboards.DeleteTrashedDashboardCommand{
+		OrgID: c.OrgID,
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return trashedDashboardErrorResponse(err, "Failed to delete dashboard")
+	}
+	return response.Success("Dashboard deleted permanently")
+}
+
+func trashedDashboardErrorResponse(err error, message string) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		if body := dashboardErr.Body(); body != nil {
+			return response.JSON(dashboardErr.StatusCode, body)
+		}
+		return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+	}
+	if errors.Is(err, dashboards.ErrFolderWithSameUIDExists) || errors.Is(err, dashboards.ErrFolderSameNameExists) {
+		return response.Error(http.StatusConflict, err.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
+
+// swagger:parameters restoreTrashedDashboard deleteTrashedDashboard
+type TrashedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getTrashedDashboardsResponse
+type GetTrashedDashboardsResponse struct {
+	// in: body
+	Body []*dashboards.TrashedDashboard `json:"body"`
+}
@@ -36,6 +36,8 @@ type DashboardMeta struct {
 	PublicDashboardAccessToken string                `json:"publicDashboardAccessToken"`
 	PublicDashboardUID         string                `json:"publicDashboardUid"`
 	PublicDashboardEnabled     bool                  `json:"publicDashboardEnabled"`
+	// PublicDashboardTemplateVariables are the values viewers of a public dashboard may select for its template variables
+	PublicDashboardTemplateVariables map[string][]string `json:"publicDashboardTemplateVariables,omitempty"`
 }
 type AnnotationPermission struct {
 	Dashboard    AnnotationActions `json:"dashboard"`
@@ -74,6 +76,10 @@ type CalculateDiffTarget struct {
 	UnsavedDashboard *simplejson.Json `json:"unsavedDashboard"`
 }
 
+type LintDashboardCommand struct {
+	Dashboard *simplejson.Json `json:"dashboard"`
+}
+
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
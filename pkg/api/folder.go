@@ -236,17 +236,23 @@ func (hs *HTTPServer) UpdateFolder(c *models.ReqContext) response.Response {
 // 404: notFoundError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteFolder(c *models.ReqContext) response.Response { // temporarily adding this function to HTTPServer, will be removed from HTTPServer when librarypanels featuretoggle is removed
-	err := hs.LibraryElementService.DeleteLibraryElementsInFolder(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
-	if err != nil {
-		if errors.Is(err, libraryelements.ErrFolderHasConnectedLibraryElements) {
-			return response.Error(403, "Folder could not be deleted because it contains library elements in use", err)
+	// Library elements of a folder moved to trash are moved to trash with it.
+	if hs.Cfg.DashboardTrashRetention <= 0 {
+		err := hs.LibraryElementService.DeleteLibraryElementsInFolder(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+		if err != nil {
+			if errors.Is(err, libraryelements.ErrFolderHasConnectedLibraryElements) {
+				return response.Error(403, "Folder could not be deleted because it contains library elements in use", err)
+			}
+			return apierrors.ToFolderErrorResponse(err)
 		}
-		return apierrors.ToFolderErrorResponse(err)
 	}
 
 	uid := web.Params(c.Req)[":uid"]
-	err = hs.folderService.Delete(c.Req.Context(), &folder.DeleteFolderCommand{UID: uid, OrgID: c.OrgID, ForceDeleteRules: c.QueryBool("forceDeleteRules"), SignedInUser: c.SignedInUser})
+	err := hs.folderService.Delete(c.Req.Context(), &folder.DeleteFolderCommand{UID: uid, OrgID: c.OrgID, ForceDeleteRules: c.QueryBool("forceDeleteRules"), SignedInUser: c.SignedInUser})
 	if err != nil {
+		if errors.Is(err, dashboards.ErrFolderContainsLibraryElementsInUse) {
+			return response.Error(403, "Folder could not be deleted because it contains library elements in use", err)
+		}
 		return apierrors.ToFolderErrorResponse(err)
 	}
 
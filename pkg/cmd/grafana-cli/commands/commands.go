@@ -269,4 +269,50 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:  "dashboards",
+		Usage: "Dashboard commands",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "lint",
+				Usage: "lint <dashboard json file or directory...>",
+				Action: func(context *cli.Context) error {
+					return lintDashboardsCommand(&utils.ContextCommandLine{Context: context})
+				},
+			},
+			{
+				Name:   "snapshot",
+				Usage:  "snapshot <dashboard uid>",
+				Action: runRunnerCommand(snapshotDashboardCommand),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The ID of the organization of the dashboard",
+						Value: 1,
+					},
+					&cli.IntFlag{
+						Name:  "user-id",
+						Usage: "The ID of the user running the queries and owning the snapshot",
+						Value: DefaultAdminUserId,
+					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Start of the time range, defaults to the time range of the dashboard",
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "End of the time range, defaults to the time range of the dashboard",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Snapshot name, defaults to the dashboard title",
+					},
+					&cli.IntFlag{
+						Name:  "expires",
+						Usage: "Seconds until the snapshot expires, 0 to never expire",
+					},
+				},
+			},
+		},
+	},
 }
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
+)
+
+// lintDashboardsCommand lints dashboard JSON files. Directories are searched recursively
+// for .json files.
+func lintDashboardsCommand(c utils.CommandLine) error {
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("missing path to a dashboard file or directory")
+	}
+
+	files := make([]string, 0)
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Files passed explicitly are linted regardless of their extension.
+			if !d.IsDir() && (p == path || strings.EqualFold(filepath.Ext(p), ".json")) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	numErrors := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		model, err := simplejson.NewJson(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse dashboard %s: %w", file, err)
+		}
+
+		result := lint.Default().Lint(model)
+		if len(result.Problems) == 0 {
+			logger.Infof("%s: %s\n", file, color.GreenString("ok"))
+			continue
+		}
+		logger.Infof("%s:\n", file)
+		for _, p := range result.Problems {
+			logger.Infof("  %s %s %s\n", formatLintSeverity(p.Severity), formatLintLocation(p), p.Message)
+		}
+		numErrors += len(result.Errors())
+	}
+
+	if numErrors > 0 {
+		return fmt.Errorf("found %d linting error(s)", numErrors)
+	}
+	return nil
+}
+
+func formatLintSeverity(severity lint.Severity) string {
+	if severity == lint.SeverityError {
+		return color.RedString("error  ")
+	}
+	return color.YellowString("warning")
+}
+
+func formatLintLocation(p lint.Problem) string {
+	switch {
+	case p.PanelID != 0 || p.PanelTitle != "":
+		return fmt.Sprintf("[%s] panel %d %q:", p.Rule, p.PanelID, p.PanelTitle)
+	case p.Variable != "":
+		return fmt.Sprintf("[%s] variable %q:", p.Rule, p.Variable)
+	}
+	return fmt.Sprintf("[%s]", p.Rule)
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/runner"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// snapshotDashboardCommand creates a local snapshot of a dashboard, the panel queries
+// are run as the given user.
+func snapshotDashboardCommand(c utils.CommandLine, runner runner.Runner) error {
+	uid := c.Args().First()
+	if uid == "" {
+		return fmt.Errorf("missing dashboard uid")
+	}
+
+	ctx := context.Background()
+	orgID := int64(c.Int("org-id"))
+	signedInUser, err := runner.UserService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{
+		UserID: int64(c.Int("user-id")),
+		OrgID:  orgID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not read user from database: %w", err)
+	}
+
+	query := &models.GetDashboardQuery{Uid: uid, OrgId: orgID}
+	if err := runner.DashboardService.GetDashboard(ctx, query); err != nil {
+		return fmt.Errorf("failed to get dashboard %q: %w", uid, err)
+	}
+
+	snapshot, err := runner.DashboardSnapshotRenderer.RenderSnapshot(ctx, &dashboardsnapshots.RenderSnapshotCommand{
+		Dashboard: query.Result.Data,
+		From:      c.String("from"),
+		To:        c.String("to"),
+		User:      signedInUser,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render snapshot: %w", err)
+	}
+	snapshot.SetPath([]string{"snapshot", "originalUrl"}, "/d/"+uid)
+
+	cmd := &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Dashboard: snapshot,
+		Name:      c.String("name"),
+		Expires:   int64(c.Int("expires")),
+		OrgId:     orgID,
+		UserId:    signedInUser.UserID,
+	}
+	if cmd.Name == "" {
+		cmd.Name = query.Result.Title
+	}
+	if cmd.Key, err = util.GetRandomString(32); err != nil {
+		return err
+	}
+	if cmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+		return err
+	}
+
+	if err := runner.DashboardSnapshotService.CreateDashboardSnapshot(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	logger.Infof("Snapshot of %q created %s\n", query.Result.Title, color.GreenString("✔"))
+	logger.Infof("URL: %s\n", setting.ToAbsUrl("dashboard/snapshot/"+cmd.Key))
+	logger.Infof("Delete URL: %s\n", setting.ToAbsUrl("api/snapshots-delete/"+cmd.DeleteKey))
+	return nil
+}
@@ -61,6 +61,7 @@ import (
 	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
 	"github.com/grafana/grafana/pkg/services/export"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/gitsync"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/hooks"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
@@ -204,6 +205,8 @@ var wireSet = wire.NewSet(
 	searchV2.ProvideService,
 	store.ProvideService,
 	export.ProvideService,
+	gitsync.ProvideService,
+	wire.Bind(new(gitsync.Service), new(*gitsync.GitSyncService)),
 	live.ProvideService,
 	pushhttp.ProvideService,
 	contexthandler.ProvideService,
@@ -237,6 +240,8 @@ var wireSet = wire.NewSet(
 	dashsnapstore.ProvideStore,
 	wire.Bind(new(dashboardsnapshots.Service), new(*dashsnapsvc.ServiceImpl)),
 	dashsnapsvc.ProvideService,
+	dashsnapsvc.ProvideRenderer,
+	wire.Bind(new(dashboardsnapshots.Renderer), new(*dashsnapsvc.RendererImpl)),
 	datasourceservice.ProvideService,
 	wire.Bind(new(datasources.DataSourceService), new(*datasourceservice.Service)),
 	pluginSettings.ProvideService,
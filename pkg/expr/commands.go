@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -72,17 +73,20 @@ func (gm *MathCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars
 
 // ReduceCommand is an expression command for reduction of a timeseries such as a min, mean, or max.
 type ReduceCommand struct {
-	Reducer      string
-	VarToReduce  string
-	refID        string
-	seriesMapper mathexp.ReduceMapper
+	Reducer         string
+	VarToReduce     string
+	refID           string
+	seriesMapper    mathexp.ReduceMapper
+	histogramParams mathexp.HistogramReducerParams
 }
 
 // NewReduceCommand creates a new ReduceCMD.
 func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
-	if err != nil {
-		return nil, err
+	if !mathexp.IsHistogramReduceFunc(reducer) {
+		_, err := mathexp.GetReduceFunc(reducer)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &ReduceCommand{
@@ -143,7 +147,65 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	cmd, err := NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	if err != nil {
+		return nil, err
+	}
+	if mathexp.IsHistogramReduceFunc(redFunc) {
+		s, _ := settings.(map[string]interface{})
+		cmd.histogramParams, err = unmarshalHistogramReducerParams(redFunc, s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+// unmarshalHistogramReducerParams reads the settings of the quantile and
+// fraction reducers. The quantile reducer requires "quantile", and the fraction
+// reducer reads "lower" and "upper", which default to -Inf and +Inf.
+func unmarshalHistogramReducerParams(reducer string, settings map[string]interface{}) (mathexp.HistogramReducerParams, error) {
+	params := mathexp.HistogramReducerParams{
+		Lower: math.Inf(-1),
+		Upper: math.Inf(1),
+	}
+
+	readNumber := func(key string, required bool, dst *float64) error {
+		raw, ok := settings[key]
+		if !ok {
+			if required {
+				return fmt.Errorf("setting %s must be specified when reducer is '%s'", key, reducer)
+			}
+			return nil
+		}
+		v, ok := raw.(float64)
+		if !ok {
+			return fmt.Errorf("setting %s must be a number, got %T", key, raw)
+		}
+		*dst = v
+		return nil
+	}
+
+	switch strings.ToLower(reducer) {
+	case "quantile":
+		if err := readNumber("quantile", true, &params.Quantile); err != nil {
+			return params, err
+		}
+		if params.Quantile < 0 || params.Quantile > 1 {
+			return params, fmt.Errorf("setting quantile must be between 0 and 1, got %v", params.Quantile)
+		}
+	case "fraction":
+		if err := readNumber("lower", false, &params.Lower); err != nil {
+			return params, err
+		}
+		if err := readNumber("upper", false, &params.Upper); err != nil {
+			return params, err
+		}
+		if params.Lower > params.Upper {
+			return params, fmt.Errorf("setting lower (%v) must not be greater than upper (%v)", params.Lower, params.Upper)
+		}
+	}
+	return params, nil
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -159,11 +221,20 @@ func (gr *ReduceCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Va
 	for _, val := range vars[gr.VarToReduce].Values {
 		switch v := val.(type) {
 		case mathexp.Series:
+			if mathexp.IsHistogramReduceFunc(gr.Reducer) {
+				return newRes, fmt.Errorf("reduction %s can only be applied to histograms, got type %v", gr.Reducer, val.Type())
+			}
 			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
 			if err != nil {
 				return newRes, err
 			}
 			newRes.Values = append(newRes.Values, num)
+		case mathexp.Histogram:
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.histogramParams)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, num)
 		case mathexp.Number: // if incoming vars is just a number, any reduce op is just a noop, add it as it is
 			copyV := mathexp.NewNumber(gr.refID, v.GetLabels())
 			copyV.SetValue(v.GetFloat64Value())
@@ -175,7 +246,7 @@ func (gr *ReduceCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Va
 		case mathexp.NoData:
 			newRes.Values = append(newRes.Values, v.New())
 		default:
-			return newRes, fmt.Errorf("can only reduce type series, got type %v", val.Type())
+			return newRes, fmt.Errorf("can only reduce type series or histogram, got type %v", val.Type())
 		}
 	}
 	return newRes, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 	"time"
@@ -170,3 +171,62 @@ func randomReduceFunc() string {
 	res := mathexp.GetSupportedReduceFuncs()
 	return res[rand.Intn(len(res)-1)]
 }
+
+func TestReduceHistogram(t *testing.T) {
+	unmarshal := func(t *testing.T, q string) (*ReduceCommand, error) {
+		t.Helper()
+		var qmap = make(map[string]interface{})
+		require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+		return UnmarshalReduceCommand(&rawNode{RefID: "B", Query: qmap})
+	}
+
+	t.Run("should read histogram reducer settings", func(t *testing.T) {
+		cmd, err := unmarshal(t, `{ "expression": "$A", "reducer": "quantile", "settings": { "quantile": 0.99 } }`)
+		require.NoError(t, err)
+		require.Equal(t, 0.99, cmd.histogramParams.Quantile)
+
+		cmd, err = unmarshal(t, `{ "expression": "$A", "reducer": "fraction", "settings": { "upper": 0.5 } }`)
+		require.NoError(t, err)
+		require.True(t, math.IsInf(cmd.histogramParams.Lower, -1))
+		require.Equal(t, 0.5, cmd.histogramParams.Upper)
+
+		_, err = unmarshal(t, `{ "expression": "$A", "reducer": "quantile" }`)
+		require.Error(t, err)
+
+		_, err = unmarshal(t, `{ "expression": "$A", "reducer": "quantile", "settings": { "quantile": 2 } }`)
+		require.Error(t, err)
+
+		_, err = unmarshal(t, `{ "expression": "$A", "reducer": "fraction", "settings": { "lower": 2, "upper": 1 } }`)
+		require.Error(t, err)
+	})
+
+	t.Run("should reduce histograms to numbers", func(t *testing.T) {
+		cmd, err := unmarshal(t, `{ "expression": "$A", "reducer": "quantile", "settings": { "quantile": 0.5 } }`)
+		require.NoError(t, err)
+
+		h, err := mathexp.HistogramFromFrame(data.NewFrame("",
+			data.NewField("xMax", nil, []time.Time{time.Unix(10, 0), time.Unix(10, 0)}),
+			data.NewField("yMin", data.Labels{"job": "a"}, []float64{0, 1}),
+			data.NewField("yMax", nil, []float64{1, 2}),
+			data.NewField("count", nil, []float64{1, 3}),
+		))
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": {Values: mathexp.Values{h}}})
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+
+		n := res.Values[0].(mathexp.Number)
+		require.Equal(t, data.Labels{"job": "a"}, n.GetLabels())
+		require.InDelta(t, 1.333, *n.GetFloat64Value(), 0.001)
+	})
+
+	t.Run("should error when reducing series with histogram reducers", func(t *testing.T) {
+		cmd, err := NewReduceCommand("B", "quantile", "A", nil)
+		require.NoError(t, err)
+
+		s := mathexp.NewSeries("A", nil, 0)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": {Values: mathexp.Values{s}}})
+		require.Error(t, err)
+	})
+}
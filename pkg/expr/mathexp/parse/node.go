@@ -403,6 +403,8 @@ const (
 	TypeVariantSet
 	// TypeNoData is a no data response without a known data type.
 	TypeNoData
+	// TypeHistogramSet is a collection of labelled native histograms.
+	TypeHistogramSet
 )
 
 // String returns a string representation of the ReturnType.
@@ -420,6 +422,8 @@ func (f ReturnType) String() string {
 		return "variant"
 	case TypeNoData:
 		return "noData"
+	case TypeHistogramSet:
+		return "histogramSet"
 	default:
 		return "unknown"
 	}
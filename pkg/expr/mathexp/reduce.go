@@ -105,6 +105,26 @@ func GetSupportedReduceFuncs() []string {
 	return []string{"sum", "mean", "min", "max", "count", "last"}
 }
 
+// HistogramReducerParams holds the parameters of the reduction functions that
+// only apply to histograms.
+type HistogramReducerParams struct {
+	// Quantile is the quantile (0 <= q <= 1) estimated by the quantile reducer.
+	Quantile float64
+	// Lower and Upper are the bounds of the fraction reducer.
+	Lower float64
+	Upper float64
+}
+
+// IsHistogramReduceFunc returns true if the reduction function can only be
+// applied to histograms.
+func IsHistogramReduceFunc(rFunc string) bool {
+	switch strings.ToLower(rFunc) {
+	case "quantile", "fraction":
+		return true
+	}
+	return false
+}
+
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
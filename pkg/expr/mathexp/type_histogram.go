@@ -0,0 +1,200 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// Histogram is a labelled native histogram. The frame holds heatmap cells
+// with one row per bucket and point in time, as returned by Prometheus:
+// xMax (time), yMin, yMax, count and optionally yLayout. The labels are
+// stored on the yMin field.
+type Histogram struct{ Frame *data.Frame }
+
+const (
+	histogramTypeTimeIdx  = 0
+	histogramTypeMinIdx   = 1
+	histogramTypeMaxIdx   = 2
+	histogramTypeCountIdx = 3
+)
+
+// HistogramFromFrame validates that the dataframe can be considered a
+// Histogram type and populates the Histogram.
+func HistogramFromFrame(frame *data.Frame) (Histogram, error) {
+	if len(frame.Fields) < 4 {
+		return Histogram{}, fmt.Errorf("histogram frame must have at least 4 fields (xMax, yMin, yMax, count), got %d", len(frame.Fields))
+	}
+	if frame.Fields[histogramTypeTimeIdx].Type() != data.FieldTypeTime {
+		return Histogram{}, fmt.Errorf("histogram frame must have a time field first, got %s", frame.Fields[histogramTypeTimeIdx].Type())
+	}
+	for _, idx := range []int{histogramTypeMinIdx, histogramTypeMaxIdx, histogramTypeCountIdx} {
+		if !frame.Fields[idx].Type().Numeric() {
+			return Histogram{}, fmt.Errorf("histogram field %q must be numeric, got %s", frame.Fields[idx].Name, frame.Fields[idx].Type())
+		}
+	}
+	return Histogram{Frame: frame}, nil
+}
+
+// Type returns the Value type and allows it to fulfill the Value interface.
+func (h Histogram) Type() parse.ReturnType { return parse.TypeHistogramSet }
+
+// Value returns the actual value allows it to fulfill the Value interface.
+func (h Histogram) Value() interface{} { return &h }
+
+func (h Histogram) GetLabels() data.Labels { return h.Frame.Fields[histogramTypeMinIdx].Labels }
+
+func (h Histogram) SetLabels(ls data.Labels) { h.Frame.Fields[histogramTypeMinIdx].Labels = ls }
+
+func (h Histogram) GetMeta() interface{} {
+	return h.Frame.Meta.Custom
+}
+
+func (h Histogram) SetMeta(v interface{}) {
+	m := h.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		h.Frame.SetMeta(m)
+	}
+	m.Custom = v
+}
+
+func (h Histogram) AddNotice(notice data.Notice) {
+	m := h.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		h.Frame.SetMeta(m)
+	}
+	m.Notices = append(m.Notices, notice)
+}
+
+// AsDataFrame returns the underlying *data.Frame.
+func (h Histogram) AsDataFrame() *data.Frame { return h.Frame }
+
+// HistogramBucket is a single bucket of a histogram.
+type HistogramBucket struct {
+	Lower float64
+	Upper float64
+	Count float64
+}
+
+// Latest returns the buckets of the most recent point in time of the
+// histogram, sorted by their bounds.
+func (h Histogram) Latest() []HistogramBucket {
+	timeField := h.Frame.Fields[histogramTypeTimeIdx]
+
+	var latest time.Time
+	for i := 0; i < timeField.Len(); i++ {
+		if t := timeField.At(i).(time.Time); t.After(latest) {
+			latest = t
+		}
+	}
+
+	buckets := []HistogramBucket{}
+	for i := 0; i < timeField.Len(); i++ {
+		if !timeField.At(i).(time.Time).Equal(latest) {
+			continue
+		}
+		lower, _ := h.Frame.FloatAt(histogramTypeMinIdx, i)
+		upper, _ := h.Frame.FloatAt(histogramTypeMaxIdx, i)
+		count, _ := h.Frame.FloatAt(histogramTypeCountIdx, i)
+		buckets = append(buckets, HistogramBucket{Lower: lower, Upper: upper, Count: count})
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		if buckets[i].Upper == buckets[j].Upper {
+			return buckets[i].Lower < buckets[j].Lower
+		}
+		return buckets[i].Upper < buckets[j].Upper
+	})
+	return buckets
+}
+
+// Reduce turns the most recent point in time of the Histogram into a Number
+// based on the given histogram reduction function.
+func (h Histogram) Reduce(refID, rFunc string, params HistogramReducerParams) (Number, error) {
+	var l data.Labels
+	if h.GetLabels() != nil {
+		l = h.GetLabels().Copy()
+	}
+	number := NewNumber(refID, l)
+
+	buckets := h.Latest()
+	var f float64
+	switch strings.ToLower(rFunc) {
+	case "quantile":
+		f = HistogramQuantile(params.Quantile, buckets)
+	case "fraction":
+		f = HistogramFraction(params.Lower, params.Upper, buckets)
+	default:
+		return number, fmt.Errorf("invalid expression '%s': reduction %v is not supported for histograms", refID, rFunc)
+	}
+	number.SetValue(&f)
+	return number, nil
+}
+
+// HistogramQuantile estimates the q-quantile (0 <= q <= 1) of the buckets,
+// interpolating linearly within the bucket that holds the quantile.
+// It returns NaN if the histogram is empty, -Inf for q < 0 and +Inf for q > 1.
+func HistogramQuantile(q float64, buckets []HistogramBucket) float64 {
+	switch {
+	case math.IsNaN(q):
+		return math.NaN()
+	case q < 0:
+		return math.Inf(-1)
+	case q > 1:
+		return math.Inf(1)
+	}
+
+	total := 0.0
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total == 0 || math.IsNaN(total) {
+		return math.NaN()
+	}
+
+	rank := q * total
+	cumulative := 0.0
+	for _, b := range buckets {
+		if b.Count > 0 && cumulative+b.Count >= rank {
+			return b.Lower + (b.Upper-b.Lower)*((rank-cumulative)/b.Count)
+		}
+		cumulative += b.Count
+	}
+	return buckets[len(buckets)-1].Upper
+}
+
+// HistogramFraction estimates the fraction of observations between lower and
+// upper, assuming observations are distributed evenly within each bucket.
+// It returns NaN if the histogram is empty.
+func HistogramFraction(lower, upper float64, buckets []HistogramBucket) float64 {
+	if math.IsNaN(lower) || math.IsNaN(upper) {
+		return math.NaN()
+	}
+
+	total, inRange := 0.0, 0.0
+	for _, b := range buckets {
+		total += b.Count
+
+		from, to := math.Max(lower, b.Lower), math.Min(upper, b.Upper)
+		switch {
+		case from > to:
+			continue
+		case b.Lower >= lower && b.Upper <= upper:
+			inRange += b.Count
+		case b.Upper > b.Lower && !math.IsInf(b.Upper-b.Lower, 0):
+			inRange += b.Count * (to - from) / (b.Upper - b.Lower)
+		}
+	}
+	if total == 0 || math.IsNaN(total) {
+		return math.NaN()
+	}
+	return inRange / total
+}
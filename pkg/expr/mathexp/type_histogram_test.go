@@ -0,0 +1,100 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func makeHistogram(labels data.Labels) Histogram {
+	t0, t1 := time.Unix(10, 0), time.Unix(20, 0)
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{t0, t1, t1, t1, t0}),
+		data.NewField("yMin", labels, []float64{0, 1, 0, 2, 1}),
+		data.NewField("yMax", nil, []float64{1, 2, 1, 4, 2}),
+		data.NewField("count", nil, []float64{100, 4, 2, 4, 100}),
+		data.NewField("yLayout", nil, []int8{0, 0, 0, 0, 0}),
+	)
+	h, err := HistogramFromFrame(frame)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func TestHistogramFromFrame(t *testing.T) {
+	t.Run("should reject frames that are not heatmap cells", func(t *testing.T) {
+		_, err := HistogramFromFrame(data.NewFrame("",
+			data.NewField("time", nil, []time.Time{}),
+			data.NewField("value", nil, []float64{}),
+		))
+		require.Error(t, err)
+	})
+
+	t.Run("should return the buckets of the most recent sample", func(t *testing.T) {
+		h := makeHistogram(data.Labels{"job": "a"})
+		require.Equal(t, "histogramSet", h.Type().String())
+		require.Equal(t, data.Labels{"job": "a"}, h.GetLabels())
+		require.Equal(t, []HistogramBucket{
+			{Lower: 0, Upper: 1, Count: 2},
+			{Lower: 1, Upper: 2, Count: 4},
+			{Lower: 2, Upper: 4, Count: 4},
+		}, h.Latest())
+	})
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := []HistogramBucket{
+		{Lower: 0, Upper: 1, Count: 2},
+		{Lower: 1, Upper: 2, Count: 4},
+		{Lower: 2, Upper: 4, Count: 4},
+	}
+
+	require.Equal(t, 0.0, HistogramQuantile(0, buckets))
+	require.Equal(t, 1.0, HistogramQuantile(0.2, buckets))
+	require.Equal(t, 1.75, HistogramQuantile(0.5, buckets))
+	require.Equal(t, 3.0, HistogramQuantile(0.8, buckets))
+	require.Equal(t, 4.0, HistogramQuantile(1, buckets))
+	require.True(t, math.IsInf(HistogramQuantile(-1, buckets), -1))
+	require.True(t, math.IsInf(HistogramQuantile(2, buckets), 1))
+	require.True(t, math.IsNaN(HistogramQuantile(0.5, nil)))
+}
+
+func TestHistogramFraction(t *testing.T) {
+	buckets := []HistogramBucket{
+		{Lower: 0, Upper: 1, Count: 2},
+		{Lower: 1, Upper: 2, Count: 4},
+		{Lower: 2, Upper: 4, Count: 4},
+	}
+
+	require.Equal(t, 1.0, HistogramFraction(math.Inf(-1), math.Inf(1), buckets))
+	require.Equal(t, 0.6, HistogramFraction(math.Inf(-1), 2, buckets))
+	require.Equal(t, 0.6, HistogramFraction(0.5, 2.5, buckets))
+	require.Equal(t, 0.0, HistogramFraction(5, 10, buckets))
+	require.True(t, math.IsNaN(HistogramFraction(0, 1, nil)))
+}
+
+func TestHistogramReduce(t *testing.T) {
+	h := makeHistogram(data.Labels{"job": "a"})
+
+	t.Run("quantile", func(t *testing.T) {
+		n, err := h.Reduce("B", "quantile", HistogramReducerParams{Quantile: 0.5})
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{"job": "a"}, n.GetLabels())
+		require.Equal(t, 1.75, *n.GetFloat64Value())
+	})
+
+	t.Run("fraction", func(t *testing.T) {
+		n, err := h.Reduce("B", "fraction", HistogramReducerParams{Lower: math.Inf(-1), Upper: 1})
+		require.NoError(t, err)
+		require.Equal(t, 0.2, *n.GetFloat64Value())
+	})
+
+	t.Run("other reducers are not supported", func(t *testing.T) {
+		_, err := h.Reduce("B", "mean", HistogramReducerParams{})
+		require.Error(t, err)
+	})
+}
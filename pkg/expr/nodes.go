@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins/adapters"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/util/converter"
 
 	"gonum.org/v1/gonum/graph/simple"
 )
@@ -274,6 +275,16 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		}
 
 		for _, frame := range qr.Frames {
+			if converter.IsHistogramFrame(frame) {
+				logger.Debug("expression datasource query (histogramSet)", "query", refID)
+				h, err := mathexp.HistogramFromFrame(frame)
+				if err != nil {
+					return mathexp.Results{}, err
+				}
+				vals = append(vals, h)
+				continue
+			}
+
 			logger.Debug("expression datasource query (seriesSet)", "query", refID)
 			// Check for TimeSeriesTypeNot in InfluxDB queries. A data frame of this type will cause
 			// the WideToMany() function to error out, which results in unhealthy alerts.
@@ -302,6 +313,9 @@ func isAllFrameVectors(datasourceType string, frames data.Frames) bool {
 	}
 	allVector := false
 	for i, frame := range frames {
+		if converter.IsHistogramFrame(frame) {
+			return false
+		}
 		if frame.Meta != nil && frame.Meta.Custom != nil {
 			if sMap, ok := frame.Meta.Custom.(map[string]string); ok {
 				if sMap != nil {
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"errors"
 	"net/url"
 	"regexp"
@@ -13,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/setting"
@@ -165,10 +167,18 @@ func AdminOrEditorAndFeatureEnabled(enabled bool) web.Handler {
 }
 
 // SnapshotPublicModeOrSignedIn creates a middleware that allows access
-// if snapshot public mode is enabled or if user is signed in.
+// if snapshot public mode is enabled or if user is signed in. When a public
+// mode token is configured, anonymous requests must send it.
 func SnapshotPublicModeOrSignedIn(cfg *setting.Cfg) web.Handler {
 	return func(c *models.ReqContext) {
 		if cfg.SnapshotPublicMode {
+			if c.IsSignedIn || cfg.SnapshotPublicModeToken == "" {
+				return
+			}
+			token := c.Req.Header.Get(dashboardsnapshots.TokenHeader)
+			if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.SnapshotPublicModeToken)) != 1 {
+				notAuthorized(c)
+			}
 			return
 		}
 
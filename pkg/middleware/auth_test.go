@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -110,6 +111,28 @@ func TestMiddlewareAuth(t *testing.T) {
 		sc.fakeReq("GET", "/api/snapshot").exec()
 		assert.Equal(t, 200, sc.resp.Code)
 	})
+
+	middlewareScenario(t, "Snapshot public mode enabled with token and unauthenticated request without token should return 401", func(
+		t *testing.T, sc *scenarioContext) {
+		sc.cfg.SnapshotPublicMode = true
+		sc.cfg.SnapshotPublicModeToken = "secret"
+		sc.m.Get("/api/snapshot", SnapshotPublicModeOrSignedIn(sc.cfg), sc.defaultHandler)
+		sc.fakeReq("GET", "/api/snapshot")
+		sc.req.Header.Set(dashboardsnapshots.TokenHeader, "wrong")
+		sc.exec()
+		assert.Equal(t, 401, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "Snapshot public mode enabled with token and unauthenticated request with token should return 200", func(
+		t *testing.T, sc *scenarioContext) {
+		sc.cfg.SnapshotPublicMode = true
+		sc.cfg.SnapshotPublicModeToken = "secret"
+		sc.m.Get("/api/snapshot", SnapshotPublicModeOrSignedIn(sc.cfg), sc.defaultHandler)
+		sc.fakeReq("GET", "/api/snapshot")
+		sc.req.Header.Set(dashboardsnapshots.TokenHeader, "secret")
+		sc.exec()
+		assert.Equal(t, 200, sc.resp.Code)
+	})
 }
 
 func TestRemoveForceLoginparams(t *testing.T) {
@@ -220,6 +220,13 @@ type DeleteDashboardCommand struct {
 	Id                     int64
 	OrgId                  int64
 	ForceDeleteFolderRules bool
+	// MoveToTrash keeps the dashboard in trash if trash is enabled,
+	// otherwise the dashboard is deleted permanently.
+	MoveToTrash bool
+	// TrashedWith is the UID of a folder which is moved to trash by the
+	// same delete, like the parent of a nested folder. The trash entry is
+	// restored and purged together with the one of that folder.
+	TrashedWith string
 }
 
 type DeleteOrphanedProvisionedDashboardsCommand struct {
@@ -39,6 +39,15 @@ const (
 	// the kind may need to change to better encapsulate { targets:[], transforms:[] }
 	StandardKindQuery = "query"
 
+	// StandardKindAlertRule: only used for searchV2 right now
+	StandardKindAlertRule = "alertrule"
+
+	// StandardKindLibraryPanel: only used for searchV2 right now
+	StandardKindLibraryPanel = "librarypanel"
+
+	// StandardKindCorrelation: only used for searchV2 right now
+	StandardKindCorrelation = "correlation"
+
 	//----------------------------------------
 	// References are referenced from objects
 	//----------------------------------------
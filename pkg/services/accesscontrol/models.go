@@ -404,6 +404,11 @@ const (
 	// Alerting provisioning actions
 	ActionAlertingProvisioningRead  = "alert.provisioning:read"
 	ActionAlertingProvisioningWrite = "alert.provisioning:write"
+
+	// Live channels actions. Checked only for channels with pipeline rules
+	// which reference them.
+	ActionLiveChannelsPublish   = "live.channels:publish"
+	ActionLiveChannelsSubscribe = "live.channels:subscribe"
 )
 
 var (
@@ -417,6 +422,11 @@ var (
 	ScopeAnnotationsID               = Scope(ScopeAnnotationsRoot, "id", Parameter(":annotationId"))
 	ScopeAnnotationsTypeDashboard    = ScopeAnnotationsProvider.GetResourceScopeType(annotations.Dashboard.String())
 	ScopeAnnotationsTypeOrganization = ScopeAnnotationsProvider.GetResourceScopeType(annotations.Organization.String())
+
+	// Live channel scopes, channel path is used as uid: channels:uid:stream/namespace/path
+	ScopeLiveChannelsRoot     = "channels"
+	ScopeLiveChannelsProvider = NewScopeProvider(ScopeLiveChannelsRoot)
+	ScopeLiveChannelsAll      = ScopeLiveChannelsProvider.GetResourceAllScope()
 )
 
 func BuiltInRolesWithParents(builtInRoles []string) map[string]struct{} {
@@ -2,6 +2,7 @@ package annotationsimpl
 
 import (
 	"context"
+	"errors"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -11,43 +12,234 @@ import (
 )
 
 type RepositoryImpl struct {
+	// store keeps dashboard annotations and the annotations of all types which are not kept in other stores.
 	store store
+	// alertStore and apiStore keep alert and API annotations outside of the Grafana database when configured.
+	alertStore typeStore
+	apiStore   typeStore
+	// externalStores are the stores besides the Grafana database by the store their ids are tagged with.
+	externalStores map[int64]typeStore
+	log            log.Logger
 }
 
 func ProvideService(db db.DB, cfg *setting.Cfg, tagService tag.Service) *RepositoryImpl {
-	return &RepositoryImpl{
+	l := log.New("annotations")
+	r := &RepositoryImpl{
 		store: &xormRepositoryImpl{
 			cfg:               cfg,
 			db:                db,
-			log:               log.New("annotations"),
+			log:               l,
 			tagService:        tagService,
 			maximumTagsLength: cfg.AnnotationMaximumTagsLength,
 		},
+		log: l,
 	}
+
+	r.externalStores = map[int64]typeStore{}
+	typeStoreOf := func(kind setting.AnnotationStore) typeStore {
+		switch kind {
+		case setting.AnnotationStoreLoki:
+			if _, ok := r.externalStores[externalIDStoreLoki]; !ok {
+				r.externalStores[externalIDStoreLoki] = newLokiStore(cfg.AnnotationStores.Loki, newExternalIDs(externalIDStoreLoki))
+			}
+			return r.externalStores[externalIDStoreLoki]
+		case setting.AnnotationStoreElasticsearch:
+			if _, ok := r.externalStores[externalIDStoreElasticsearch]; !ok {
+				r.externalStores[externalIDStoreElasticsearch] = newElasticsearchStore(cfg.AnnotationStores.Elasticsearch, newExternalIDs(externalIDStoreElasticsearch))
+			}
+			return r.externalStores[externalIDStoreElasticsearch]
+		}
+		return nil
+	}
+	r.alertStore = typeStoreOf(cfg.AnnotationStores.AlertStore)
+	r.apiStore = typeStoreOf(cfg.AnnotationStores.APIStore)
+
+	return r
 }
 
 func (r *RepositoryImpl) Save(ctx context.Context, item *annotations.Item) error {
-	return r.store.Add(ctx, item)
+	return r.storeFor(item).Add(ctx, item)
 }
 
 // SaveMany inserts multiple annotations at once.
 // It does not return IDs associated with created annotations. If you need this functionality, use the single-item Save instead.
 func (r *RepositoryImpl) SaveMany(ctx context.Context, items []annotations.Item) error {
-	return r.store.AddMany(ctx, items)
+	if r.alertStore == nil && r.apiStore == nil {
+		return r.store.AddMany(ctx, items)
+	}
+
+	var stores []typeStore
+	itemsByStore := map[typeStore][]annotations.Item{}
+	for i := range items {
+		s := r.storeFor(&items[i])
+		if _, ok := itemsByStore[s]; !ok {
+			stores = append(stores, s)
+		}
+		itemsByStore[s] = append(itemsByStore[s], items[i])
+	}
+
+	for _, s := range stores {
+		if err := s.AddMany(ctx, itemsByStore[s]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// Update updates the annotation in the store keeping it. Annotations stored in Loki cannot be updated.
 func (r *RepositoryImpl) Update(ctx context.Context, item *annotations.Item) error {
-	return r.store.Update(ctx, item)
+	if s, ok := r.externalStoreOf(item.Id); ok {
+		return s.Update(ctx, item)
+	}
+
+	// annotations stored before their ids were tagged with their store can be in any store
+	err := r.store.Update(ctx, item)
+	for _, s := range r.typeStores(nil) {
+		if !errors.Is(err, errAnnotationNotFound) {
+			break
+		}
+		err = s.Update(ctx, item)
+	}
+	return err
 }
 
+// Find returns the annotations matching the query from all stores, most recent first.
 func (r *RepositoryImpl) Find(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
-	return r.store.Get(ctx, query)
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	items, err := r.store.Get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := r.typeStores(query)
+	if len(stores) == 0 {
+		return items, nil
+	}
+
+	for _, s := range stores {
+		found, err := s.Get(ctx, query)
+		if err != nil {
+			// annotations of the other stores are still shown
+			r.log.Error("Failed to find annotations", "error", err)
+			continue
+		}
+		found, err = r.store.FilterReadable(ctx, query.SignedInUser, found)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, found...)
+	}
+
+	sortItems(items)
+	if int64(len(items)) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, nil
 }
 
+// Delete deletes an annotation from the store keeping it, or the annotations of a dashboard panel from all stores.
+// Deleting an annotation which does not exist is not an error. Annotations stored in Loki cannot be deleted.
 func (r *RepositoryImpl) Delete(ctx context.Context, params *annotations.DeleteParams) error {
-	return r.store.Delete(ctx, params)
+	if params.Id != 0 {
+		var err error
+		if s, ok := r.externalStoreOf(params.Id); ok {
+			err = s.Delete(ctx, params)
+		} else {
+			err = r.deleteByLegacyID(ctx, params)
+		}
+		if errors.Is(err, errAnnotationNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := r.store.Delete(ctx, params); err != nil {
+		return err
+	}
+	for _, s := range r.typeStores(nil) {
+		if err := s.Delete(ctx, params); err != nil {
+			// the annotations of the Grafana database are deleted already
+			r.log.Error("Failed to delete annotations", "error", err, "dashboardId", params.DashboardId, "panelId", params.PanelId)
+		}
+	}
+	return nil
+}
+
+// deleteByLegacyID deletes an annotation whose id is not tagged with its store, trying the Grafana database first.
+func (r *RepositoryImpl) deleteByLegacyID(ctx context.Context, params *annotations.DeleteParams) error {
+	err := r.store.Delete(ctx, params)
+	for _, s := range r.typeStores(nil) {
+		if !errors.Is(err, errAnnotationNotFound) {
+			break
+		}
+		err = s.Delete(ctx, params)
+	}
+	return err
 }
 
+// FindTags returns the tags of the annotations of all stores.
 func (r *RepositoryImpl) FindTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
-	return r.store.GetTags(ctx, query)
+	result, err := r.store.GetTags(ctx, query)
+	stores := r.typeStores(nil)
+	if err != nil || len(stores) == 0 {
+		return result, err
+	}
+
+	counts := map[string]int64{}
+	for _, t := range result.Tags {
+		counts[t.Tag] += t.Count
+	}
+	for _, s := range stores {
+		found, err := s.GetTags(ctx, query)
+		if err != nil {
+			r.log.Error("Failed to find annotation tags", "error", err)
+			continue
+		}
+		for _, t := range found.Tags {
+			counts[t.Tag] += t.Count
+		}
+	}
+	return tagsResult(counts, query.Limit), nil
+}
+
+// storeFor returns the store keeping annotations like the item.
+func (r *RepositoryImpl) storeFor(item *annotations.Item) typeStore {
+	switch {
+	case item.AlertId != 0 && r.alertStore != nil:
+		return r.alertStore
+	case item.AlertId == 0 && item.DashboardId == 0 && r.apiStore != nil:
+		return r.apiStore
+	}
+	return r.store
+}
+
+// externalStoreOf returns the store besides the Grafana database keeping the annotation with the id, if the id is
+// tagged with its store.
+func (r *RepositoryImpl) externalStoreOf(id int64) (typeStore, bool) {
+	idStore, ok := externalIDStore(id)
+	if !ok {
+		return nil, false
+	}
+	s, ok := r.externalStores[idStore]
+	if !ok {
+		return nil, false
+	}
+	return s, true
+}
+
+// typeStores returns the stores besides the Grafana database that can keep annotations matching the query, or all
+// of them when the query is nil.
+func (r *RepositoryImpl) typeStores(query *annotations.ItemQuery) []typeStore {
+	var stores []typeStore
+	if r.alertStore != nil && (query == nil || query.Type != "annotation") {
+		stores = append(stores, r.alertStore)
+	}
+	if r.apiStore != nil && r.apiStore != r.alertStore &&
+		(query == nil || (query.Type != "alert" && query.AlertId == 0 && query.DashboardId == 0)) {
+		stores = append(stores, r.apiStore)
+	}
+	return stores
 }
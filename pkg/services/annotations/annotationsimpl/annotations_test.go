@@ -0,0 +1,295 @@
+package annotationsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// memoryStore keeps annotations in memory, it is used in place of the database and the other stores.
+type memoryStore struct {
+	items     []annotations.Item
+	nextID    int64
+	ids       *externalIDs
+	readable  func(item *annotations.ItemDTO) bool
+	deleteErr error
+	updates   int
+	deletes   int
+}
+
+func (s *memoryStore) Add(_ context.Context, item *annotations.Item) error {
+	if item.Id == 0 && s.ids != nil {
+		item.Id = s.ids.next()
+	}
+	if item.Id == 0 {
+		s.nextID++
+		item.Id = s.nextID
+	}
+	s.items = append(s.items, *item)
+	return nil
+}
+
+func (s *memoryStore) AddMany(ctx context.Context, items []annotations.Item) error {
+	for i := range items {
+		if err := s.Add(ctx, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Update(_ context.Context, item *annotations.Item) error {
+	s.updates++
+	for i := range s.items {
+		if s.items[i].Id == item.Id {
+			s.items[i].Text = item.Text
+			return nil
+		}
+	}
+	return errAnnotationNotFound
+}
+
+func (s *memoryStore) Get(_ context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	items := make([]*annotations.ItemDTO, 0)
+	for _, item := range s.items {
+		dto := &annotations.ItemDTO{Id: item.Id, AlertId: item.AlertId, DashboardId: item.DashboardId, Time: item.Epoch, TimeEnd: item.Epoch, Text: item.Text, Tags: item.Tags}
+		if (query.Type == "alert" && item.AlertId == 0) || (query.Type == "annotation" && item.AlertId != 0) || !matchesQuery(dto, query) {
+			continue
+		}
+		items = append(items, dto)
+	}
+	sortItems(items)
+	if int64(len(items)) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, params *annotations.DeleteParams) error {
+	s.deletes++
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	kept := s.items[:0]
+	for _, item := range s.items {
+		if (params.Id != 0 && item.Id != params.Id) || (params.Id == 0 && (item.DashboardId != params.DashboardId || item.PanelId != params.PanelId)) {
+			kept = append(kept, item)
+		}
+	}
+	deleted := len(s.items) - len(kept)
+	s.items = kept
+	if params.Id != 0 && deleted == 0 {
+		return errAnnotationNotFound
+	}
+	return nil
+}
+
+func (s *memoryStore) GetTags(_ context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	counts := map[string]int64{}
+	for _, item := range s.items {
+		for _, t := range item.Tags {
+			counts[t]++
+		}
+	}
+	return tagsResult(counts, query.Limit), nil
+}
+
+func (s *memoryStore) CleanAnnotations(context.Context, setting.AnnotationCleanupSettings, string) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryStore) CleanOrphanedAnnotationTags(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryStore) FilterReadable(_ context.Context, _ *user.SignedInUser, items []*annotations.ItemDTO) ([]*annotations.ItemDTO, error) {
+	readable := make([]*annotations.ItemDTO, 0, len(items))
+	for _, item := range items {
+		if s.readable == nil || s.readable(item) {
+			readable = append(readable, item)
+		}
+	}
+	return readable, nil
+}
+
+func TestRepositoryWithTypeStores(t *testing.T) {
+	setup := func() (*RepositoryImpl, *memoryStore, *memoryStore, *memoryStore) {
+		sqlStore := &memoryStore{}
+		alertStore := &memoryStore{ids: newExternalIDs(externalIDStoreLoki)}
+		apiStore := &memoryStore{ids: newExternalIDs(externalIDStoreElasticsearch)}
+		repo := &RepositoryImpl{
+			store:          sqlStore,
+			alertStore:     alertStore,
+			apiStore:       apiStore,
+			externalStores: map[int64]typeStore{externalIDStoreLoki: alertStore, externalIDStoreElasticsearch: apiStore},
+			log:            log.New("annotations.test"),
+		}
+		return repo, sqlStore, alertStore, apiStore
+	}
+
+	t.Run("should keep annotations in the store of their type", func(t *testing.T) {
+		repo, sqlStore, alertStore, apiStore := setup()
+
+		require.NoError(t, repo.Save(context.Background(), &annotations.Item{OrgId: 1, DashboardId: 1, Epoch: 10}))
+		require.NoError(t, repo.SaveMany(context.Background(), []annotations.Item{
+			{OrgId: 1, AlertId: 1, Epoch: 20},
+			{OrgId: 1, Epoch: 30},
+			{OrgId: 1, AlertId: 2, DashboardId: 1, Epoch: 40},
+		}))
+
+		require.Len(t, sqlStore.items, 1)
+		assert.Equal(t, int64(1), sqlStore.items[0].DashboardId)
+		require.Len(t, alertStore.items, 2)
+		assert.Equal(t, int64(1), alertStore.items[0].AlertId)
+		assert.Equal(t, int64(2), alertStore.items[1].AlertId)
+		require.Len(t, apiStore.items, 1)
+		assert.Equal(t, int64(30), apiStore.items[0].Epoch)
+	})
+
+	t.Run("should find annotations of all stores, most recent first", func(t *testing.T) {
+		repo, sqlStore, alertStore, apiStore := setup()
+		require.NoError(t, sqlStore.Add(context.Background(), &annotations.Item{OrgId: 1, DashboardId: 1, Epoch: 10}))
+		require.NoError(t, alertStore.Add(context.Background(), &annotations.Item{OrgId: 1, AlertId: 1, Epoch: 30}))
+		require.NoError(t, apiStore.Add(context.Background(), &annotations.Item{OrgId: 1, Epoch: 20}))
+
+		items, err := repo.Find(context.Background(), &annotations.ItemQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, []int64{30, 20, 10}, []int64{items[0].Time, items[1].Time, items[2].Time})
+
+		items, err = repo.Find(context.Background(), &annotations.ItemQuery{OrgId: 1, Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+
+		items, err = repo.Find(context.Background(), &annotations.ItemQuery{OrgId: 1, Type: "alert"})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, int64(1), items[0].AlertId)
+	})
+
+	t.Run("should only find annotations of other stores the user can read", func(t *testing.T) {
+		repo, sqlStore, alertStore, _ := setup()
+		sqlStore.readable = func(item *annotations.ItemDTO) bool { return item.DashboardId == 0 }
+		require.NoError(t, alertStore.Add(context.Background(), &annotations.Item{OrgId: 1, AlertId: 1, Epoch: 10}))
+		require.NoError(t, alertStore.Add(context.Background(), &annotations.Item{OrgId: 1, AlertId: 1, DashboardId: 1, Epoch: 20}))
+
+		items, err := repo.Find(context.Background(), &annotations.ItemQuery{OrgId: 1})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, int64(0), items[0].DashboardId)
+	})
+
+	t.Run("should update and delete annotations in the store keeping them", func(t *testing.T) {
+		repo, sqlStore, _, apiStore := setup()
+		sqlItem := &annotations.Item{OrgId: 1, DashboardId: 1, Epoch: 10}
+		apiItem := &annotations.Item{OrgId: 1, Epoch: 10}
+		require.NoError(t, repo.Save(context.Background(), sqlItem))
+		require.NoError(t, repo.Save(context.Background(), apiItem))
+
+		require.NoError(t, repo.Update(context.Background(), &annotations.Item{OrgId: 1, Id: apiItem.Id, Text: "updated"}))
+		assert.Equal(t, "updated", apiStore.items[0].Text)
+		assert.Empty(t, sqlStore.items[0].Text)
+		assert.Equal(t, 0, sqlStore.updates, "annotations of other stores are updated in their store directly")
+
+		err := repo.Update(context.Background(), &annotations.Item{OrgId: 1, Id: 42, Text: "updated"})
+		require.ErrorIs(t, err, errAnnotationNotFound)
+
+		require.NoError(t, repo.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: sqlItem.Id}))
+		assert.Empty(t, sqlStore.items)
+		assert.Len(t, apiStore.items, 1)
+		assert.Equal(t, 0, apiStore.deletes, "annotations found in the database are not deleted from other stores")
+
+		require.NoError(t, repo.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: apiItem.Id}))
+		assert.Empty(t, apiStore.items)
+		assert.Equal(t, 1, sqlStore.deletes, "annotations of other stores are deleted from their store directly")
+
+		require.NoError(t, repo.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: 42}))
+	})
+
+	t.Run("should look for annotations whose ids are not tagged with their store in all stores", func(t *testing.T) {
+		repo, sqlStore, _, apiStore := setup()
+		legacyID := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC).UnixMicro()
+		require.NoError(t, apiStore.Add(context.Background(), &annotations.Item{OrgId: 1, Id: legacyID, Epoch: 10}))
+
+		require.NoError(t, repo.Update(context.Background(), &annotations.Item{OrgId: 1, Id: legacyID, Text: "updated"}))
+		assert.Equal(t, "updated", apiStore.items[0].Text)
+		assert.Equal(t, 1, sqlStore.updates)
+
+		require.NoError(t, repo.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: legacyID}))
+		assert.Empty(t, apiStore.items)
+		assert.Equal(t, 1, sqlStore.deletes)
+	})
+
+	t.Run("should return errors of the store keeping the annotation to delete", func(t *testing.T) {
+		repo, _, alertStore, apiStore := setup()
+		alertStore.deleteErr = errReadOnlyStore
+		alertItem := &annotations.Item{OrgId: 1, AlertId: 1, Epoch: 10}
+		require.NoError(t, repo.Save(context.Background(), alertItem))
+
+		err := repo.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: alertItem.Id})
+		require.ErrorIs(t, err, errReadOnlyStore)
+		assert.Len(t, alertStore.items, 1)
+		assert.Equal(t, 0, apiStore.deletes)
+	})
+
+	t.Run("should delete the annotations of a panel from all stores, logging failures of other stores", func(t *testing.T) {
+		repo, sqlStore, alertStore, apiStore := setup()
+		require.NoError(t, sqlStore.Add(context.Background(), &annotations.Item{OrgId: 1, DashboardId: 1, PanelId: 2}))
+		require.NoError(t, apiStore.Add(context.Background(), &annotations.Item{OrgId: 1, DashboardId: 1, PanelId: 2}))
+		alertStore.deleteErr = errReadOnlyStore
+
+		require.NoError(t, repo.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, DashboardId: 1, PanelId: 2}))
+		assert.Empty(t, sqlStore.items)
+		assert.Empty(t, apiStore.items)
+		assert.Equal(t, 1, alertStore.deletes)
+	})
+
+	t.Run("should merge the tags of all stores", func(t *testing.T) {
+		repo, sqlStore, alertStore, _ := setup()
+		require.NoError(t, sqlStore.Add(context.Background(), &annotations.Item{OrgId: 1, DashboardId: 1, Tags: []string{"deploy", "env:prod"}}))
+		require.NoError(t, alertStore.Add(context.Background(), &annotations.Item{OrgId: 1, AlertId: 1, Tags: []string{"env:prod"}}))
+
+		result, err := repo.FindTags(context.Background(), &annotations.TagsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []*annotations.TagsDTO{{Tag: "deploy", Count: 1}, {Tag: "env:prod", Count: 2}}, result.Tags)
+	})
+}
+
+func TestExternalIDs(t *testing.T) {
+	t.Run("should generate increasing ids tagged with their store", func(t *testing.T) {
+		ids := newExternalIDs(externalIDStoreElasticsearch)
+		prev := int64(0)
+		for i := 0; i < 10; i++ {
+			id := ids.next()
+			require.Greater(t, id, prev)
+			require.GreaterOrEqual(t, id, int64(1)<<52)
+			require.Less(t, id, int64(1)<<53, "ids stay precise in JavaScript numbers")
+
+			store, ok := externalIDStore(id)
+			require.True(t, ok)
+			require.Equal(t, externalIDStoreElasticsearch, store)
+			prev = id
+		}
+	})
+
+	t.Run("should not collide between instances", func(t *testing.T) {
+		a := &externalIDs{store: externalIDStoreLoki, instance: 1}
+		b := &externalIDs{store: externalIDStoreLoki, instance: 2}
+		require.NotEqual(t, a.next(), b.next())
+	})
+
+	t.Run("should not tag ids of the Grafana database and older ids", func(t *testing.T) {
+		for _, id := range []int64{1, 42, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC).UnixMicro(), -1} {
+			_, ok := externalIDStore(id)
+			require.False(t, ok, id)
+		}
+	})
+}
@@ -0,0 +1,515 @@
+package annotationsimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/tag"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// elasticsearchMapping is the mapping of the index created for annotations.
+const elasticsearchMapping = `{
+	"mappings": {
+		"properties": {
+			"id":          {"type": "long"},
+			"orgId":       {"type": "long"},
+			"type":        {"type": "keyword"},
+			"dashboardId": {"type": "long"},
+			"panelId":     {"type": "long"},
+			"alertId":     {"type": "long"},
+			"userId":      {"type": "long"},
+			"text":        {"type": "text"},
+			"tags":        {"type": "keyword"},
+			"prevState":   {"type": "keyword"},
+			"newState":    {"type": "keyword"},
+			"epoch":       {"type": "long"},
+			"epochEnd":    {"type": "long"},
+			"created":     {"type": "long"},
+			"updated":     {"type": "long"},
+			"data":        {"type": "object", "enabled": false}
+		}
+	}
+}`
+
+// elasticsearchStore keeps annotations as documents of an Elasticsearch index. The index is created on first use.
+type elasticsearchStore struct {
+	cfg    setting.AnnotationElasticsearchSettings
+	client *http.Client
+	log    log.Logger
+	ids    *externalIDs
+
+	mu         sync.Mutex
+	indexReady bool
+}
+
+func newElasticsearchStore(cfg setting.AnnotationElasticsearchSettings, ids *externalIDs) *elasticsearchStore {
+	return &elasticsearchStore{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}},
+		log:    log.New("annotations.elasticsearch"),
+		ids:    ids,
+	}
+}
+
+type elasticsearchDocument struct {
+	Id          int64            `json:"id"`
+	OrgId       int64            `json:"orgId"`
+	Type        string           `json:"type"`
+	DashboardId int64            `json:"dashboardId"`
+	PanelId     int64            `json:"panelId"`
+	AlertId     int64            `json:"alertId"`
+	UserId      int64            `json:"userId"`
+	Text        string           `json:"text"`
+	Tags        []string         `json:"tags"`
+	PrevState   string           `json:"prevState"`
+	NewState    string           `json:"newState"`
+	Epoch       int64            `json:"epoch"`
+	EpochEnd    int64            `json:"epochEnd"`
+	Created     int64            `json:"created"`
+	Updated     int64            `json:"updated"`
+	Data        *simplejson.Json `json:"data,omitempty"`
+}
+
+func newElasticsearchDocument(item *annotations.Item) elasticsearchDocument {
+	return elasticsearchDocument{
+		Id:          item.Id,
+		OrgId:       item.OrgId,
+		Type:        annotationTypeOf(item),
+		DashboardId: item.DashboardId,
+		PanelId:     item.PanelId,
+		AlertId:     item.AlertId,
+		UserId:      item.UserId,
+		Text:        item.Text,
+		Tags:        item.Tags,
+		PrevState:   item.PrevState,
+		NewState:    item.NewState,
+		Epoch:       item.Epoch,
+		EpochEnd:    item.EpochEnd,
+		Created:     item.Created,
+		Updated:     item.Updated,
+		Data:        item.Data,
+	}
+}
+
+func (d elasticsearchDocument) toItemDTO() *annotations.ItemDTO {
+	return &annotations.ItemDTO{
+		Id:          d.Id,
+		AlertId:     d.AlertId,
+		DashboardId: d.DashboardId,
+		PanelId:     d.PanelId,
+		UserId:      d.UserId,
+		NewState:    d.NewState,
+		PrevState:   d.PrevState,
+		Created:     d.Created,
+		Updated:     d.Updated,
+		Time:        d.Epoch,
+		TimeEnd:     d.EpochEnd,
+		Text:        d.Text,
+		Tags:        d.Tags,
+		Data:        d.Data,
+	}
+}
+
+type elasticsearchHit struct {
+	Source elasticsearchDocument `json:"_source"`
+}
+
+type elasticsearchSearchResponse struct {
+	Hits struct {
+		Hits []elasticsearchHit `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		Tags struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int64  `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"tags"`
+	} `json:"aggregations"`
+}
+
+func (s *elasticsearchStore) Add(ctx context.Context, item *annotations.Item) error {
+	if err := prepareItem(item, s.ids); err != nil {
+		return err
+	}
+	return s.index(ctx, item)
+}
+
+func (s *elasticsearchStore) AddMany(ctx context.Context, items []annotations.Item) error {
+	if err := s.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	for i := range items {
+		item := &items[i]
+		if err := prepareItem(item, s.ids); err != nil {
+			return err
+		}
+
+		action := map[string]interface{}{"index": map[string]interface{}{"_index": s.cfg.Index, "_id": strconv.FormatInt(item.Id, 10)}}
+		for _, line := range []interface{}{action, newElasticsearchDocument(item)} {
+			encoded, err := json.Marshal(line)
+			if err != nil {
+				return err
+			}
+			body.Write(encoded)
+			body.WriteByte('\n')
+		}
+	}
+
+	status, resp, err := s.request(ctx, http.MethodPost, "/_bulk", url.Values{"refresh": {"wait_for"}}, body.Bytes(), "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to index annotations in Elasticsearch: %w", responseError(status, resp))
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	if result.Errors {
+		return fmt.Errorf("failed to index annotations in Elasticsearch: %w", responseError(status, resp))
+	}
+	return nil
+}
+
+func (s *elasticsearchStore) Update(ctx context.Context, item *annotations.Item) error {
+	status, body, err := s.request(ctx, http.MethodGet, s.documentPath(item.Id), nil, nil, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return errAnnotationNotFound
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to get annotation from Elasticsearch: %w", responseError(status, body))
+	}
+
+	var hit elasticsearchHit
+	if err := json.Unmarshal(body, &hit); err != nil {
+		return err
+	}
+	doc := hit.Source
+	if doc.OrgId != item.OrgId {
+		return errAnnotationNotFound
+	}
+
+	existing := &annotations.Item{
+		Id:          doc.Id,
+		OrgId:       doc.OrgId,
+		UserId:      doc.UserId,
+		DashboardId: doc.DashboardId,
+		PanelId:     doc.PanelId,
+		AlertId:     doc.AlertId,
+		PrevState:   doc.PrevState,
+		NewState:    doc.NewState,
+		Epoch:       doc.Epoch,
+		EpochEnd:    doc.EpochEnd,
+		Created:     doc.Created,
+		Tags:        doc.Tags,
+		Data:        doc.Data,
+		Text:        item.Text,
+		Updated:     timeNow().UnixNano() / int64(time.Millisecond),
+	}
+	if item.Epoch != 0 {
+		existing.Epoch = item.Epoch
+	}
+	if item.EpochEnd != 0 {
+		existing.EpochEnd = item.EpochEnd
+	}
+	if item.Data != nil {
+		existing.Data = item.Data
+	}
+	if item.Tags != nil {
+		existing.Tags = tag.JoinTagPairs(tag.ParseTagPairs(item.Tags))
+	}
+	if err := validateTimeRange(existing); err != nil {
+		return err
+	}
+
+	return s.index(ctx, existing)
+}
+
+func (s *elasticsearchStore) Get(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	search := map[string]interface{}{
+		"size":  query.Limit,
+		"sort":  []interface{}{map[string]string{"epochEnd": "desc"}, map[string]string{"epoch": "desc"}},
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": buildElasticsearchFilters(query)}},
+	}
+	if query.Limit <= 0 {
+		search["size"] = 100
+	}
+
+	resp, err := s.search(ctx, search)
+	if err != nil || resp == nil {
+		return []*annotations.ItemDTO{}, err
+	}
+
+	items := make([]*annotations.ItemDTO, 0, len(resp.Hits.Hits))
+	for _, hit := range resp.Hits.Hits {
+		items = append(items, hit.Source.toItemDTO())
+	}
+	return items, nil
+}
+
+func (s *elasticsearchStore) Delete(ctx context.Context, params *annotations.DeleteParams) error {
+	filters := []interface{}{term("orgId", params.OrgId)}
+	if params.Id != 0 {
+		filters = append(filters, term("id", params.Id))
+	} else {
+		filters = append(filters, term("dashboardId", params.DashboardId), term("panelId", params.PanelId))
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filters}}})
+	if err != nil {
+		return err
+	}
+
+	status, resp, err := s.request(ctx, http.MethodPost, "/"+url.PathEscape(s.cfg.Index)+"/_delete_by_query", url.Values{"refresh": {"true"}}, body, "application/json")
+	if err != nil {
+		return err
+	}
+	// nothing to delete before the first annotation created the index
+	if status != http.StatusOK && status != http.StatusNotFound {
+		return fmt.Errorf("failed to delete annotations from Elasticsearch: %w", responseError(status, resp))
+	}
+	if params.Id == 0 {
+		return nil
+	}
+	if status == http.StatusNotFound {
+		return errAnnotationNotFound
+	}
+
+	var result struct {
+		Deleted int64 `json:"deleted"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	if result.Deleted == 0 {
+		return errAnnotationNotFound
+	}
+	return nil
+}
+
+func (s *elasticsearchStore) GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	terms := map[string]interface{}{"field": "tags", "size": limit, "order": map[string]string{"_key": "asc"}}
+	if query.Tag != "" {
+		terms["include"] = ".*" + escapeRegexp(query.Tag) + ".*"
+	}
+	search := map[string]interface{}{
+		"size":  0,
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": []interface{}{term("orgId", query.OrgID)}}},
+		"aggs":  map[string]interface{}{"tags": map[string]interface{}{"terms": terms}},
+	}
+
+	resp, err := s.search(ctx, search)
+	if err != nil || resp == nil {
+		return annotations.FindTagsResult{Tags: []*annotations.TagsDTO{}}, err
+	}
+
+	counts := map[string]int64{}
+	for _, bucket := range resp.Aggregations.Tags.Buckets {
+		counts[bucket.Key] = bucket.DocCount
+	}
+	return tagsResult(counts, limit), nil
+}
+
+// search runs a search on the annotations index, it returns no response when the index does not exist yet.
+func (s *elasticsearchStore) search(ctx context.Context, search map[string]interface{}) (*elasticsearchSearchResponse, error) {
+	body, err := json.Marshal(search)
+	if err != nil {
+		return nil, err
+	}
+
+	status, resp, err := s.request(ctx, http.MethodPost, "/"+url.PathEscape(s.cfg.Index)+"/_search", nil, body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to search annotations in Elasticsearch: %w", responseError(status, resp))
+	}
+
+	var result elasticsearchSearchResponse
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *elasticsearchStore) index(ctx context.Context, item *annotations.Item) error {
+	if err := s.ensureIndex(ctx); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(newElasticsearchDocument(item))
+	if err != nil {
+		return err
+	}
+
+	status, resp, err := s.request(ctx, http.MethodPut, s.documentPath(item.Id), url.Values{"refresh": {"wait_for"}}, body, "application/json")
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		return fmt.Errorf("failed to index annotation in Elasticsearch: %w", responseError(status, resp))
+	}
+	return nil
+}
+
+// ensureIndex creates the annotations index with its mapping when it does not exist.
+func (s *elasticsearchStore) ensureIndex(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indexReady {
+		return nil
+	}
+
+	path := "/" + url.PathEscape(s.cfg.Index)
+	status, resp, err := s.request(ctx, http.MethodHead, path, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		status, resp, err = s.request(ctx, http.MethodPut, path, nil, []byte(elasticsearchMapping), "application/json")
+		if err != nil {
+			return err
+		}
+		// another Grafana instance may have created the index in the meantime
+		if status == http.StatusBadRequest && bytes.Contains(resp, []byte("resource_already_exists_exception")) {
+			status = http.StatusOK
+		}
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to create annotations index in Elasticsearch: %w", responseError(status, resp))
+	}
+
+	s.indexReady = true
+	return nil
+}
+
+func (s *elasticsearchStore) documentPath(id int64) string {
+	return "/" + url.PathEscape(s.cfg.Index) + "/_doc/" + strconv.FormatInt(id, 10)
+}
+
+func (s *elasticsearchStore) request(ctx context.Context, method string, path string, params url.Values, body []byte, contentType string) (int, []byte, error) {
+	u, err := url.Parse(strings.TrimSuffix(s.cfg.URL, "/") + path)
+	if err != nil {
+		return 0, nil, err
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.cfg.Username != "" || s.cfg.Password != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+
+	return sendRequest(s.client, req)
+}
+
+func buildElasticsearchFilters(query *annotations.ItemQuery) []interface{} {
+	filters := []interface{}{term("orgId", query.OrgId)}
+
+	idFilters := []struct {
+		field string
+		value int64
+	}{
+		{"id", query.AnnotationId},
+		{"alertId", query.AlertId},
+		{"dashboardId", query.DashboardId},
+		{"panelId", query.PanelId},
+		{"userId", query.UserId},
+	}
+	for _, filter := range idFilters {
+		if filter.value != 0 {
+			filters = append(filters, term(filter.field, filter.value))
+		}
+	}
+
+	if query.From > 0 && query.To > 0 {
+		filters = append(filters,
+			map[string]interface{}{"range": map[string]interface{}{"epoch": map[string]int64{"lte": query.To}}},
+			map[string]interface{}{"range": map[string]interface{}{"epochEnd": map[string]int64{"gte": query.From}}},
+		)
+	}
+
+	if query.Type == "alert" {
+		filters = append(filters, term("type", "alert"))
+	} else if query.Type == "annotation" {
+		filters = append(filters, map[string]interface{}{"bool": map[string]interface{}{"must_not": term("type", "alert")}})
+	}
+
+	if len(query.Tags) > 0 {
+		var tagFilters []interface{}
+		for _, t := range tag.ParseTagPairs(query.Tags) {
+			if t.Value != "" {
+				tagFilters = append(tagFilters, term("tags", t.Key+":"+t.Value))
+				continue
+			}
+			// tags without value match all values of the key
+			tagFilters = append(tagFilters, map[string]interface{}{"bool": map[string]interface{}{
+				"should": []interface{}{
+					term("tags", t.Key),
+					map[string]interface{}{"prefix": map[string]interface{}{"tags": t.Key + ":"}},
+				},
+				"minimum_should_match": 1,
+			}})
+		}
+
+		if query.MatchAny {
+			filters = append(filters, map[string]interface{}{"bool": map[string]interface{}{"should": tagFilters, "minimum_should_match": 1}})
+		} else {
+			filters = append(filters, tagFilters...)
+		}
+	}
+
+	return filters
+}
+
+func term(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"term": map[string]interface{}{field: value}}
+}
+
+// escapeRegexp escapes the reserved characters of Elasticsearch regular expressions.
+func escapeRegexp(s string) string {
+	var escaped strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`.?+*|{}[]()"\#@&<>~`, r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,194 @@
+package annotationsimpl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeElasticsearch implements the parts of the Elasticsearch API used by the store.
+type fakeElasticsearch struct {
+	mu           sync.Mutex
+	indexCreated int
+	docs         map[string]json.RawMessage
+	searches     []string
+	deletes      []string
+}
+
+func (es *fakeElasticsearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case r.URL.Path == "/annotations" && r.Method == http.MethodHead:
+		if es.indexCreated == 0 {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.URL.Path == "/annotations" && r.Method == http.MethodPut:
+		es.indexCreated++
+	case strings.HasPrefix(r.URL.Path, "/annotations/_doc/") && r.Method == http.MethodPut:
+		es.docs[strings.TrimPrefix(r.URL.Path, "/annotations/_doc/")] = body
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(r.URL.Path, "/annotations/_doc/") && r.Method == http.MethodGet:
+		doc, ok := es.docs[strings.TrimPrefix(r.URL.Path, "/annotations/_doc/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"_source": doc})
+	case r.URL.Path == "/_bulk":
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			var action struct {
+				Index struct {
+					Id string `json:"_id"`
+				} `json:"index"`
+			}
+			_ = json.Unmarshal(scanner.Bytes(), &action)
+			scanner.Scan()
+			es.docs[action.Index.Id] = append(json.RawMessage{}, scanner.Bytes()...)
+		}
+		_, _ = w.Write([]byte(`{"errors": false}`))
+	case r.URL.Path == "/annotations/_search":
+		es.searches = append(es.searches, string(body))
+		hits := make([]interface{}, 0)
+		for _, doc := range es.docs {
+			hits = append(hits, map[string]interface{}{"_source": doc})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"hits":         map[string]interface{}{"hits": hits},
+			"aggregations": map[string]interface{}{"tags": map[string]interface{}{"buckets": []interface{}{map[string]interface{}{"key": "env:prod", "doc_count": 2}}}},
+		})
+	case r.URL.Path == "/annotations/_delete_by_query":
+		es.deletes = append(es.deletes, string(body))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"deleted": len(es.docs)})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestElasticsearchStore(t *testing.T) {
+	setup := func(t *testing.T) (*elasticsearchStore, *fakeElasticsearch) {
+		t.Helper()
+		es := &fakeElasticsearch{docs: map[string]json.RawMessage{}}
+		server := httptest.NewServer(es)
+		t.Cleanup(server.Close)
+
+		return newElasticsearchStore(setting.AnnotationElasticsearchSettings{URL: server.URL, Index: "annotations"}, newExternalIDs(externalIDStoreElasticsearch)), es
+	}
+
+	t.Run("should index annotations and create the index once", func(t *testing.T) {
+		store, es := setup(t)
+
+		item := &annotations.Item{OrgId: 1, Epoch: 1000, Text: "deploy", Tags: []string{"deploy"}}
+		require.NoError(t, store.Add(context.Background(), item))
+		require.NoError(t, store.AddMany(context.Background(), []annotations.Item{
+			{OrgId: 1, AlertId: 1, Epoch: 2000},
+			{OrgId: 1, AlertId: 2, Epoch: 3000},
+		}))
+
+		assert.Equal(t, 1, es.indexCreated)
+		require.Len(t, es.docs, 3)
+
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{OrgId: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		for _, i := range items {
+			if i.Id == item.Id {
+				assert.Equal(t, "deploy", i.Text)
+				assert.Equal(t, int64(1000), i.Time)
+				assert.Equal(t, int64(1000), i.TimeEnd)
+				assert.Equal(t, []string{"deploy"}, i.Tags)
+			}
+		}
+	})
+
+	t.Run("should update annotations", func(t *testing.T) {
+		store, _ := setup(t)
+
+		item := &annotations.Item{OrgId: 1, Epoch: 1000, Text: "deploy", Tags: []string{"deploy"}}
+		require.NoError(t, store.Add(context.Background(), item))
+
+		require.NoError(t, store.Update(context.Background(), &annotations.Item{OrgId: 1, Id: item.Id, Text: "rollback", Tags: []string{"rollback"}}))
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{OrgId: 1, AnnotationId: item.Id})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "rollback", items[0].Text)
+		assert.Equal(t, []string{"rollback"}, items[0].Tags)
+		assert.Equal(t, int64(1000), items[0].Time)
+
+		err = store.Update(context.Background(), &annotations.Item{OrgId: 2, Id: item.Id, Text: "rollback"})
+		require.ErrorIs(t, err, errAnnotationNotFound)
+		err = store.Update(context.Background(), &annotations.Item{OrgId: 1, Id: 42, Text: "rollback"})
+		require.ErrorIs(t, err, errAnnotationNotFound)
+	})
+
+	t.Run("should search annotations matching the query", func(t *testing.T) {
+		store, es := setup(t)
+
+		_, err := store.Get(context.Background(), &annotations.ItemQuery{OrgId: 1, DashboardId: 2, From: 100, To: 200, Type: "alert", Tags: []string{"env:prod", "team"}, MatchAny: true, Limit: 5})
+		require.NoError(t, err)
+		require.Len(t, es.searches, 1)
+		assert.JSONEq(t, `{
+			"size": 5,
+			"sort": [{"epochEnd": "desc"}, {"epoch": "desc"}],
+			"query": {"bool": {"filter": [
+				{"term": {"orgId": 1}},
+				{"term": {"dashboardId": 2}},
+				{"range": {"epoch": {"lte": 200}}},
+				{"range": {"epochEnd": {"gte": 100}}},
+				{"term": {"type": "alert"}},
+				{"bool": {"minimum_should_match": 1, "should": [
+					{"term": {"tags": "env:prod"}},
+					{"bool": {"minimum_should_match": 1, "should": [{"term": {"tags": "team"}}, {"prefix": {"tags": "team:"}}]}}
+				]}}
+			]}}
+		}`, es.searches[0])
+	})
+
+	t.Run("should delete annotations", func(t *testing.T) {
+		store, es := setup(t)
+
+		item := &annotations.Item{OrgId: 1, DashboardId: 2, PanelId: 4, Epoch: 1000}
+		require.NoError(t, store.Add(context.Background(), item))
+
+		require.NoError(t, store.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: item.Id}))
+		require.NoError(t, store.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, DashboardId: 2, PanelId: 4}))
+		require.Len(t, es.deletes, 2)
+		assert.JSONEq(t, fmt.Sprintf(`{"query": {"bool": {"filter": [{"term": {"orgId": 1}}, {"term": {"id": %d}}]}}}`, item.Id), es.deletes[0])
+		assert.JSONEq(t, `{"query": {"bool": {"filter": [{"term": {"orgId": 1}}, {"term": {"dashboardId": 2}}, {"term": {"panelId": 4}}]}}}`, es.deletes[1])
+	})
+
+	t.Run("should not find annotations to delete by id", func(t *testing.T) {
+		store, _ := setup(t)
+
+		err := store.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: 3})
+		require.ErrorIs(t, err, errAnnotationNotFound)
+		require.NoError(t, store.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, DashboardId: 2, PanelId: 4}))
+	})
+
+	t.Run("should aggregate tags", func(t *testing.T) {
+		store, es := setup(t)
+
+		result, err := store.GetTags(context.Background(), &annotations.TagsQuery{OrgID: 1, Tag: "env.", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []*annotations.TagsDTO{{Tag: "env:prod", Count: 2}}, result.Tags)
+		require.Len(t, es.searches, 1)
+		assert.Contains(t, es.searches[0], `"include":".*env\\..*"`)
+	})
+}
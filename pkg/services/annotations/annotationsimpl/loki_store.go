@@ -0,0 +1,334 @@
+package annotationsimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	lokiStreamSelector = `from="grafana-annotations"`
+	// lokiDefaultLookback is the time range of queries without time range, it stays within the default maximum
+	// query length of Loki.
+	lokiDefaultLookback = 30 * 24 * time.Hour
+	// lokiMaxLines is the default maximum number of lines Loki returns for a query.
+	lokiMaxLines = 5000
+)
+
+// lokiStore keeps annotations as log lines in Loki. Loki does not support changing log lines, so annotations cannot
+// be updated or deleted and are removed by the retention of Loki.
+type lokiStore struct {
+	cfg    setting.AnnotationLokiSettings
+	client *http.Client
+	log    log.Logger
+	ids    *externalIDs
+}
+
+func newLokiStore(cfg setting.AnnotationLokiSettings, ids *externalIDs) *lokiStore {
+	return &lokiStore{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}},
+		log:    log.New("annotations.loki"),
+		ids:    ids,
+	}
+}
+
+// lokiEntry is the log line of an annotation, the start time of the annotation is the time of the line.
+type lokiEntry struct {
+	Id          int64            `json:"id"`
+	DashboardId int64            `json:"dashboardId,omitempty"`
+	PanelId     int64            `json:"panelId,omitempty"`
+	AlertId     int64            `json:"alertId,omitempty"`
+	UserId      int64            `json:"userId,omitempty"`
+	Text        string           `json:"text"`
+	Tags        []string         `json:"tags,omitempty"`
+	PrevState   string           `json:"prevState,omitempty"`
+	NewState    string           `json:"newState,omitempty"`
+	TimeEnd     int64            `json:"timeEnd"`
+	Created     int64            `json:"created"`
+	Data        *simplejson.Json `json:"data,omitempty"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiQueryResponse struct {
+	Data struct {
+		Result []lokiStream `json:"result"`
+	} `json:"data"`
+}
+
+func (s *lokiStore) Add(ctx context.Context, item *annotations.Item) error {
+	return s.push(ctx, []*annotations.Item{item})
+}
+
+func (s *lokiStore) AddMany(ctx context.Context, items []annotations.Item) error {
+	toPush := make([]*annotations.Item, 0, len(items))
+	for i := range items {
+		toPush = append(toPush, &items[i])
+	}
+	return s.push(ctx, toPush)
+}
+
+func (s *lokiStore) push(ctx context.Context, items []*annotations.Item) error {
+	streams := map[string]*lokiStream{}
+	for _, item := range items {
+		if err := prepareItem(item, s.ids); err != nil {
+			return err
+		}
+
+		line, err := json.Marshal(lokiEntry{
+			Id:          item.Id,
+			DashboardId: item.DashboardId,
+			PanelId:     item.PanelId,
+			AlertId:     item.AlertId,
+			UserId:      item.UserId,
+			Text:        item.Text,
+			Tags:        item.Tags,
+			PrevState:   item.PrevState,
+			NewState:    item.NewState,
+			TimeEnd:     item.EpochEnd,
+			Created:     item.Created,
+			Data:        item.Data,
+		})
+		if err != nil {
+			return err
+		}
+
+		labels := map[string]string{"from": "grafana-annotations", "org_id": strconv.FormatInt(item.OrgId, 10), "type": annotationTypeOf(item)}
+		key := labels["org_id"] + "/" + labels["type"]
+		if streams[key] == nil {
+			streams[key] = &lokiStream{Stream: labels}
+		}
+		timestamp := strconv.FormatInt(time.UnixMilli(item.Epoch).UnixNano(), 10)
+		streams[key].Values = append(streams[key].Values, [2]string{timestamp, string(line)})
+	}
+
+	body := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, stream := range streams {
+		body.Streams = append(body.Streams, stream)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	status, resp, err := s.request(ctx, http.MethodPost, "/loki/api/v1/push", nil, payload)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent && status != http.StatusOK {
+		return fmt.Errorf("failed to push annotations to Loki: %w", responseError(status, resp))
+	}
+	return nil
+}
+
+// Update fails for annotations in Loki since log lines cannot be changed.
+func (s *lokiStore) Update(ctx context.Context, item *annotations.Item) error {
+	exists, err := s.exists(ctx, item.OrgId, item.Id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errAnnotationNotFound
+	}
+	return errReadOnlyStore
+}
+
+func (s *lokiStore) Get(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	limit := query.Limit
+	if len(query.Tags) > 0 || limit <= 0 {
+		// tags are matched after reading the lines
+		limit = lokiMaxLines
+	}
+
+	params := url.Values{}
+	params.Set("query", buildLogQuery(query))
+	params.Set("limit", strconv.FormatInt(limit, 10))
+	params.Set("direction", "backward")
+	if query.From > 0 && query.To > 0 {
+		params.Set("start", strconv.FormatInt(time.UnixMilli(query.From).UnixNano(), 10))
+		params.Set("end", strconv.FormatInt(time.UnixMilli(query.To).UnixNano(), 10))
+	} else {
+		now := timeNow()
+		params.Set("start", strconv.FormatInt(now.Add(-lokiDefaultLookback).UnixNano(), 10))
+		params.Set("end", strconv.FormatInt(now.UnixNano(), 10))
+	}
+
+	status, body, err := s.request(ctx, http.MethodGet, "/loki/api/v1/query_range", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to query annotations from Loki: %w", responseError(status, body))
+	}
+
+	var resp lokiQueryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	items := make([]*annotations.ItemDTO, 0)
+	for _, stream := range resp.Data.Result {
+		for _, value := range stream.Values {
+			item, err := parseLokiEntry(value)
+			if err != nil {
+				s.log.Warn("Skipping invalid annotation", "line", value[1], "error", err)
+				continue
+			}
+			if matchesQuery(item, query) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	sortItems(items)
+	if query.Limit > 0 && int64(len(items)) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, nil
+}
+
+// Delete fails for annotations in Loki since log lines cannot be removed. Annotations of dashboard panels are only
+// deleted from the other stores.
+func (s *lokiStore) Delete(ctx context.Context, params *annotations.DeleteParams) error {
+	if params.Id == 0 {
+		return nil
+	}
+
+	exists, err := s.exists(ctx, params.OrgId, params.Id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errReadOnlyStore
+	}
+	return errAnnotationNotFound
+}
+
+func (s *lokiStore) GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	items, err := s.Get(ctx, &annotations.ItemQuery{OrgId: query.OrgID, Limit: lokiMaxLines})
+	if err != nil {
+		return annotations.FindTagsResult{Tags: []*annotations.TagsDTO{}}, err
+	}
+
+	counts := map[string]int64{}
+	for _, item := range items {
+		for _, tag := range item.Tags {
+			if strings.Contains(tag, query.Tag) {
+				counts[tag]++
+			}
+		}
+	}
+	return tagsResult(counts, query.Limit), nil
+}
+
+func (s *lokiStore) exists(ctx context.Context, orgID int64, id int64) (bool, error) {
+	items, err := s.Get(ctx, &annotations.ItemQuery{OrgId: orgID, AnnotationId: id, Limit: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(items) > 0, nil
+}
+
+func (s *lokiStore) request(ctx context.Context, method string, path string, params url.Values, body []byte) (int, []byte, error) {
+	u, err := url.Parse(strings.TrimSuffix(s.cfg.URL, "/") + path)
+	if err != nil {
+		return 0, nil, err
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.cfg.TenantID)
+	}
+	if s.cfg.BasicAuthUser != "" || s.cfg.BasicAuthPassword != "" {
+		req.SetBasicAuth(s.cfg.BasicAuthUser, s.cfg.BasicAuthPassword)
+	}
+
+	return sendRequest(s.client, req)
+}
+
+// buildLogQuery returns the LogQL query of the annotations matching the query, tags and time range are matched
+// after reading the lines.
+func buildLogQuery(query *annotations.ItemQuery) string {
+	var logQuery strings.Builder
+	fmt.Fprintf(&logQuery, `{%s, org_id="%d"`, lokiStreamSelector, query.OrgId)
+	if query.Type == "alert" {
+		logQuery.WriteString(`, type="alert"`)
+	} else if query.Type == "annotation" {
+		logQuery.WriteString(`, type!="alert"`)
+	}
+	logQuery.WriteString("} | json")
+
+	filters := []struct {
+		label string
+		value int64
+	}{
+		{"id", query.AnnotationId},
+		{"alertId", query.AlertId},
+		{"dashboardId", query.DashboardId},
+		{"panelId", query.PanelId},
+		{"userId", query.UserId},
+	}
+	for _, filter := range filters {
+		if filter.value != 0 {
+			fmt.Fprintf(&logQuery, " | %s == %d", filter.label, filter.value)
+		}
+	}
+	return logQuery.String()
+}
+
+func parseLokiEntry(value [2]string) (*annotations.ItemDTO, error) {
+	timestamp, err := strconv.ParseInt(value[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry lokiEntry
+	if err := json.Unmarshal([]byte(value[1]), &entry); err != nil {
+		return nil, err
+	}
+
+	item := &annotations.ItemDTO{
+		Id:          entry.Id,
+		AlertId:     entry.AlertId,
+		DashboardId: entry.DashboardId,
+		PanelId:     entry.PanelId,
+		UserId:      entry.UserId,
+		NewState:    entry.NewState,
+		PrevState:   entry.PrevState,
+		Created:     entry.Created,
+		Updated:     entry.Created,
+		Time:        time.Unix(0, timestamp).UnixMilli(),
+		TimeEnd:     entry.TimeEnd,
+		Text:        entry.Text,
+		Tags:        entry.Tags,
+		Data:        entry.Data,
+	}
+	if item.TimeEnd == 0 {
+		item.TimeEnd = item.Time
+	}
+	return item, nil
+}
@@ -0,0 +1,129 @@
+package annotationsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLokiStore(t *testing.T) {
+	type request struct {
+		path   string
+		query  string
+		tenant string
+		body   []byte
+	}
+
+	setup := func(t *testing.T, response string) (*lokiStore, *[]request) {
+		t.Helper()
+		var requests []request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			user, password, _ := r.BasicAuth()
+			assert.Equal(t, "user", user)
+			assert.Equal(t, "secret", password)
+
+			requests = append(requests, request{path: r.URL.Path, query: r.URL.Query().Get("query"), tenant: r.Header.Get("X-Scope-OrgID"), body: body})
+			if r.URL.Path == "/loki/api/v1/push" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = w.Write([]byte(response))
+		}))
+		t.Cleanup(server.Close)
+
+		store := newLokiStore(setting.AnnotationLokiSettings{URL: server.URL, TenantID: "tenant", BasicAuthUser: "user", BasicAuthPassword: "secret"}, newExternalIDs(externalIDStoreLoki))
+		return store, &requests
+	}
+
+	t.Run("should push annotations as log lines", func(t *testing.T) {
+		store, requests := setup(t, "")
+
+		item := &annotations.Item{OrgId: 1, AlertId: 3, Epoch: 1000, EpochEnd: 2000, Text: "firing", Tags: []string{"severity:high"}, NewState: "Alerting"}
+		require.NoError(t, store.Add(context.Background(), item))
+		assert.NotZero(t, item.Id)
+
+		require.Len(t, *requests, 1)
+		assert.Equal(t, "tenant", (*requests)[0].tenant)
+
+		var body struct {
+			Streams []lokiStream `json:"streams"`
+		}
+		require.NoError(t, json.Unmarshal((*requests)[0].body, &body))
+		require.Len(t, body.Streams, 1)
+		assert.Equal(t, map[string]string{"from": "grafana-annotations", "org_id": "1", "type": "alert"}, body.Streams[0].Stream)
+		require.Len(t, body.Streams[0].Values, 1)
+		assert.Equal(t, "1000000000", body.Streams[0].Values[0][0])
+
+		var entry lokiEntry
+		require.NoError(t, json.Unmarshal([]byte(body.Streams[0].Values[0][1]), &entry))
+		assert.Equal(t, item.Id, entry.Id)
+		assert.Equal(t, int64(3), entry.AlertId)
+		assert.Equal(t, int64(2000), entry.TimeEnd)
+		assert.Equal(t, "firing", entry.Text)
+		assert.Equal(t, []string{"severity:high"}, entry.Tags)
+	})
+
+	t.Run("should find annotations matching the query", func(t *testing.T) {
+		store, requests := setup(t, `{"status": "success", "data": {"resultType": "streams", "result": [
+			{"stream": {"type": "api"}, "values": [
+				["2000000000", "{\"id\": 2, \"text\": \"deploy\", \"tags\": [\"deploy\", \"env:prod\"], \"timeEnd\": 3000}"],
+				["1000000000", "{\"id\": 1, \"text\": \"restart\", \"tags\": [\"restart\"]}"],
+				["500000000", "not json"]
+			]}
+		]}}`)
+
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{OrgId: 1, Type: "annotation", UserId: 5, Tags: []string{"env"}, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, int64(2), items[0].Id)
+		assert.Equal(t, int64(2000), items[0].Time)
+		assert.Equal(t, int64(3000), items[0].TimeEnd)
+		assert.Equal(t, "deploy", items[0].Text)
+
+		require.Len(t, *requests, 1)
+		assert.Equal(t, `{from="grafana-annotations", org_id="1", type!="alert"} | json | userId == 5`, (*requests)[0].query)
+
+		items, err = store.Get(context.Background(), &annotations.ItemQuery{OrgId: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, int64(1000), items[1].TimeEnd, "the end of annotations without end is their start")
+	})
+
+	t.Run("should not update or delete annotations", func(t *testing.T) {
+		store, requests := setup(t, `{"data": {"result": [{"stream": {}, "values": [["1000000000", "{\"id\": 1}"]]}]}}`)
+
+		err := store.Update(context.Background(), &annotations.Item{OrgId: 1, Id: 1, Text: "updated"})
+		require.ErrorIs(t, err, errReadOnlyStore)
+		err = store.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: 1})
+		require.ErrorIs(t, err, errReadOnlyStore)
+		assert.Equal(t, `{from="grafana-annotations", org_id="1"} | json | id == 1`, (*requests)[0].query)
+
+		store, _ = setup(t, `{"data": {"result": []}}`)
+		err = store.Update(context.Background(), &annotations.Item{OrgId: 1, Id: 1, Text: "updated"})
+		require.ErrorIs(t, err, errAnnotationNotFound)
+		err = store.Delete(context.Background(), &annotations.DeleteParams{OrgId: 1, Id: 1})
+		require.ErrorIs(t, err, errAnnotationNotFound)
+	})
+
+	t.Run("should count the tags of annotations", func(t *testing.T) {
+		store, _ := setup(t, `{"data": {"result": [{"stream": {}, "values": [
+			["2000000000", "{\"id\": 2, \"tags\": [\"deploy\", \"env:prod\"]}"],
+			["1000000000", "{\"id\": 1, \"tags\": [\"env:dev\", \"env:prod\"]}"]
+		]}]}}`)
+
+		result, err := store.GetTags(context.Background(), &annotations.TagsQuery{OrgID: 1, Tag: "env"})
+		require.NoError(t, err)
+		assert.Equal(t, []*annotations.TagsDTO{{Tag: "env:dev", Count: 1}, {Tag: "env:prod", Count: 2}}, result.Tags)
+	})
+}
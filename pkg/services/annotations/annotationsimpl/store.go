@@ -2,18 +2,212 @@ package annotationsimpl
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/tag"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 type store interface {
+	typeStore
+	CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error)
+	CleanOrphanedAnnotationTags(ctx context.Context) (int64, error)
+	FilterReadable(ctx context.Context, user *user.SignedInUser, items []*annotations.ItemDTO) ([]*annotations.ItemDTO, error)
+}
+
+// typeStore keeps the annotations of selected types outside of the Grafana database.
+type typeStore interface {
 	Add(ctx context.Context, items *annotations.Item) error
 	AddMany(ctx context.Context, items []annotations.Item) error
 	Update(ctx context.Context, item *annotations.Item) error
 	Get(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error)
 	Delete(ctx context.Context, params *annotations.DeleteParams) error
 	GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error)
-	CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error)
-	CleanOrphanedAnnotationTags(ctx context.Context) (int64, error)
+}
+
+var (
+	errAnnotationNotFound = errors.New("annotation not found")
+	// errReadOnlyStore is returned when updating or deleting annotations stored in Loki, which cannot change log lines.
+	errReadOnlyStore = errors.New("annotations stored in Loki cannot be updated or deleted")
+)
+
+// The ids of annotations kept outside of the Grafana database are in a range database ids never reach, and stay
+// precise in JavaScript numbers:
+//
+//	bit 52:       always set, marks the id as external
+//	bits 50-51:   the store keeping the annotation
+//	bits 41-49:   an instance id chosen at random on startup, so Grafana instances sharing a store do not collide
+//	bits 0-40:    a counter based on the milliseconds since externalIDEpoch
+const (
+	externalIDFlag          = int64(1) << 52
+	externalIDStoreShift    = 50
+	externalIDStoreMask     = int64(3)
+	externalIDInstanceShift = 41
+	externalIDInstanceBits  = 9
+	externalIDCounterMask   = int64(1)<<externalIDInstanceShift - 1
+)
+
+// externalIDEpoch is the start of the counter of external ids, which lasts about 69 years.
+var externalIDEpoch = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// The stores an external id can belong to.
+const (
+	externalIDStoreLoki          int64 = 1
+	externalIDStoreElasticsearch int64 = 2
+)
+
+// externalIDs generates the ids of the annotations of one store kept outside of the Grafana database.
+type externalIDs struct {
+	store    int64
+	instance int64
+
+	mu   sync.Mutex
+	last int64
+}
+
+func newExternalIDs(store int64) *externalIDs {
+	instance, err := rand.Int(rand.Reader, big.NewInt(1<<externalIDInstanceBits))
+	if err != nil {
+		// ids are still unique within this instance
+		return &externalIDs{store: store}
+	}
+	return &externalIDs{store: store, instance: instance.Int64()}
+}
+
+func (g *externalIDs) next() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	counter := timeNow().Sub(externalIDEpoch).Milliseconds()
+	if counter <= g.last {
+		counter = g.last + 1
+	}
+	g.last = counter
+	return externalIDFlag | g.store<<externalIDStoreShift | g.instance<<externalIDInstanceShift | counter&externalIDCounterMask
+}
+
+// externalIDStore returns the store an id generated by externalIDs belongs to, or false for ids of the Grafana
+// database and ids of annotations stored before ids were tagged with their store.
+func externalIDStore(id int64) (int64, bool) {
+	if id < externalIDFlag || id >= externalIDFlag<<1 {
+		return 0, false
+	}
+	return id >> externalIDStoreShift & externalIDStoreMask, true
+}
+
+// prepareItem sets the fields the database sets for annotations kept outside of the Grafana database.
+func prepareItem(item *annotations.Item, ids *externalIDs) error {
+	item.Tags = tag.JoinTagPairs(tag.ParseTagPairs(item.Tags))
+	item.Created = timeNow().UnixNano() / int64(time.Millisecond)
+	item.Updated = item.Created
+	if item.Epoch == 0 {
+		item.Epoch = item.Created
+	}
+	if err := validateTimeRange(item); err != nil {
+		return err
+	}
+	if item.Id == 0 {
+		item.Id = ids.next()
+	}
+	return nil
+}
+
+// matchesQuery applies the filters of the query which are not applied by the store itself.
+func matchesQuery(item *annotations.ItemDTO, query *annotations.ItemQuery) bool {
+	if query.From > 0 && query.To > 0 && (item.Time > query.To || item.TimeEnd < query.From) {
+		return false
+	}
+	if len(query.Tags) == 0 {
+		return true
+	}
+
+	itemTags := make(map[tag.Tag]bool, len(item.Tags))
+	for _, t := range tag.ParseTagPairs(item.Tags) {
+		itemTags[*t] = true
+		itemTags[tag.Tag{Key: t.Key}] = true
+	}
+
+	queryTags := tag.ParseTagPairs(query.Tags)
+	matches := 0
+	for _, t := range queryTags {
+		if itemTags[*t] {
+			matches++
+		}
+	}
+	if query.MatchAny {
+		return matches > 0
+	}
+	return matches == len(queryTags)
+}
+
+// sortItems orders annotations like the database does, most recent first.
+func sortItems(items []*annotations.ItemDTO) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].TimeEnd != items[j].TimeEnd {
+			return items[i].TimeEnd > items[j].TimeEnd
+		}
+		return items[i].Time > items[j].Time
+	})
+}
+
+// tagsResult returns the tags with their counts, ordered like the database orders them.
+func tagsResult(counts map[string]int64, limit int64) annotations.FindTagsResult {
+	tags := make([]*annotations.TagsDTO, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, &annotations.TagsDTO{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+
+	if limit == 0 {
+		limit = 100
+	}
+	if int64(len(tags)) > limit {
+		tags = tags[:limit]
+	}
+	return annotations.FindTagsResult{Tags: tags}
+}
+
+// sendRequest sends a request to the HTTP API of a store kept outside of the Grafana database and returns the status
+// code and body of the response.
+func sendRequest(client *http.Client, req *http.Request) (int, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func responseError(status int, body []byte) error {
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return fmt.Errorf("unexpected status %d: %s", status, body)
+}
+
+// annotationTypeOf returns the type of annotations kept outside of the Grafana database.
+func annotationTypeOf(item *annotations.Item) string {
+	if item.AlertId != 0 {
+		return "alert"
+	}
+	return "api"
 }
@@ -152,7 +152,7 @@ func (r *xormRepositoryImpl) Update(ctx context.Context, item *annotations.Item)
 			return err
 		}
 		if !isExist {
-			return errors.New("annotation not found")
+			return errAnnotationNotFound
 		}
 
 		existing.Updated = timeNow().UnixNano() / int64(time.Millisecond)
@@ -324,16 +324,9 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query *annotations.ItemQue
 }
 
 func getAccessControlFilter(user *user.SignedInUser) (string, []interface{}, error) {
-	if user == nil || user.Permissions[user.OrgID] == nil {
-		return "", nil, errors.New("missing permissions")
-	}
-	scopes, has := user.Permissions[user.OrgID][ac.ActionAnnotationsRead]
-	if !has {
-		return "", nil, errors.New("missing permissions")
-	}
-	types, hasWildcardScope := ac.ParseScopes(ac.ScopeAnnotationsProvider.GetResourceScopeType(""), scopes)
-	if hasWildcardScope {
-		types = map[interface{}]struct{}{annotations.Dashboard.String(): {}, annotations.Organization.String(): {}}
+	types, err := readableAnnotationTypes(user)
+	if err != nil {
+		return "", nil, err
 	}
 
 	var filters []string
@@ -354,6 +347,71 @@ func getAccessControlFilter(user *user.SignedInUser) (string, []interface{}, err
 	return strings.Join(filters, " OR "), params, nil
 }
 
+func readableAnnotationTypes(user *user.SignedInUser) (map[interface{}]struct{}, error) {
+	if user == nil || user.Permissions[user.OrgID] == nil {
+		return nil, errors.New("missing permissions")
+	}
+	scopes, has := user.Permissions[user.OrgID][ac.ActionAnnotationsRead]
+	if !has {
+		return nil, errors.New("missing permissions")
+	}
+	types, hasWildcardScope := ac.ParseScopes(ac.ScopeAnnotationsProvider.GetResourceScopeType(""), scopes)
+	if hasWildcardScope {
+		types = map[interface{}]struct{}{annotations.Dashboard.String(): {}, annotations.Organization.String(): {}}
+	}
+	return types, nil
+}
+
+// FilterReadable returns the annotations read from other stores which the user can read, following the same rules
+// as the access control filter of Get.
+func (r *xormRepositoryImpl) FilterReadable(ctx context.Context, user *user.SignedInUser, items []*annotations.ItemDTO) ([]*annotations.ItemDTO, error) {
+	if ac.IsDisabled(r.cfg) || len(items) == 0 {
+		return items, nil
+	}
+
+	types, err := readableAnnotationTypes(user)
+	if err != nil {
+		return nil, err
+	}
+	_, canReadOrganization := types[annotations.Organization.String()]
+	_, canReadDashboards := types[annotations.Dashboard.String()]
+
+	readableDashboards := map[int64]bool{}
+	if canReadDashboards {
+		var dashboardIDs []interface{}
+		for _, item := range items {
+			if item.DashboardId != 0 {
+				dashboardIDs = append(dashboardIDs, item.DashboardId)
+			}
+		}
+
+		if len(dashboardIDs) > 0 {
+			dashboardFilter, params := permissions.NewAccessControlDashboardPermissionFilter(user, models.PERMISSION_VIEW, searchstore.TypeDashboard).Where()
+			sql := fmt.Sprintf("SELECT id FROM dashboard WHERE (%s) AND id IN (?%s)", dashboardFilter, strings.Repeat(",?", len(dashboardIDs)-1))
+			params = append(params, dashboardIDs...)
+
+			var ids []int64
+			err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
+				return sess.SQL(sql, params...).Find(&ids)
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				readableDashboards[id] = true
+			}
+		}
+	}
+
+	readable := make([]*annotations.ItemDTO, 0, len(items))
+	for _, item := range items {
+		if (item.DashboardId == 0 && canReadOrganization) || readableDashboards[item.DashboardId] {
+			readable = append(readable, item)
+		}
+	}
+	return readable, nil
+}
+
 func (r *xormRepositoryImpl) Delete(ctx context.Context, params *annotations.DeleteParams) error {
 	return r.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var (
@@ -370,9 +428,17 @@ func (r *xormRepositoryImpl) Delete(ctx context.Context, params *annotations.Del
 				return err
 			}
 
-			if _, err := sess.Exec(sql, params.Id, params.OrgId); err != nil {
+			res, err := sess.Exec(sql, params.Id, params.OrgId)
+			if err != nil {
 				return err
 			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				return errAnnotationNotFound
+			}
 		} else {
 			annoTagSQL = "DELETE FROM annotation_tag WHERE annotation_id IN (SELECT id FROM annotation WHERE dashboard_id = ? AND panel_id = ? AND org_id = ?)"
 			sql = "DELETE FROM annotation WHERE dashboard_id = ? AND panel_id = ? AND org_id = ?"
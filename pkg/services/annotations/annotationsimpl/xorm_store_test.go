@@ -411,6 +411,9 @@ func TestIntegrationAnnotations(t *testing.T) {
 			items, err = repo.Get(context.Background(), query)
 			require.NoError(t, err)
 			assert.Empty(t, items)
+
+			err = repo.Delete(context.Background(), &annotations.DeleteParams{Id: annotationId, OrgId: 1})
+			require.ErrorIs(t, err, errAnnotationNotFound)
 		})
 
 		t.Run("Can delete annotation using dashboard id and panel id", func(t *testing.T) {
@@ -627,6 +630,18 @@ func TestIntegrationAnnotationListingWithRBAC(t *testing.T) {
 			for _, r := range results {
 				assert.Contains(t, tc.expectedAnnotationIds, r.Id)
 			}
+
+			// annotations read from other stores are filtered the same way
+			readable, err := repo.FilterReadable(context.Background(), user, []*annotations.ItemDTO{
+				{Id: dash1Annotation.Id, DashboardId: dash1Annotation.DashboardId},
+				{Id: dash2Annotation.Id, DashboardId: dash2Annotation.DashboardId},
+				{Id: organizationAnnotation.Id},
+			})
+			require.NoError(t, err)
+			assert.Len(t, readable, len(tc.expectedAnnotationIds))
+			for _, r := range readable {
+				assert.Contains(t, tc.expectedAnnotationIds, r.Id)
+			}
 		})
 	}
 }
@@ -18,11 +18,14 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
+	"github.com/grafana/grafana/pkg/services/store/entity/sqlstash"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -30,7 +33,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	dashboardService dashboards.DashboardService) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -44,6 +48,7 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		dashboardService:          dashboardService,
 	}
 	return s
 }
@@ -61,6 +66,7 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	dashboardService          dashboards.DashboardService
 }
 
 type cleanUpJob struct {
@@ -103,6 +109,8 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"purge trashed dashboards", srv.purgeTrashedDashboards},
+		{"delete old entity changes", srv.deleteOldEntityChanges},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -280,3 +288,31 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) purgeTrashedDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.DashboardTrashRetention == 0 {
+		return
+	}
+	cmd := dashboards.PurgeTrashedDashboardsCommand{
+		OlderThan: time.Now().Add(-srv.Cfg.DashboardTrashRetention),
+	}
+	if err := srv.dashboardService.PurgeTrashedDashboards(ctx, &cmd); err != nil {
+		logger.Error("Problem purging trashed dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Purged trashed dashboards", "rows affected", cmd.DeletedRows)
+	}
+}
+
+func (srv *CleanUpService) deleteOldEntityChanges(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if !srv.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagEntityStore) {
+		return
+	}
+	affected, err := sqlstash.DeleteOldEvents(ctx, srv.store.GetSqlxSession(), time.Now().Add(-sqlstash.EventRetention))
+	if err != nil {
+		logger.Error("Problem deleting old entity changes", "error", err.Error())
+	} else {
+		logger.Debug("Deleted old entity changes", "rows affected", affected)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -48,7 +49,7 @@ func (s CorrelationsService) createCorrelation(ctx context.Context, cmd CreateCo
 			return err
 		}
 
-		return nil
+		return store.InsertEntityEvents(session, s.features, cmd.OrgId, store.EntityTypeCorrelation, store.EntityEventTypeCreate, correlation.UID)
 	})
 
 	if err != nil {
@@ -76,7 +77,10 @@ func (s CorrelationsService) deleteCorrelation(ctx context.Context, cmd DeleteCo
 		if deletedCount == 0 {
 			return ErrCorrelationNotFound
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		return store.InsertEntityEvents(session, s.features, cmd.OrgId, store.EntityTypeCorrelation, store.EntityEventTypeDelete, cmd.UID)
 	})
 }
 
@@ -132,7 +136,10 @@ func (s CorrelationsService) updateCorrelation(ctx context.Context, cmd UpdateCo
 		if updateCount == 0 {
 			return ErrCorrelationNotFound
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		return store.InsertEntityEvents(session, s.features, cmd.OrgId, store.EntityTypeCorrelation, store.EntityEventTypeUpdate, correlation.UID)
 	})
 
 	if err != nil {
@@ -208,6 +215,9 @@ func (s CorrelationsService) getCorrelations(ctx context.Context, cmd GetCorrela
 
 func (s CorrelationsService) deleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
 	return s.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		if err := s.insertDeleteEntityEvents(session, cmd.OrgId, &Correlation{SourceUID: cmd.SourceUID}); err != nil {
+			return err
+		}
 		_, err := session.Delete(&Correlation{SourceUID: cmd.SourceUID})
 		return err
 	})
@@ -215,7 +225,26 @@ func (s CorrelationsService) deleteCorrelationsBySourceUID(ctx context.Context,
 
 func (s CorrelationsService) deleteCorrelationsByTargetUID(ctx context.Context, cmd DeleteCorrelationsByTargetUIDCommand) error {
 	return s.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		if err := s.insertDeleteEntityEvents(session, cmd.OrgId, &Correlation{TargetUID: &cmd.TargetUID}); err != nil {
+			return err
+		}
 		_, err := session.Delete(&Correlation{TargetUID: &cmd.TargetUID})
 		return err
 	})
 }
+
+// insertDeleteEntityEvents records the deletion of all correlations matching the condition
+func (s CorrelationsService) insertDeleteEntityEvents(session *db.Session, orgID int64, cond *Correlation) error {
+	if !store.EntityEventsEnabled(s.features) || orgID == 0 {
+		return nil
+	}
+	var deleted []Correlation
+	if err := session.Find(&deleted, cond); err != nil {
+		return err
+	}
+	uids := make([]string, 0, len(deleted))
+	for _, c := range deleted {
+		uids = append(uids, c.UID)
+	}
+	return store.InsertEntityEvents(session, s.features, orgID, store.EntityTypeCorrelation, store.EntityEventTypeDelete, uids...)
+}
@@ -0,0 +1,181 @@
+package dashboardbulk
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *DashboardBulkService) registerAPIEndpoints() {
+	authorize := ac.Middleware(s.ac)
+
+	s.routeRegister.Group("/api/dashboards/bulk", func(bulkRoute routing.RouteRegister) {
+		bulkRoute.Post("/move", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(s.moveHandler))
+		bulkRoute.Post("/tags", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(s.tagHandler))
+		bulkRoute.Post("/delete", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsDelete)), routing.Wrap(s.deleteHandler))
+		bulkRoute.Post("/permissions", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(s.permissionsHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route POST /dashboards/bulk/move dashboards bulkMoveDashboards
+//
+// Move dashboards to a folder.
+//
+// Moves all selected dashboards to a folder in a single transaction. Either all dashboards are moved or none,
+// the result reports the outcome for each dashboard.
+//
+// Responses:
+// 200: bulkDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 422: bulkDashboardsResponse
+// 500: internalServerError
+func (s *DashboardBulkService) moveHandler(c *models.ReqContext) response.Response {
+	cmd := MoveDashboardsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.User = c.SignedInUser
+
+	result, err := s.MoveDashboards(c.Req.Context(), &cmd)
+	return toResponse(result, err, "Failed to move dashboards")
+}
+
+// swagger:route POST /dashboards/bulk/tags dashboards bulkTagDashboards
+//
+// Add and remove tags of dashboards.
+//
+// Responses:
+// 200: bulkDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 422: bulkDashboardsResponse
+// 500: internalServerError
+func (s *DashboardBulkService) tagHandler(c *models.ReqContext) response.Response {
+	cmd := TagDashboardsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.User = c.SignedInUser
+
+	result, err := s.TagDashboards(c.Req.Context(), &cmd)
+	return toResponse(result, err, "Failed to change dashboard tags")
+}
+
+// swagger:route POST /dashboards/bulk/delete dashboards bulkDeleteDashboards
+//
+// Delete dashboards.
+//
+// Deleted dashboards can be restored from trash.
+//
+// Responses:
+// 200: bulkDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 422: bulkDashboardsResponse
+// 500: internalServerError
+func (s *DashboardBulkService) deleteHandler(c *models.ReqContext) response.Response {
+	cmd := DeleteDashboardsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.User = c.SignedInUser
+
+	result, err := s.DeleteDashboards(c.Req.Context(), &cmd)
+	return toResponse(result, err, "Failed to delete dashboards")
+}
+
+// swagger:route POST /dashboards/bulk/permissions dashboards bulkSetDashboardPermissions
+//
+// Set permissions of dashboards.
+//
+// Sets the permissions of the given users, teams and roles, other permissions of the dashboards are kept.
+//
+// Responses:
+// 200: bulkDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 422: bulkDashboardsResponse
+// 500: internalServerError
+func (s *DashboardBulkService) permissionsHandler(c *models.ReqContext) response.Response {
+	cmd := SetDashboardPermissionsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.User = c.SignedInUser
+
+	result, err := s.SetDashboardPermissions(c.Req.Context(), &cmd)
+	return toResponse(result, err, "Failed to set dashboard permissions")
+}
+
+func toResponse(result *Result, err error, message string) response.Response {
+	if err != nil {
+		var dashboardErr dashboards.DashboardErr
+		switch {
+		case errors.Is(err, ErrEmptySelection), errors.Is(err, ErrEmptyQuery), errors.Is(err, ErrBatchTooLarge),
+			errors.Is(err, ErrNoTagChanges), errors.Is(err, ErrNoPermissions), errors.Is(err, models.ErrDashboardACLInfoMissing):
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, dashboards.ErrFolderNotFound):
+			return response.Error(http.StatusNotFound, err.Error(), err)
+		case errors.Is(err, dashboards.ErrFolderAccessDenied):
+			return response.Error(http.StatusForbidden, err.Error(), err)
+		case errors.As(err, &dashboardErr):
+			return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, message, err)
+	}
+
+	if !result.Applied {
+		return response.JSON(http.StatusUnprocessableEntity, result)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters bulkMoveDashboards
+type BulkMoveDashboardsParams struct {
+	// in:body
+	// required:true
+	Body MoveDashboardsCommand
+}
+
+// swagger:parameters bulkTagDashboards
+type BulkTagDashboardsParams struct {
+	// in:body
+	// required:true
+	Body TagDashboardsCommand
+}
+
+// swagger:parameters bulkDeleteDashboards
+type BulkDeleteDashboardsParams struct {
+	// in:body
+	// required:true
+	Body DeleteDashboardsCommand
+}
+
+// swagger:parameters bulkSetDashboardPermissions
+type BulkSetDashboardPermissionsParams struct {
+	// in:body
+	// required:true
+	Body SetDashboardPermissionsCommand
+}
+
+// swagger:response bulkDashboardsResponse
+type BulkDashboardsResponse struct {
+	// in: body
+	Body Result `json:"body"`
+}
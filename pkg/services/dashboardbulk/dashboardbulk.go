@@ -0,0 +1,414 @@
+package dashboardbulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Service changes many dashboards at once. Each operation runs in a single transaction and
+// reports the outcome for every selected dashboard.
+type Service interface {
+	MoveDashboards(ctx context.Context, cmd *MoveDashboardsCommand) (*Result, error)
+	TagDashboards(ctx context.Context, cmd *TagDashboardsCommand) (*Result, error)
+	DeleteDashboards(ctx context.Context, cmd *DeleteDashboardsCommand) (*Result, error)
+	SetDashboardPermissions(ctx context.Context, cmd *SetDashboardPermissionsCommand) (*Result, error)
+}
+
+func ProvideService(sqlStore db.DB, routeRegister routing.RouteRegister, dashboardService dashboards.DashboardService,
+	folderService folder.Service, libraryElementService libraryelements.Service,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService, ac accesscontrol.AccessControl,
+) *DashboardBulkService {
+	s := &DashboardBulkService{
+		sqlStore:              sqlStore,
+		routeRegister:         routeRegister,
+		log:                   log.New("dashboard-bulk"),
+		dashboardService:      dashboardService,
+		folderService:         folderService,
+		libraryElementService: libraryElementService,
+		dashboardPermissions:  dashboardPermissionsService,
+		ac:                    ac,
+	}
+
+	s.registerAPIEndpoints()
+
+	return s
+}
+
+type DashboardBulkService struct {
+	sqlStore              db.DB
+	routeRegister         routing.RouteRegister
+	log                   log.Logger
+	dashboardService      dashboards.DashboardService
+	folderService         folder.Service
+	libraryElementService libraryelements.Service
+	dashboardPermissions  accesscontrol.DashboardPermissionsService
+	ac                    accesscontrol.AccessControl
+}
+
+var _ Service = (*DashboardBulkService)(nil)
+
+// operation is applied to every dashboard of a batch.
+type operation struct {
+	// canApply checks that the user is allowed to apply the operation to a dashboard.
+	canApply func(g guardian.DashboardGuardian) (bool, error)
+	apply    func(ctx context.Context, dash *models.Dashboard) error
+}
+
+func (s *DashboardBulkService) MoveDashboards(ctx context.Context, cmd *MoveDashboardsCommand) (*Result, error) {
+	var folderID int64
+	if cmd.FolderUID != "" {
+		f, err := s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &cmd.FolderUID, OrgID: cmd.OrgID, SignedInUser: cmd.User})
+		if err != nil {
+			return nil, err
+		}
+		folderID = f.ID
+	}
+
+	return s.run(ctx, cmd.OrgID, cmd.User, cmd.Selector, models.PERMISSION_EDIT, operation{
+		canApply: guardian.DashboardGuardian.CanSave,
+		apply: func(ctx context.Context, dash *models.Dashboard) error {
+			if dash.FolderId == folderID {
+				return nil
+			}
+			// Permissions on the target folder are checked when saving.
+			dash.FolderId = folderID
+			_, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+				OrgId:     cmd.OrgID,
+				User:      cmd.User,
+				Message:   "Moved by bulk operation",
+				Dashboard: dash,
+			}, false)
+			return err
+		},
+	})
+}
+
+func (s *DashboardBulkService) TagDashboards(ctx context.Context, cmd *TagDashboardsCommand) (*Result, error) {
+	if len(cmd.AddTags) == 0 && len(cmd.RemoveTags) == 0 {
+		return nil, ErrNoTagChanges
+	}
+
+	return s.run(ctx, cmd.OrgID, cmd.User, cmd.Selector, models.PERMISSION_EDIT, operation{
+		canApply: guardian.DashboardGuardian.CanSave,
+		apply: func(ctx context.Context, dash *models.Dashboard) error {
+			tags, changed := updateTags(dash.GetTags(), cmd.AddTags, cmd.RemoveTags)
+			if !changed {
+				return nil
+			}
+			// Tags are read back from the JSON model when saving, which only supports []interface{} arrays.
+			jsonTags := make([]interface{}, 0, len(tags))
+			for _, tag := range tags {
+				jsonTags = append(jsonTags, tag)
+			}
+			dash.Data.Set("tags", jsonTags)
+			_, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+				OrgId:     cmd.OrgID,
+				User:      cmd.User,
+				Message:   "Tags changed by bulk operation",
+				Dashboard: dash,
+			}, false)
+			return err
+		},
+	})
+}
+
+// updateTags adds and removes tags, keeping the order of existing tags.
+func updateTags(tags []string, add []string, remove []string) ([]string, bool) {
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+
+	result := make([]string, 0, len(tags)+len(add))
+	seen := make(map[string]bool, len(tags)+len(add))
+	for _, list := range [][]string{tags, add} {
+		for _, tag := range list {
+			if removed[tag] || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	if len(result) != len(tags) {
+		return result, true
+	}
+	for i := range tags {
+		if tags[i] != result[i] {
+			return result, true
+		}
+	}
+	return result, false
+}
+
+func (s *DashboardBulkService) DeleteDashboards(ctx context.Context, cmd *DeleteDashboardsCommand) (*Result, error) {
+	return s.run(ctx, cmd.OrgID, cmd.User, cmd.Selector, models.PERMISSION_EDIT, operation{
+		canApply: guardian.DashboardGuardian.CanDelete,
+		apply: func(ctx context.Context, dash *models.Dashboard) error {
+			if err := s.libraryElementService.DisconnectElementsFromDashboard(ctx, dash.Id); err != nil {
+				return fmt.Errorf("failed to disconnect library elements: %w", err)
+			}
+			return s.dashboardService.DeleteDashboard(ctx, dash.Id, cmd.OrgID)
+		},
+	})
+}
+
+func (s *DashboardBulkService) SetDashboardPermissions(ctx context.Context, cmd *SetDashboardPermissionsCommand) (*Result, error) {
+	if len(cmd.Items) == 0 {
+		return nil, ErrNoPermissions
+	}
+	for _, item := range cmd.Items {
+		if item.UserID == 0 && item.TeamID == 0 && (item.Role == nil || !item.Role.IsValid()) {
+			return nil, models.ErrDashboardACLInfoMissing
+		}
+	}
+
+	return s.run(ctx, cmd.OrgID, cmd.User, cmd.Selector, models.PERMISSION_ADMIN, operation{
+		canApply: guardian.DashboardGuardian.CanAdmin,
+		apply: func(ctx context.Context, dash *models.Dashboard) error {
+			if !s.ac.IsDisabled() {
+				return s.setAccessControlPermissions(ctx, dash, cmd.Items)
+			}
+			return s.setLegacyPermissions(ctx, dash, cmd.Items)
+		},
+	})
+}
+
+func (s *DashboardBulkService) setAccessControlPermissions(ctx context.Context, dash *models.Dashboard, items []PermissionItem) error {
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(items))
+	for _, item := range items {
+		role := ""
+		if item.Role != nil {
+			role = string(*item.Role)
+		}
+		permission := ""
+		if item.Permission != 0 {
+			permission = item.Permission.String()
+		}
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{
+			UserID:      item.UserID,
+			TeamID:      item.TeamID,
+			BuiltinRole: role,
+			Permission:  permission,
+		})
+	}
+	_, err := s.dashboardPermissions.SetPermissions(ctx, dash.OrgId, dash.Uid, commands...)
+	return err
+}
+
+// setLegacyPermissions merges the items into the dashboard ACL.
+func (s *DashboardBulkService) setLegacyPermissions(ctx context.Context, dash *models.Dashboard, items []PermissionItem) error {
+	query := &models.GetDashboardACLInfoListQuery{DashboardID: dash.Id, OrgID: dash.OrgId}
+	if err := s.dashboardService.GetDashboardACLInfoList(ctx, query); err != nil {
+		return err
+	}
+
+	sameSubject := func(item PermissionItem, existing *models.DashboardACLInfoDTO) bool {
+		switch {
+		case item.UserID != 0:
+			return item.UserID == existing.UserId
+		case item.TeamID != 0:
+			return item.TeamID == existing.TeamId
+		}
+		return existing.Role != nil && *item.Role == *existing.Role
+	}
+
+	now := time.Now()
+	acl := make([]*models.DashboardACL, 0, len(query.Result)+len(items))
+	for _, existing := range query.Result {
+		if existing.Inherited {
+			continue
+		}
+		replaced := false
+		for _, item := range items {
+			if sameSubject(item, existing) {
+				replaced = true
+				break
+			}
+		}
+		if replaced {
+			continue
+		}
+		acl = append(acl, &models.DashboardACL{
+			OrgID:       dash.OrgId,
+			DashboardID: dash.Id,
+			UserID:      existing.UserId,
+			TeamID:      existing.TeamId,
+			Role:        existing.Role,
+			Permission:  existing.Permission,
+			Created:     existing.Created,
+			Updated:     existing.Updated,
+		})
+	}
+	for _, item := range items {
+		if item.Permission == 0 {
+			continue
+		}
+		acl = append(acl, &models.DashboardACL{
+			OrgID:       dash.OrgId,
+			DashboardID: dash.Id,
+			UserID:      item.UserID,
+			TeamID:      item.TeamID,
+			Role:        item.Role,
+			Permission:  item.Permission,
+			Created:     now,
+			Updated:     now,
+		})
+	}
+	return s.dashboardService.UpdateDashboardACL(ctx, dash.Id, acl)
+}
+
+type batchItem struct {
+	result    *ItemResult
+	dashboard *models.Dashboard
+}
+
+// run applies the operation to all selected dashboards in a single transaction. Nothing is
+// changed if any of the dashboards can't be found, the user isn't allowed to change it or
+// the operation fails.
+func (s *DashboardBulkService) run(ctx context.Context, orgID int64, usr *user.SignedInUser, selector Selector,
+	permission models.PermissionType, op operation) (*Result, error) {
+	uids, err := s.selectDashboards(ctx, orgID, usr, selector, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Items: make([]*ItemResult, 0, len(uids))}
+	items := make([]*batchItem, 0, len(uids))
+	failed := false
+	for _, uid := range uids {
+		item := &batchItem{result: &ItemResult{UID: uid, Status: ItemStatusNotApplied}}
+		items = append(items, item)
+		result.Items = append(result.Items, item.result)
+
+		if err := s.checkDashboard(ctx, orgID, usr, item, op); err != nil {
+			item.result.Status = ItemStatusFailed
+			item.result.Message = err.Error()
+			failed = true
+		}
+	}
+	if failed {
+		return result, nil
+	}
+
+	err = s.sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range items {
+			if err := op.apply(ctx, item.dashboard); err != nil {
+				item.result.Status = ItemStatusFailed
+				item.result.Message = err.Error()
+				return err
+			}
+			item.result.Status = ItemStatusOK
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Warn("Bulk operation failed and was rolled back", "orgId", orgID, "numDashboards", len(items), "error", err)
+		for _, item := range items {
+			if item.result.Status == ItemStatusOK {
+				item.result.Status = ItemStatusNotApplied
+			}
+		}
+		return result, nil
+	}
+
+	result.Applied = true
+	return result, nil
+}
+
+func (s *DashboardBulkService) checkDashboard(ctx context.Context, orgID int64, usr *user.SignedInUser, item *batchItem, op operation) error {
+	query := &models.GetDashboardQuery{Uid: item.result.UID, OrgId: orgID}
+	if err := s.dashboardService.GetDashboard(ctx, query); err != nil {
+		return err
+	}
+	dash := query.Result
+	item.dashboard = dash
+	item.result.Title = dash.Title
+
+	if dash.IsFolder {
+		return errors.New("folders can't be changed by bulk dashboard operations")
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, orgID, usr)
+	if err != nil {
+		return err
+	}
+	if ok, err := op.canApply(g); err != nil || !ok {
+		if err != nil {
+			return err
+		}
+		return dashboards.ErrDashboardUpdateAccessDenied
+	}
+	return nil
+}
+
+// selectDashboards returns the UIDs of the dashboards selected by UID or query.
+func (s *DashboardBulkService) selectDashboards(ctx context.Context, orgID int64, usr *user.SignedInUser,
+	selector Selector, permission models.PermissionType) ([]string, error) {
+	if len(selector.DashboardUIDs) == 0 && selector.Query == nil {
+		return nil, ErrEmptySelection
+	}
+
+	uids := make([]string, 0, len(selector.DashboardUIDs))
+	seen := make(map[string]bool, len(selector.DashboardUIDs))
+	add := func(uid string) {
+		if uid != "" && !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+	for _, uid := range selector.DashboardUIDs {
+		add(uid)
+	}
+
+	if q := selector.Query; q != nil {
+		if q.Query == "" && len(q.Tags) == 0 && len(q.FolderUIDs) == 0 {
+			return nil, ErrEmptyQuery
+		}
+
+		folderIDs := make([]int64, 0, len(q.FolderUIDs))
+		for i := range q.FolderUIDs {
+			f, err := s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &q.FolderUIDs[i], OrgID: orgID, SignedInUser: usr})
+			if err != nil {
+				return nil, err
+			}
+			folderIDs = append(folderIDs, f.ID)
+		}
+
+		search := &models.FindPersistedDashboardsQuery{
+			Title:        q.Query,
+			Tags:         q.Tags,
+			FolderIds:    folderIDs,
+			OrgId:        orgID,
+			SignedInUser: usr,
+			Type:         string(models.DashHitDB),
+			Permission:   permission,
+			Limit:        MaxBatchSize + 1,
+		}
+		if err := s.dashboardService.SearchDashboards(ctx, search); err != nil {
+			return nil, err
+		}
+		for _, hit := range search.Result {
+			add(hit.UID)
+		}
+	}
+
+	if len(uids) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d, the maximum is %d", ErrBatchTooLarge, len(uids), MaxBatchSize)
+	}
+	return uids, nil
+}
@@ -0,0 +1,273 @@
+package dashboardbulk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db/dbtest"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestDashboardBulkService(t *testing.T) {
+	usr := &user.SignedInUser{OrgID: 1, UserID: 1}
+
+	setup := func(t *testing.T, dashes ...*models.Dashboard) (*DashboardBulkService, *dashboards.FakeDashboardService, *fakeTransactions) {
+		t.Helper()
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Return(
+			func(ctx context.Context, query *models.GetDashboardQuery) error {
+				for _, dash := range dashes {
+					if dash.Uid == query.Uid {
+						query.Result = dash
+						return nil
+					}
+				}
+				return dashboards.ErrDashboardNotFound
+			}).Maybe()
+
+		folderService := foldertest.NewFakeService()
+		folderService.ExpectedFolder = &folder.Folder{ID: 10, UID: "folder"}
+
+		db := &fakeTransactions{}
+		s := &DashboardBulkService{
+			sqlStore:              db,
+			log:                   log.NewNopLogger(),
+			dashboardService:      dashboardService,
+			folderService:         folderService,
+			libraryElementService: &fakeLibraryElementService{},
+			ac:                    actest.FakeAccessControl{ExpectedDisabled: true},
+		}
+		return s, dashboardService, db
+	}
+
+	// mockGuardian allows everything except for the dashboards with the given UIDs.
+	mockGuardian := func(t *testing.T, deniedUIDs ...string) {
+		origNewByDashboard := guardian.NewByDashboard
+		t.Cleanup(func() {
+			guardian.NewByDashboard = origNewByDashboard
+		})
+		guardian.NewByDashboard = func(_ context.Context, dash *models.Dashboard, _ int64, _ *user.SignedInUser) (guardian.DashboardGuardian, error) {
+			allowed := true
+			for _, uid := range deniedUIDs {
+				allowed = allowed && dash.Uid != uid
+			}
+			return &guardian.FakeDashboardGuardian{CanSaveValue: allowed, CanAdminValue: allowed}, nil
+		}
+	}
+
+	t.Run("Should move all selected dashboards in a transaction", func(t *testing.T) {
+		mockGuardian(t)
+		s, dashboardService, db := setup(t, newDashboard(1, "a", 0), newDashboard(2, "b", 0), newDashboard(3, "c", 10))
+
+		saved := make([]string, 0)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), false).Run(func(args mock.Arguments) {
+			dto := args.Get(1).(*dashboards.SaveDashboardDTO)
+			require.Equal(t, int64(10), dto.Dashboard.FolderId)
+			require.False(t, dto.Overwrite)
+			saved = append(saved, dto.Dashboard.Uid)
+		}).Return(nil, nil)
+
+		result, err := s.MoveDashboards(context.Background(), &MoveDashboardsCommand{
+			Selector:  Selector{DashboardUIDs: []string{"a", "b", "c", "a"}},
+			FolderUID: "folder",
+			OrgID:     1,
+			User:      usr,
+		})
+		require.NoError(t, err)
+		require.True(t, result.Applied)
+		require.Equal(t, []*ItemResult{
+			{UID: "a", Title: "a", Status: ItemStatusOK},
+			{UID: "b", Title: "b", Status: ItemStatusOK},
+			{UID: "c", Title: "c", Status: ItemStatusOK},
+		}, result.Items)
+		// Dashboard c is already in the folder.
+		require.Equal(t, []string{"a", "b"}, saved)
+		require.Equal(t, 1, db.transactions)
+	})
+
+	t.Run("Should not change any dashboard if one can't be changed", func(t *testing.T) {
+		mockGuardian(t, "b")
+		s, _, db := setup(t, newDashboard(1, "a", 0), newDashboard(2, "b", 0), &models.Dashboard{Id: 3, Uid: "f", Title: "f", IsFolder: true})
+
+		result, err := s.DeleteDashboards(context.Background(), &DeleteDashboardsCommand{
+			Selector: Selector{DashboardUIDs: []string{"a", "b", "missing", "f"}},
+			OrgID:    1,
+			User:     usr,
+		})
+		require.NoError(t, err)
+		require.False(t, result.Applied)
+		require.Equal(t, []*ItemResult{
+			{UID: "a", Title: "a", Status: ItemStatusNotApplied},
+			{UID: "b", Title: "b", Status: ItemStatusFailed, Message: dashboards.ErrDashboardUpdateAccessDenied.Error()},
+			{UID: "missing", Status: ItemStatusFailed, Message: dashboards.ErrDashboardNotFound.Error()},
+			{UID: "f", Title: "f", Status: ItemStatusFailed, Message: "folders can't be changed by bulk dashboard operations"},
+		}, result.Items)
+		require.Equal(t, 0, db.transactions)
+	})
+
+	t.Run("Should roll back if applying the operation fails", func(t *testing.T) {
+		mockGuardian(t)
+		s, dashboardService, db := setup(t, newDashboard(1, "a", 0), newDashboard(2, "b", 0), newDashboard(3, "c", 0))
+		dashboardService.On("DeleteDashboard", mock.Anything, int64(1), int64(1)).Return(nil)
+		dashboardService.On("DeleteDashboard", mock.Anything, int64(2), int64(1)).Return(dashboards.ErrDashboardCannotDeleteProvisionedDashboard)
+
+		result, err := s.DeleteDashboards(context.Background(), &DeleteDashboardsCommand{
+			Selector: Selector{DashboardUIDs: []string{"a", "b", "c"}},
+			OrgID:    1,
+			User:     usr,
+		})
+		require.NoError(t, err)
+		require.False(t, result.Applied)
+		require.Equal(t, []*ItemResult{
+			{UID: "a", Title: "a", Status: ItemStatusNotApplied},
+			{UID: "b", Title: "b", Status: ItemStatusFailed, Message: dashboards.ErrDashboardCannotDeleteProvisionedDashboard.Error()},
+			{UID: "c", Title: "c", Status: ItemStatusNotApplied},
+		}, result.Items)
+		require.Equal(t, 1, db.transactions)
+		require.Equal(t, 1, db.rolledBack)
+	})
+
+	t.Run("Should select dashboards by query", func(t *testing.T) {
+		mockGuardian(t)
+		a, b := newDashboard(1, "a", 0), newDashboard(2, "b", 0)
+		a.Data.Set("tags", []interface{}{"old", "keep"})
+		b.Data.Set("tags", []interface{}{"new"})
+		s, dashboardService, _ := setup(t, a, b)
+
+		dashboardService.On("SearchDashboards", mock.Anything, mock.AnythingOfType("*models.FindPersistedDashboardsQuery")).Run(func(args mock.Arguments) {
+			query := args.Get(1).(*models.FindPersistedDashboardsQuery)
+			require.Equal(t, []string{"old"}, query.Tags)
+			require.Equal(t, []int64{10}, query.FolderIds)
+			require.Equal(t, string(models.DashHitDB), query.Type)
+			require.Equal(t, models.PERMISSION_EDIT, query.Permission)
+			query.Result = models.HitList{{UID: "a"}, {UID: "b"}}
+		}).Return(nil)
+
+		saved := make(map[string][]string)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), false).Run(func(args mock.Arguments) {
+			dash := args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard
+			saved[dash.Uid] = dash.GetTags()
+		}).Return(nil, nil)
+
+		result, err := s.TagDashboards(context.Background(), &TagDashboardsCommand{
+			Selector:   Selector{Query: &SearchQuery{Tags: []string{"old"}, FolderUIDs: []string{"folder"}}},
+			AddTags:    []string{"new"},
+			RemoveTags: []string{"old"},
+			OrgID:      1,
+			User:       usr,
+		})
+		require.NoError(t, err)
+		require.True(t, result.Applied)
+		require.Len(t, result.Items, 2)
+		// Dashboard b already has the new tag.
+		require.Equal(t, map[string][]string{"a": {"keep", "new"}}, saved)
+	})
+
+	t.Run("Should validate the selection", func(t *testing.T) {
+		s, _, _ := setup(t)
+
+		_, err := s.DeleteDashboards(context.Background(), &DeleteDashboardsCommand{OrgID: 1, User: usr})
+		require.ErrorIs(t, err, ErrEmptySelection)
+
+		_, err = s.DeleteDashboards(context.Background(), &DeleteDashboardsCommand{Selector: Selector{Query: &SearchQuery{}}, OrgID: 1, User: usr})
+		require.ErrorIs(t, err, ErrEmptyQuery)
+
+		uids := make([]string, MaxBatchSize+1)
+		for i := range uids {
+			uids[i] = fmt.Sprintf("dash-%d", i)
+		}
+		_, err = s.DeleteDashboards(context.Background(), &DeleteDashboardsCommand{Selector: Selector{DashboardUIDs: uids}, OrgID: 1, User: usr})
+		require.ErrorIs(t, err, ErrBatchTooLarge)
+	})
+
+	t.Run("Should merge legacy permissions", func(t *testing.T) {
+		mockGuardian(t)
+		s, dashboardService, _ := setup(t, newDashboard(1, "a", 0))
+
+		dashboardService.On("GetDashboardACLInfoList", mock.Anything, mock.AnythingOfType("*models.GetDashboardACLInfoListQuery")).Run(func(args mock.Arguments) {
+			query := args.Get(1).(*models.GetDashboardACLInfoListQuery)
+			query.Result = []*models.DashboardACLInfoDTO{
+				{DashboardId: 1, UserId: 2, Permission: models.PERMISSION_VIEW},
+				{DashboardId: 1, TeamId: 3, Permission: models.PERMISSION_VIEW},
+				{DashboardId: 10, UserId: 4, Permission: models.PERMISSION_EDIT, Inherited: true},
+			}
+		}).Return(nil)
+
+		var acl []*models.DashboardACL
+		dashboardService.On("UpdateDashboardACL", mock.Anything, int64(1), mock.Anything).Run(func(args mock.Arguments) {
+			acl = args.Get(2).([]*models.DashboardACL)
+		}).Return(nil)
+
+		result, err := s.SetDashboardPermissions(context.Background(), &SetDashboardPermissionsCommand{
+			Selector: Selector{DashboardUIDs: []string{"a"}},
+			Items: []PermissionItem{
+				{UserID: 2, Permission: models.PERMISSION_EDIT},
+				{TeamID: 3},
+			},
+			OrgID: 1,
+			User:  usr,
+		})
+		require.NoError(t, err)
+		require.True(t, result.Applied)
+		require.Len(t, acl, 1)
+		require.Equal(t, int64(2), acl[0].UserID)
+		require.Equal(t, models.PERMISSION_EDIT, acl[0].Permission)
+	})
+}
+
+func TestUpdateTags(t *testing.T) {
+	tags, changed := updateTags([]string{"a", "b"}, []string{"c", "a"}, []string{"b"})
+	require.True(t, changed)
+	require.Equal(t, []string{"a", "c"}, tags)
+
+	tags, changed = updateTags([]string{"a", "b"}, []string{"b"}, []string{"c"})
+	require.False(t, changed)
+	require.Equal(t, []string{"a", "b"}, tags)
+}
+
+func newDashboard(id int64, uid string, folderID int64) *models.Dashboard {
+	dash := models.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+		"id":    id,
+		"uid":   uid,
+		"title": uid,
+	}))
+	dash.FolderId = folderID
+	return dash
+}
+
+// fakeTransactions calls transaction callbacks directly and counts them.
+type fakeTransactions struct {
+	dbtest.FakeDB
+	transactions int
+	rolledBack   int
+}
+
+func (f *fakeTransactions) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.transactions++
+	err := fn(ctx)
+	if err != nil {
+		f.rolledBack++
+	}
+	return err
+}
+
+type fakeLibraryElementService struct {
+	libraryelements.Service
+}
+
+func (f *fakeLibraryElementService) DisconnectElementsFromDashboard(c context.Context, dashboardID int64) error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package dashboardbulk
+
+import (
+	"errors"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// MaxBatchSize is the maximum number of dashboards a single bulk operation can change.
+const MaxBatchSize = 1000
+
+var (
+	ErrEmptySelection = errors.New("no dashboards selected, set dashboardUids or a query")
+	ErrEmptyQuery     = errors.New("query must filter dashboards by title, tags or folders")
+	ErrBatchTooLarge  = errors.New("too many dashboards selected")
+	ErrNoTagChanges   = errors.New("no tags to add or remove")
+	ErrNoPermissions  = errors.New("no permissions to set")
+)
+
+// Selector selects the dashboards of a bulk operation. Dashboards selected by UID and by
+// query are combined.
+type Selector struct {
+	DashboardUIDs []string     `json:"dashboardUids"`
+	Query         *SearchQuery `json:"query"`
+}
+
+// SearchQuery selects all dashboards matching the title query, tags and folders.
+type SearchQuery struct {
+	Query      string   `json:"query"`
+	Tags       []string `json:"tags"`
+	FolderUIDs []string `json:"folderUids"`
+}
+
+type MoveDashboardsCommand struct {
+	Selector
+	// FolderUID of the target folder, empty for the General folder.
+	FolderUID string `json:"folderUid"`
+
+	OrgID int64              `json:"-"`
+	User  *user.SignedInUser `json:"-"`
+}
+
+type TagDashboardsCommand struct {
+	Selector
+	AddTags    []string `json:"addTags"`
+	RemoveTags []string `json:"removeTags"`
+
+	OrgID int64              `json:"-"`
+	User  *user.SignedInUser `json:"-"`
+}
+
+type DeleteDashboardsCommand struct {
+	Selector
+
+	OrgID int64              `json:"-"`
+	User  *user.SignedInUser `json:"-"`
+}
+
+type SetDashboardPermissionsCommand struct {
+	Selector
+	Items []PermissionItem `json:"items"`
+
+	OrgID int64              `json:"-"`
+	User  *user.SignedInUser `json:"-"`
+}
+
+// PermissionItem sets the permission of a user, team or role. Other permissions of the
+// dashboards are kept, the permission is removed if Permission is 0.
+type PermissionItem struct {
+	UserID     int64                 `json:"userId"`
+	TeamID     int64                 `json:"teamId"`
+	Role       *org.RoleType         `json:"role,omitempty"`
+	Permission models.PermissionType `json:"permission"`
+}
+
+type ItemStatus string
+
+const (
+	ItemStatusOK ItemStatus = "ok"
+	// ItemStatusFailed is set for the dashboards which caused the batch to fail.
+	ItemStatusFailed ItemStatus = "failed"
+	// ItemStatusNotApplied is set for the other dashboards of a failed batch.
+	ItemStatusNotApplied ItemStatus = "not-applied"
+)
+
+type ItemResult struct {
+	UID     string     `json:"uid"`
+	Title   string     `json:"title,omitempty"`
+	Status  ItemStatus `json:"status"`
+	Message string     `json:"message,omitempty"`
+}
+
+// Result reports the outcome of a bulk operation for each selected dashboard. Bulk
+// operations are transactional, either all dashboards are changed or none.
+type Result struct {
+	Applied bool          `json:"applied"`
+	Items   []*ItemResult `json:"items"`
+}
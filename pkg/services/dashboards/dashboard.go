@@ -29,6 +29,14 @@ type DashboardService interface {
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*models.DashboardACL) error
 	DeleteACLByUser(ctx context.Context, userID int64) error
 	CountDashboardsInFolder(ctx context.Context, query *CountDashboardsInFolderQuery) (int64, error)
+	// GetTrashedDashboards returns deleted dashboards and folders which can be restored.
+	GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)
+	// RestoreDashboard restores a deleted dashboard or folder with its permissions.
+	RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) (*TrashedDashboard, error)
+	// DeleteTrashedDashboard permanently deletes a dashboard or folder from trash.
+	DeleteTrashedDashboard(ctx context.Context, cmd *DeleteTrashedDashboardCommand) error
+	// PurgeTrashedDashboards permanently deletes dashboards and folders kept in trash longer than retention period.
+	PurgeTrashedDashboards(ctx context.Context, cmd *PurgeTrashedDashboardsCommand) error
 }
 
 // PluginService is a service for operating on plugin dashboards.
@@ -83,6 +91,10 @@ type Store interface {
 	// CountDashboardsInFolder returns the number of dashboards associated with
 	// the given parent folder ID.
 	CountDashboardsInFolder(ctx context.Context, request *CountDashboardsInFolderRequest) (int64, error)
+	GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)
+	RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) (*TrashedDashboard, error)
+	DeleteTrashedDashboard(ctx context.Context, cmd *DeleteTrashedDashboardCommand) error
+	PurgeTrashedDashboards(ctx context.Context, cmd *PurgeTrashedDashboardsCommand) error
 
 	FolderStore
 }
@@ -761,6 +761,9 @@ func (d *DashboardStore) GetDashboardsByPluginID(ctx context.Context, query *mod
 
 func (d *DashboardStore) DeleteDashboard(ctx context.Context, cmd *models.DeleteDashboardCommand) error {
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if cmd.MoveToTrash && d.trashEnabled() {
+			return d.trashDashboard(cmd, sess, d.emitEntityEvent())
+		}
 		return d.deleteDashboard(cmd, sess, d.emitEntityEvent())
 	})
 }
@@ -836,29 +839,9 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 			}
 		}
 
-		var existingRuleID int64
-		exists, err := sess.Table("alert_rule").Where("namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)", dashboard.Id).Cols("id").Get(&existingRuleID)
-		if err != nil {
+		if err := d.deleteFolderAlertRules(cmd, dashboard.Id, sess); err != nil {
 			return err
 		}
-		if exists {
-			if !cmd.ForceDeleteFolderRules {
-				return fmt.Errorf("folder cannot be deleted: %w", dashboards.ErrFolderContainsAlertRules)
-			}
-
-			// Delete all rules under this folder.
-			deleteNGAlertsByFolder := []string{
-				"DELETE FROM alert_rule WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
-				"DELETE FROM alert_rule_version WHERE rule_namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
-			}
-
-			for _, sql := range deleteNGAlertsByFolder {
-				_, err := sess.Exec(sql, dashboard.Id)
-				if err != nil {
-					return err
-				}
-			}
-		}
 	} else {
 		_, err = sess.Exec("DELETE FROM permission WHERE scope = ?", ac.GetResourceScopeUID("dashboards", dashboard.Uid))
 		if err != nil {
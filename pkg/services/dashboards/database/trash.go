@@ -0,0 +1,756 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+// trashedDashboard is a row of dashboard_trash table. Data contains rows
+// removed from other tables, so they can be inserted back on restore with
+// their original IDs. Versions, annotations, stars and playlist items stay
+// in their tables until the dashboard is purged. Alert rules and library
+// elements of a folder are moved to trash with it.
+type trashedDashboard struct {
+	dashboards.TrashedDashboard `xorm:"extends"`
+	Data                        string
+}
+
+func (trashedDashboard) TableName() string {
+	return "dashboard_trash"
+}
+
+type trashedContent struct {
+	Dashboards   []*models.Dashboard             `json:"dashboards"`
+	Tags         []*DashboardTag                 `json:"tags,omitempty"`
+	ACL          []*models.DashboardACL          `json:"acl,omitempty"`
+	Provisioning []*models.DashboardProvisioning `json:"provisioning,omitempty"`
+	Permissions  []*trashedPermission            `json:"permissions,omitempty"`
+
+	PublicDashboards []*trashedPublicDashboard `json:"publicDashboards,omitempty"`
+
+	AlertRules             []*ngmodels.AlertRule           `json:"alertRules,omitempty"`
+	AlertRuleVersions      []*ngmodels.AlertRuleVersion    `json:"alertRuleVersions,omitempty"`
+	LibraryElements        []*trashedLibraryElement        `json:"libraryElements,omitempty"`
+	LibraryElementVersions []*trashedLibraryElementVersion `json:"libraryElementVersions,omitempty"`
+	// Folder is the row of the nested folder table.
+	Folder *trashedFolder `json:"folder,omitempty"`
+}
+
+type trashedPermission struct {
+	RoleID  int64     `json:"roleId"`
+	Action  string    `json:"action"`
+	Scope   string    `json:"scope"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// trashedPublicDashboard is a row of dashboard_public table, the
+// publicdashboards models don't marshal all columns.
+type trashedPublicDashboard struct {
+	UID                  string     `xorm:"pk 'uid'" json:"uid"`
+	DashboardUID         string     `xorm:"dashboard_uid" json:"dashboardUid"`
+	OrgID                int64      `xorm:"org_id" json:"orgId"`
+	TimeSettings         *string    `xorm:"time_settings" json:"timeSettings"`
+	TemplateVariables    *string    `xorm:"template_variables" json:"templateVariables"`
+	IsEnabled            bool       `xorm:"is_enabled" json:"isEnabled"`
+	AccessToken          string     `xorm:"access_token" json:"accessToken"`
+	AnnotationsEnabled   bool       `xorm:"annotations_enabled" json:"annotationsEnabled"`
+	TimeSelectionEnabled bool       `xorm:"time_selection_enabled" json:"timeSelectionEnabled"`
+	ExpiresAt            *time.Time `xorm:"expires_at" json:"expiresAt"`
+	IPAllowList          *string    `xorm:"ip_allow_list" json:"ipAllowList"`
+	CreatedBy            int64      `xorm:"created_by" json:"createdBy"`
+	UpdatedBy            int64      `xorm:"updated_by" json:"updatedBy"`
+	CreatedAt            time.Time  `xorm:"created_at" json:"createdAt"`
+	UpdatedAt            time.Time  `xorm:"updated_at" json:"updatedAt"`
+}
+
+func (trashedPublicDashboard) TableName() string {
+	return "dashboard_public"
+}
+
+// trashedLibraryElement is a row of library_element table, the libraryelements
+// package can't be imported here.
+type trashedLibraryElement struct {
+	ID          int64  `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID       int64  `xorm:"org_id" json:"orgId"`
+	FolderID    int64  `xorm:"folder_id" json:"folderId"`
+	UID         string `xorm:"uid" json:"uid"`
+	Name        string `json:"name"`
+	Kind        int64  `json:"kind"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Model       string `json:"model"`
+	Version     int64  `json:"version"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+
+	CreatedBy int64 `json:"createdBy"`
+	UpdatedBy int64 `json:"updatedBy"`
+}
+
+func (trashedLibraryElement) TableName() string {
+	return "library_element"
+}
+
+// trashedLibraryElementVersion is a row of library_element_version table.
+type trashedLibraryElementVersion struct {
+	ID            int64     `xorm:"pk autoincr 'id'" json:"id"`
+	ElementID     int64     `xorm:"element_id" json:"elementId"`
+	ParentVersion int64     `json:"parentVersion"`
+	RestoredFrom  int64     `json:"restoredFrom"`
+	Version       int64     `json:"version"`
+	Name          string    `json:"name"`
+	Model         string    `json:"model"`
+	Message       string    `json:"message"`
+	Created       time.Time `json:"created"`
+	CreatedBy     int64     `json:"createdBy"`
+}
+
+func (trashedLibraryElementVersion) TableName() string {
+	return "library_element_version"
+}
+
+// trashedFolder is a row of the nested folder table.
+type trashedFolder struct {
+	ID          int64     `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID       int64     `xorm:"org_id" json:"orgId"`
+	UID         string    `xorm:"uid" json:"uid"`
+	ParentUID   string    `xorm:"parent_uid" json:"parentUid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+func (trashedFolder) TableName() string {
+	return "folder"
+}
+
+// topLevelTrashCondition selects trash entries which were not moved to trash
+// with a folder. Entries whose folder entry is missing, because moving the
+// folder to trash failed, are top level too.
+const topLevelTrashCondition = `(trashed_with = '' OR NOT EXISTS (
+	SELECT 1 FROM dashboard_trash AS f WHERE f.org_id = dashboard_trash.org_id AND f.dashboard_uid = dashboard_trash.trashed_with AND f.id > dashboard_trash.id))`
+
+func (d *DashboardStore) trashEnabled() bool {
+	return d.cfg != nil && d.cfg.DashboardTrashRetention > 0
+}
+
+// trashDashboard moves a dashboard or a folder with its dashboards to trash.
+func (d *DashboardStore) trashDashboard(cmd *models.DeleteDashboardCommand, sess *db.Session, emitEntityEvent bool) error {
+	dashboard := models.Dashboard{Id: cmd.Id, OrgId: cmd.OrgId}
+	has, err := sess.Get(&dashboard)
+	if err != nil {
+		return err
+	} else if !has {
+		return dashboards.ErrDashboardNotFound
+	}
+
+	content := trashedContent{Dashboards: []*models.Dashboard{&dashboard}}
+	scopes := []string{ac.GetResourceScopeUID("dashboards", dashboard.Uid)}
+	if dashboard.IsFolder {
+		scopes = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(dashboard.Uid)}
+
+		var children []*models.Dashboard
+		if err := sess.Where("org_id = ? AND folder_id = ?", dashboard.OrgId, dashboard.Id).Find(&children); err != nil {
+			return err
+		}
+		for _, child := range children {
+			content.Dashboards = append(content.Dashboards, child)
+			scopes = append(scopes, ac.GetResourceScopeUID("dashboards", child.Uid))
+		}
+
+		if err := trashFolderAlertRules(cmd, &dashboard, &content, sess, emitEntityEvent); err != nil {
+			return err
+		}
+		if err := trashFolderLibraryElements(&dashboard, &content, sess, emitEntityEvent); err != nil {
+			return err
+		}
+		if d.features != nil && d.features.IsEnabled(featuremgmt.FlagNestedFolders) {
+			if err := trashNestedFolder(&dashboard, &content, sess); err != nil {
+				return err
+			}
+		}
+	}
+
+	ids := make([]int64, 0, len(content.Dashboards))
+	uids := make([]string, 0, len(content.Dashboards))
+	for _, dash := range content.Dashboards {
+		ids = append(ids, dash.Id)
+		uids = append(uids, dash.Uid)
+	}
+
+	if err := sess.In("dashboard_id", ids).Find(&content.Tags); err != nil {
+		return err
+	}
+	if err := sess.In("dashboard_id", ids).Find(&content.ACL); err != nil {
+		return err
+	}
+	if err := sess.In("dashboard_id", ids).Find(&content.Provisioning); err != nil {
+		return err
+	}
+	if err := sess.In("dashboard_uid", uids).Find(&content.PublicDashboards); err != nil {
+		return err
+	}
+	var permissions []*ac.Permission
+	if err := sess.Table("permission").In("scope", scopes).Find(&permissions); err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		content.Permissions = append(content.Permissions, &trashedPermission{
+			RoleID:  p.RoleID,
+			Action:  p.Action,
+			Scope:   p.Scope,
+			Created: p.Created,
+			Updated: p.Updated,
+		})
+	}
+
+	for _, id := range ids {
+		if err := d.deleteAlertDefinition(id, sess); err != nil {
+			return err
+		}
+	}
+
+	deletes := []struct {
+		table  string
+		column string
+		args   []interface{}
+	}{
+		{"dashboard_tag", "dashboard_id", int64Args(ids)},
+		{"dashboard_acl", "dashboard_id", int64Args(ids)},
+		{"dashboard_provisioning", "dashboard_id", int64Args(ids)},
+		{"dashboard_public", "dashboard_uid", stringArgs(uids)},
+		{"permission", "scope", stringArgs(scopes)},
+		{"dashboard", "id", int64Args(ids)},
+	}
+	for _, del := range deletes {
+		if err := deleteIn(sess, del.table, del.column, del.args); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	entry := &trashedDashboard{
+		TrashedDashboard: dashboards.TrashedDashboard{
+			OrgID:        dashboard.OrgId,
+			DashboardID:  dashboard.Id,
+			DashboardUID: dashboard.Uid,
+			Title:        dashboard.Title,
+			IsFolder:     dashboard.IsFolder,
+			TrashedWith:  cmd.TrashedWith,
+			Deleted:      time.Now(),
+		},
+		Data: string(data),
+	}
+	if dashboard.FolderId > 0 {
+		var folderUID string
+		if _, err := sess.Table("dashboard").Where("id = ?", dashboard.FolderId).Cols("uid").Get(&folderUID); err != nil {
+			return err
+		}
+		entry.FolderUID = folderUID
+	}
+	// A dashboard with the same UID may have been deleted before, keep
+	// only the latest one.
+	previous, previousContent, err := getTrashedDashboard(sess, dashboard.OrgId, dashboard.Uid)
+	if err != nil && !errors.Is(err, dashboards.ErrTrashedDashboardNotFound) {
+		return err
+	}
+	if previous != nil {
+		if err := purgeTrashedDashboard(sess, previous, previousContent); err != nil {
+			return err
+		}
+	}
+	if _, err := sess.Insert(entry); err != nil {
+		return err
+	}
+
+	if emitEntityEvent {
+		if _, err := sess.Insert(createEntityEvent(&dashboard, store.EntityEventTypeDelete)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashFolderAlertRules moves alert rules of a folder with their versions to
+// trash, which is only allowed if the command forces it.
+func trashFolderAlertRules(cmd *models.DeleteDashboardCommand, folder *models.Dashboard, content *trashedContent, sess *db.Session, emitEntityEvent bool) error {
+	if err := sess.Table("alert_rule").Where("org_id = ? AND namespace_uid = ?", folder.OrgId, folder.Uid).Find(&content.AlertRules); err != nil {
+		return err
+	}
+	if len(content.AlertRules) == 0 {
+		return nil
+	}
+	if !cmd.ForceDeleteFolderRules {
+		return fmt.Errorf("folder cannot be deleted: %w", dashboards.ErrFolderContainsAlertRules)
+	}
+	if err := sess.Table("alert_rule_version").Where("rule_org_id = ? AND rule_namespace_uid = ?", folder.OrgId, folder.Uid).Find(&content.AlertRuleVersions); err != nil {
+		return err
+	}
+
+	deletes := []string{
+		"DELETE FROM alert_rule WHERE org_id = ? AND namespace_uid = ?",
+		"DELETE FROM alert_rule_version WHERE rule_org_id = ? AND rule_namespace_uid = ?",
+	}
+	for _, sql := range deletes {
+		if _, err := sess.Exec(sql, folder.OrgId, folder.Uid); err != nil {
+			return err
+		}
+	}
+
+	if emitEntityEvent {
+		for _, rule := range content.AlertRules {
+			if _, err := sess.Insert(store.NewDatabaseEntityEvent(rule.UID, rule.OrgID, store.EntityTypeAlertRule, store.EntityEventTypeDelete)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// trashFolderLibraryElements moves library elements of a folder with their
+// versions to trash. Library elements which are used by dashboards can't be
+// deleted.
+func trashFolderLibraryElements(folder *models.Dashboard, content *trashedContent, sess *db.Session, emitEntityEvent bool) error {
+	if err := sess.Where("org_id = ? AND folder_id = ?", folder.OrgId, folder.Id).Find(&content.LibraryElements); err != nil {
+		return err
+	}
+	if len(content.LibraryElements) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(content.LibraryElements))
+	for _, element := range content.LibraryElements {
+		ids = append(ids, element.ID)
+	}
+	connected, err := sess.Table("library_element_connection").In("element_id", int64Args(ids)...).Exist()
+	if err != nil {
+		return err
+	}
+	if connected {
+		return dashboards.ErrFolderContainsLibraryElementsInUse
+	}
+	if err := sess.In("element_id", int64Args(ids)...).Find(&content.LibraryElementVersions); err != nil {
+		return err
+	}
+
+	if err := deleteIn(sess, "library_element_version", "element_id", int64Args(ids)); err != nil {
+		return err
+	}
+	if err := deleteIn(sess, "library_element", "id", int64Args(ids)); err != nil {
+		return err
+	}
+
+	if emitEntityEvent {
+		for _, element := range content.LibraryElements {
+			if _, err := sess.Insert(store.NewDatabaseEntityEvent(element.UID, element.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeDelete)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// trashNestedFolder moves the row of a folder in the nested folder table to
+// trash. Subfolders are moved to trash by the folder service one by one,
+// linked to the entry of the deleted folder by TrashedWith.
+func trashNestedFolder(folder *models.Dashboard, content *trashedContent, sess *db.Session) error {
+	var row trashedFolder
+	has, err := sess.Where("org_id = ? AND uid = ?", folder.OrgId, folder.Uid).Get(&row)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return nil
+	}
+	content.Folder = &row
+	_, err = sess.ID(row.ID).Delete(&trashedFolder{})
+	return err
+}
+
+// deleteFolderAlertRules deletes alert rules of a folder, which is only
+// allowed if the command forces it.
+func (d *DashboardStore) deleteFolderAlertRules(cmd *models.DeleteDashboardCommand, folderID int64, sess *db.Session) error {
+	var existingRuleID int64
+	exists, err := sess.Table("alert_rule").Where("namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)", folderID).Cols("id").Get(&existingRuleID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	if !cmd.ForceDeleteFolderRules {
+		return fmt.Errorf("folder cannot be deleted: %w", dashboards.ErrFolderContainsAlertRules)
+	}
+
+	deleteNGAlertsByFolder := []string{
+		"DELETE FROM alert_rule WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+		"DELETE FROM alert_rule_version WHERE rule_namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+	}
+	for _, sql := range deleteNGAlertsByFolder {
+		if _, err := sess.Exec(sql, folderID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTrashedDashboards returns dashboards and folders of an organization in
+// trash, most recently deleted first.
+func (d *DashboardStore) GetTrashedDashboards(ctx context.Context, query *dashboards.GetTrashedDashboardsQuery) ([]*dashboards.TrashedDashboard, error) {
+	result := make([]*dashboards.TrashedDashboard, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var entries []*trashedDashboard
+		if err := sess.Where("org_id = ? AND "+topLevelTrashCondition, query.OrgID).Omit("data").Desc("deleted").Find(&entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			trashed := entry.TrashedDashboard
+			trashed.Expires = trashed.Deleted.Add(d.cfg.DashboardTrashRetention)
+			result = append(result, &trashed)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// RestoreDashboard moves a dashboard or a folder with its dashboards out of
+// trash. If the parent folder of a dashboard does not exist anymore the
+// dashboard is restored to the General folder. Subfolders which were moved
+// to trash with a folder are restored with it.
+func (d *DashboardStore) RestoreDashboard(ctx context.Context, cmd *dashboards.RestoreDashboardCommand) (*dashboards.TrashedDashboard, error) {
+	var restored *dashboards.TrashedDashboard
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		entry, content, err := getTrashedDashboard(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if err := d.restoreTrashedDashboard(sess, entry, content); err != nil {
+			return err
+		}
+
+		// Subfolders are moved to trash before their parents, restore
+		// them in the reverse order so parents exist first.
+		linked, err := getLinkedTrashedDashboards(sess, entry)
+		if err != nil {
+			return err
+		}
+		for i := len(linked) - 1; i >= 0; i-- {
+			var linkedContent trashedContent
+			if err := json.Unmarshal([]byte(linked[i].Data), &linkedContent); err != nil {
+				return err
+			}
+			if err := d.restoreTrashedDashboard(sess, linked[i], &linkedContent); err != nil {
+				return err
+			}
+		}
+
+		restored = &entry.TrashedDashboard
+		return nil
+	})
+	return restored, err
+}
+
+// restoreTrashedDashboard inserts back the rows of a trash entry and deletes
+// the entry.
+func (d *DashboardStore) restoreTrashedDashboard(sess *db.Session, entry *trashedDashboard, content *trashedContent) error {
+	if len(content.Dashboards) == 0 {
+		return dashboards.ErrDashboardCorrupt
+	}
+
+	top := content.Dashboards[0]
+	if top.FolderId > 0 {
+		exists, err := sess.Table("dashboard").Where("id = ? AND org_id = ? AND is_folder = "+d.store.GetDialect().BooleanStr(true), top.FolderId, top.OrgId).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			top.FolderId = 0
+		}
+	}
+
+	for _, dash := range content.Dashboards {
+		exists, err := sess.Table("dashboard").Where("org_id = ? AND (uid = ? OR id = ?)", dash.OrgId, dash.Uid, dash.Id).Exist()
+		if err != nil {
+			return err
+		}
+		if exists {
+			if dash.IsFolder {
+				return dashboards.ErrFolderWithSameUIDExists
+			}
+			return dashboards.ErrDashboardWithSameUIDExists
+		}
+	}
+	exists, err := sess.Table("dashboard").Where("org_id = ? AND folder_id = ? AND title = ?", top.OrgId, top.FolderId, top.Title).Exist()
+	if err != nil {
+		return err
+	}
+	if exists {
+		if top.IsFolder {
+			return dashboards.ErrFolderSameNameExists
+		}
+		return dashboards.ErrDashboardWithSameNameInFolderExists
+	}
+
+	for _, dash := range content.Dashboards {
+		if _, err := sess.Insert(dash); err != nil {
+			return err
+		}
+	}
+	for _, tag := range content.Tags {
+		if _, err := sess.Table("dashboard_tag").Insert(tag); err != nil {
+			return err
+		}
+	}
+	for _, item := range content.ACL {
+		if _, err := sess.Insert(item); err != nil {
+			return err
+		}
+	}
+	for _, provisioning := range content.Provisioning {
+		if _, err := sess.Insert(provisioning); err != nil {
+			return err
+		}
+	}
+	for _, pd := range content.PublicDashboards {
+		if _, err := sess.Insert(pd); err != nil {
+			return err
+		}
+	}
+	if err := restoreFolderContent(sess, content); err != nil {
+		return err
+	}
+	for _, p := range content.Permissions {
+		// Skip permissions of managed roles deleted in the meantime,
+		// for example when a user or a team was removed.
+		roleExists, err := sess.Table("role").Where("id = ?", p.RoleID).Exist()
+		if err != nil {
+			return err
+		}
+		if !roleExists {
+			continue
+		}
+		if _, err := sess.Table("permission").Insert(&ac.Permission{
+			RoleID:  p.RoleID,
+			Action:  p.Action,
+			Scope:   p.Scope,
+			Created: p.Created,
+			Updated: p.Updated,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := sess.ID(entry.ID).Delete(&trashedDashboard{}); err != nil {
+		return err
+	}
+
+	if d.emitEntityEvent() {
+		if _, err := sess.Insert(createEntityEvent(top, store.EntityEventTypeCreate)); err != nil {
+			return err
+		}
+		for _, rule := range content.AlertRules {
+			if _, err := sess.Insert(store.NewDatabaseEntityEvent(rule.UID, rule.OrgID, store.EntityTypeAlertRule, store.EntityEventTypeCreate)); err != nil {
+				return err
+			}
+		}
+		for _, element := range content.LibraryElements {
+			if _, err := sess.Insert(store.NewDatabaseEntityEvent(element.UID, element.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeCreate)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreFolderContent inserts back the alert rules, library elements and the
+// nested folder row of a trashed folder.
+func restoreFolderContent(sess *db.Session, content *trashedContent) error {
+	for _, rule := range content.AlertRules {
+		if _, err := sess.Table("alert_rule").Insert(rule); err != nil {
+			return err
+		}
+		// xorm resets the version column on insert, keep the version the
+		// rule had so it still matches its versions.
+		if _, err := sess.Exec("UPDATE alert_rule SET version = ? WHERE id = ?", rule.Version, rule.ID); err != nil {
+			return err
+		}
+	}
+	for _, version := range content.AlertRuleVersions {
+		if _, err := sess.Table("alert_rule_version").Insert(version); err != nil {
+			return err
+		}
+	}
+	for _, element := range content.LibraryElements {
+		if _, err := sess.Insert(element); err != nil {
+			return err
+		}
+	}
+	for _, version := range content.LibraryElementVersions {
+		if _, err := sess.Insert(version); err != nil {
+			return err
+		}
+	}
+	if content.Folder != nil {
+		// The parent folder may have been deleted in the meantime.
+		if content.Folder.ParentUID != "" {
+			exists, err := sess.Table("folder").Where("org_id = ? AND uid = ?", content.Folder.OrgID, content.Folder.ParentUID).Exist()
+			if err != nil {
+				return err
+			}
+			if !exists {
+				content.Folder.ParentUID = ""
+			}
+		}
+		if _, err := sess.Insert(content.Folder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTrashedDashboard permanently deletes a dashboard or a folder from trash.
+func (d *DashboardStore) DeleteTrashedDashboard(ctx context.Context, cmd *dashboards.DeleteTrashedDashboardCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		entry, content, err := getTrashedDashboard(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		return purgeTrashedDashboard(sess, entry, content)
+	})
+}
+
+// PurgeTrashedDashboards permanently deletes dashboards and folders which
+// were moved to trash before cmd.OlderThan.
+func (d *DashboardStore) PurgeTrashedDashboards(ctx context.Context, cmd *dashboards.PurgeTrashedDashboardsCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var entries []*trashedDashboard
+		if err := sess.Where("deleted < ? AND "+topLevelTrashCondition, cmd.OlderThan).Find(&entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			var content trashedContent
+			if err := json.Unmarshal([]byte(entry.Data), &content); err != nil {
+				return err
+			}
+			if err := purgeTrashedDashboard(sess, entry, &content); err != nil {
+				return err
+			}
+			cmd.DeletedRows++
+		}
+		return nil
+	})
+}
+
+func getTrashedDashboard(sess *db.Session, orgID int64, uid string) (*trashedDashboard, *trashedContent, error) {
+	var entry trashedDashboard
+	has, err := sess.Where("org_id = ? AND dashboard_uid = ?", orgID, uid).Get(&entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !has {
+		return nil, nil, dashboards.ErrTrashedDashboardNotFound
+	}
+	var content trashedContent
+	if err := json.Unmarshal([]byte(entry.Data), &content); err != nil {
+		return nil, nil, err
+	}
+	return &entry, &content, nil
+}
+
+// getLinkedTrashedDashboards returns the entries which were moved to trash
+// with a folder, in the order they were moved to trash. They were moved to
+// trash before the folder, a newer entry with the same link belongs to a
+// later delete.
+func getLinkedTrashedDashboards(sess *db.Session, entry *trashedDashboard) ([]*trashedDashboard, error) {
+	var linked []*trashedDashboard
+	err := sess.Where("org_id = ? AND trashed_with = ? AND id < ?", entry.OrgID, entry.DashboardUID, entry.ID).Asc("id").Find(&linked)
+	return linked, err
+}
+
+// purgeTrashedDashboard deletes rows which were kept for restore together
+// with the trash entry and the entries which were moved to trash with it.
+func purgeTrashedDashboard(sess *db.Session, entry *trashedDashboard, content *trashedContent) error {
+	linked, err := getLinkedTrashedDashboards(sess, entry)
+	if err != nil {
+		return err
+	}
+	for _, l := range linked {
+		var linkedContent trashedContent
+		if err := json.Unmarshal([]byte(l.Data), &linkedContent); err != nil {
+			return err
+		}
+		if err := purgeTrashedDashboard(sess, l, &linkedContent); err != nil {
+			return err
+		}
+	}
+
+	ids := make([]int64, 0, len(content.Dashboards))
+	for _, dash := range content.Dashboards {
+		ids = append(ids, dash.Id)
+	}
+	if len(ids) > 0 {
+		for _, table := range []string{"star", "dashboard_version", "annotation"} {
+			if err := deleteIn(sess, table, "dashboard_id", int64Args(ids)); err != nil {
+				return err
+			}
+		}
+		for _, id := range ids {
+			if _, err := sess.Exec("DELETE FROM playlist_item WHERE type = 'dashboard_by_id' AND value = ?", fmt.Sprintf("%d", id)); err != nil {
+				return err
+			}
+		}
+	}
+	publicUIDs := make([]string, 0, len(content.PublicDashboards))
+	for _, pd := range content.PublicDashboards {
+		publicUIDs = append(publicUIDs, pd.UID)
+	}
+	if err := deleteIn(sess, "dashboard_public_usage", "public_dashboard_uid", stringArgs(publicUIDs)); err != nil {
+		return err
+	}
+	_, err = sess.ID(entry.ID).Delete(&trashedDashboard{})
+	return err
+}
+
+func deleteIn(sess *db.Session, table string, column string, args []interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+	sql := "DELETE FROM " + table + " WHERE " + column + " IN (?" + strings.Repeat(",?", len(args)-1) + ")"
+	_, err := sess.Exec(append([]interface{}{sql}, args...)...)
+	return err
+}
+
+func int64Args(values []int64) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
+
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
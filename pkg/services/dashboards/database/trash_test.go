@@ -0,0 +1,316 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+)
+
+func TestIntegrationDashboardTrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	sqlStore.Cfg.DashboardTrashRetention = 24 * time.Hour
+	dashboardStore, err := ProvideDashboardStore(sqlStore, sqlStore.Cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	folder := insertTestDashboard(t, dashboardStore, "trash folder", 1, 0, true)
+	childDash := insertTestDashboard(t, dashboardStore, "trash child", 1, folder.Id, false, "prod")
+	rootDash := insertTestDashboard(t, dashboardStore, "trash root", 1, 0, false)
+	err = updateDashboardACL(t, dashboardStore, folder.Id, models.DashboardACL{
+		DashboardID: folder.Id,
+		OrgID:       1,
+		UserID:      999,
+		Permission:  models.PERMISSION_EDIT,
+	})
+	require.NoError(t, err)
+
+	getDashboard := func(uid string) (*models.Dashboard, error) {
+		return dashboardStore.GetDashboard(ctx, &models.GetDashboardQuery{OrgId: 1, Uid: uid})
+	}
+
+	t.Run("Deleting a folder moves it with its dashboards to trash", func(t *testing.T) {
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: folder.Id, OrgId: 1, MoveToTrash: true})
+		require.NoError(t, err)
+
+		_, err = getDashboard(folder.Uid)
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+		_, err = getDashboard(childDash.Uid)
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		require.Equal(t, folder.Uid, trashed[0].DashboardUID)
+		require.True(t, trashed[0].IsFolder)
+		require.Equal(t, trashed[0].Deleted.Add(24*time.Hour), trashed[0].Expires)
+	})
+
+	t.Run("Restoring a folder restores its dashboards, tags and permissions", func(t *testing.T) {
+		restored, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{OrgID: 1, UID: folder.Uid})
+		require.NoError(t, err)
+		require.Equal(t, folder.Uid, restored.DashboardUID)
+
+		restoredFolder, err := getDashboard(folder.Uid)
+		require.NoError(t, err)
+		require.Equal(t, folder.Id, restoredFolder.Id)
+		restoredChild, err := getDashboard(childDash.Uid)
+		require.NoError(t, err)
+		require.Equal(t, folder.Id, restoredChild.FolderId)
+
+		tagsQuery := &models.GetDashboardTagsQuery{OrgId: 1}
+		require.NoError(t, dashboardStore.GetDashboardTags(ctx, tagsQuery))
+		require.Len(t, tagsQuery.Result, 1)
+		require.Equal(t, "prod", tagsQuery.Result[0].Term)
+
+		aclQuery := &models.GetDashboardACLInfoListQuery{DashboardID: childDash.Id, OrgID: 1}
+		require.NoError(t, dashboardStore.GetDashboardACLInfoList(ctx, aclQuery))
+		require.Len(t, aclQuery.Result, 1)
+		require.Equal(t, int64(999), aclQuery.Result[0].UserId)
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, trashed)
+	})
+
+	t.Run("Restoring fails when a dashboard with the same name was created", func(t *testing.T) {
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: rootDash.Id, OrgId: 1, MoveToTrash: true})
+		require.NoError(t, err)
+		insertTestDashboard(t, dashboardStore, "trash root", 1, 0, false)
+
+		_, err = dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{OrgID: 1, UID: rootDash.Uid})
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameNameInFolderExists)
+	})
+
+	t.Run("Restoring unknown dashboard returns not found", func(t *testing.T) {
+		_, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{OrgID: 1, UID: "unknown"})
+		require.ErrorIs(t, err, dashboards.ErrTrashedDashboardNotFound)
+	})
+
+	t.Run("Purging deletes dashboards kept in trash longer than retention", func(t *testing.T) {
+		cmd := &dashboards.PurgeTrashedDashboardsCommand{OlderThan: time.Now().Add(-time.Hour)}
+		require.NoError(t, dashboardStore.PurgeTrashedDashboards(ctx, cmd))
+		require.Equal(t, int64(0), cmd.DeletedRows)
+
+		cmd = &dashboards.PurgeTrashedDashboardsCommand{OlderThan: time.Now().Add(time.Hour)}
+		require.NoError(t, dashboardStore.PurgeTrashedDashboards(ctx, cmd))
+		require.Equal(t, int64(1), cmd.DeletedRows)
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, trashed)
+	})
+
+	t.Run("Deleting without moving to trash removes dashboard permanently", func(t *testing.T) {
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: childDash.Id, OrgId: 1})
+		require.NoError(t, err)
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, trashed)
+	})
+}
+
+func TestIntegrationDashboardTrashFolderContent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	sqlStore.Cfg.DashboardTrashRetention = 24 * time.Hour
+	dashboardStore, err := ProvideDashboardStore(sqlStore, sqlStore.Cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	folder := insertTestDashboard(t, dashboardStore, "trash folder", 1, 0, true)
+	insertTestRule(t, sqlStore, folder.OrgId, folder.Uid)
+	element := &trashedLibraryElement{OrgID: 1, FolderID: folder.Id, UID: "panel", Name: "panel", Kind: 1, Model: "{}", Version: 1, Created: time.Now(), Updated: time.Now()}
+	err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(element); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&trashedLibraryElementVersion{ElementID: element.ID, Version: 1, Name: "panel", Model: "{}", Created: time.Now()})
+		return err
+	})
+	require.NoError(t, err)
+
+	count := func(table string, where string, args ...interface{}) int64 {
+		var n int64
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			n, err = sess.Table(table).Where(where, args...).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	t.Run("Moving a folder with alert rules to trash requires forcing it", func(t *testing.T) {
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: folder.Id, OrgId: 1, MoveToTrash: true})
+		require.ErrorIs(t, err, dashboards.ErrFolderContainsAlertRules)
+	})
+
+	t.Run("Moving a folder with library elements in use to trash fails", func(t *testing.T) {
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec("INSERT INTO library_element_connection (element_id, kind, connection_id, created, created_by) VALUES (?, 1, 42, ?, 1)", element.ID, time.Now())
+			return err
+		})
+		require.NoError(t, err)
+
+		err = dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: folder.Id, OrgId: 1, MoveToTrash: true, ForceDeleteFolderRules: true})
+		require.ErrorIs(t, err, dashboards.ErrFolderContainsLibraryElementsInUse)
+		require.Equal(t, int64(1), count("alert_rule", "namespace_uid = ?", folder.Uid))
+
+		err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec("DELETE FROM library_element_connection WHERE element_id = ?", element.ID)
+			return err
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Alert rules and library elements are moved to trash and restored with their folder", func(t *testing.T) {
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: folder.Id, OrgId: 1, MoveToTrash: true, ForceDeleteFolderRules: true})
+		require.NoError(t, err)
+		require.Zero(t, count("alert_rule", "namespace_uid = ?", folder.Uid))
+		require.Zero(t, count("alert_rule_version", "rule_namespace_uid = ?", folder.Uid))
+		require.Zero(t, count("library_element", "folder_id = ?", folder.Id))
+		require.Zero(t, count("library_element_version", "element_id = ?", element.ID))
+
+		_, err = dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{OrgID: 1, UID: folder.Uid})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count("alert_rule", "namespace_uid = ?", folder.Uid))
+		require.Equal(t, int64(1), count("alert_rule_version", "rule_namespace_uid = ?", folder.Uid))
+		require.Equal(t, int64(1), count("library_element", "folder_id = ? AND uid = ?", folder.Id, "panel"))
+		require.Equal(t, int64(1), count("library_element_version", "element_id = ?", element.ID))
+	})
+
+	t.Run("Moving a dashboard to trash again purges the previous trash entry", func(t *testing.T) {
+		dash := insertTestDashboard(t, dashboardStore, "trash again", 1, 0, false)
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: dash.Id, OrgId: 1, MoveToTrash: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count("dashboard_version", "dashboard_id = ?", dash.Id))
+
+		cmd := models.SaveDashboardCommand{
+			OrgId: 1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"uid":   dash.Uid,
+				"title": "trash again",
+			}),
+		}
+		recreated, err := dashboardStore.SaveDashboard(ctx, cmd)
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: recreated.Id, OrgId: 1, MoveToTrash: true})
+		require.NoError(t, err)
+
+		require.Zero(t, count("dashboard_version", "dashboard_id = ?", dash.Id))
+		require.Equal(t, int64(1), count("dashboard_trash", "dashboard_uid = ?", dash.Uid))
+	})
+}
+
+func TestIntegrationDashboardTrashLinkedContent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	sqlStore.Cfg.DashboardTrashRetention = 24 * time.Hour
+	dashboardStore, err := ProvideDashboardStore(sqlStore, sqlStore.Cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	parent := insertTestDashboard(t, dashboardStore, "trash parent", 1, 0, true)
+	subfolder := insertTestDashboard(t, dashboardStore, "trash subfolder", 1, 0, true)
+	dash := insertTestDashboard(t, dashboardStore, "trash public", 1, subfolder.Id, false)
+	err = updateDashboardACL(t, dashboardStore, dash.Id, models.DashboardACL{
+		DashboardID: dash.Id,
+		OrgID:       1,
+		UserID:      999,
+		Permission:  models.PERMISSION_EDIT,
+	})
+	require.NoError(t, err)
+	timeSettings := "{}"
+	pd := &trashedPublicDashboard{UID: "public", DashboardUID: dash.Uid, OrgID: 1, TimeSettings: &timeSettings, IsEnabled: true, AccessToken: "token", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(pd)
+		return err
+	})
+	require.NoError(t, err)
+
+	count := func(table string, where string, args ...interface{}) int64 {
+		var n int64
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			n, err = sess.Table(table).Where(where, args...).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return n
+	}
+	aclID := func() int64 {
+		var id int64
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Table("dashboard_acl").Where("dashboard_id = ?", dash.Id).Cols("id").Get(&id)
+			return err
+		})
+		require.NoError(t, err)
+		return id
+	}
+	originalACLID := aclID()
+	require.NotZero(t, originalACLID)
+
+	trash := func() {
+		t.Helper()
+		err := dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: subfolder.Id, OrgId: 1, MoveToTrash: true, TrashedWith: parent.Uid})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(ctx, &models.DeleteDashboardCommand{Id: parent.Id, OrgId: 1, MoveToTrash: true})
+		require.NoError(t, err)
+	}
+
+	t.Run("Subfolders moved to trash with a folder are listed with it", func(t *testing.T) {
+		trash()
+		require.Zero(t, count("dashboard_public", "uid = ?", pd.UID))
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		require.Equal(t, parent.Uid, trashed[0].DashboardUID)
+	})
+
+	t.Run("Restoring a folder restores its subfolders, public dashboards and permissions with their IDs", func(t *testing.T) {
+		_, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{OrgID: 1, UID: parent.Uid})
+		require.NoError(t, err)
+
+		restored, err := dashboardStore.GetDashboard(ctx, &models.GetDashboardQuery{OrgId: 1, Uid: dash.Uid})
+		require.NoError(t, err)
+		require.Equal(t, subfolder.Id, restored.FolderId)
+		require.Equal(t, int64(1), count("dashboard_public", "uid = ? AND dashboard_uid = ? AND access_token = ?", pd.UID, dash.Uid, "token"))
+		require.Equal(t, originalACLID, aclID())
+		require.Zero(t, count("dashboard_trash", "org_id = ?", 1))
+	})
+
+	t.Run("Purging a folder purges its subfolders", func(t *testing.T) {
+		trash()
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec("INSERT INTO dashboard_public_usage (org_id, public_dashboard_uid, day) VALUES (1, ?, '2022-10-01')", pd.UID)
+			return err
+		})
+		require.NoError(t, err)
+
+		cmd := &dashboards.PurgeTrashedDashboardsCommand{OlderThan: time.Now().Add(time.Hour)}
+		require.NoError(t, dashboardStore.PurgeTrashedDashboards(ctx, cmd))
+		require.Equal(t, int64(1), cmd.DeletedRows)
+		require.Zero(t, count("dashboard_trash", "org_id = ?", 1))
+		require.Zero(t, count("dashboard_public_usage", "public_dashboard_uid = ?", pd.UID))
+	})
+}
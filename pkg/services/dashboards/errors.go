@@ -2,7 +2,9 @@ package dashboards
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -125,16 +127,22 @@ var (
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrTrashedDashboardNotFound = DashboardErr{
+		Reason:     "Dashboard not found in trash",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
 
-	ErrFolderNotFound                = errors.New("folder not found")
-	ErrFolderVersionMismatch         = errors.New("the folder has been changed by someone else")
-	ErrFolderTitleEmpty              = errors.New("folder title cannot be empty")
-	ErrFolderWithSameUIDExists       = errors.New("a folder/dashboard with the same uid already exists")
-	ErrFolderInvalidUID              = errors.New("invalid uid for folder provided")
-	ErrFolderSameNameExists          = errors.New("a folder or dashboard in the general folder with the same name already exists")
-	ErrFolderFailedGenerateUniqueUid = errors.New("failed to generate unique folder ID")
-	ErrFolderAccessDenied            = errors.New("access denied to folder")
-	ErrFolderContainsAlertRules      = errors.New("folder contains alert rules")
+	ErrFolderNotFound                     = errors.New("folder not found")
+	ErrFolderVersionMismatch              = errors.New("the folder has been changed by someone else")
+	ErrFolderTitleEmpty                   = errors.New("folder title cannot be empty")
+	ErrFolderWithSameUIDExists            = errors.New("a folder/dashboard with the same uid already exists")
+	ErrFolderInvalidUID                   = errors.New("invalid uid for folder provided")
+	ErrFolderSameNameExists               = errors.New("a folder or dashboard in the general folder with the same name already exists")
+	ErrFolderFailedGenerateUniqueUid      = errors.New("failed to generate unique folder ID")
+	ErrFolderAccessDenied                 = errors.New("access denied to folder")
+	ErrFolderContainsAlertRules           = errors.New("folder contains alert rules")
+	ErrFolderContainsLibraryElementsInUse = errors.New("folder contains library elements that are linked in use")
 )
 
 // DashboardErr represents a dashboard error.
@@ -173,3 +181,12 @@ type UpdatePluginDashboardError struct {
 func (d UpdatePluginDashboardError) Error() string {
 	return "Dashboard belongs to plugin"
 }
+
+// LintError is returned when a dashboard is rejected because of problems found by the dashboard linter.
+type LintError struct {
+	Problems []lint.Problem
+}
+
+func (e LintError) Error() string {
+	return fmt.Sprintf("Dashboard failed linting with %d error(s)", len(e.Problems))
+}
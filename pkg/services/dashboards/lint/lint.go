@@ -0,0 +1,187 @@
+// Package lint checks dashboard JSON models against best-practice rules, such as
+// using datasource template variables or setting units on panels.
+package lint
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Problem is a single rule violation found in a dashboard.
+type Problem struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// PanelID and PanelTitle are set for problems found in a panel.
+	PanelID    int64  `json:"panelId,omitempty"`
+	PanelTitle string `json:"panelTitle,omitempty"`
+	// Variable is set for problems found in a template variable.
+	Variable string `json:"variable,omitempty"`
+}
+
+// Result holds all problems found in a dashboard.
+type Result struct {
+	Problems []Problem `json:"problems"`
+}
+
+// HasErrors returns true if any of the problems has error severity.
+func (r Result) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the problems with error severity.
+func (r Result) Errors() []Problem {
+	errs := make([]Problem, 0)
+	for _, p := range r.Problems {
+		if p.Severity == SeverityError {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+// Rule checks a dashboard for a single kind of problem. Rule and Severity of the
+// problems returned by Check are filled in by the Linter.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(d *Dashboard) []Problem
+}
+
+// Linter runs a set of rules against dashboards.
+type Linter struct {
+	rules []Rule
+}
+
+func New(rules ...Rule) *Linter {
+	return &Linter{rules: rules}
+}
+
+var defaultLinter = New(DefaultRules()...)
+
+// Default returns a linter with all built-in rules.
+func Default() *Linter {
+	return defaultLinter
+}
+
+// Rules returns the rules run by the linter.
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Lint runs all rules against the dashboard model. Problems are sorted by severity,
+// errors first, and then by panel.
+func (l *Linter) Lint(model *simplejson.Json) Result {
+	d := newDashboard(model)
+	problems := make([]Problem, 0)
+	for _, rule := range l.rules {
+		for _, p := range rule.Check(d) {
+			p.Rule = rule.Name
+			p.Severity = rule.Severity
+			problems = append(problems, p)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Severity != problems[j].Severity {
+			return problems[i].Severity == SeverityError
+		}
+		return problems[i].PanelID < problems[j].PanelID
+	})
+	return Result{Problems: problems}
+}
+
+// Dashboard is the dashboard model prepared for rules.
+type Dashboard struct {
+	Model *simplejson.Json
+	// Panels contains all panels, including the ones nested in rows. Row panels
+	// themselves are not included.
+	Panels    []Panel
+	Variables []Variable
+}
+
+type Panel struct {
+	ID    int64
+	Title string
+	Type  string
+	Model *simplejson.Json
+}
+
+// Targets returns the queries of the panel.
+func (p Panel) Targets() []*simplejson.Json {
+	return jsonArray(p.Model.Get("targets"))
+}
+
+type Variable struct {
+	Name  string
+	Type  string
+	Index int
+	Model *simplejson.Json
+}
+
+func newDashboard(model *simplejson.Json) *Dashboard {
+	d := &Dashboard{Model: model}
+
+	addPanels := func(panels []*simplejson.Json) {
+		for _, p := range panels {
+			panel := Panel{
+				ID:    p.Get("id").MustInt64(),
+				Title: p.Get("title").MustString(),
+				Type:  p.Get("type").MustString(),
+				Model: p,
+			}
+			if panel.Type == "row" {
+				continue
+			}
+			d.Panels = append(d.Panels, panel)
+		}
+	}
+
+	for _, p := range jsonArray(model.Get("panels")) {
+		// Panels of collapsed rows are nested in the row panel.
+		if p.Get("type").MustString() == "row" {
+			addPanels(jsonArray(p.Get("panels")))
+		}
+	}
+	addPanels(jsonArray(model.Get("panels")))
+	// Dashboards with schema version older than 16 keep panels in rows.
+	for _, row := range jsonArray(model.Get("rows")) {
+		addPanels(jsonArray(row.Get("panels")))
+	}
+	sort.SliceStable(d.Panels, func(i, j int) bool {
+		return d.Panels[i].ID < d.Panels[j].ID
+	})
+
+	for i, v := range jsonArray(model.GetPath("templating", "list")) {
+		d.Variables = append(d.Variables, Variable{
+			Name:  v.Get("name").MustString(),
+			Type:  v.Get("type").MustString(),
+			Index: i,
+			Model: v,
+		})
+	}
+	return d
+}
+
+func jsonArray(j *simplejson.Json) []*simplejson.Json {
+	items := j.MustArray()
+	result := make([]*simplejson.Json, 0, len(items))
+	for _, item := range items {
+		result = append(result, simplejson.NewFromAny(item))
+	}
+	return result
+}
+
+// isVariableReference returns true if s refers to a template variable, e.g. $ds or ${ds}.
+func isVariableReference(s string) bool {
+	return strings.HasPrefix(s, "$") || strings.HasPrefix(s, "[[")
+}
@@ -0,0 +1,142 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func lintJSON(t *testing.T, rule Rule, dashboard string) []Problem {
+	t.Helper()
+	model, err := simplejson.NewJson([]byte(dashboard))
+	require.NoError(t, err)
+	return New(rule).Lint(model).Problems
+}
+
+func TestRules(t *testing.T) {
+	t.Run("datasource-uid", func(t *testing.T) {
+		problems := lintJSON(t, DatasourceUIDRule, `{
+			"panels": [
+				{"id": 1, "title": "by name", "datasource": "prom", "targets": [{"refId": "A", "datasource": "loki"}]},
+				{"id": 2, "datasource": {"type": "prometheus", "uid": "abc"}, "targets": [{"refId": "A"}]},
+				{"id": 3, "datasource": "$ds"},
+				{"id": 4, "datasource": "-- Grafana --"},
+				{"id": 5, "type": "row", "collapsed": true, "panels": [{"id": 6, "datasource": "influx"}]}
+			],
+			"templating": {"list": [{"name": "job", "type": "query", "datasource": "prom"}]},
+			"annotations": {"list": [{"name": "deploys", "datasource": "elastic"}, {"name": "builtin", "datasource": "-- Grafana --"}]}
+		}`)
+		require.Equal(t, []Problem{
+			{Rule: "datasource-uid", Severity: SeverityError, Message: `Variable refers to datasource "prom" by name instead of UID`, Variable: "job"},
+			{Rule: "datasource-uid", Severity: SeverityError, Message: `Annotation "deploys" refers to datasource "elastic" by name instead of UID`},
+			{Rule: "datasource-uid", Severity: SeverityError, Message: `Panel refers to datasource "prom" by name instead of UID`, PanelID: 1, PanelTitle: "by name"},
+			{Rule: "datasource-uid", Severity: SeverityError, Message: `Query "A" refers to datasource "loki" by name instead of UID`, PanelID: 1, PanelTitle: "by name"},
+			{Rule: "datasource-uid", Severity: SeverityError, Message: `Panel refers to datasource "influx" by name instead of UID`, PanelID: 6},
+		}, problems)
+	})
+
+	t.Run("panel-datasource-variable", func(t *testing.T) {
+		problems := lintJSON(t, PanelDatasourceVariableRule, `{
+			"panels": [
+				{"id": 1, "datasource": {"uid": "abc"}, "targets": [{"refId": "A"}]},
+				{"id": 2, "datasource": {"uid": "${ds}"}, "targets": [{"refId": "A"}]},
+				{"id": 3, "datasource": {"uid": "-- Mixed --"}, "targets": [{"datasource": {"uid": "$ds"}}, {"datasource": {"uid": "def"}}]},
+				{"id": 4, "datasource": {"uid": "-- Mixed --"}, "targets": [{"datasource": {"uid": "$ds"}}]},
+				{"id": 5, "datasource": {"uid": "abc"}},
+				{"id": 6, "datasource": null, "targets": [{"refId": "A"}]},
+				{"id": 7, "datasource": {"uid": "grafana"}, "targets": [{"refId": "A"}]}
+			]
+		}`)
+		require.Equal(t, []Problem{
+			{Rule: "panel-datasource-variable", Severity: SeverityWarning, Message: `Panel queries datasource "abc" directly instead of using a datasource template variable`, PanelID: 1},
+			{Rule: "panel-datasource-variable", Severity: SeverityWarning, Message: `Panel queries datasource "def" directly instead of using a datasource template variable`, PanelID: 3},
+		}, problems)
+	})
+
+	t.Run("panel-units", func(t *testing.T) {
+		problems := lintJSON(t, PanelUnitsRule, `{
+			"panels": [
+				{"id": 1, "type": "timeseries", "fieldConfig": {"defaults": {}}},
+				{"id": 2, "type": "timeseries", "fieldConfig": {"defaults": {"unit": "bytes"}}},
+				{"id": 3, "type": "stat", "fieldConfig": {"defaults": {}, "overrides": [{"properties": [{"id": "unit", "value": "s"}]}]}},
+				{"id": 4, "type": "graph", "yaxes": [{"format": "short"}]},
+				{"id": 5, "type": "graph", "yaxes": [{}]},
+				{"id": 6, "type": "text"}
+			]
+		}`)
+		require.Equal(t, []Problem{
+			{Rule: "panel-units", Severity: SeverityWarning, Message: "Panel has no unit", PanelID: 1},
+			{Rule: "panel-units", Severity: SeverityWarning, Message: "Panel has no unit", PanelID: 5},
+		}, problems)
+	})
+
+	t.Run("deprecated-panel-type", func(t *testing.T) {
+		problems := lintJSON(t, DeprecatedPanelTypeRule, `{
+			"rows": [{"panels": [{"id": 1, "type": "singlestat", "title": "old"}, {"id": 2, "type": "stat"}]}]
+		}`)
+		require.Equal(t, []Problem{
+			{Rule: "deprecated-panel-type", Severity: SeverityWarning, Message: `Panel type "singlestat" is deprecated, use "stat" instead`, PanelID: 1, PanelTitle: "old"},
+		}, problems)
+	})
+
+	t.Run("panel-queries", func(t *testing.T) {
+		problems := lintJSON(t, PanelQueriesRule, `{
+			"panels": [
+				{"id": 1, "targets": [{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}]},
+				{"id": 2, "targets": [{}, {}, {}, {}, {}, {}, {}, {}, {}, {}]}
+			]
+		}`)
+		require.Equal(t, []Problem{
+			{Rule: "panel-queries", Severity: SeverityWarning, Message: "Panel has 11 queries, more than the recommended maximum of 10", PanelID: 1},
+		}, problems)
+	})
+
+	t.Run("unused-variable", func(t *testing.T) {
+		problems := lintJSON(t, UnusedVariableRule, `{
+			"title": "$env overview",
+			"panels": [
+				{"id": 1, "repeat": "instance", "datasource": {"uid": "${ds}"}, "targets": [{"expr": "up{job=\"[[job]]\"}"}]}
+			],
+			"templating": {"list": [
+				{"name": "env", "type": "custom"},
+				{"name": "ds", "type": "datasource"},
+				{"name": "job", "type": "query", "query": "label_values(job)"},
+				{"name": "instance", "type": "query", "query": "label_values(up{job=\"$job\"}, instance)"},
+				{"name": "namespace", "type": "query", "query": "label_values(namespace)"},
+				{"name": "name", "type": "query", "query": "label_values(name) $namespace"},
+				{"name": "filters", "type": "adhoc"},
+				{"name": "self", "type": "query", "query": "$self"}
+			]}
+		}`)
+		require.Equal(t, []Problem{
+			{Rule: "unused-variable", Severity: SeverityWarning, Message: `Variable "name" is not used by the dashboard`, Variable: "name"},
+			{Rule: "unused-variable", Severity: SeverityWarning, Message: `Variable "self" is not used by the dashboard`, Variable: "self"},
+		}, problems)
+	})
+}
+
+func TestLinter(t *testing.T) {
+	model, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 2, "type": "graph", "datasource": "prom", "targets": [{"refId": "A"}], "yaxes": [{"format": "short"}]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	result := Default().Lint(model)
+	require.True(t, result.HasErrors())
+	require.Len(t, result.Errors(), 1)
+
+	rules := make([]string, 0, len(result.Problems))
+	for _, p := range result.Problems {
+		rules = append(rules, p.Rule)
+	}
+	// Errors come first.
+	require.Equal(t, []string{"datasource-uid", "panel-datasource-variable", "deprecated-panel-type"}, rules)
+
+	result = Default().Lint(simplejson.New())
+	require.False(t, result.HasErrors())
+	require.Empty(t, result.Problems)
+}
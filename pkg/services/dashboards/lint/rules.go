@@ -0,0 +1,316 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const maxQueriesPerPanel = 10
+
+// DefaultRules returns all built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		DatasourceUIDRule,
+		PanelDatasourceVariableRule,
+		PanelUnitsRule,
+		DeprecatedPanelTypeRule,
+		PanelQueriesRule,
+		UnusedVariableRule,
+	}
+}
+
+var DatasourceUIDRule = Rule{
+	Name:        "datasource-uid",
+	Description: "Datasources should be referenced by UID, references by name break when the datasource is renamed.",
+	Severity:    SeverityError,
+	Check:       checkDatasourceUIDs,
+}
+
+var PanelDatasourceVariableRule = Rule{
+	Name:        "panel-datasource-variable",
+	Description: "Panels should query datasources through a datasource template variable, so that the dashboard can be used with other datasources.",
+	Severity:    SeverityWarning,
+	Check:       checkPanelDatasourceVariables,
+}
+
+var PanelUnitsRule = Rule{
+	Name:        "panel-units",
+	Description: "Panels displaying numeric values should have a unit.",
+	Severity:    SeverityWarning,
+	Check:       checkPanelUnits,
+}
+
+var DeprecatedPanelTypeRule = Rule{
+	Name:        "deprecated-panel-type",
+	Description: "Deprecated panel types should be replaced by their successors.",
+	Severity:    SeverityWarning,
+	Check:       checkDeprecatedPanelTypes,
+}
+
+var PanelQueriesRule = Rule{
+	Name:        "panel-queries",
+	Description: fmt.Sprintf("Panels should not run more than %d queries.", maxQueriesPerPanel),
+	Severity:    SeverityWarning,
+	Check:       checkPanelQueries,
+}
+
+var UnusedVariableRule = Rule{
+	Name:        "unused-variable",
+	Description: "Template variables should be used by the dashboard.",
+	Severity:    SeverityWarning,
+	Check:       checkUnusedVariables,
+}
+
+// builtInDatasources are the special datasources which can't be templated.
+var builtInDatasources = map[string]bool{
+	"grafana":         true,
+	"-- Grafana --":   true,
+	"-- Mixed --":     true,
+	"-- Dashboard --": true,
+}
+
+type datasourceRef struct {
+	// name is set for legacy references by name.
+	name string
+	uid  string
+}
+
+func (r datasourceRef) isSet() bool {
+	return r.name != "" || r.uid != ""
+}
+
+func (r datasourceRef) String() string {
+	if r.uid != "" {
+		return r.uid
+	}
+	return r.name
+}
+
+func (r datasourceRef) isBuiltIn() bool {
+	return builtInDatasources[r.String()]
+}
+
+func (r datasourceRef) isVariable() bool {
+	return isVariableReference(r.String())
+}
+
+func getDatasourceRef(j *simplejson.Json) datasourceRef {
+	switch v := j.Interface().(type) {
+	case string:
+		return datasourceRef{name: v}
+	case map[string]interface{}:
+		return datasourceRef{uid: j.Get("uid").MustString()}
+	}
+	return datasourceRef{}
+}
+
+func checkDatasourceUIDs(d *Dashboard) []Problem {
+	problems := make([]Problem, 0)
+	isNameRef := func(ref datasourceRef) bool {
+		return ref.name != "" && !ref.isVariable() && !ref.isBuiltIn()
+	}
+
+	for _, panel := range d.Panels {
+		if ref := getDatasourceRef(panel.Model.Get("datasource")); isNameRef(ref) {
+			problems = append(problems, Problem{
+				Message:    fmt.Sprintf("Panel refers to datasource %q by name instead of UID", ref.name),
+				PanelID:    panel.ID,
+				PanelTitle: panel.Title,
+			})
+		}
+		for _, target := range panel.Targets() {
+			if ref := getDatasourceRef(target.Get("datasource")); isNameRef(ref) {
+				problems = append(problems, Problem{
+					Message:    fmt.Sprintf("Query %q refers to datasource %q by name instead of UID", target.Get("refId").MustString(), ref.name),
+					PanelID:    panel.ID,
+					PanelTitle: panel.Title,
+				})
+			}
+		}
+	}
+
+	for _, v := range d.Variables {
+		if ref := getDatasourceRef(v.Model.Get("datasource")); isNameRef(ref) {
+			problems = append(problems, Problem{
+				Message:  fmt.Sprintf("Variable refers to datasource %q by name instead of UID", ref.name),
+				Variable: v.Name,
+			})
+		}
+	}
+
+	for _, annotation := range jsonArray(d.Model.GetPath("annotations", "list")) {
+		if ref := getDatasourceRef(annotation.Get("datasource")); isNameRef(ref) {
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("Annotation %q refers to datasource %q by name instead of UID", annotation.Get("name").MustString(), ref.name),
+			})
+		}
+	}
+	return problems
+}
+
+func checkPanelDatasourceVariables(d *Dashboard) []Problem {
+	problems := make([]Problem, 0)
+	for _, panel := range d.Panels {
+		targets := panel.Targets()
+		if len(targets) == 0 {
+			continue
+		}
+
+		refs := []datasourceRef{getDatasourceRef(panel.Model.Get("datasource"))}
+		if refs[0].String() == "-- Mixed --" {
+			// Each query of mixed panels refers to its own datasource.
+			refs = refs[:0]
+			for _, target := range targets {
+				refs = append(refs, getDatasourceRef(target.Get("datasource")))
+			}
+		}
+
+		for _, ref := range refs {
+			if !ref.isSet() || ref.isVariable() || ref.isBuiltIn() {
+				continue
+			}
+			problems = append(problems, Problem{
+				Message:    fmt.Sprintf("Panel queries datasource %q directly instead of using a datasource template variable", ref),
+				PanelID:    panel.ID,
+				PanelTitle: panel.Title,
+			})
+			break
+		}
+	}
+	return problems
+}
+
+var panelTypesWithUnits = map[string]bool{
+	"timeseries": true,
+	"graph":      true,
+	"stat":       true,
+	"gauge":      true,
+	"bargauge":   true,
+}
+
+func checkPanelUnits(d *Dashboard) []Problem {
+	problems := make([]Problem, 0)
+	for _, panel := range d.Panels {
+		if !panelTypesWithUnits[panel.Type] || panelHasUnit(panel) {
+			continue
+		}
+		problems = append(problems, Problem{
+			Message:    "Panel has no unit",
+			PanelID:    panel.ID,
+			PanelTitle: panel.Title,
+		})
+	}
+	return problems
+}
+
+func panelHasUnit(panel Panel) bool {
+	if panel.Type == "graph" {
+		for _, axis := range jsonArray(panel.Model.Get("yaxes")) {
+			if axis.Get("format").MustString() != "" {
+				return true
+			}
+		}
+	}
+	if panel.Model.GetPath("fieldConfig", "defaults", "unit").MustString() != "" {
+		return true
+	}
+	for _, override := range jsonArray(panel.Model.GetPath("fieldConfig", "overrides")) {
+		for _, property := range jsonArray(override.Get("properties")) {
+			if property.Get("id").MustString() == "unit" && property.Get("value").MustString() != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deprecatedPanelTypes maps deprecated panel types to their replacements.
+var deprecatedPanelTypes = map[string]string{
+	"graph":                    "timeseries",
+	"singlestat":               "stat",
+	"grafana-singlestat-panel": "stat",
+	"table-old":                "table",
+	"grafana-piechart-panel":   "piechart",
+	"grafana-worldmap-panel":   "geomap",
+}
+
+func checkDeprecatedPanelTypes(d *Dashboard) []Problem {
+	problems := make([]Problem, 0)
+	for _, panel := range d.Panels {
+		replacement, ok := deprecatedPanelTypes[panel.Type]
+		if !ok {
+			continue
+		}
+		problems = append(problems, Problem{
+			Message:    fmt.Sprintf("Panel type %q is deprecated, use %q instead", panel.Type, replacement),
+			PanelID:    panel.ID,
+			PanelTitle: panel.Title,
+		})
+	}
+	return problems
+}
+
+func checkPanelQueries(d *Dashboard) []Problem {
+	problems := make([]Problem, 0)
+	for _, panel := range d.Panels {
+		if n := len(panel.Targets()); n > maxQueriesPerPanel {
+			problems = append(problems, Problem{
+				Message:    fmt.Sprintf("Panel has %d queries, more than the recommended maximum of %d", n, maxQueriesPerPanel),
+				PanelID:    panel.ID,
+				PanelTitle: panel.Title,
+			})
+		}
+	}
+	return problems
+}
+
+func checkUnusedVariables(d *Dashboard) []Problem {
+	problems := make([]Problem, 0)
+	if len(d.Variables) == 0 {
+		return problems
+	}
+
+	// Variables can be used anywhere in the dashboard except in their own definition.
+	model := make(map[string]interface{})
+	for k, v := range d.Model.MustMap() {
+		if k != "templating" {
+			model[k] = v
+		}
+	}
+	modelJSON, err := json.Marshal(model)
+	if err != nil {
+		return problems
+	}
+	variablesJSON := make([][]byte, len(d.Variables))
+	for i, v := range d.Variables {
+		if variablesJSON[i], err = v.Model.Encode(); err != nil {
+			return problems
+		}
+	}
+
+	for i, v := range d.Variables {
+		// Ad hoc filters are applied to queries implicitly.
+		if v.Name == "" || v.Type == "adhoc" {
+			continue
+		}
+		name := regexp.QuoteMeta(v.Name)
+		reference := regexp.MustCompile(`\$` + name + `(\W|$)|\$\{` + name + `[}:.\[]|\[\[` + name + `[\]:]|"repeat":"` + name + `"`)
+		used := reference.Match(modelJSON)
+		for j := range variablesJSON {
+			if used {
+				break
+			}
+			used = i != j && reference.Match(variablesJSON[j])
+		}
+		if !used {
+			problems = append(problems, Problem{
+				Message:  fmt.Sprintf("Variable %q is not used by the dashboard", v.Name),
+				Variable: v.Name,
+			})
+		}
+	}
+	return problems
+}
@@ -48,3 +48,39 @@ type CountDashboardsInFolderRequest struct {
 	FolderID int64
 	OrgID    int64
 }
+
+// TrashedDashboard is a deleted dashboard or folder kept in trash until it is
+// restored or purged. Trashed folders include their dashboards. Subfolders of
+// a deleted nested folder have their own entries, TrashedWith links them to
+// the entry of the deleted folder.
+type TrashedDashboard struct {
+	ID           int64     `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID        int64     `json:"-" xorm:"org_id"`
+	DashboardID  int64     `json:"id" xorm:"dashboard_id"`
+	DashboardUID string    `json:"uid" xorm:"dashboard_uid"`
+	Title        string    `json:"title"`
+	IsFolder     bool      `json:"isFolder"`
+	FolderUID    string    `json:"folderUid" xorm:"folder_uid"`
+	TrashedWith  string    `json:"-" xorm:"trashed_with"`
+	Deleted      time.Time `json:"deleted"`
+	Expires      time.Time `json:"expires" xorm:"-"`
+}
+
+type GetTrashedDashboardsQuery struct {
+	OrgID int64
+}
+
+type RestoreDashboardCommand struct {
+	OrgID int64
+	UID   string
+}
+
+type DeleteTrashedDashboardCommand struct {
+	OrgID int64
+	UID   string
+}
+
+type PurgeTrashedDashboardsCommand struct {
+	OlderThan   time.Time
+	DeletedRows int64
+}
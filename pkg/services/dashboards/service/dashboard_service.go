@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -238,6 +239,25 @@ func validateDashboardRefreshInterval(dash *models.Dashboard) error {
 	return nil
 }
 
+// lintDashboard runs the dashboard linter according to mode. Problems are logged, and in reject
+// mode a dashboards.LintError is returned if any of them has error severity.
+func (dr *DashboardServiceImpl) lintDashboard(dash *models.Dashboard, mode setting.DashboardLintMode) error {
+	if mode == "" || mode == setting.DashboardLintOff || dash.IsFolder || dash.Data == nil {
+		return nil
+	}
+
+	result := lint.Default().Lint(dash.Data)
+	for _, p := range result.Problems {
+		dr.log.Warn("Dashboard lint problem", "dashboardUid", dash.Uid, "dashboardTitle", dash.Title,
+			"rule", p.Rule, "severity", p.Severity, "panelId", p.PanelID, "variable", p.Variable, "message", p.Message)
+	}
+
+	if mode == setting.DashboardLintReject && result.HasErrors() {
+		return dashboards.LintError{Problems: result.Errors()}
+	}
+	return nil
+}
+
 func (dr *DashboardServiceImpl) SaveProvisionedDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO,
 	provisioning *models.DashboardProvisioning) (*models.Dashboard, error) {
 	if err := validateDashboardRefreshInterval(dto.Dashboard); err != nil {
@@ -246,6 +266,10 @@ func (dr *DashboardServiceImpl) SaveProvisionedDashboard(ctx context.Context, dt
 		dto.Dashboard.Data.Set("refresh", setting.MinRefreshInterval)
 	}
 
+	if err := dr.lintDashboard(dto.Dashboard, dr.cfg.DashboardLintOnProvisioning); err != nil {
+		return nil, err
+	}
+
 	dto.User = accesscontrol.BackgroundUser("dashboard_provisioning", dto.OrgId, org.RoleAdmin, provisionerPermissions)
 
 	cmd, err := dr.BuildSaveDashboardCommand(ctx, dto, setting.IsLegacyAlertingEnabled(), false)
@@ -337,6 +361,10 @@ func (dr *DashboardServiceImpl) SaveDashboard(ctx context.Context, dto *dashboar
 		dto.Dashboard.Data.Set("refresh", setting.MinRefreshInterval)
 	}
 
+	if err := dr.lintDashboard(dto.Dashboard, dr.cfg.DashboardLintOnSave); err != nil {
+		return nil, err
+	}
+
 	cmd, err := dr.BuildSaveDashboardCommand(ctx, dto, setting.IsLegacyAlertingEnabled(), !allowUiUpdate)
 	if err != nil {
 		return nil, err
@@ -445,7 +473,8 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 			return dashboards.ErrDashboardCannotDeleteProvisionedDashboard
 		}
 	}
-	cmd := &models.DeleteDashboardCommand{OrgId: orgId, Id: dashboardId}
+	// Dashboards deleted by users can be restored from trash.
+	cmd := &models.DeleteDashboardCommand{OrgId: orgId, Id: dashboardId, MoveToTrash: validateProvisionedDashboard}
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
@@ -458,6 +487,10 @@ func (dr *DashboardServiceImpl) ImportDashboard(ctx context.Context, dto *dashbo
 		dto.Dashboard.Data.Set("refresh", setting.MinRefreshInterval)
 	}
 
+	if err := dr.lintDashboard(dto.Dashboard, dr.cfg.DashboardLintOnSave); err != nil {
+		return nil, err
+	}
+
 	cmd, err := dr.BuildSaveDashboardCommand(ctx, dto, false, true)
 	if err != nil {
 		return nil, err
@@ -630,3 +663,19 @@ func (dr DashboardServiceImpl) CountDashboardsInFolder(ctx context.Context, quer
 
 	return dr.dashboardStore.CountDashboardsInFolder(ctx, &dashboards.CountDashboardsInFolderRequest{FolderID: folder.ID, OrgID: u.OrgID})
 }
+
+func (dr *DashboardServiceImpl) GetTrashedDashboards(ctx context.Context, query *dashboards.GetTrashedDashboardsQuery) ([]*dashboards.TrashedDashboard, error) {
+	return dr.dashboardStore.GetTrashedDashboards(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) RestoreDashboard(ctx context.Context, cmd *dashboards.RestoreDashboardCommand) (*dashboards.TrashedDashboard, error) {
+	return dr.dashboardStore.RestoreDashboard(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) DeleteTrashedDashboard(ctx context.Context, cmd *dashboards.DeleteTrashedDashboardCommand) error {
+	return dr.dashboardStore.DeleteTrashedDashboard(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) PurgeTrashedDashboards(ctx context.Context, cmd *dashboards.PurgeTrashedDashboardsCommand) error {
+	return dr.dashboardStore.PurgeTrashedDashboards(ctx, cmd)
+}
@@ -137,6 +137,48 @@ func TestDashboardService(t *testing.T) {
 				require.Error(t, err)
 				require.Equal(t, err.Error(), "alert validation error")
 			})
+
+			t.Run("Should lint dashboards according to the configured mode", func(t *testing.T) {
+				lintedDashboard := func() *models.Dashboard {
+					dash := models.NewDashboard("Dash")
+					dash.SetId(3)
+					dash.Data.Set("panels", []interface{}{
+						map[string]interface{}{"id": 1, "type": "stat", "datasource": "prom", "targets": []interface{}{}},
+					})
+					return dash
+				}
+				origSave, origProvisioning := service.cfg.DashboardLintOnSave, service.cfg.DashboardLintOnProvisioning
+				t.Cleanup(func() {
+					service.cfg.DashboardLintOnSave, service.cfg.DashboardLintOnProvisioning = origSave, origProvisioning
+				})
+
+				service.cfg.DashboardLintOnSave = setting.DashboardLintReject
+				dto.Dashboard = lintedDashboard()
+				dto.User = &user.SignedInUser{UserID: 1}
+				_, err := service.SaveDashboard(context.Background(), dto, false)
+				var lintErr dashboards.LintError
+				require.ErrorAs(t, err, &lintErr)
+				require.Len(t, lintErr.Problems, 1)
+				require.Equal(t, "datasource-uid", lintErr.Problems[0].Rule)
+
+				dto.Dashboard = lintedDashboard()
+				_, err = service.ImportDashboard(context.Background(), dto)
+				require.ErrorAs(t, err, &lintErr)
+
+				service.cfg.DashboardLintOnProvisioning = setting.DashboardLintReject
+				dto.Dashboard = lintedDashboard()
+				_, err = service.SaveProvisionedDashboard(context.Background(), dto, &models.DashboardProvisioning{})
+				require.ErrorAs(t, err, &lintErr)
+
+				// Problems are only logged in warn mode.
+				service.cfg.DashboardLintOnSave = setting.DashboardLintWarn
+				fakeStore.On("ValidateDashboardBeforeSave", mock.Anything, mock.Anything, mock.AnythingOfType("bool")).Return(true, nil).Once()
+				fakeStore.On("GetProvisionedDataByDashboardID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, nil).Once()
+				fakeStore.On("SaveDashboard", mock.Anything, mock.AnythingOfType("models.SaveDashboardCommand")).Return(&models.Dashboard{Data: simplejson.New()}, nil).Once()
+				dto.Dashboard = lintedDashboard()
+				_, err = service.SaveDashboard(context.Background(), dto, false)
+				require.NoError(t, err)
+			})
 		})
 
 		t.Run("Save provisioned dashboard validation", func(t *testing.T) {
@@ -209,8 +251,8 @@ func TestDashboardService(t *testing.T) {
 				require.NoError(t, err)
 			})
 
-			t.Run("DeleteDashboard should delete it", func(t *testing.T) {
-				args := &models.DeleteDashboardCommand{OrgId: 1, Id: 1}
+			t.Run("DeleteDashboard should move it to trash", func(t *testing.T) {
+				args := &models.DeleteDashboardCommand{OrgId: 1, Id: 1, MoveToTrash: true}
 				fakeStore.On("DeleteDashboard", mock.Anything, args).Return(nil).Once()
 				fakeStore.On("GetProvisionedDataByDashboardID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, nil).Once()
 				err := service.DeleteDashboard(context.Background(), 1, 1)
@@ -0,0 +1,168 @@
+// Package templating interpolates the template variables of dashboards on the server, for
+// features which query datasources without a browser.
+package templating
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// variableRegex matches the $var, ${var}, ${var:format} and [[var]] template variable syntaxes.
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::([^\}]+))?\}`)
+
+// Variable holds the values of a template variable.
+type Variable struct {
+	Values []string
+	// Raw is set for custom all values, which are never formatted.
+	Raw bool
+}
+
+// Variables holds the values of the template variables of a dashboard by name.
+type Variables map[string]Variable
+
+// FromDashboard returns the current values of the template variables of a dashboard.
+func FromDashboard(dashboard *simplejson.Json) Variables {
+	vars := make(Variables)
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(variableObj)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		values := StringValues(v.GetPath("current", "value"))
+		if len(values) == 1 && values[0] == "$__all" {
+			if allValue := v.Get("allValue").MustString(); allValue != "" {
+				vars[name] = Variable{Values: []string{allValue}, Raw: true}
+				continue
+			}
+			values = values[:0]
+			for _, option := range v.Get("options").MustArray() {
+				if value := simplejson.NewFromAny(option).Get("value").MustString(); value != "$__all" {
+					values = append(values, value)
+				}
+			}
+		}
+		vars[name] = Variable{Values: values}
+	}
+	return vars
+}
+
+// StringValues returns a string or array value, such as the current value of a variable, as strings.
+func StringValues(j *simplejson.Json) []string {
+	switch v := j.Interface().(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	case nil:
+		return []string{}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// InterpolateValue replaces the template variables in all strings of a JSON value.
+func (vars Variables) InterpolateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return vars.Interpolate(v)
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[key] = vars.InterpolateValue(item)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = vars.InterpolateValue(item)
+		}
+		return res
+	}
+	return value
+}
+
+// Interpolate replaces the template variables in a string. Built-in variables, like
+// $__interval, are left for the datasources to replace. Multiple values are formatted
+// as glob unless a format is given, datasources which format them differently in the
+// browser should use an explicit format, such as ${var:regex}.
+func (vars Variables) Interpolate(s string) string {
+	if !strings.ContainsAny(s, "$[") {
+		return s
+	}
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		if groups[2] != "" {
+			name, format = groups[2], groups[3]
+		} else if groups[4] != "" {
+			name, format = groups[4], groups[5]
+		}
+
+		v, ok := vars[name]
+		if !ok || strings.HasPrefix(name, "__") {
+			return match
+		}
+		if v.Raw {
+			return v.Values[0]
+		}
+		return formatValues(name, v.Values, format)
+	})
+}
+
+func formatValues(name string, values []string, format string) string {
+	switch format {
+	case "csv", "raw":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, value := range values {
+			escaped[i] = regexp.QuoteMeta(value)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "json":
+		var b []byte
+		if len(values) == 1 {
+			b, _ = json.Marshal(values[0])
+		} else {
+			b, _ = json.Marshal(values)
+		}
+		return string(b)
+	case "singlequote", "doublequote":
+		quote := "'"
+		if format == "doublequote" {
+			quote = `"`
+		}
+		quoted := make([]string, len(values))
+		for i, value := range values {
+			quoted[i] = quote + strings.ReplaceAll(value, quote, `\`+quote) + quote
+		}
+		return strings.Join(quoted, ",")
+	case "queryparam":
+		params := make([]string, len(values))
+		for i, value := range values {
+			params[i] = "var-" + url.QueryEscape(name) + "=" + url.QueryEscape(value)
+		}
+		return strings.Join(params, "&")
+	}
+
+	if len(values) == 1 {
+		return values[0]
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
@@ -0,0 +1,42 @@
+package templating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestInterpolate(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{"templating": {"list": [
+		{"name": "single", "current": {"value": "a.b"}},
+		{"name": "multi", "current": {"value": ["a", "b"]}},
+		{"name": "all", "current": {"value": ["$__all"]}, "options": [{"value": "$__all"}, {"value": "x"}, {"value": "y"}]},
+		{"name": "custom", "current": {"value": "$__all"}, "allValue": ".*"}
+	]}}`))
+	require.NoError(t, err)
+	vars := FromDashboard(dashboard)
+
+	tests := map[string]string{
+		"$single":                "a.b",
+		"[[single]]":             "a.b",
+		"${single:regex}":        `a\.b`,
+		"$multi":                 "{a,b}",
+		"${multi:csv}":           "a,b",
+		"${multi:pipe}":          "a|b",
+		"${multi:regex}":         "(a|b)",
+		"${multi:json}":          `["a","b"]`,
+		"${multi:singlequote}":   "'a','b'",
+		"${multi:queryparam}":    "var-multi=a&var-multi=b",
+		"[[multi:doublequote]]":  `"a","b"`,
+		"${all:pipe}":            "x|y",
+		"${custom:regex}":        ".*",
+		"$unknown $__from":       "$unknown $__from",
+		"job=$single,$multi end": "job=a.b,{a,b} end",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, vars.Interpolate(input), input)
+	}
+}
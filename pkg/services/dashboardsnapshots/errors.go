@@ -4,4 +4,7 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
-var ErrBaseNotFound = errutil.NewBase(errutil.StatusNotFound, "dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+var (
+	ErrBaseNotFound     = errutil.NewBase(errutil.StatusNotFound, "dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+	ErrInvalidTimeRange = errutil.NewBase(errutil.StatusBadRequest, "dashboardsnapshots.invalid-time-range", errutil.WithPublicMessage("Invalid snapshot time range"))
+)
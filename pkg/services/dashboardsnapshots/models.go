@@ -7,6 +7,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
+// TokenHeader carries the token of a Grafana instance publishing snapshots to a Grafana
+// instance in public mode.
+const TokenHeader = "X-Grafana-Snapshot-Token"
+
 // DashboardSnapshot model
 type DashboardSnapshot struct {
 	Id                int64
@@ -81,6 +85,39 @@ type CreateDashboardSnapshotCommand struct {
 	Result *DashboardSnapshot
 }
 
+// swagger:model
+type CreateDashboardSnapshotByUIDCommand struct {
+	// Snapshot name, defaults to the dashboard title.
+	// required:false
+	Name string `json:"name"`
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+	// Start of the time range of the snapshot, in epoch milliseconds or relative using Grafana time units. Defaults to the time range of the dashboard.
+	// required:false
+	// example: now-6h
+	From string `json:"from"`
+	// End of the time range of the snapshot. Defaults to the time range of the dashboard.
+	// required:false
+	// example: now
+	To string `json:"to"`
+	// Save the snapshot on an external server rather than locally.
+	// required:false
+	// default: false
+	External bool `json:"external"`
+}
+
+// RenderSnapshotCommand renders the snapshot of a dashboard with the data of the given
+// time range. The time range of the dashboard is used when From and To are empty.
+type RenderSnapshotCommand struct {
+	Dashboard *simplejson.Json
+	From      string
+	To        string
+	// User runs the panel queries.
+	User *user.SignedInUser
+}
+
 type DeleteDashboardSnapshotCommand struct {
 	DeleteKey string `json:"-"`
 }
@@ -2,6 +2,8 @@ package dashboardsnapshots
 
 import (
 	"context"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
 //go:generate mockery --name Service --structname MockService --inpackage --filename service_mock.go
@@ -12,3 +14,9 @@ type Service interface {
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) error
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) error
 }
+
+// Renderer creates the snapshot of a dashboard on the server, by running the queries of
+// all panels and embedding the results into the dashboard.
+type Renderer interface {
+	RenderSnapshot(context.Context, *RenderSnapshotCommand) (*simplejson.Json, error)
+}
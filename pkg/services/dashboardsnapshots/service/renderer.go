@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+const (
+	// defaultMaxDataPoints is used for panels without maxDataPoints, the browser uses the
+	// width of the panel instead.
+	defaultMaxDataPoints = 1000
+
+	mixedDatasourceUID     = "-- Mixed --"
+	dashboardDatasourceUID = "-- Dashboard --"
+)
+
+// queryDataService is implemented by query.Service.
+type queryDataService interface {
+	QueryData(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error)
+}
+
+type RendererImpl struct {
+	queryDataService   queryDataService
+	dataSourceService  datasources.DataSourceService
+	intervalCalculator intervalv2.Calculator
+	log                log.Logger
+}
+
+// RendererImpl implements the dashboardsnapshots Renderer interface
+var _ dashboardsnapshots.Renderer = (*RendererImpl)(nil)
+
+func ProvideRenderer(queryDataService *query.Service, dataSourceService datasources.DataSourceService) *RendererImpl {
+	return newRenderer(queryDataService, dataSourceService)
+}
+
+func newRenderer(queryDataService queryDataService, dataSourceService datasources.DataSourceService) *RendererImpl {
+	return &RendererImpl{
+		queryDataService:   queryDataService,
+		dataSourceService:  dataSourceService,
+		intervalCalculator: intervalv2.NewCalculator(),
+		log:                log.New("dashboardsnapshots.renderer"),
+	}
+}
+
+// RenderSnapshot runs the queries of all panels for the time range of the command and
+// returns a copy of the dashboard with the results embedded as panel snapshot data. Queries,
+// links and datasources are removed from the copy the same way the browser does when
+// sharing a snapshot. Failed queries don't fail the snapshot, their error is embedded as a
+// notice instead.
+func (r *RendererImpl) RenderSnapshot(ctx context.Context, cmd *dashboardsnapshots.RenderSnapshotCommand) (*simplejson.Json, error) {
+	dashboard, err := copyJSON(cmd.Dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := cmd.From, cmd.To
+	if from == "" {
+		from = dashboard.GetPath("time", "from").MustString("now-6h")
+	}
+	if to == "" {
+		to = dashboard.GetPath("time", "to").MustString("now")
+	}
+	timeRange, err := parseTimeRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := templating.FromDashboard(dashboard)
+	panels := getPanels(dashboard)
+	snapshotData := make(map[int64][]frameDTO, len(panels))
+	var dashboardPanels []*simplejson.Json
+	for _, panel := range panels {
+		if len(panel.Get("targets").MustArray()) == 0 {
+			continue
+		}
+		if getDatasourceUID(panel.Get("datasource")) == dashboardDatasourceUID {
+			// Panels reusing the results of another panel are rendered last.
+			dashboardPanels = append(dashboardPanels, panel)
+			continue
+		}
+
+		frames := r.renderPanel(ctx, cmd.User, panel, timeRange, variables)
+		snapshotData[panel.Get("id").MustInt64()] = frames
+		panel.Set("snapshotData", frames)
+	}
+	for _, panel := range dashboardPanels {
+		sourceID := simplejson.NewFromAny(panel.Get("targets").MustArray()[0]).Get("panelId").MustInt64()
+		frames, ok := snapshotData[sourceID]
+		if !ok {
+			frames = []frameDTO{}
+		}
+		panel.Set("snapshotData", frames)
+	}
+
+	scrubDashboard(dashboard, panels)
+	dashboard.Set("time", map[string]interface{}{
+		"from": timeRange.From.Format(time.RFC3339Nano),
+		"to":   timeRange.To.Format(time.RFC3339Nano),
+	})
+	dashboard.Set("snapshot", map[string]interface{}{
+		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+	})
+
+	// Converts the frames to plain JSON values, just like a snapshot sent by the browser.
+	return copyJSON(dashboard)
+}
+
+func (r *RendererImpl) renderPanel(ctx context.Context, u *user.SignedInUser, panel *simplejson.Json, timeRange backend.TimeRange, variables templating.Variables) []frameDTO {
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	minInterval := time.Millisecond
+	if v := variables.Interpolate(panel.Get("interval").MustString()); v != "" {
+		if parsed, err := intervalv2.ParseIntervalStringToTimeDuration(v); err == nil && parsed > minInterval {
+			minInterval = parsed
+		}
+	}
+	interval := r.intervalCalculator.Calculate(timeRange, minInterval, maxDataPoints)
+
+	panelDatasource := variables.InterpolateValue(panel.Get("datasource").Interface())
+	hidden := make(map[string]bool)
+	hasExpression := false
+	queries := make([]*simplejson.Json, 0)
+	frames := make([]frameDTO, 0)
+	for _, target := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(variables.InterpolateValue(target))
+		refID := query.Get("refId").MustString("A")
+
+		ref := query.Get("datasource").Interface()
+		if ref == nil && getDatasourceUID(simplejson.NewFromAny(panelDatasource)) != mixedDatasourceUID {
+			ref = panelDatasource
+		}
+		datasource, err := r.resolveDatasource(ctx, u, simplejson.NewFromAny(ref))
+		if err != nil {
+			frames = append(frames, errorFrame(refID, err))
+			continue
+		}
+		if expr.IsDataSource(datasource["uid"].(string)) {
+			hasExpression = true
+		}
+
+		query.Set("refId", refID)
+		query.Set("datasource", datasource)
+		query.Set("maxDataPoints", maxDataPoints)
+		query.Set("intervalMs", interval.Milliseconds())
+		hidden[refID] = query.Get("hide").MustBool()
+		queries = append(queries, query)
+	}
+
+	if !hasExpression {
+		// Hidden queries are only needed as inputs of expressions.
+		visible := queries[:0]
+		for _, query := range queries {
+			if !hidden[query.Get("refId").MustString()] {
+				visible = append(visible, query)
+			}
+		}
+		queries = visible
+	}
+	if len(queries) == 0 {
+		return frames
+	}
+
+	res, err := r.queryDataService.QueryData(ctx, u, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(timeRange.From.UnixMilli(), 10),
+		To:      strconv.FormatInt(timeRange.To.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		r.log.Warn("Failed to query panel data for snapshot", "panelId", panel.Get("id").MustInt64(), "error", err)
+		for _, query := range queries {
+			frames = append(frames, errorFrame(query.Get("refId").MustString(), err))
+		}
+		return frames
+	}
+
+	for _, query := range queries {
+		refID := query.Get("refId").MustString()
+		if hidden[refID] {
+			continue
+		}
+		resp, ok := res.Responses[refID]
+		if !ok {
+			continue
+		}
+		if resp.Error != nil {
+			frames = append(frames, errorFrame(refID, resp.Error))
+			continue
+		}
+		for _, frame := range resp.Frames {
+			frames = append(frames, toFrameDTO(refID, frame))
+		}
+	}
+	return frames
+}
+
+// resolveDatasource returns the reference by UID of the datasource of a query, datasources
+// referenced by name and queries without datasource are resolved as well.
+func (r *RendererImpl) resolveDatasource(ctx context.Context, u *user.SignedInUser, ref *simplejson.Json) (map[string]interface{}, error) {
+	uid := getDatasourceUID(ref)
+	switch uid {
+	case "", "default":
+		query := &datasources.GetDefaultDataSourceQuery{OrgId: u.OrgID, User: u}
+		if err := r.dataSourceService.GetDefaultDataSource(ctx, query); err != nil {
+			return nil, fmt.Errorf("failed to get default datasource: %w", err)
+		}
+		return map[string]interface{}{"uid": query.Result.Uid, "type": query.Result.Type}, nil
+	case grafanads.DatasourceUID, grafanads.DatasourceName:
+		return map[string]interface{}{"uid": grafanads.DatasourceUID, "type": "datasource"}, nil
+	}
+	if expr.IsDataSource(uid) {
+		return map[string]interface{}{"uid": expr.DatasourceUID, "type": expr.DatasourceType}, nil
+	}
+
+	// Legacy references and the values of datasource variables may be names.
+	query := &datasources.GetDataSourceQuery{Uid: uid, OrgId: u.OrgID}
+	err := r.dataSourceService.GetDataSource(ctx, query)
+	if errors.Is(err, datasources.ErrDataSourceNotFound) {
+		query = &datasources.GetDataSourceQuery{Name: uid, OrgId: u.OrgID}
+		err = r.dataSourceService.GetDataSource(ctx, query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource %q: %w", uid, err)
+	}
+	return map[string]interface{}{"uid": query.Result.Uid, "type": query.Result.Type}, nil
+}
+
+func parseTimeRange(from, to string) (backend.TimeRange, error) {
+	tr := legacydata.NewDataTimeRange(from, to)
+	fromTime, err := tr.ParseFrom()
+	if err != nil {
+		return backend.TimeRange{}, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid from %q: %w", from, err)
+	}
+	toTime, err := tr.ParseTo()
+	if err != nil {
+		return backend.TimeRange{}, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid to %q: %w", to, err)
+	}
+	if !fromTime.Before(toTime) {
+		return backend.TimeRange{}, dashboardsnapshots.ErrInvalidTimeRange.Errorf("from %q is not before to %q", from, to)
+	}
+	return backend.TimeRange{From: fromTime.UTC(), To: toTime.UTC()}, nil
+}
+
+// getPanels returns all panels of the dashboard, including the panels of collapsed rows.
+func getPanels(dashboard *simplejson.Json) []*simplejson.Json {
+	panels := make([]*simplejson.Json, 0)
+	for _, panelObj := range dashboard.Get("panels").MustArray() {
+		panel := simplejson.NewFromAny(panelObj)
+		panels = append(panels, panel)
+		for _, rowPanelObj := range panel.Get("panels").MustArray() {
+			panels = append(panels, simplejson.NewFromAny(rowPanelObj))
+		}
+	}
+	return panels
+}
+
+func getDatasourceUID(ref *simplejson.Json) string {
+	uid := ref.Get("uid").MustString()
+
+	// before 8.3 datasources were referenced by name
+	if uid == "" {
+		uid = ref.MustString()
+	}
+
+	return uid
+}
+
+// scrubDashboard removes everything but the snapshot data from the dashboard, so that the
+// snapshot doesn't reveal the queries of the dashboard.
+func scrubDashboard(dashboard *simplejson.Json, panels []*simplejson.Json) {
+	for _, panel := range panels {
+		panel.Set("targets", []interface{}{})
+		panel.Set("links", []interface{}{})
+		panel.Set("datasource", nil)
+	}
+
+	annotations := make([]interface{}, 0)
+	for _, annotationObj := range dashboard.GetPath("annotations", "list").MustArray() {
+		annotation := simplejson.NewFromAny(annotationObj)
+		if !annotation.Get("enable").MustBool() {
+			continue
+		}
+		annotations = append(annotations, map[string]interface{}{
+			"name":         annotation.Get("name").Interface(),
+			"enable":       true,
+			"iconColor":    annotation.Get("iconColor").Interface(),
+			"snapshotData": []interface{}{},
+			"type":         annotation.Get("type").Interface(),
+			"builtIn":      annotation.Get("builtIn").Interface(),
+			"hide":         annotation.Get("hide").Interface(),
+		})
+	}
+	dashboard.SetPath([]string{"annotations", "list"}, annotations)
+
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		options := []interface{}{}
+		if current, ok := variable.CheckGet("current"); ok {
+			options = append(options, current.Interface())
+		}
+		variable.Set("query", "")
+		variable.Set("options", options)
+		variable.Set("refresh", 0)
+	}
+}
+
+func copyJSON(j *simplejson.Json) (*simplejson.Json, error) {
+	b, err := j.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return simplejson.NewJson(b)
+}
+
+// frameDTO is the JSON representation of data frames used by snapshots.
+type frameDTO struct {
+	Name   string          `json:"name,omitempty"`
+	RefID  string          `json:"refId,omitempty"`
+	Meta   *data.FrameMeta `json:"meta,omitempty"`
+	Fields []fieldDTO      `json:"fields"`
+}
+
+type fieldDTO struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Config *data.FieldConfig `json:"config,omitempty"`
+	Labels data.Labels       `json:"labels,omitempty"`
+	Values []interface{}     `json:"values"`
+}
+
+func toFrameDTO(refID string, frame *data.Frame) frameDTO {
+	dto := frameDTO{
+		Name:   frame.Name,
+		RefID:  frame.RefID,
+		Meta:   frame.Meta,
+		Fields: make([]fieldDTO, 0, len(frame.Fields)),
+	}
+	if dto.RefID == "" {
+		dto.RefID = refID
+	}
+
+	for _, field := range frame.Fields {
+		values := make([]interface{}, field.Len())
+		for i := range values {
+			values[i] = fieldValue(field, i)
+		}
+		dto.Fields = append(dto.Fields, fieldDTO{
+			Name:   field.Name,
+			Type:   fieldType(field.Type()),
+			Config: field.Config,
+			Labels: field.Labels,
+			Values: values,
+		})
+	}
+	return dto
+}
+
+func errorFrame(refID string, err error) frameDTO {
+	return frameDTO{
+		RefID: refID,
+		Meta: &data.FrameMeta{
+			Notices: []data.Notice{{Severity: data.NoticeSeverityError, Text: err.Error()}},
+		},
+		Fields: []fieldDTO{},
+	}
+}
+
+// fieldType returns the type of a field as named by the frontend.
+func fieldType(t data.FieldType) string {
+	switch {
+	case t.Time():
+		return "time"
+	case t.Numeric():
+		return "number"
+	case t == data.FieldTypeString || t == data.FieldTypeNullableString:
+		return "string"
+	case t == data.FieldTypeBool || t == data.FieldTypeNullableBool:
+		return "boolean"
+	}
+	return "other"
+}
+
+// fieldValue returns the value of a field as JSON value, times are returned as epoch
+// milliseconds.
+func fieldValue(field *data.Field, idx int) interface{} {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case time.Time:
+		return v.UnixMilli()
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return nil
+		}
+	}
+	return v
+}
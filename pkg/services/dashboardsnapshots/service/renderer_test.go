@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeQueryDataService struct {
+	requests []dtos.MetricRequest
+	err      error
+}
+
+func (s *fakeQueryDataService) QueryData(_ context.Context, _ *user.SignedInUser, _ bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	s.requests = append(s.requests, reqDTO)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	res := backend.NewQueryDataResponse()
+	for _, query := range reqDTO.Queries {
+		refID := query.Get("refId").MustString()
+		res.Responses[refID] = backend.DataResponse{Frames: data.Frames{
+			data.NewFrame("series",
+				data.NewField("time", nil, []time.Time{time.UnixMilli(1000), time.UnixMilli(2000)}),
+				data.NewField("value", data.Labels{"job": "a"}, []*float64{float64Ptr(1), nil}),
+			),
+		}}
+	}
+	return res, nil
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+const testSnapshotDashboard = `{
+	"uid": "dash",
+	"time": {"from": "now-6h", "to": "now"},
+	"annotations": {"list": [
+		{"name": "Annotations & Alerts", "enable": true, "builtIn": 1, "datasource": {"uid": "grafana"}},
+		{"name": "Deploys", "enable": false, "datasource": {"uid": "prom"}}
+	]},
+	"templating": {"list": [
+		{"name": "job", "type": "query", "query": "label_values(job)", "current": {"text": ["a", "b"], "value": ["a", "b"]}, "refresh": 1},
+		{"name": "ds", "type": "datasource", "query": "prometheus", "current": {"text": "Prometheus", "value": "Prometheus"}}
+	]},
+	"links": [{"title": "Docs"}],
+	"panels": [
+		{
+			"id": 1,
+			"type": "timeseries",
+			"datasource": {"uid": "${ds}"},
+			"maxDataPoints": 100,
+			"links": [{"title": "Details"}],
+			"targets": [
+				{"refId": "A", "expr": "up{job=~\"${job:regex}\"}"},
+				{"refId": "B", "expr": "down", "hide": true}
+			]
+		},
+		{
+			"id": 2,
+			"type": "row",
+			"collapsed": true,
+			"panels": [
+				{"id": 3, "type": "table", "targets": [{"refId": "A", "expr": "$__interval"}]}
+			]
+		},
+		{
+			"id": 4,
+			"type": "stat",
+			"datasource": {"uid": "-- Dashboard --"},
+			"targets": [{"refId": "A", "panelId": 1}]
+		},
+		{"id": 5, "type": "text"}
+	]
+}`
+
+func TestRenderSnapshot(t *testing.T) {
+	dataSources := &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{Uid: "prom", Name: "Prometheus", Type: "prometheus", OrgId: 1},
+		{Uid: "loki", Name: "Loki", Type: "loki", OrgId: 1, IsDefault: true},
+	}}
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+	from, to := time.UnixMilli(0).UTC(), time.UnixMilli(3600*1000).UTC()
+
+	t.Run("should embed the query results and remove the queries", func(t *testing.T) {
+		queryData := &fakeQueryDataService{}
+		dashboard, err := simplejson.NewJson([]byte(testSnapshotDashboard))
+		require.NoError(t, err)
+
+		snapshot, err := newRenderer(queryData, dataSources).RenderSnapshot(context.Background(), &dashboardsnapshots.RenderSnapshotCommand{
+			Dashboard: dashboard,
+			From:      "0",
+			To:        "3600000",
+			User:      signedInUser,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, queryData.requests, 2)
+		req := queryData.requests[0]
+		assert.Equal(t, "0", req.From)
+		assert.Equal(t, "3600000", req.To)
+		require.Len(t, req.Queries, 1, "hidden queries are not run")
+		query := req.Queries[0]
+		assert.Equal(t, `up{job=~"(a|b)"}`, query.Get("expr").MustString())
+		assert.Equal(t, "prom", query.GetPath("datasource", "uid").MustString())
+		assert.Equal(t, int64(100), query.Get("maxDataPoints").MustInt64())
+		assert.Equal(t, int64(30000), query.Get("intervalMs").MustInt64())
+
+		rowQuery := queryData.requests[1].Queries[0]
+		assert.Equal(t, "$__interval", rowQuery.Get("expr").MustString(), "built-in variables are left to the datasource")
+		assert.Equal(t, "loki", rowQuery.GetPath("datasource", "uid").MustString(), "panels without datasource use the default")
+
+		panels := snapshot.Get("panels")
+		frame := panels.GetIndex(0).Get("snapshotData").GetIndex(0)
+		assert.Equal(t, "series", frame.Get("name").MustString())
+		assert.Equal(t, "A", frame.Get("refId").MustString())
+		assert.Equal(t, "time", frame.Get("fields").GetIndex(0).Get("type").MustString())
+		assert.Equal(t, int64(1000), frame.Get("fields").GetIndex(0).Get("values").GetIndex(0).MustInt64())
+		assert.Equal(t, int64(2000), frame.Get("fields").GetIndex(0).Get("values").GetIndex(1).MustInt64())
+		assert.Equal(t, "number", frame.Get("fields").GetIndex(1).Get("type").MustString())
+		assert.Equal(t, 1.0, frame.Get("fields").GetIndex(1).Get("values").GetIndex(0).MustFloat64())
+		assert.Nil(t, frame.Get("fields").GetIndex(1).Get("values").GetIndex(1).Interface())
+		assert.Equal(t, "a", frame.Get("fields").GetIndex(1).GetPath("labels", "job").MustString())
+
+		assert.Len(t, panels.GetIndex(1).Get("panels").GetIndex(0).Get("snapshotData").MustArray(), 1)
+		assert.Equal(t, panels.GetIndex(0).Get("snapshotData").Interface(), panels.GetIndex(2).Get("snapshotData").Interface(),
+			"panels using the dashboard datasource reuse the results of their source panel")
+		_, ok := panels.GetIndex(3).CheckGet("snapshotData")
+		assert.False(t, ok)
+
+		for _, panel := range []*simplejson.Json{panels.GetIndex(0), panels.GetIndex(1).Get("panels").GetIndex(0)} {
+			assert.Empty(t, panel.Get("targets").MustArray())
+			assert.Empty(t, panel.Get("links").MustArray())
+			assert.Nil(t, panel.Get("datasource").Interface())
+		}
+
+		variable := snapshot.GetPath("templating", "list").GetIndex(0)
+		assert.Equal(t, "", variable.Get("query").MustString())
+		assert.Equal(t, 0, variable.Get("refresh").MustInt())
+		assert.Len(t, variable.Get("options").MustArray(), 1)
+
+		annotations := snapshot.GetPath("annotations", "list").MustArray()
+		require.Len(t, annotations, 1)
+		assert.Nil(t, simplejson.NewFromAny(annotations[0]).Get("datasource").Interface())
+
+		assert.Equal(t, from.Format(time.RFC3339Nano), snapshot.GetPath("time", "from").MustString())
+		assert.Equal(t, to.Format(time.RFC3339Nano), snapshot.GetPath("time", "to").MustString())
+		assert.NotEmpty(t, snapshot.GetPath("snapshot", "timestamp").MustString())
+
+		assert.Equal(t, "${ds}", dashboard.GetPath("panels").GetIndex(0).GetPath("datasource", "uid").MustString(), "the dashboard is not modified")
+	})
+
+	t.Run("should embed query errors as notices", func(t *testing.T) {
+		queryData := &fakeQueryDataService{err: errors.New("datasource is down")}
+		dashboard, err := simplejson.NewJson([]byte(testSnapshotDashboard))
+		require.NoError(t, err)
+
+		snapshot, err := newRenderer(queryData, dataSources).RenderSnapshot(context.Background(), &dashboardsnapshots.RenderSnapshotCommand{
+			Dashboard: dashboard,
+			User:      signedInUser,
+		})
+		require.NoError(t, err)
+
+		frame := snapshot.Get("panels").GetIndex(0).Get("snapshotData").GetIndex(0)
+		assert.Equal(t, "A", frame.Get("refId").MustString())
+		notice := frame.GetPath("meta", "notices").GetIndex(0)
+		assert.Equal(t, "error", notice.Get("severity").MustString())
+		assert.Equal(t, "datasource is down", notice.Get("text").MustString())
+	})
+
+	t.Run("should fail for an invalid time range", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(testSnapshotDashboard))
+		require.NoError(t, err)
+
+		_, err = newRenderer(&fakeQueryDataService{}, dataSources).RenderSnapshot(context.Background(), &dashboardsnapshots.RenderSnapshotCommand{
+			Dashboard: dashboard,
+			From:      "now",
+			To:        "now-1h",
+			User:      signedInUser,
+		})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidTimeRange)
+	})
+}
+
+func TestRenderSnapshotInterpolatesVariables(t *testing.T) {
+	tests := map[string]string{
+		"$single":                "a.b",
+		"[[single]]":             "a.b",
+		"${single:regex}":        `a\.b`,
+		"$multi":                 "{a,b}",
+		"${multi:csv}":           "a,b",
+		"${multi:pipe}":          "a|b",
+		"${multi:regex}":         "(a|b)",
+		"${multi:json}":          `["a","b"]`,
+		"${multi:singlequote}":   "'a','b'",
+		"${multi:queryparam}":    "var-multi=a&var-multi=b",
+		"[[multi:doublequote]]":  `"a","b"`,
+		"${all:pipe}":            "x|y",
+		"${custom:regex}":        ".*",
+		"$unknown $__from":       "$unknown $__from",
+		"job=$single,$multi end": "job=a.b,{a,b} end",
+	}
+
+	inputs := map[string]string{}
+	targets := make([]interface{}, 0, len(tests))
+	for input := range tests {
+		refID := fmt.Sprintf("Q%d", len(targets))
+		inputs[refID] = input
+		targets = append(targets, map[string]interface{}{"refId": refID, "expr": input})
+	}
+	dashboard, err := simplejson.NewJson([]byte(`{"templating": {"list": [
+		{"name": "single", "current": {"value": "a.b"}},
+		{"name": "multi", "current": {"value": ["a", "b"]}},
+		{"name": "all", "current": {"value": ["$__all"]}, "options": [{"value": "$__all"}, {"value": "x"}, {"value": "y"}]},
+		{"name": "custom", "current": {"value": "$__all"}, "allValue": ".*"}
+	]}}`))
+	require.NoError(t, err)
+	dashboard.Set("panels", []interface{}{
+		map[string]interface{}{"id": 1, "type": "timeseries", "datasource": map[string]interface{}{"uid": "prom"}, "targets": targets},
+	})
+
+	queryData := &fakeQueryDataService{}
+	dataSources := &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{Uid: "prom", Name: "Prometheus", Type: "prometheus", OrgId: 1},
+	}}
+	_, err = newRenderer(queryData, dataSources).RenderSnapshot(context.Background(), &dashboardsnapshots.RenderSnapshotCommand{
+		Dashboard: dashboard,
+		From:      "0",
+		To:        "3600000",
+		User:      &user.SignedInUser{UserID: 1, OrgID: 1},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, queryData.requests, 1)
+	require.Len(t, queryData.requests[0].Queries, len(tests))
+	for _, query := range queryData.requests[0].Queries {
+		input := inputs[query.Get("refId").MustString()]
+		assert.Equal(t, tests[input], query.Get("expr").MustString(), input)
+	}
+}
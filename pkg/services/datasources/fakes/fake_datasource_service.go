@@ -104,7 +104,13 @@ func (s *FakeDataSourceService) UpdateDataSource(ctx context.Context, cmd *datas
 }
 
 func (s *FakeDataSourceService) GetDefaultDataSource(ctx context.Context, query *datasources.GetDefaultDataSourceQuery) error {
-	return nil
+	for _, datasource := range s.DataSources {
+		if datasource.IsDefault && datasource.OrgId == query.OrgId {
+			query.Result = datasource
+			return nil
+		}
+	}
+	return datasources.ErrDataSourceNotFound
 }
 
 func (s *FakeDataSourceService) GetHTTPTransport(ctx context.Context, ds *datasources.DataSource, provider httpclient.Provider, customMiddlewares ...sdkhttpclient.Middleware) (http.RoundTripper, error) {
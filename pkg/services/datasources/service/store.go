@@ -16,8 +16,10 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -36,8 +38,9 @@ type Store interface {
 }
 
 type SqlStore struct {
-	db     db.DB
-	logger log.Logger
+	db       db.DB
+	logger   log.Logger
+	features featuremgmt.FeatureToggles
 }
 
 func CreateStore(db db.DB, logger log.Logger) *SqlStore {
@@ -146,6 +149,10 @@ func (ss *SqlStore) DeleteDataSource(ctx context.Context, cmd *datasources.Delet
 
 			cmd.DeletedDatasourcesCount, _ = result.RowsAffected()
 
+			if err := store.InsertEntityEvents(sess, ss.features, ds.OrgId, store.EntityTypeDatasource, store.EntityEventTypeDelete, ds.Uid); err != nil {
+				return err
+			}
+
 			// Remove associated AccessControl permissions
 			if _, errDeletingPerms := sess.Exec("DELETE FROM permission WHERE scope=?",
 				ac.Scope(datasources.ScopeProvider.GetResourceScope(dsQuery.Result.Uid))); errDeletingPerms != nil {
@@ -271,6 +278,10 @@ func (ss *SqlStore) AddDataSource(ctx context.Context, cmd *datasources.AddDataS
 			return err
 		}
 
+		if err := store.InsertEntityEvents(sess, ss.features, ds.OrgId, store.EntityTypeDatasource, store.EntityEventTypeCreate, ds.Uid); err != nil {
+			return err
+		}
+
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
 				// ss.logger.Error("Failed to update datasource secrets -- rolling back update", "name", cmd.Name, "type", cmd.Type, "orgId", cmd.OrgId)
@@ -364,6 +375,20 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 		}
 
 		err = updateIsDefaultFlag(ds, sess)
+		if err != nil {
+			return err
+		}
+
+		uid := ds.Uid
+		if uid == "" && store.EntityEventsEnabled(ss.features) {
+			// updates may address the datasource by id only
+			if _, err := sess.Table("data_source").Where("id=? AND org_id=?", ds.Id, ds.OrgId).Cols("uid").Get(&uid); err != nil {
+				return err
+			}
+		}
+		if err := store.InsertEntityEvents(sess, ss.features, ds.OrgId, store.EntityTypeDatasource, store.EntityEventTypeUpdate, uid); err != nil {
+			return err
+		}
 
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
@@ -14,6 +14,8 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/store"
 )
 
 func TestIntegrationDataAccess(t *testing.T) {
@@ -345,6 +347,32 @@ func TestIntegrationDataAccess(t *testing.T) {
 		require.Equal(t, 0, len(query.Result))
 	})
 
+	t.Run("Records entity events for the search index", func(t *testing.T) {
+		sqlStore := db.InitTestDB(t)
+		ss := SqlStore{db: sqlStore, features: featuremgmt.WithFeatures(featuremgmt.FlagPanelTitleSearch)}
+
+		cmd := defaultAddDatasourceCommand
+		cmd.Uid = "ds-uid"
+		require.NoError(t, ss.AddDataSource(context.Background(), &cmd))
+
+		update := defaultUpdateDatasourceCommand
+		update.Id = cmd.Result.Id
+		require.NoError(t, ss.UpdateDataSource(context.Background(), &update))
+
+		require.NoError(t, ss.DeleteDataSource(context.Background(), &datasources.DeleteDataSourceCommand{UID: "ds-uid", OrgID: 10}))
+
+		var events []*store.EntityEvent
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			return sess.OrderBy("id").Find(&events)
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		for i, eventType := range []store.EntityEventType{store.EntityEventTypeCreate, store.EntityEventTypeUpdate, store.EntityEventTypeDelete} {
+			require.Equal(t, "database/10/datasource/ds-uid", events[i].EntityId)
+			require.Equal(t, eventType, events[i].EventType)
+		}
+	})
+
 	t.Run("GetDataSources", func(t *testing.T) {
 		t.Run("Number of data sources returned limited to 6 per organization", func(t *testing.T) {
 			db := db.InitTestDB(t)
@@ -495,7 +495,7 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 	}
 
 	if s.features.IsEnabled(featuremgmt.FlagNestedFolders) {
-		err := s.nestedFolderDelete(ctx, cmd)
+		err := s.nestedFolderDelete(ctx, cmd, cmd.UID)
 		if err != nil {
 			logger.Error("the delete folder on folder table failed with err: ", "error", err)
 			return err
@@ -519,11 +519,18 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 		return dashboards.ErrFolderAccessDenied
 	}
 
-	return s.legacyDelete(ctx, cmd, dashFolder)
+	return s.legacyDelete(ctx, cmd, dashFolder, "")
 }
 
-func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, dashFolder *folder.Folder) error {
-	deleteCmd := models.DeleteDashboardCommand{OrgId: cmd.OrgID, Id: dashFolder.ID, ForceDeleteFolderRules: cmd.ForceDeleteRules}
+// trashEnabled returns whether deleted folders are moved to trash.
+func (s *Service) trashEnabled() bool {
+	return s.cfg != nil && s.cfg.DashboardTrashRetention > 0
+}
+
+// legacyDelete deletes the folder from the dashboard table. trashedWith is the UID of the deleted folder when a
+// subfolder is moved to trash with it.
+func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, dashFolder *folder.Folder, trashedWith string) error {
+	deleteCmd := models.DeleteDashboardCommand{OrgId: cmd.OrgID, Id: dashFolder.ID, ForceDeleteFolderRules: cmd.ForceDeleteRules, MoveToTrash: true, TrashedWith: trashedWith}
 
 	if err := s.dashboardStore.DeleteDashboard(ctx, &deleteCmd); err != nil {
 		return toFolderError(err)
@@ -578,7 +585,9 @@ func (s *Service) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*fol
 	})
 }
 
-func (s *Service) nestedFolderDelete(ctx context.Context, cmd *folder.DeleteFolderCommand) error {
+// nestedFolderDelete deletes a folder with its subfolders from the nested folder table. deletedUID is the UID of the
+// folder which is deleted, the trash entries of its subfolders are linked to it.
+func (s *Service) nestedFolderDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, deletedUID string) error {
 	logger := s.log.FromContext(ctx)
 	if cmd.SignedInUser == nil {
 		return folder.ErrBadRequest.Errorf("missing signed in user")
@@ -599,11 +608,28 @@ func (s *Service) nestedFolderDelete(ctx context.Context, cmd *folder.DeleteFold
 	}
 	for _, f := range folders {
 		logger.Info("deleting subfolder", "org_id", f.OrgID, "uid", f.UID)
-		err := s.nestedFolderDelete(ctx, &folder.DeleteFolderCommand{UID: f.UID, OrgID: f.OrgID, ForceDeleteRules: cmd.ForceDeleteRules, SignedInUser: cmd.SignedInUser})
+		subCmd := &folder.DeleteFolderCommand{UID: f.UID, OrgID: f.OrgID, ForceDeleteRules: cmd.ForceDeleteRules, SignedInUser: cmd.SignedInUser}
+		err := s.nestedFolderDelete(ctx, subCmd, deletedUID)
 		if err != nil {
 			logger.Error("failed deleting subfolder", "org_id", f.OrgID, "uid", f.UID, "error", err)
 			return err
 		}
+		if s.trashEnabled() {
+			// subfolders are moved to trash one by one and restored with
+			// the deleted folder, which is moved to trash by Delete.
+			dashFolder, err := s.dashboardStore.GetFolderByUID(ctx, f.OrgID, f.UID)
+			if err != nil {
+				return err
+			}
+			if err := s.legacyDelete(ctx, subCmd, dashFolder, deletedUID); err != nil {
+				logger.Error("failed moving subfolder to trash", "org_id", f.OrgID, "uid", f.UID, "error", err)
+				return err
+			}
+		}
+	}
+	if s.trashEnabled() {
+		// the row of the folder table is moved to trash with the folder.
+		return nil
 	}
 	logger.Info("deleting folder", "org_id", cmd.OrgID, "uid", cmd.UID)
 	err = s.store.Delete(ctx, cmd.UID, cmd.OrgID)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -657,6 +658,30 @@ func TestNestedFolderService(t *testing.T) {
 			require.True(t, store.DeleteCalled)
 		})
 
+		t.Run("delete with trash enabled keeps the folder row for trash", func(t *testing.T) {
+			cfg.DashboardTrashRetention = time.Hour
+			store.DeleteCalled = false
+			var actualCmd *models.DeleteDashboardCommand
+			dashStore.On("DeleteDashboard", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				actualCmd = args.Get(1).(*models.DeleteDashboardCommand)
+			}).Return(nil).Once()
+			dashStore.On("GetFolderByUID", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(&models.Folder{}, nil)
+
+			g := guardian.New
+			guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true, CanViewValue: true})
+
+			err := foldersvc.Delete(context.Background(), &folder.DeleteFolderCommand{UID: "myFolder", OrgID: orgID, SignedInUser: usr})
+			require.NoError(t, err)
+			require.NotNil(t, actualCmd)
+			require.True(t, actualCmd.MoveToTrash)
+
+			t.Cleanup(func() {
+				guardian.New = g
+				cfg.DashboardTrashRetention = 0
+			})
+			require.False(t, store.DeleteCalled)
+		})
+
 		t.Run("create returns error if maximum depth reached", func(t *testing.T) {
 			// This test creates and deletes the dashboard, so needs some extra setup.
 			g := guardian.New
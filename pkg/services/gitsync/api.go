@@ -0,0 +1,34 @@
+package gitsync
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func (s *GitSyncService) HandleGetStatus(c *models.ReqContext) response.Response {
+	status, err := s.getStatus(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get git sync status", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+func (s *GitSyncService) HandleGetConflicts(c *models.ReqContext) response.Response {
+	status, err := s.getStatus(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get git sync conflicts", err)
+	}
+	return response.JSON(http.StatusOK, status.Conflicts)
+}
+
+func (s *GitSyncService) HandleSync(c *models.ReqContext) response.Response {
+	if s.IsDisabled() {
+		return response.Error(http.StatusBadRequest, "Git sync is not enabled", nil)
+	}
+	if err := s.Sync(c.Req.Context()); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to synchronize dashboards", err)
+	}
+	return s.HandleGetStatus(c)
+}
@@ -0,0 +1,251 @@
+package gitsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Service synchronizes dashboards and folders with a git repository.
+type Service interface {
+	registry.BackgroundService
+	registry.CanBeDisabled
+
+	// AllowsUIChanges returns whether synchronized dashboards can be saved in the UI.
+	AllowsUIChanges() bool
+	// SaveDashboard commits a synchronized dashboard to the repository.
+	SaveDashboard(ctx context.Context, cmd *SaveDashboardCommand) (*SaveDashboardResult, error)
+	// Sync synchronizes dashboards with the repository immediately.
+	Sync(ctx context.Context) error
+
+	// HandleGetStatus returns the status of the last synchronization.
+	HandleGetStatus(c *models.ReqContext) response.Response
+	// HandleGetConflicts returns files which could not be synchronized.
+	HandleGetConflicts(c *models.ReqContext) response.Response
+	// HandleSync synchronizes dashboards with the repository immediately.
+	HandleSync(c *models.ReqContext) response.Response
+}
+
+const (
+	kvNamespace = "git-sync"
+	kvStateKey  = "state"
+
+	// applyLockName is the server lock which prevents instances from applying the repository to the database at the
+	// same time.
+	applyLockName = "git sync apply"
+	// applyLockTimeout is how long the apply lock is held at most when an instance fails to release it.
+	applyLockTimeout = 5 * time.Minute
+	// applyLockRetryInterval is how often acquiring the apply lock is retried while another instance holds it.
+	applyLockRetryInterval = time.Second
+)
+
+var _ Service = (*GitSyncService)(nil)
+
+type GitSyncService struct {
+	cfg                 setting.GitSyncSettings
+	log                 log.Logger
+	kv                  *kvstore.NamespacedKVStore
+	serverLock          *serverlock.ServerLockService
+	dashboardService    dashboards.DashboardService
+	provisioningService dashboards.DashboardProvisioningService
+	repo                *repository
+
+	// mu guards the working copy of the repository.
+	mu sync.Mutex
+}
+
+func ProvideService(cfg *setting.Cfg, kv kvstore.KVStore, serverLock *serverlock.ServerLockService,
+	dashboardService dashboards.DashboardService, provisioningService dashboards.DashboardProvisioningService) *GitSyncService {
+	return newService(cfg.GitSync, filepath.Join(cfg.DataPath, "git-sync"), kv, serverLock, dashboardService, provisioningService)
+}
+
+func newService(cfg setting.GitSyncSettings, dir string, kv kvstore.KVStore, serverLock *serverlock.ServerLockService,
+	dashboardService dashboards.DashboardService, provisioningService dashboards.DashboardProvisioningService) *GitSyncService {
+	return &GitSyncService{
+		cfg:                 cfg,
+		log:                 log.New("gitsync"),
+		kv:                  kvstore.WithNamespace(kv, cfg.OrgID, kvNamespace),
+		serverLock:          serverLock,
+		dashboardService:    dashboardService,
+		provisioningService: provisioningService,
+		repo:                newRepository(cfg, dir),
+	}
+}
+
+func (s *GitSyncService) IsDisabled() bool {
+	return !s.cfg.Enabled
+}
+
+func (s *GitSyncService) Run(ctx context.Context) error {
+	s.log.Info("Starting git sync", "repository", s.cfg.Repository, "branch", s.cfg.Branch, "interval", s.cfg.Interval)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Only one instance synchronizes the database, the others pick the changes up from it.
+		err := s.serverLock.LockAndExecute(ctx, "git sync", s.cfg.Interval/2, func(ctx context.Context) {
+			if err := s.Sync(ctx); err != nil {
+				s.log.Error("Failed to synchronize dashboards", "error", err)
+			}
+		})
+		if err != nil {
+			s.log.Error("Failed to acquire git sync lock", "error", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *GitSyncService) AllowsUIChanges() bool {
+	return s.cfg.Enabled && s.cfg.UIChanges != setting.GitSyncUIChangesReadOnly
+}
+
+func (s *GitSyncService) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncWithServerLock(ctx)
+}
+
+// syncWithServerLock synchronizes the database while holding the apply lock, waiting for other instances which hold
+// it. The caller must hold s.mu.
+func (s *GitSyncService) syncWithServerLock(ctx context.Context) error {
+	for {
+		var syncErr error
+		err := s.serverLock.LockExecuteAndRelease(ctx, applyLockName, applyLockTimeout, func(ctx context.Context) {
+			syncErr = s.sync(ctx)
+		})
+		var lockExists *serverlock.ServerLockExistsError
+		if !errors.As(err, &lockExists) {
+			if err != nil {
+				return err
+			}
+			return syncErr
+		}
+
+		select {
+		case <-time.After(applyLockRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *GitSyncService) SaveDashboard(ctx context.Context, cmd *SaveDashboardCommand) (*SaveDashboardResult, error) {
+	if !s.AllowsUIChanges() {
+		return nil, ErrUIChangesNotAllowed
+	}
+
+	data := cmd.Dashboard.Data
+	// The database identifiers are not part of the dashboard in the repository.
+	data.Del("id")
+	data.Del("version")
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	body = append(body, '\n')
+
+	message := cmd.Message
+	if message == "" {
+		message = fmt.Sprintf("Update dashboard %s", cmd.Dashboard.Title)
+	}
+	author := &object.Signature{Name: cmd.User.Login, Email: cmd.User.Email, When: time.Now()}
+	if cmd.User.Name != "" {
+		author.Name = cmd.User.Name
+	}
+
+	branch := s.cfg.Branch
+	if s.cfg.UIChanges == setting.GitSyncUIChangesBranch {
+		branch = fmt.Sprintf("grafana/%s-%d", cmd.Dashboard.Uid, time.Now().Unix())
+	}
+
+	checkSum := cmd.CheckSum
+	if cmd.Overwrite {
+		checkSum = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, err := s.repo.commitAndPush(ctx, cmd.Path, body, checkSum, message, author, branch)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("Committed dashboard", "uid", cmd.Dashboard.Uid, "path", cmd.Path, "branch", branch, "commit", hash)
+
+	result := &SaveDashboardResult{Commit: hash, Branch: branch}
+	if branch != s.cfg.Branch {
+		return result, nil
+	}
+
+	// Apply the commit to the database right away instead of waiting for the next synchronization.
+	if err := s.syncWithServerLock(ctx); err != nil {
+		return nil, err
+	}
+	query := &models.GetDashboardQuery{OrgId: s.cfg.OrgID, Uid: cmd.Dashboard.Uid}
+	if err := s.dashboardService.GetDashboard(ctx, query); err != nil {
+		return nil, err
+	}
+	result.Dashboard = query.Result
+	return result, nil
+}
+
+func (s *GitSyncService) loadState(ctx context.Context) (*syncState, error) {
+	state := &syncState{Folders: map[string]string{}}
+	value, ok, err := s.kv.Get(ctx, kvStateKey)
+	if err != nil || !ok {
+		return state, err
+	}
+	if err := json.Unmarshal([]byte(value), state); err != nil {
+		return nil, err
+	}
+	if state.Folders == nil {
+		state.Folders = map[string]string{}
+	}
+	return state, nil
+}
+
+func (s *GitSyncService) saveState(ctx context.Context, state *syncState) error {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, kvStateKey, string(value))
+}
+
+func (s *GitSyncService) getStatus(ctx context.Context) (Status, error) {
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	status := state.Status
+	status.Enabled = s.cfg.Enabled
+	status.Repository = s.cfg.Repository
+	status.Branch = s.cfg.Branch
+	status.Path = s.cfg.Path
+	status.UIChanges = s.cfg.UIChanges
+	if status.Conflicts == nil {
+		status.Conflicts = []Conflict{}
+	}
+	return status, nil
+}
@@ -0,0 +1,69 @@
+package gitsync
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// ProvisionerName is the provisioner name of dashboards synchronized from git.
+const ProvisionerName = "git-sync"
+
+var (
+	// ErrUIChangesNotAllowed is returned when a synchronized dashboard is saved while ui changes are read only.
+	ErrUIChangesNotAllowed = errors.New("changes of dashboards synchronized from git are not allowed")
+	// ErrRemoteChanged is returned when the branch changed in the remote repository while committing.
+	ErrRemoteChanged = errors.New("the branch was changed in the repository, reload the dashboard and try again")
+)
+
+// Conflict describes a file in the repository which could not be synchronized.
+type Conflict struct {
+	Path   string `json:"path"`
+	UID    string `json:"uid,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Status is the status of the last synchronization.
+type Status struct {
+	Enabled    bool       `json:"enabled"`
+	Repository string     `json:"repository,omitempty"`
+	Branch     string     `json:"branch,omitempty"`
+	Path       string     `json:"path,omitempty"`
+	UIChanges  string     `json:"uiChanges,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	Synced     time.Time  `json:"synced,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Conflicts  []Conflict `json:"conflicts"`
+}
+
+// SaveDashboardCommand saves a synchronized dashboard to the repository.
+type SaveDashboardCommand struct {
+	User      *user.SignedInUser
+	Dashboard *models.Dashboard
+	// Path of the dashboard file in the repository, relative to the synchronized path.
+	Path    string
+	Message string
+	// CheckSum of the file the dashboard was synchronized from. The dashboard is not committed when the file was
+	// changed in the repository since, unless Overwrite is set.
+	CheckSum  string
+	Overwrite bool
+}
+
+// SaveDashboardResult is the result of saving a dashboard to the repository.
+type SaveDashboardResult struct {
+	// Commit is the hash of the created commit.
+	Commit string `json:"commit"`
+	// Branch is the branch the commit was pushed to.
+	Branch string `json:"branch"`
+	// Dashboard is the saved dashboard, nil when changes were pushed to a new branch.
+	Dashboard *models.Dashboard `json:"-"`
+}
+
+// syncState is the persisted state of the synchronization.
+type syncState struct {
+	// Folders maps directories in the repository to folder UIDs.
+	Folders map[string]string `json:"folders"`
+	Status  Status            `json:"status"`
+}
@@ -0,0 +1,235 @@
+package gitsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const remoteName = "origin"
+
+// repository is a local working copy of the synchronized branch.
+type repository struct {
+	cfg  setting.GitSyncSettings
+	dir  string
+	repo *git.Repository
+}
+
+func newRepository(cfg setting.GitSyncSettings, dir string) *repository {
+	return &repository{cfg: cfg, dir: dir}
+}
+
+func (r *repository) auth() transport.AuthMethod {
+	if r.cfg.Username == "" && r.cfg.Password == "" {
+		return nil
+	}
+	return &http.BasicAuth{Username: r.cfg.Username, Password: r.cfg.Password}
+}
+
+// root returns the directory of the working copy which is synchronized.
+func (r *repository) root() string {
+	return filepath.Join(r.dir, filepath.FromSlash(r.cfg.Path))
+}
+
+// open opens the working copy, cloning the repository when it does not exist yet.
+func (r *repository) open(ctx context.Context) error {
+	if r.repo != nil {
+		return nil
+	}
+
+	repo, err := git.PlainOpen(r.dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if err := os.MkdirAll(r.dir, 0750); err != nil {
+			return err
+		}
+		repo, err = git.PlainCloneContext(ctx, r.dir, false, &git.CloneOptions{
+			URL:           r.cfg.Repository,
+			Auth:          r.auth(),
+			RemoteName:    remoteName,
+			ReferenceName: plumbing.NewBranchReferenceName(r.cfg.Branch),
+			SingleBranch:  true,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	r.repo = repo
+	return nil
+}
+
+// pull fetches the remote branch and resets the working copy to it. It returns the hash of the head commit.
+func (r *repository) pull(ctx context.Context) (string, error) {
+	if err := r.open(ctx); err != nil {
+		return "", err
+	}
+	if err := r.fetch(ctx); err != nil {
+		return "", err
+	}
+	return r.reset()
+}
+
+// fetch updates the remote tracking branch from the remote repository.
+func (r *repository) fetch(ctx context.Context) error {
+	err := r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remoteName,
+		Auth:       r.auth(),
+		RefSpecs:   []config.RefSpec{r.fetchRefSpec()},
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch repository: %w", err)
+	}
+	return nil
+}
+
+// reset discards local changes and moves the working copy to the last fetched remote commit.
+func (r *repository) reset() (string, error) {
+	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName(remoteName, r.cfg.Branch), true)
+	if err != nil {
+		return "", fmt.Errorf("failed to find branch %s: %w", r.cfg.Branch, err)
+	}
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: ref.Hash(), Mode: git.HardReset}); err != nil {
+		return "", err
+	}
+	if err := wt.Clean(&git.CleanOptions{Dir: true}); err != nil {
+		return "", err
+	}
+	return ref.Hash().String(), nil
+}
+
+// commitAndPush writes a file, commits it on top of the remote branch and pushes the commit to the given remote branch.
+// When checkSum is set, the commit is rejected with dashboards.ErrDashboardVersionMismatch if the file in the remote
+// branch has a different checksum. A push which is rejected because another commit was pushed in the meantime is
+// retried once.
+func (r *repository) commitAndPush(ctx context.Context, path string, body []byte, checkSum string, message string, author *object.Signature, branch string) (string, error) {
+	if err := r.open(ctx); err != nil {
+		return "", err
+	}
+
+	relPath := filepath.ToSlash(filepath.Join(r.cfg.Path, path))
+	if !isSubPath(r.cfg.Path, relPath) {
+		return "", fmt.Errorf("invalid path %q", path)
+	}
+	if author.When.IsZero() {
+		author.When = time.Now()
+	}
+
+	hash, err := r.tryCommitAndPush(ctx, relPath, body, checkSum, message, author, branch)
+	if errors.Is(err, ErrRemoteChanged) {
+		hash, err = r.tryCommitAndPush(ctx, relPath, body, checkSum, message, author, branch)
+	}
+	return hash, err
+}
+
+func (r *repository) tryCommitAndPush(ctx context.Context, relPath string, body []byte, checkSum string, message string, author *object.Signature, branch string) (string, error) {
+	if err := r.fetch(ctx); err != nil {
+		return "", err
+	}
+	if _, err := r.reset(); err != nil {
+		return "", err
+	}
+
+	fullPath := filepath.Join(r.dir, filepath.FromSlash(relPath))
+	if checkSum != "" {
+		// nolint:gosec
+		// The path is checked to be within the working copy.
+		current, err := os.ReadFile(fullPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		currentSum, err := util.Md5SumString(string(current))
+		if err != nil {
+			return "", err
+		}
+		if current == nil || currentSum != checkSum {
+			return "", dashboards.ErrDashboardVersionMismatch
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0750); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(fullPath, body, 0600); err != nil {
+		return "", err
+	}
+
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	if _, err := wt.Add(relPath); err != nil {
+		return "", err
+	}
+	hash, err := wt.Commit(message, &git.CommitOptions{Author: author})
+	if err != nil {
+		return "", err
+	}
+
+	err = r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: remoteName,
+		Auth:       r.auth(),
+		RefSpecs: []config.RefSpec{
+			config.RefSpec(plumbing.NewBranchReferenceName(r.cfg.Branch).String() + ":" + plumbing.NewBranchReferenceName(branch).String()),
+		},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		// Drop the local commit, the next pull restores the remote state.
+		_, _ = r.reset()
+		if strings.Contains(err.Error(), git.ErrNonFastForwardUpdate.Error()) {
+			return "", ErrRemoteChanged
+		}
+		return "", fmt.Errorf("failed to push to repository: %w", err)
+	}
+
+	if branch != r.cfg.Branch {
+		// The synchronized branch must keep following the remote one.
+		if _, err := r.reset(); err != nil {
+			return "", err
+		}
+	} else if err := r.updateRemoteRef(hash); err != nil {
+		return "", err
+	}
+
+	return hash.String(), nil
+}
+
+// updateRemoteRef moves the remote tracking branch to a commit which was pushed.
+func (r *repository) updateRemoteRef(hash plumbing.Hash) error {
+	ref := plumbing.NewHashReference(plumbing.NewRemoteReferenceName(remoteName, r.cfg.Branch), hash)
+	return r.repo.Storer.SetReference(ref)
+}
+
+func (r *repository) fetchRefSpec() config.RefSpec {
+	return config.RefSpec(fmt.Sprintf("+%s:%s",
+		plumbing.NewBranchReferenceName(r.cfg.Branch),
+		plumbing.NewRemoteReferenceName(remoteName, r.cfg.Branch)))
+}
+
+// isSubPath checks that a slash separated path is within the root.
+func isSubPath(root string, path string) bool {
+	root = strings.Trim(filepath.ToSlash(filepath.Clean(root)), "/")
+	path = filepath.ToSlash(filepath.Clean(path))
+	if root == "." || root == "" {
+		return !strings.HasPrefix(path, "../") && path != ".." && !filepath.IsAbs(path)
+	}
+	return strings.HasPrefix(path, root+"/")
+}
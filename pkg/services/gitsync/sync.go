@@ -0,0 +1,357 @@
+package gitsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// repositoryFile is a dashboard file found in the repository.
+type repositoryFile struct {
+	// path relative to the synchronized path, with slashes.
+	path   string
+	folder string
+}
+
+// syncRun holds the state of a single synchronization.
+type syncRun struct {
+	state     *syncState
+	managed   map[string]*models.DashboardProvisioning
+	managedID map[int64]*models.DashboardProvisioning
+	uids      map[string]string
+	folderIDs map[string]int64
+	conflicts []Conflict
+	// saved holds the ids of dashboards which are kept.
+	saved map[int64]bool
+	// paths holds the dashboard files found in the repository.
+	paths map[string]bool
+}
+
+func (r *syncRun) conflict(path string, uid string, reason string) {
+	r.conflicts = append(r.conflicts, Conflict{Path: path, UID: uid, Reason: reason})
+}
+
+// sync pulls the repository and applies it to the database. The caller must hold s.mu.
+func (s *GitSyncService) sync(ctx context.Context) error {
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	commit, err := s.repo.pull(ctx)
+	if err == nil {
+		err = s.apply(ctx, state)
+	}
+
+	state.Status.Synced = time.Now()
+	state.Status.Error = ""
+	if err != nil {
+		state.Status.Error = err.Error()
+	} else {
+		state.Status.Commit = commit
+	}
+	if saveErr := s.saveState(ctx, state); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// apply creates, updates and deletes dashboards and folders so that they match the working copy.
+func (s *GitSyncService) apply(ctx context.Context, state *syncState) error {
+	folders, files, run, err := s.scan(state)
+	if err != nil {
+		return err
+	}
+
+	provisioned, err := s.provisioningService.GetProvisionedDashboardData(ctx, ProvisionerName)
+	if err != nil {
+		return err
+	}
+	for _, p := range provisioned {
+		run.managed[p.ExternalId] = p
+		run.managedID[p.DashboardId] = p
+	}
+
+	for _, folder := range folders {
+		if err := s.syncFolder(ctx, run, folder); err != nil {
+			run.conflict(folder, "", err.Error())
+		}
+	}
+
+	for _, file := range files {
+		if _, ok := run.folderIDs[file.folder]; !ok {
+			run.conflict(file.path, "", "folder could not be synchronized")
+			continue
+		}
+		if err := s.syncDashboard(ctx, run, file); err != nil {
+			return err
+		}
+	}
+
+	for externalID, p := range run.managed {
+		if run.saved[p.DashboardId] {
+			continue
+		}
+		s.log.Info("Deleting dashboard removed from repository", "path", externalID)
+		if err := s.provisioningService.DeleteProvisionedDashboard(ctx, p.DashboardId, s.cfg.OrgID); err != nil &&
+			!errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return err
+		}
+	}
+
+	found := make(map[string]bool, len(folders))
+	for _, folder := range folders {
+		found[folder] = true
+	}
+	for folder, uid := range state.Folders {
+		if found[folder] {
+			continue
+		}
+		if err := s.deleteFolder(ctx, uid); err != nil {
+			run.conflict(folder, uid, err.Error())
+			continue
+		}
+		delete(state.Folders, folder)
+	}
+
+	sort.Slice(run.conflicts, func(i, j int) bool { return run.conflicts[i].Path < run.conflicts[j].Path })
+	state.Status.Conflicts = run.conflicts
+	return nil
+}
+
+// scan lists the folders and dashboard files of the working copy.
+// Directories directly under the synchronized path are mapped to folders, files in the path itself to the General folder.
+func (s *GitSyncService) scan(state *syncState) ([]string, []repositoryFile, *syncRun, error) {
+	run := &syncRun{
+		state:     state,
+		managed:   map[string]*models.DashboardProvisioning{},
+		managedID: map[int64]*models.DashboardProvisioning{},
+		saved:     map[int64]bool{},
+		uids:      map[string]string{},
+		paths:     map[string]bool{},
+		folderIDs: map[string]int64{"": 0},
+	}
+
+	root := s.repo.root()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read path %q in repository: %w", s.cfg.Path, err)
+	}
+
+	var folders []string
+	var files []repositoryFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if !entry.IsDir() {
+			if isDashboardFile(name) {
+				files = append(files, repositoryFile{path: name})
+			}
+			continue
+		}
+
+		folders = append(folders, name)
+		children, err := os.ReadDir(filepath.Join(root, name))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, child := range children {
+			childPath := path.Join(name, child.Name())
+			switch {
+			case strings.HasPrefix(child.Name(), "."):
+			case child.IsDir():
+				run.conflict(childPath, "", "nested folders are not supported")
+			case isDashboardFile(child.Name()):
+				files = append(files, repositoryFile{path: childPath, folder: name})
+			}
+		}
+	}
+	for _, file := range files {
+		run.paths[file.path] = true
+	}
+	return folders, files, run, nil
+}
+
+func (s *GitSyncService) syncFolder(ctx context.Context, run *syncRun, folder string) error {
+	uid, ok := run.state.Folders[folder]
+	if !ok {
+		uid = folderUID(folder)
+	}
+
+	query := &models.GetDashboardQuery{OrgId: s.cfg.OrgID, Uid: uid}
+	err := s.dashboardService.GetDashboard(ctx, query)
+	switch {
+	case err == nil:
+		if !query.Result.IsFolder {
+			return fmt.Errorf("a dashboard with uid %s already exists", uid)
+		}
+		run.folderIDs[folder] = query.Result.Id
+	case errors.Is(err, dashboards.ErrDashboardNotFound):
+		dto := &dashboards.SaveDashboardDTO{
+			OrgId:     s.cfg.OrgID,
+			Overwrite: true,
+			Dashboard: models.NewDashboardFolder(folder),
+		}
+		dto.Dashboard.SetUid(uid)
+		dash, err := s.provisioningService.SaveFolderForProvisionedDashboards(ctx, dto)
+		if err != nil {
+			return err
+		}
+		s.log.Info("Created folder", "folder", folder, "uid", uid)
+		run.folderIDs[folder] = dash.Id
+	default:
+		return err
+	}
+
+	run.state.Folders[folder] = uid
+	return nil
+}
+
+func (s *GitSyncService) syncDashboard(ctx context.Context, run *syncRun, file repositoryFile) error {
+	// nolint:gosec
+	// The path is read from the working copy of the configured repository.
+	body, err := os.ReadFile(filepath.Join(s.repo.root(), filepath.FromSlash(file.path)))
+	if err != nil {
+		return err
+	}
+	checkSum, err := util.Md5SumString(string(body))
+	if err != nil {
+		return err
+	}
+
+	existing := run.managed[file.path]
+	// keep preserves the dashboard of a file which cannot be synchronized until it is fixed or removed.
+	keep := func() {
+		if existing != nil {
+			run.saved[existing.DashboardId] = true
+		}
+	}
+	if existing != nil && existing.CheckSum == checkSum {
+		keep()
+		return nil
+	}
+
+	data, err := simplejson.NewJson(body)
+	if err != nil {
+		keep()
+		run.conflict(file.path, "", "invalid dashboard json: "+err.Error())
+		return nil
+	}
+	dash := models.NewDashboardFromJson(data)
+	if dash.Uid == "" {
+		dash.SetUid(dashboardUID(file.path))
+	}
+	if other, ok := run.uids[dash.Uid]; ok {
+		keep()
+		run.conflict(file.path, dash.Uid, "uid is already used by "+other)
+		return nil
+	}
+	run.uids[dash.Uid] = file.path
+
+	dash.Id = 0
+	dash.Data.Del("id")
+	query := &models.GetDashboardQuery{OrgId: s.cfg.OrgID, Uid: dash.Uid}
+	err = s.dashboardService.GetDashboard(ctx, query)
+	switch {
+	case err == nil:
+		p, ok := run.managedID[query.Result.Id]
+		if !ok || query.Result.IsFolder {
+			keep()
+			run.conflict(file.path, dash.Uid, "a dashboard with the same uid exists and is not synchronized from git")
+			return nil
+		}
+		if p.ExternalId != file.path && run.paths[p.ExternalId] {
+			keep()
+			run.conflict(file.path, dash.Uid, "uid is already used by "+p.ExternalId)
+			return nil
+		}
+		// Either the file changed or it was moved within the repository.
+		dash.SetId(p.DashboardId)
+	case !errors.Is(err, dashboards.ErrDashboardNotFound):
+		return err
+	}
+
+	dash.OrgId = s.cfg.OrgID
+	dash.FolderId = run.folderIDs[file.folder]
+	dto := &dashboards.SaveDashboardDTO{
+		OrgId:     s.cfg.OrgID,
+		UpdatedAt: time.Now(),
+		Overwrite: true,
+		Dashboard: dash,
+	}
+	dp := &models.DashboardProvisioning{
+		Name:       ProvisionerName,
+		ExternalId: file.path,
+		Updated:    time.Now().Unix(),
+		CheckSum:   checkSum,
+	}
+	saved, err := s.provisioningService.SaveProvisionedDashboard(ctx, dto, dp)
+	if err != nil {
+		keep()
+		run.conflict(file.path, dash.Uid, err.Error())
+		return nil
+	}
+	s.log.Debug("Saved dashboard", "path", file.path, "uid", saved.Uid)
+	run.saved[saved.Id] = true
+	return nil
+}
+
+// deleteFolder deletes a folder which was removed from the repository, unless it still contains dashboards.
+func (s *GitSyncService) deleteFolder(ctx context.Context, uid string) error {
+	query := &models.GetDashboardQuery{OrgId: s.cfg.OrgID, Uid: uid}
+	if err := s.dashboardService.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Synchronization runs in the background, the dashboard service gets the
+	// organization from the user of the context.
+	ctx = appcontext.WithUser(ctx, accesscontrol.BackgroundUser("git_sync", s.cfg.OrgID, org.RoleAdmin, nil))
+	count, err := s.dashboardService.CountDashboardsInFolder(ctx, &dashboards.CountDashboardsInFolderQuery{OrgID: s.cfg.OrgID, FolderUID: uid})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("folder was removed from the repository but still contains %d dashboards", count)
+	}
+	s.log.Info("Deleting folder removed from repository", "uid", uid)
+	return s.dashboardService.DeleteDashboard(ctx, query.Result.Id, s.cfg.OrgID)
+}
+
+func isDashboardFile(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".json")
+}
+
+// folderUID returns a stable uid for a folder of the repository.
+func folderUID(folder string) string {
+	return "git-" + shortHash("folder/"+folder)
+}
+
+// dashboardUID returns a stable uid for a dashboard file without uid.
+func dashboardUID(path string) string {
+	return "git-" + shortHash("dashboard/"+path)
+}
+
+func shortHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("%x", sum)[:20]
+}
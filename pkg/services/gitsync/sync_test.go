@@ -0,0 +1,445 @@
+package gitsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationGitSync(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	remote := newTestRemote(t)
+	remote.commit(t, "initial", map[string]string{
+		"dashboards/home.json":        `{"uid": "home", "title": "Home"}`,
+		"dashboards/Team A/ops.json":  `{"title": "Ops"}`,
+		"dashboards/Team A/README.md": `not a dashboard`,
+		"other/ignored.json":          `{"uid": "ignored", "title": "Ignored"}`,
+	})
+
+	store := newFakeDashboards()
+	svc := newTestService(t, remote, store, setting.GitSyncUIChangesCommit)
+
+	t.Run("Sync creates folders and dashboards from the repository", func(t *testing.T) {
+		require.NoError(t, svc.Sync(ctx))
+
+		home := store.get("home")
+		require.NotNil(t, home)
+		require.Equal(t, "Home", home.Title)
+		require.Equal(t, int64(0), home.FolderId)
+
+		folder := store.get(folderUID("Team A"))
+		require.NotNil(t, folder)
+		require.True(t, folder.IsFolder)
+		ops := store.get(dashboardUID("Team A/ops.json"))
+		require.NotNil(t, ops)
+		require.Equal(t, folder.Id, ops.FolderId)
+		require.Nil(t, store.get("ignored"))
+
+		status, err := svc.getStatus(ctx)
+		require.NoError(t, err)
+		require.Equal(t, remote.head(t, "main"), status.Commit)
+		require.Empty(t, status.Error)
+		require.Empty(t, status.Conflicts)
+	})
+
+	t.Run("Sync reports files which cannot be synchronized", func(t *testing.T) {
+		store.add(&models.Dashboard{Uid: "taken", Title: "Taken"})
+		remote.commit(t, "conflicts", map[string]string{
+			"dashboards/broken.json":               `{`,
+			"dashboards/taken.json":                `{"uid": "taken", "title": "Taken in git"}`,
+			"dashboards/duplicate.json":            `{"uid": "home", "title": "Duplicate"}`,
+			"dashboards/Team A/nested/nested.json": `{"title": "Nested"}`,
+		})
+		require.NoError(t, svc.Sync(ctx))
+
+		status, err := svc.getStatus(ctx)
+		require.NoError(t, err)
+		paths := make([]string, 0, len(status.Conflicts))
+		for _, c := range status.Conflicts {
+			paths = append(paths, c.Path)
+		}
+		require.Equal(t, []string{"Team A/nested", "broken.json", "duplicate.json", "taken.json"}, paths)
+		require.Equal(t, "Taken", store.get("taken").Title)
+		require.Equal(t, "Home", store.get("home").Title)
+	})
+
+	t.Run("Sync updates and deletes dashboards and folders", func(t *testing.T) {
+		homeID := store.get("home").Id
+		remote.commit(t, "update", map[string]string{
+			"dashboards/home.json": `{"uid": "home", "title": "Home updated"}`,
+		}, "dashboards/Team A/ops.json", "dashboards/Team A/README.md", "dashboards/Team A/nested/nested.json",
+			"dashboards/broken.json", "dashboards/taken.json", "dashboards/duplicate.json")
+		require.NoError(t, svc.Sync(ctx))
+
+		home := store.get("home")
+		require.Equal(t, homeID, home.Id)
+		require.Equal(t, "Home updated", home.Title)
+		require.Nil(t, store.get(dashboardUID("Team A/ops.json")))
+		require.Nil(t, store.get(folderUID("Team A")))
+		require.NotNil(t, store.get("taken"))
+
+		status, err := svc.getStatus(ctx)
+		require.NoError(t, err)
+		require.Empty(t, status.Conflicts)
+	})
+
+	t.Run("Saving a dashboard commits it to the branch", func(t *testing.T) {
+		dash := store.get("home")
+		dash.Data.Set("title", "Home from UI")
+		result, err := svc.SaveDashboard(ctx, &SaveDashboardCommand{
+			User:      &user.SignedInUser{Login: "editor", Email: "editor@example.com"},
+			Dashboard: models.NewDashboardFromJson(dash.Data),
+			Path:      "home.json",
+			Message:   "Rename home",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "main", result.Branch)
+		require.Equal(t, remote.head(t, "main"), result.Commit)
+		require.Equal(t, "Home from UI", result.Dashboard.Title)
+		require.Equal(t, "Home from UI", store.get("home").Title)
+
+		commit := remote.commitObject(t, result.Commit)
+		require.Equal(t, "Rename home", commit.Message)
+		require.Equal(t, "editor", commit.Author.Name)
+		require.Equal(t, "editor@example.com", commit.Author.Email)
+	})
+
+	t.Run("Saving a dashboard commits on top of changes to other files in the repository", func(t *testing.T) {
+		remote.commit(t, "remote change", map[string]string{
+			"dashboards/other.json": `{"uid": "other", "title": "Other"}`,
+		})
+		dash := store.get("home")
+		dash.Data.Set("title", "Home from UI again")
+		result, err := svc.SaveDashboard(ctx, &SaveDashboardCommand{
+			User:      &user.SignedInUser{Login: "editor"},
+			Dashboard: models.NewDashboardFromJson(dash.Data),
+			Path:      "home.json",
+			CheckSum:  managedCheckSum(t, store, "home.json"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, remote.head(t, "main"), result.Commit)
+		require.Equal(t, "Home from UI again", store.get("home").Title)
+		require.Equal(t, "Other", store.get("other").Title)
+	})
+
+	t.Run("Saving a dashboard fails when the file changed in the repository", func(t *testing.T) {
+		checkSum := managedCheckSum(t, store, "home.json")
+		remote.commit(t, "remote change", map[string]string{
+			"dashboards/home.json": `{"uid": "home", "title": "Home changed in git"}`,
+		})
+		head := remote.head(t, "main")
+		_, err := svc.SaveDashboard(ctx, &SaveDashboardCommand{
+			User:      &user.SignedInUser{Login: "editor"},
+			Dashboard: models.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{"uid": "home", "title": "Stale"})),
+			Path:      "home.json",
+			CheckSum:  checkSum,
+		})
+		require.ErrorIs(t, err, dashboards.ErrDashboardVersionMismatch)
+		require.Equal(t, head, remote.head(t, "main"))
+
+		require.NoError(t, svc.Sync(ctx))
+		require.Equal(t, "Home changed in git", store.get("home").Title)
+	})
+
+	t.Run("Saving a dashboard overwrites changes in the repository when asked to", func(t *testing.T) {
+		checkSum := managedCheckSum(t, store, "home.json")
+		remote.commit(t, "remote change", map[string]string{
+			"dashboards/home.json": `{"uid": "home", "title": "Home changed in git again"}`,
+		})
+		result, err := svc.SaveDashboard(ctx, &SaveDashboardCommand{
+			User:      &user.SignedInUser{Login: "editor"},
+			Dashboard: models.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{"uid": "home", "title": "Home changed in git"})),
+			Path:      "home.json",
+			CheckSum:  checkSum,
+			Overwrite: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, remote.head(t, "main"), result.Commit)
+		require.Equal(t, "Home changed in git", store.get("home").Title)
+	})
+
+	t.Run("Saving a dashboard pushes a new branch", func(t *testing.T) {
+		branchSvc := newTestService(t, remote, store, setting.GitSyncUIChangesBranch)
+		mainHead := remote.head(t, "main")
+		result, err := branchSvc.SaveDashboard(ctx, &SaveDashboardCommand{
+			User:      &user.SignedInUser{Login: "editor"},
+			Dashboard: models.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{"uid": "home", "title": "Proposal"})),
+			Path:      "home.json",
+		})
+		require.NoError(t, err)
+		require.Contains(t, result.Branch, "grafana/home-")
+		require.Nil(t, result.Dashboard)
+		require.Equal(t, result.Commit, remote.head(t, result.Branch))
+		require.Equal(t, mainHead, remote.head(t, "main"))
+		require.Equal(t, "Home changed in git", store.get("home").Title)
+	})
+
+	t.Run("Saving a dashboard is not allowed when read only", func(t *testing.T) {
+		readOnlySvc := newTestService(t, remote, store, setting.GitSyncUIChangesReadOnly)
+		require.False(t, readOnlySvc.AllowsUIChanges())
+		_, err := readOnlySvc.SaveDashboard(ctx, &SaveDashboardCommand{
+			User:      &user.SignedInUser{Login: "editor"},
+			Dashboard: store.get("home"),
+			Path:      "home.json",
+		})
+		require.ErrorIs(t, err, ErrUIChangesNotAllowed)
+	})
+}
+
+func TestIntegrationGitSyncDeleteFolder(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	features := featuremgmt.WithFeatures()
+	dashboardStore, err := database.ProvideDashboardStore(sqlStore, sqlStore.Cfg, features, tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+	dashboardService := service.ProvideDashboardService(sqlStore.Cfg, dashboardStore, nil, features, nil, nil, actest.FakeAccessControl{})
+
+	svc := newService(setting.GitSyncSettings{Enabled: true, OrgID: 1}, t.TempDir(), kvstore.ProvideService(sqlStore), nil, dashboardService, dashboardService)
+
+	save := func(title string, folderID int64, isFolder bool) *models.Dashboard {
+		dash, err := dashboardStore.SaveDashboard(ctx, models.SaveDashboardCommand{
+			OrgId:     1,
+			FolderId:  folderID,
+			IsFolder:  isFolder,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": title}),
+		})
+		require.NoError(t, err)
+		return dash
+	}
+	folder := save("Team A", 0, true)
+	dash := save("Ops", folder.Id, false)
+
+	t.Run("a folder which still contains dashboards is kept", func(t *testing.T) {
+		err := svc.deleteFolder(ctx, folder.Uid)
+		require.ErrorContains(t, err, "still contains 1 dashboards")
+	})
+
+	t.Run("an empty folder is deleted", func(t *testing.T) {
+		require.NoError(t, dashboardService.DeleteDashboard(ctx, dash.Id, 1))
+		require.NoError(t, svc.deleteFolder(ctx, folder.Uid))
+
+		_, err := dashboardStore.GetDashboard(ctx, &models.GetDashboardQuery{OrgId: 1, Uid: folder.Uid})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+	})
+}
+
+func newTestService(t *testing.T, remote *testRemote, store *fakeDashboards, uiChanges string) *GitSyncService {
+	t.Helper()
+	cfg := setting.GitSyncSettings{
+		Enabled:    true,
+		Repository: remote.dir,
+		Branch:     "main",
+		Path:       "dashboards",
+		OrgID:      1,
+		Interval:   time.Minute,
+		UIChanges:  uiChanges,
+	}
+	sqlStore := db.InitTestDB(t)
+	serverLock := serverlock.ProvideService(sqlStore, tracing.InitializeTracerForTest())
+	return newService(cfg, t.TempDir(), kvstore.ProvideService(sqlStore), serverLock, store, store)
+}
+
+// managedCheckSum returns the checksum of a synchronized file.
+func managedCheckSum(t *testing.T, store *fakeDashboards, path string) string {
+	t.Helper()
+	provisioned, err := store.GetProvisionedDashboardData(context.Background(), ProvisionerName)
+	require.NoError(t, err)
+	for _, p := range provisioned {
+		if p.ExternalId == path {
+			return p.CheckSum
+		}
+	}
+	t.Fatalf("file %s is not synchronized", path)
+	return ""
+}
+
+// testRemote is a bare repository with a working copy used to push commits to it.
+type testRemote struct {
+	dir  string
+	work *git.Repository
+}
+
+func newTestRemote(t *testing.T) *testRemote {
+	t.Helper()
+	dir := t.TempDir()
+	_, err := git.PlainInit(dir, true)
+	require.NoError(t, err)
+
+	work, err := git.PlainInit(t.TempDir(), false)
+	require.NoError(t, err)
+	require.NoError(t, work.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+	_, err = work.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{dir}})
+	require.NoError(t, err)
+	return &testRemote{dir: dir, work: work}
+}
+
+func (r *testRemote) commit(t *testing.T, message string, files map[string]string, removed ...string) {
+	t.Helper()
+	wt, err := r.work.Worktree()
+	require.NoError(t, err)
+
+	// Pick up commits pushed by the service.
+	err = r.work.Fetch(&git.FetchOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"+refs/heads/main:refs/remotes/origin/main"}})
+	if err == nil {
+		ref, err := r.work.Reference(plumbing.NewRemoteReferenceName("origin", "main"), true)
+		require.NoError(t, err)
+		require.NoError(t, wt.Reset(&git.ResetOptions{Commit: ref.Hash(), Mode: git.HardReset}))
+	}
+
+	for name, body := range files {
+		fullPath := filepath.Join(wt.Filesystem.Root(), name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0750))
+		require.NoError(t, os.WriteFile(fullPath, []byte(body), 0600))
+		_, err := wt.Add(name)
+		require.NoError(t, err)
+	}
+	for _, name := range removed {
+		_, err := wt.Remove(name)
+		require.NoError(t, err)
+	}
+	_, err = wt.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	require.NoError(t, r.work.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/main:refs/heads/main"}}))
+}
+
+func (r *testRemote) repo(t *testing.T) *git.Repository {
+	t.Helper()
+	repo, err := git.PlainOpen(r.dir)
+	require.NoError(t, err)
+	return repo
+}
+
+func (r *testRemote) head(t *testing.T, branch string) string {
+	t.Helper()
+	ref, err := r.repo(t).Reference(plumbing.NewBranchReferenceName(branch), true)
+	require.NoError(t, err)
+	return ref.Hash().String()
+}
+
+func (r *testRemote) commitObject(t *testing.T, hash string) *object.Commit {
+	t.Helper()
+	commit, err := r.repo(t).CommitObject(plumbing.NewHash(hash))
+	require.NoError(t, err)
+	return commit
+}
+
+// fakeDashboards is an in-memory dashboard store.
+type fakeDashboards struct {
+	dashboards.DashboardService
+	dashboards.DashboardProvisioningService
+
+	nextID      int64
+	byID        map[int64]*models.Dashboard
+	provisioned map[int64]*models.DashboardProvisioning
+}
+
+func newFakeDashboards() *fakeDashboards {
+	return &fakeDashboards{
+		byID:        map[int64]*models.Dashboard{},
+		provisioned: map[int64]*models.DashboardProvisioning{},
+	}
+}
+
+func (f *fakeDashboards) get(uid string) *models.Dashboard {
+	for _, d := range f.byID {
+		if d.Uid == uid {
+			return d
+		}
+	}
+	return nil
+}
+
+func (f *fakeDashboards) add(dash *models.Dashboard) *models.Dashboard {
+	if dash.Id == 0 {
+		f.nextID++
+		dash.Id = f.nextID
+	}
+	if dash.Data == nil {
+		dash.Data = simplejson.NewFromAny(map[string]interface{}{"uid": dash.Uid, "title": dash.Title})
+	}
+	f.byID[dash.Id] = dash
+	return dash
+}
+
+func (f *fakeDashboards) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	if dash := f.get(query.Uid); dash != nil {
+		query.Result = dash
+		return nil
+	}
+	return dashboards.ErrDashboardNotFound
+}
+
+func (f *fakeDashboards) DeleteDashboard(_ context.Context, id int64, _ int64) error {
+	delete(f.byID, id)
+	return nil
+}
+
+func (f *fakeDashboards) CountDashboardsInFolder(_ context.Context, query *dashboards.CountDashboardsInFolderQuery) (int64, error) {
+	folder := f.get(query.FolderUID)
+	var count int64
+	for _, d := range f.byID {
+		if folder != nil && d.FolderId == folder.Id {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeDashboards) GetProvisionedDashboardData(_ context.Context, name string) ([]*models.DashboardProvisioning, error) {
+	result := make([]*models.DashboardProvisioning, 0)
+	for _, p := range f.provisioned {
+		if p.Name == name {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeDashboards) SaveFolderForProvisionedDashboards(_ context.Context, dto *dashboards.SaveDashboardDTO) (*models.Dashboard, error) {
+	return f.add(dto.Dashboard), nil
+}
+
+func (f *fakeDashboards) SaveProvisionedDashboard(_ context.Context, dto *dashboards.SaveDashboardDTO, p *models.DashboardProvisioning) (*models.Dashboard, error) {
+	dash := f.add(dto.Dashboard)
+	p.DashboardId = dash.Id
+	f.provisioned[dash.Id] = p
+	return dash, nil
+}
+
+func (f *fakeDashboards) DeleteProvisionedDashboard(_ context.Context, id int64, _ int64) error {
+	delete(f.byID, id)
+	delete(f.provisioned, id)
+	return nil
+}
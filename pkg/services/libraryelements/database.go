@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -148,7 +149,10 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		if err := store.InsertEntityEvents(session, l.features, element.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeCreate, element.UID); err != nil {
+			return err
+		}
+		return saveLibraryElementVersion(session, element, 0, 0, "")
 	})
 
 	dto := LibraryElementDTO{
@@ -209,6 +213,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 			return errLibraryElementHasConnections
 		}
 
+		if _, err := session.Exec("DELETE FROM library_element_version WHERE element_id=?", element.ID); err != nil {
+			return err
+		}
 		result, err := session.Exec("DELETE FROM library_element WHERE id=?", element.ID)
 		if err != nil {
 			return err
@@ -218,6 +225,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 		} else if rowsAffected != 1 {
 			return ErrLibraryElementNotFound
 		}
+		if err := store.InsertEntityEvents(session, l.features, element.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeDelete, element.UID); err != nil {
+			return err
+		}
 
 		elementID = element.ID
 		return nil
@@ -525,6 +535,17 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return ErrLibraryElementNotFound
 		}
+		if err := saveLibraryElementVersion(session, libraryElement, elementInDB.Version, 0, cmd.Message); err != nil {
+			return err
+		}
+		if updateUID != uid {
+			if err := store.InsertEntityEvents(session, l.features, libraryElement.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeDelete, uid); err != nil {
+				return err
+			}
+		}
+		if err := store.InsertEntityEvents(session, l.features, libraryElement.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeUpdate, updateUID); err != nil {
+			return err
+		}
 
 		dto = LibraryElementDTO{
 			ID:          libraryElement.ID,
@@ -744,17 +765,24 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 		}
 
 		var elementIDs []struct {
-			ID int64 `xorm:"id"`
+			ID  int64  `xorm:"id"`
+			UID string `xorm:"uid"`
 		}
-		err = session.SQL("SELECT id from library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.OrgID).Find(&elementIDs)
+		err = session.SQL("SELECT id, uid from library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.OrgID).Find(&elementIDs)
 		if err != nil {
 			return err
 		}
 		for _, elementID := range elementIDs {
+			if err := store.InsertEntityEvents(session, l.features, signedInUser.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeDelete, elementID.UID); err != nil {
+				return err
+			}
 			_, err := session.Exec("DELETE FROM "+models.LibraryElementConnectionTableName+" WHERE element_id=?", elementID.ID)
 			if err != nil {
 				return err
 			}
+			if _, err := session.Exec("DELETE FROM library_element_version WHERE element_id=?", elementID.ID); err != nil {
+				return err
+			}
 		}
 		if _, err := session.Exec("DELETE FROM library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.OrgID); err != nil {
 			return err
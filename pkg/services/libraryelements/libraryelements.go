@@ -6,17 +6,21 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, folderService folder.Service) *LibraryElementService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, folderService folder.Service, live *live.GrafanaLive, features featuremgmt.FeatureToggles) *LibraryElementService {
 	l := &LibraryElementService{
 		Cfg:           cfg,
 		SQLStore:      sqlStore,
 		RouteRegister: routeRegister,
 		folderService: folderService,
+		live:          live,
+		features:      features,
 		log:           log.New("library-elements"),
 	}
 	l.registerAPIEndpoints()
@@ -39,6 +43,8 @@ type LibraryElementService struct {
 	SQLStore      db.DB
 	RouteRegister routing.RouteRegister
 	folderService folder.Service
+	live          *live.GrafanaLive
+	features      featuremgmt.FeatureToggles
 	log           log.Logger
 }
 
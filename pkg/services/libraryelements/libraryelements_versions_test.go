@@ -0,0 +1,153 @@
+package libraryelements
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestLibraryElementVersions(t *testing.T) {
+	scenarioWithPanel(t, "When an admin creates a library panel, it should save the first version",
+		func(t *testing.T, sc scenarioContext) {
+			versions := getVersions(t, sc)
+			require.Len(t, versions, 1)
+			require.Equal(t, int64(1), versions[0].Version)
+			require.Equal(t, int64(0), versions[0].ParentVersion)
+			require.Equal(t, "Initial save", versions[0].Message)
+			require.Equal(t, "Text - Library Panel", versions[0].Name)
+		})
+
+	scenarioWithPanel(t, "When an admin patches a library panel, it should save a new version",
+		func(t *testing.T, sc scenarioContext) {
+			patchPanelDescription(t, sc, 1, "An updated description", "Updated description")
+
+			versions := getVersions(t, sc)
+			require.Len(t, versions, 2)
+			require.Equal(t, int64(2), versions[0].Version)
+			require.Equal(t, int64(1), versions[0].ParentVersion)
+			require.Equal(t, "Updated description", versions[0].Message)
+			require.Equal(t, int64(1), versions[1].Version)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result LibraryElementVersionResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, "A description", modelDescription(t, result.Result.Model))
+		})
+
+	scenarioWithPanel(t, "When an admin gets a library panel version that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "5"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "unknown", ":version": "1"})
+			resp = sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin compares two library panel versions, it should return the diff",
+		func(t *testing.T, sc scenarioContext) {
+			patchPanelDescription(t, sc, 1, "An updated description", "")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(CalculateLibraryElementDiffCommand{Base: 1, New: 2, DiffType: "delta"})
+			resp := sc.service.calculateDiffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.Contains(t, string(resp.Body()), "An updated description")
+
+			sc.reqContext.Req.Body = mockRequestBody(CalculateLibraryElementDiffCommand{Base: 1, New: 3, DiffType: "delta"})
+			resp = sc.service.calculateDiffHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin restores a library panel version, it should save it as a new version",
+		func(t *testing.T, sc scenarioContext) {
+			patchPanelDescription(t, sc, 1, "An updated description", "")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(RestoreLibraryElementVersionCommand{Version: 1})
+			resp := sc.service.restoreHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			result := validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, int64(3), result.Result.Version)
+			require.Equal(t, "A description", result.Result.Description)
+			require.Equal(t, "A description", result.Result.Model["description"])
+
+			versions := getVersions(t, sc)
+			require.Len(t, versions, 3)
+			require.Equal(t, int64(3), versions[0].Version)
+			require.Equal(t, int64(2), versions[0].ParentVersion)
+			require.Equal(t, int64(1), versions[0].RestoredFrom)
+			require.Equal(t, "Restored from version 1", versions[0].Message)
+		})
+
+	scenarioWithPanel(t, "When an admin restores a library panel version that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(RestoreLibraryElementVersionCommand{Version: 5})
+			resp := sc.service.restoreHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin deletes a library panel, it should delete its versions",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.deleteHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			err := sc.sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+				count, err := session.Table("library_element_version").Count()
+				require.Equal(t, int64(0), count)
+				return err
+			})
+			require.NoError(t, err)
+		})
+}
+
+func getVersions(t *testing.T, sc scenarioContext) []LibraryElementVersionDTO {
+	t.Helper()
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+	resp := sc.service.getVersionsHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+	var result LibraryElementVersionsResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	return result.Result
+}
+
+func patchPanelDescription(t *testing.T, sc scenarioContext, version int64, description string, message string) {
+	t.Helper()
+	model, err := json.Marshal(map[string]interface{}{
+		"datasource":  "${DS_GDEV-TESTDATA}",
+		"id":          1,
+		"title":       "Text - Library Panel",
+		"type":        "text",
+		"description": description,
+	})
+	require.NoError(t, err)
+	cmd := PatchLibraryElementCommand{
+		FolderID: -1,
+		Model:    model,
+		Kind:     int64(models.PanelElement),
+		Version:  version,
+		Message:  message,
+	}
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	resp := sc.service.patchHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+}
+
+func modelDescription(t *testing.T, model json.RawMessage) string {
+	t.Helper()
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(model, &m))
+	return m["description"].(string)
+}
@@ -138,6 +138,50 @@ type LibraryElementConnectionDTO struct {
 	CreatedBy     LibraryElementDTOMetaUser `json:"createdBy"`
 }
 
+// libraryElementVersion is the model for library element versions.
+type libraryElementVersion struct {
+	ID            int64 `xorm:"pk autoincr 'id'"`
+	ElementID     int64 `xorm:"element_id"`
+	ParentVersion int64
+	RestoredFrom  int64
+	Version       int64
+	Name          string
+	Model         json.RawMessage
+	Message       string
+	Created       time.Time
+	CreatedBy     int64
+}
+
+// libraryElementVersionWithMeta is the model for library element versions with meta.
+type libraryElementVersionWithMeta struct {
+	ID             int64 `xorm:"pk autoincr 'id'"`
+	ElementID      int64 `xorm:"element_id"`
+	ParentVersion  int64
+	RestoredFrom   int64
+	Version        int64
+	Name           string
+	Model          json.RawMessage
+	Message        string
+	Created        time.Time
+	CreatedBy      int64
+	CreatedByName  string
+	CreatedByEmail string
+}
+
+// LibraryElementVersionDTO is the frontend DTO for library element versions.
+type LibraryElementVersionDTO struct {
+	ID            int64                     `json:"id"`
+	ElementUID    string                    `json:"elementUid"`
+	ParentVersion int64                     `json:"parentVersion"`
+	RestoredFrom  int64                     `json:"restoredFrom"`
+	Version       int64                     `json:"version"`
+	Name          string                    `json:"name"`
+	Model         json.RawMessage           `json:"model,omitempty"`
+	Message       string                    `json:"message"`
+	Created       time.Time                 `json:"created"`
+	CreatedBy     LibraryElementDTOMetaUser `json:"createdBy"`
+}
+
 var (
 	// errLibraryElementAlreadyExists is an error for when the user tries to add a library element that already exists.
 	errLibraryElementAlreadyExists = errors.New("library element with that name or UID already exists")
@@ -157,6 +201,8 @@ var (
 	errLibraryElementInvalidUID = errors.New("uid contains illegal characters")
 	// errLibraryElementUIDTooLong is an error for when the uid of a library element is invalid
 	errLibraryElementUIDTooLong = errors.New("uid too long, max 40 characters")
+	// errLibraryElementVersionNotFound is an error for when a library element version can't be found.
+	errLibraryElementVersionNotFound = errors.New("library element version could not be found")
 )
 
 // Commands
@@ -203,6 +249,35 @@ type PatchLibraryElementCommand struct {
 	Version int64 `json:"version" binding:"Required"`
 	// required: false
 	UID string `json:"uid"`
+	// Message describing the change, saved in the version history.
+	// required: false
+	Message string `json:"message"`
+}
+
+// RestoreLibraryElementVersionCommand is the command for restoring a LibraryElement to a previous version
+type RestoreLibraryElementVersionCommand struct {
+	// Version of the library element to restore.
+	Version int64 `json:"version" binding:"Required"`
+}
+
+// CalculateLibraryElementDiffCommand is the command for comparing two versions of a LibraryElement
+type CalculateLibraryElementDiffCommand struct {
+	// Version used as the base of the comparison.
+	Base int64 `json:"base" binding:"Required"`
+	// Version compared with the base version.
+	New int64 `json:"new" binding:"Required"`
+	// The type of diff to return
+	// Description:
+	// * `basic`
+	// * `json`
+	// Enum: basic,json
+	DiffType string `json:"diffType"`
+}
+
+// listLibraryElementVersionsQuery is the query used for listing versions of a LibraryElement
+type listLibraryElementVersionsQuery struct {
+	limit int
+	start int
 }
 
 // searchLibraryElementsQuery is the query used for searching for Elements
@@ -238,6 +313,16 @@ type LibraryElementConnectionsResponse struct {
 	Result []LibraryElementConnectionDTO `json:"result"`
 }
 
+// LibraryElementVersionsResponse is a response struct for an array of LibraryElementVersionDTO.
+type LibraryElementVersionsResponse struct {
+	Result []LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementVersionResponse is a response struct for LibraryElementVersionDTO.
+type LibraryElementVersionResponse struct {
+	Result LibraryElementVersionDTO `json:"result"`
+}
+
 // DeleteLibraryElementResponse is the response struct for deleting a library element.
 type DeleteLibraryElementResponse struct {
 	ID      int64  `json:"id"`
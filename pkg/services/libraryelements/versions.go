@@ -0,0 +1,250 @@
+package libraryelements
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// saveLibraryElementVersion stores the current state of a library element as a new version.
+func saveLibraryElementVersion(session *db.Session, element LibraryElement, parentVersion int64, restoredFrom int64, message string) error {
+	version := libraryElementVersion{
+		ElementID:     element.ID,
+		ParentVersion: parentVersion,
+		RestoredFrom:  restoredFrom,
+		Version:       element.Version,
+		Name:          element.Name,
+		Model:         element.Model,
+		Message:       message,
+		Created:       element.Updated,
+		CreatedBy:     element.UpdatedBy,
+	}
+	_, err := session.Insert(&version)
+	return err
+}
+
+func getLibraryElementVersion(dialect migrator.Dialect, session *db.Session, elementID int64, version int64) (libraryElementVersionWithMeta, error) {
+	versions := make([]libraryElementVersionWithMeta, 0)
+	sql := "SELECT lev.*, u.login AS created_by_name, u.email AS created_by_email" +
+		" FROM library_element_version AS lev" +
+		" LEFT JOIN " + dialect.Quote("user") + " AS u ON lev.created_by = u.id" +
+		" WHERE lev.element_id=? AND lev.version=?"
+	if err := session.SQL(sql, elementID, version).Find(&versions); err != nil {
+		return libraryElementVersionWithMeta{}, err
+	}
+	if len(versions) == 0 {
+		return libraryElementVersionWithMeta{}, errLibraryElementVersionNotFound
+	}
+	return versions[0], nil
+}
+
+func toLibraryElementVersionDTO(elementUID string, version libraryElementVersionWithMeta) LibraryElementVersionDTO {
+	message := version.Message
+	switch {
+	case version.RestoredFrom == version.Version:
+		message = "Initial save (created by migration)"
+	case version.RestoredFrom > 0:
+		message = fmt.Sprintf("Restored from version %d", version.RestoredFrom)
+	case version.ParentVersion == 0 && message == "":
+		message = "Initial save"
+	}
+
+	return LibraryElementVersionDTO{
+		ID:            version.ID,
+		ElementUID:    elementUID,
+		ParentVersion: version.ParentVersion,
+		RestoredFrom:  version.RestoredFrom,
+		Version:       version.Version,
+		Name:          version.Name,
+		Model:         version.Model,
+		Message:       message,
+		Created:       version.Created,
+		CreatedBy: LibraryElementDTOMetaUser{
+			ID:        version.CreatedBy,
+			Name:      version.CreatedByName,
+			AvatarURL: dtos.GetGravatarUrl(version.CreatedByEmail),
+		},
+	}
+}
+
+// getLibraryElementVersions gets the versions of a Library Element, most recent first.
+func (l *LibraryElementService) getLibraryElementVersions(c context.Context, signedInUser *user.SignedInUser, uid string, query listLibraryElementVersionsQuery) ([]LibraryElementVersionDTO, error) {
+	// Checks that the element exists and the user can view it.
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return nil, err
+	}
+	if query.limit <= 0 {
+		query.limit = 1000
+	}
+	if query.start < 0 {
+		query.start = 0
+	}
+
+	result := make([]LibraryElementVersionDTO, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		var versions []libraryElementVersionWithMeta
+		sql := "SELECT lev.id, lev.element_id, lev.parent_version, lev.restored_from, lev.version, lev.name, lev.message, lev.created, lev.created_by" +
+			", u.login AS created_by_name, u.email AS created_by_email" +
+			" FROM library_element_version AS lev" +
+			" LEFT JOIN " + l.SQLStore.GetDialect().Quote("user") + " AS u ON lev.created_by = u.id" +
+			" WHERE lev.element_id=?" +
+			" ORDER BY lev.version DESC" +
+			l.SQLStore.GetDialect().LimitOffset(int64(query.limit), int64(query.start))
+		if err := session.SQL(sql, element.ID).Find(&versions); err != nil {
+			return err
+		}
+		for _, version := range versions {
+			result = append(result, toLibraryElementVersionDTO(element.UID, version))
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// getLibraryElementVersionByUID gets a specific version of a Library Element.
+func (l *LibraryElementService) getLibraryElementVersionByUID(c context.Context, signedInUser *user.SignedInUser, uid string, version int64) (LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return LibraryElementVersionDTO{}, err
+	}
+
+	var dto LibraryElementVersionDTO
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		v, err := getLibraryElementVersion(l.SQLStore.GetDialect(), session, element.ID, version)
+		if err != nil {
+			return err
+		}
+		dto = toLibraryElementVersionDTO(element.UID, v)
+		return nil
+	})
+
+	return dto, err
+}
+
+// calculateLibraryElementDiff computes the diff between the models of two versions of a Library Element.
+func (l *LibraryElementService) calculateLibraryElementDiff(c context.Context, signedInUser *user.SignedInUser, uid string, cmd CalculateLibraryElementDiffCommand) (*dashdiffs.Result, dashdiffs.DiffType, error) {
+	diffType := dashdiffs.ParseDiffType(cmd.DiffType)
+	baseVersion, err := l.getLibraryElementVersionByUID(c, signedInUser, uid, cmd.Base)
+	if err != nil {
+		return nil, diffType, err
+	}
+	newVersion, err := l.getLibraryElementVersionByUID(c, signedInUser, uid, cmd.New)
+	if err != nil {
+		return nil, diffType, err
+	}
+
+	baseData, err := simplejson.NewJson(baseVersion.Model)
+	if err != nil {
+		return nil, diffType, err
+	}
+	newData, err := simplejson.NewJson(newVersion.Model)
+	if err != nil {
+		return nil, diffType, err
+	}
+
+	options := dashdiffs.Options{
+		OrgId:    signedInUser.OrgID,
+		DiffType: diffType,
+	}
+	result, err := dashdiffs.CalculateDiff(c, &options, baseData, newData)
+	return result, diffType, err
+}
+
+// restoreLibraryElementVersion restores a Library Element to a previous version.
+// The restored model is saved as a new version and connected dashboards are notified.
+func (l *LibraryElementService) restoreLibraryElementVersion(c context.Context, signedInUser *user.SignedInUser, uid string, cmd RestoreLibraryElementVersionCommand) (LibraryElementDTO, error) {
+	err := l.SQLStore.WithTransactionalDbSession(c, func(session *db.Session) error {
+		elementInDB, err := getLibraryElement(l.SQLStore.GetDialect(), session, uid, signedInUser.OrgID)
+		if err != nil {
+			return err
+		}
+		if err := l.requireEditPermissionsOnFolder(c, signedInUser, elementInDB.FolderID); err != nil {
+			return err
+		}
+		version, err := getLibraryElementVersion(l.SQLStore.GetDialect(), session, elementInDB.ID, cmd.Version)
+		if err != nil {
+			return err
+		}
+
+		libraryElement := LibraryElement{
+			ID:          elementInDB.ID,
+			OrgID:       elementInDB.OrgID,
+			FolderID:    elementInDB.FolderID,
+			UID:         elementInDB.UID,
+			Name:        version.Name,
+			Kind:        elementInDB.Kind,
+			Type:        elementInDB.Type,
+			Description: elementInDB.Description,
+			Model:       version.Model,
+			Version:     elementInDB.Version + 1,
+			Created:     elementInDB.Created,
+			CreatedBy:   elementInDB.CreatedBy,
+			Updated:     time.Now(),
+			UpdatedBy:   signedInUser.UserID,
+		}
+		if err := syncFieldsWithModel(&libraryElement); err != nil {
+			return err
+		}
+		if rowsAffected, err := session.ID(elementInDB.ID).Update(&libraryElement); err != nil {
+			if l.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return errLibraryElementAlreadyExists
+			}
+			return err
+		} else if rowsAffected != 1 {
+			return ErrLibraryElementNotFound
+		}
+		if err := store.InsertEntityEvents(session, l.features, libraryElement.OrgID, store.EntityTypeLibraryPanel, store.EntityEventTypeUpdate, libraryElement.UID); err != nil {
+			return err
+		}
+
+		return saveLibraryElementVersion(session, libraryElement, elementInDB.Version, cmd.Version, "")
+	})
+	if err != nil {
+		return LibraryElementDTO{}, err
+	}
+
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return LibraryElementDTO{}, err
+	}
+	l.notifyConnectedDashboards(c, signedInUser, element, fmt.Sprintf("Library panel %s restored to version %d", element.Name, cmd.Version))
+
+	return element, nil
+}
+
+// notifyConnectedDashboards broadcasts a save event to all dashboards connected to a Library Element
+// so that users viewing them pick up the change.
+func (l *LibraryElementService) notifyConnectedDashboards(c context.Context, signedInUser *user.SignedInUser, element LibraryElementDTO, message string) {
+	if l.live == nil || l.live.GrafanaScope.Dashboards == nil {
+		return
+	}
+
+	var connectedDashboards []*models.Dashboard
+	err := l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		sql := "SELECT dashboard.* FROM dashboard" +
+			" INNER JOIN " + models.LibraryElementConnectionTableName + " AS lec ON lec.connection_id = dashboard.id" +
+			" WHERE lec.element_id=? AND lec.kind=1"
+		return session.SQL(sql, element.ID).Find(&connectedDashboards)
+	})
+	if err != nil {
+		l.log.Error("Failed to get connected dashboards", "uid", element.UID, "error", err)
+		return
+	}
+
+	for _, dash := range connectedDashboards {
+		if err := l.live.GrafanaScope.Dashboards.DashboardSaved(signedInUser.OrgID, signedInUser.ToUserDisplayDTO(), message, dash, nil); err != nil {
+			l.log.Error("Failed to broadcast library element change", "uid", element.UID, "dashboard", dash.Uid, "error", err)
+		}
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/prometheus"
 	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
 )
 
 type Converter struct {
 	telegrafConverterWide         *telegraf.Converter
 	telegrafConverterLabelsColumn *telegraf.Converter
+	prometheusConverter           *prometheus.Converter
+	otlpConverter                 *otlp.Converter
 }
 
 func NewConverter() *Converter {
@@ -22,11 +26,16 @@ func NewConverter() *Converter {
 			telegraf.WithUseLabelsColumn(true),
 			telegraf.WithFloat64Numbers(true),
 		),
+		prometheusConverter: prometheus.NewConverter(),
+		otlpConverter:       otlp.NewConverter(),
 	}
 }
 
 var ErrUnsupportedFrameFormat = errors.New("unsupported frame format")
 
+var ErrUnsupportedInputFormat = errors.New("unsupported input format")
+
+// Convert Influx line protocol.
 func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
 	var converter telemetry.Converter
 	switch frameFormat {
@@ -44,3 +53,26 @@ func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameW
 	}
 	return metricFrames, nil
 }
+
+// ConvertInput converts data in the given input format. Frame format only
+// applies to Influx line protocol, Prometheus and OTLP metrics are always
+// converted to frames with a labeled field per series.
+func (c *Converter) ConvertInput(data []byte, inputFormat string, frameFormat string) ([]telemetry.FrameWrapper, error) {
+	var converter telemetry.Converter
+	switch inputFormat {
+	case "influx":
+		return c.Convert(data, frameFormat)
+	case "prometheus":
+		converter = c.prometheusConverter
+	case "otlp":
+		converter = c.otlpConverter
+	default:
+		return nil, ErrUnsupportedInputFormat
+	}
+
+	metricFrames, err := converter.Convert(data)
+	if err != nil {
+		return nil, fmt.Errorf("error converting metrics: %w", err)
+	}
+	return metricFrames, nil
+}
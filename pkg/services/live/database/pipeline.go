@@ -0,0 +1,413 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var _ pipeline.Storage = (*PipelineStorage)(nil)
+
+// PipelineStorage keeps Live pipeline channel rules and write configs in
+// Grafana database. Every row has a version which is incremented on each
+// update, updates and deletes with a non-zero expected version fail with
+// pipeline.ErrVersionConflict when the row was modified concurrently.
+type PipelineStorage struct {
+	store          db.DB
+	secretsService secrets.Service
+}
+
+func NewPipelineStorage(store db.DB, secretsService secrets.Service) *PipelineStorage {
+	return &PipelineStorage{store: store, secretsService: secretsService}
+}
+
+type liveChannelRule struct {
+	Id       int64
+	OrgId    int64
+	Pattern  string
+	Settings string
+	Version  int64
+	Created  time.Time
+	Updated  time.Time
+}
+
+type liveWriteConfig struct {
+	Id             int64
+	OrgId          int64
+	Uid            string
+	Settings       string
+	SecureSettings string
+	Version        int64
+	Created        time.Time
+	Updated        time.Time
+}
+
+const (
+	channelRuleTable = "live_channel_rule"
+	writeConfigTable = "live_write_config"
+)
+
+func (r liveChannelRule) toChannelRule() (pipeline.ChannelRule, error) {
+	rule := pipeline.ChannelRule{
+		OrgId:   r.OrgId,
+		Pattern: r.Pattern,
+		Version: r.Version,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+		return pipeline.ChannelRule{}, fmt.Errorf("can't unmarshal channel rule %s settings: %w", r.Pattern, err)
+	}
+	return rule, nil
+}
+
+func (c liveWriteConfig) toWriteConfig() (pipeline.WriteConfig, error) {
+	writeConfig := pipeline.WriteConfig{
+		OrgId:   c.OrgId,
+		UID:     c.Uid,
+		Version: c.Version,
+	}
+	if err := json.Unmarshal([]byte(c.Settings), &writeConfig.Settings); err != nil {
+		return pipeline.WriteConfig{}, fmt.Errorf("can't unmarshal write config %s settings: %w", c.Uid, err)
+	}
+	if c.SecureSettings != "" {
+		if err := json.Unmarshal([]byte(c.SecureSettings), &writeConfig.SecureSettings); err != nil {
+			return pipeline.WriteConfig{}, fmt.Errorf("can't unmarshal write config %s secure settings: %w", c.Uid, err)
+		}
+	}
+	return writeConfig, nil
+}
+
+func (s *PipelineStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]pipeline.WriteConfig, error) {
+	var rows []liveWriteConfig
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(writeConfigTable).Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	writeConfigs := make([]pipeline.WriteConfig, 0, len(rows))
+	for _, row := range rows {
+		writeConfig, err := row.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		writeConfigs = append(writeConfigs, writeConfig)
+	}
+	return writeConfigs, nil
+}
+
+func (s *PipelineStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigGetCmd) (pipeline.WriteConfig, bool, error) {
+	var row liveWriteConfig
+	var exists bool
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Table(writeConfigTable).Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&row)
+		return err
+	})
+	if err != nil {
+		return pipeline.WriteConfig{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	if !exists {
+		return pipeline.WriteConfig{}, false, nil
+	}
+	writeConfig, err := row.toWriteConfig()
+	if err != nil {
+		return pipeline.WriteConfig{}, false, err
+	}
+	return writeConfig, true, nil
+}
+
+func (s *PipelineStorage) newWriteConfig(ctx context.Context, orgID int64, uid string, settings pipeline.WriteSettings, secureSettings map[string]string) (pipeline.WriteConfig, liveWriteConfig, error) {
+	encrypted, err := s.secretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return pipeline.WriteConfig{}, liveWriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	writeConfig := pipeline.WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encrypted,
+	}
+	if ok, reason := writeConfig.Valid(); !ok {
+		return pipeline.WriteConfig{}, liveWriteConfig{}, fmt.Errorf("%w: %s", pipeline.ErrInvalidWriteConfig, reason)
+	}
+	settingsJSON, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return pipeline.WriteConfig{}, liveWriteConfig{}, err
+	}
+	secureSettingsJSON, err := json.Marshal(writeConfig.SecureSettings)
+	if err != nil {
+		return pipeline.WriteConfig{}, liveWriteConfig{}, err
+	}
+	row := liveWriteConfig{
+		OrgId:          orgID,
+		Uid:            uid,
+		Settings:       string(settingsJSON),
+		SecureSettings: string(secureSettingsJSON),
+	}
+	return writeConfig, row, nil
+}
+
+func (s *PipelineStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigCreateCmd) (pipeline.WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	writeConfig, row, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return pipeline.WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return s.insertWriteConfig(sess, &row)
+	})
+	if err != nil {
+		return pipeline.WriteConfig{}, err
+	}
+	writeConfig.Version = row.Version
+	return writeConfig, nil
+}
+
+func (s *PipelineStorage) insertWriteConfig(sess *db.Session, row *liveWriteConfig) error {
+	exists, err := sess.Table(writeConfigTable).Where("org_id = ? AND uid = ?", row.OrgId, row.Uid).Exist()
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", pipeline.ErrWriteConfigExists, row.Uid)
+	}
+	now := time.Now()
+	row.Version = 1
+	row.Created = now
+	row.Updated = now
+	if _, err := sess.Table(writeConfigTable).Insert(row); err != nil {
+		if s.store.GetDialect().IsUniqueConstraintViolation(err) {
+			return fmt.Errorf("%w: %s", pipeline.ErrWriteConfigExists, row.Uid)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *PipelineStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigUpdateCmd) (pipeline.WriteConfig, error) {
+	writeConfig, row, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return pipeline.WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveWriteConfig
+		exists, err := sess.Table(writeConfigTable).Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// Same as file storage update creates missing write config.
+			if cmd.Version != 0 {
+				return pipeline.ErrWriteConfigNotFound
+			}
+			return s.insertWriteConfig(sess, &row)
+		}
+		if err := pipeline.CheckVersion(cmd.Version, existing.Version); err != nil {
+			return err
+		}
+		row.Version = existing.Version + 1
+		row.Updated = time.Now()
+		affected, err := sess.Table(writeConfigTable).
+			Where("id = ? AND version = ?", existing.Id, existing.Version).
+			Cols("settings", "secure_settings", "version", "updated").
+			Update(&row)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return pipeline.ErrVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return pipeline.WriteConfig{}, err
+	}
+	writeConfig.Version = row.Version
+	return writeConfig, nil
+}
+
+func (s *PipelineStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd pipeline.WriteConfigDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveWriteConfig
+		exists, err := sess.Table(writeConfigTable).Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return pipeline.ErrWriteConfigNotFound
+		}
+		if err := pipeline.CheckVersion(cmd.Version, existing.Version); err != nil {
+			return err
+		}
+		affected, err := sess.Table(writeConfigTable).Where("id = ? AND version = ?", existing.Id, existing.Version).Delete(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return pipeline.ErrVersionConflict
+		}
+		return nil
+	})
+}
+
+func (s *PipelineStorage) ListChannelRules(ctx context.Context, orgID int64) ([]pipeline.ChannelRule, error) {
+	var rows []liveChannelRule
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return listChannelRules(sess, orgID, &rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	return toChannelRules(rows)
+}
+
+func listChannelRules(sess *db.Session, orgID int64, rows *[]liveChannelRule) error {
+	return sess.Table(channelRuleTable).Where("org_id = ?", orgID).Asc("pattern").Find(rows)
+}
+
+func toChannelRules(rows []liveChannelRule) ([]pipeline.ChannelRule, error) {
+	rules := make([]pipeline.ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := row.toChannelRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func newChannelRule(orgID int64, pattern string, settings pipeline.ChannelRuleSettings) (pipeline.ChannelRule, liveChannelRule, error) {
+	rule := pipeline.ChannelRule{
+		OrgId:    orgID,
+		Pattern:  pattern,
+		Settings: settings,
+	}
+	if ok, reason := rule.Valid(); !ok {
+		return pipeline.ChannelRule{}, liveChannelRule{}, fmt.Errorf("%w: %s", pipeline.ErrInvalidChannelRule, reason)
+	}
+	settingsJSON, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return pipeline.ChannelRule{}, liveChannelRule{}, err
+	}
+	return rule, liveChannelRule{OrgId: orgID, Pattern: pattern, Settings: string(settingsJSON)}, nil
+}
+
+func (s *PipelineStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd pipeline.ChannelRuleCreateCmd) (pipeline.ChannelRule, error) {
+	rule, row, err := newChannelRule(orgID, cmd.Pattern, cmd.Settings)
+	if err != nil {
+		return pipeline.ChannelRule{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return s.insertChannelRule(sess, rule, &row)
+	})
+	if err != nil {
+		return pipeline.ChannelRule{}, err
+	}
+	rule.Version = row.Version
+	return rule, nil
+}
+
+func (s *PipelineStorage) insertChannelRule(sess *db.Session, rule pipeline.ChannelRule, row *liveChannelRule) error {
+	var rows []liveChannelRule
+	if err := listChannelRules(sess, row.OrgId, &rows); err != nil {
+		return err
+	}
+	rules, err := toChannelRules(rows)
+	if err != nil {
+		return err
+	}
+	for _, existing := range rules {
+		if existing.Pattern == row.Pattern {
+			return fmt.Errorf("%w: %s", pipeline.ErrChannelRuleExists, row.Pattern)
+		}
+	}
+	if ok, reason := pipeline.CheckRulesValid(row.OrgId, append(rules, rule)); !ok {
+		return fmt.Errorf("%w: %s", pipeline.ErrInvalidChannelRule, reason)
+	}
+	now := time.Now()
+	row.Version = 1
+	row.Created = now
+	row.Updated = now
+	if _, err := sess.Table(channelRuleTable).Insert(row); err != nil {
+		if s.store.GetDialect().IsUniqueConstraintViolation(err) {
+			return fmt.Errorf("%w: %s", pipeline.ErrChannelRuleExists, row.Pattern)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *PipelineStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	rule, row, err := newChannelRule(orgID, cmd.Pattern, cmd.Settings)
+	if err != nil {
+		return pipeline.ChannelRule{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveChannelRule
+		exists, err := sess.Table(channelRuleTable).Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// Same as file storage update creates missing channel rule.
+			if cmd.Version != 0 {
+				return pipeline.ErrChannelRuleNotFound
+			}
+			return s.insertChannelRule(sess, rule, &row)
+		}
+		if err := pipeline.CheckVersion(cmd.Version, existing.Version); err != nil {
+			return err
+		}
+		row.Version = existing.Version + 1
+		row.Updated = time.Now()
+		affected, err := sess.Table(channelRuleTable).
+			Where("id = ? AND version = ?", existing.Id, existing.Version).
+			Cols("settings", "version", "updated").
+			Update(&row)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return pipeline.ErrVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return pipeline.ChannelRule{}, err
+	}
+	rule.Version = row.Version
+	return rule, nil
+}
+
+func (s *PipelineStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd pipeline.ChannelRuleDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveChannelRule
+		exists, err := sess.Table(channelRuleTable).Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return pipeline.ErrChannelRuleNotFound
+		}
+		if err := pipeline.CheckVersion(cmd.Version, existing.Version); err != nil {
+			return err
+		}
+		affected, err := sess.Table(channelRuleTable).Where("id = ? AND version = ?", existing.Id, existing.Version).Delete(&liveChannelRule{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return pipeline.ErrVersionConflict
+		}
+		return nil
+	})
+}
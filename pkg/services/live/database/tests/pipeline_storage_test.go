@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationPipelineChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	storage := SetupTestPipelineStorage(t)
+	ctx := context.Background()
+
+	rules, err := storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	settings := pipeline.ChannelRuleSettings{
+		Converter: &pipeline.ConverterConfig{Type: pipeline.ConverterTypeJsonAuto},
+	}
+	rule, err := storage.CreateChannelRule(ctx, 1, pipeline.ChannelRuleCreateCmd{
+		Pattern:  "stream/test/:path",
+		Settings: settings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.Version)
+
+	_, err = storage.CreateChannelRule(ctx, 1, pipeline.ChannelRuleCreateCmd{
+		Pattern:  "stream/test/:path",
+		Settings: settings,
+	})
+	require.ErrorIs(t, err, pipeline.ErrChannelRuleExists)
+
+	_, err = storage.CreateChannelRule(ctx, 1, pipeline.ChannelRuleCreateCmd{
+		Pattern:  "stream/test/:other",
+		Settings: settings,
+	})
+	require.ErrorIs(t, err, pipeline.ErrInvalidChannelRule)
+
+	_, err = storage.CreateChannelRule(ctx, 1, pipeline.ChannelRuleCreateCmd{
+		Pattern: "stream/test/invalid",
+		Settings: pipeline.ChannelRuleSettings{
+			Converter: &pipeline.ConverterConfig{Type: "unknown"},
+		},
+	})
+	require.ErrorIs(t, err, pipeline.ErrInvalidChannelRule)
+
+	// Other orgs don't see the rule.
+	rules, err = storage.ListChannelRules(ctx, 2)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	settings.FrameOutputters = []*pipeline.FrameOutputterConfig{{Type: pipeline.FrameOutputTypeManagedStream}}
+	rule, err = storage.UpdateChannelRule(ctx, 1, pipeline.ChannelRuleUpdateCmd{
+		Pattern:  "stream/test/:path",
+		Settings: settings,
+		Version:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rule.Version)
+
+	_, err = storage.UpdateChannelRule(ctx, 1, pipeline.ChannelRuleUpdateCmd{
+		Pattern:  "stream/test/:path",
+		Settings: settings,
+		Version:  1,
+	})
+	require.ErrorIs(t, err, pipeline.ErrVersionConflict)
+
+	// Zero version skips the check.
+	rule, err = storage.UpdateChannelRule(ctx, 1, pipeline.ChannelRuleUpdateCmd{
+		Pattern:  "stream/test/:path",
+		Settings: settings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), rule.Version)
+
+	rules, err = storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, "stream/test/:path", rules[0].Pattern)
+	require.Equal(t, int64(3), rules[0].Version)
+	require.Len(t, rules[0].Settings.FrameOutputters, 1)
+
+	err = storage.DeleteChannelRule(ctx, 1, pipeline.ChannelRuleDeleteCmd{Pattern: "stream/test/:path", Version: 2})
+	require.ErrorIs(t, err, pipeline.ErrVersionConflict)
+	err = storage.DeleteChannelRule(ctx, 1, pipeline.ChannelRuleDeleteCmd{Pattern: "stream/test/:path", Version: 3})
+	require.NoError(t, err)
+	err = storage.DeleteChannelRule(ctx, 1, pipeline.ChannelRuleDeleteCmd{Pattern: "stream/test/:path"})
+	require.ErrorIs(t, err, pipeline.ErrChannelRuleNotFound)
+
+	// Update without version creates missing rule, with version fails.
+	_, err = storage.UpdateChannelRule(ctx, 1, pipeline.ChannelRuleUpdateCmd{
+		Pattern:  "stream/test/new",
+		Settings: settings,
+		Version:  1,
+	})
+	require.ErrorIs(t, err, pipeline.ErrChannelRuleNotFound)
+	rule, err = storage.UpdateChannelRule(ctx, 1, pipeline.ChannelRuleUpdateCmd{
+		Pattern:  "stream/test/new",
+		Settings: settings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.Version)
+}
+
+func TestIntegrationPipelineWriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	storage := SetupTestPipelineStorage(t)
+	ctx := context.Background()
+
+	_, err := storage.CreateWriteConfig(ctx, 1, pipeline.WriteConfigCreateCmd{UID: "test"})
+	require.ErrorIs(t, err, pipeline.ErrInvalidWriteConfig)
+
+	writeConfig, err := storage.CreateWriteConfig(ctx, 1, pipeline.WriteConfigCreateCmd{
+		UID: "test",
+		Settings: pipeline.WriteSettings{
+			Endpoint:  "http://localhost:9090/api/v1/write",
+			BasicAuth: &pipeline.BasicAuth{User: "admin"},
+		},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), writeConfig.Version)
+
+	_, err = storage.CreateWriteConfig(ctx, 1, pipeline.WriteConfigCreateCmd{
+		UID:      "test",
+		Settings: pipeline.WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"},
+	})
+	require.ErrorIs(t, err, pipeline.ErrWriteConfigExists)
+
+	generated, err := storage.CreateWriteConfig(ctx, 1, pipeline.WriteConfigCreateCmd{
+		Settings: pipeline.WriteSettings{Endpoint: "http://localhost:3100"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, generated.UID)
+
+	writeConfig, ok, err := storage.GetWriteConfig(ctx, 1, pipeline.WriteConfigGetCmd{UID: "test"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "admin", writeConfig.Settings.BasicAuth.User)
+	require.Equal(t, []byte("secret"), writeConfig.SecureSettings["basicAuthPassword"])
+
+	_, ok, err = storage.GetWriteConfig(ctx, 2, pipeline.WriteConfigGetCmd{UID: "test"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	writeConfig, err = storage.UpdateWriteConfig(ctx, 1, pipeline.WriteConfigUpdateCmd{
+		UID:      "test",
+		Settings: pipeline.WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"},
+		Version:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), writeConfig.Version)
+
+	_, err = storage.UpdateWriteConfig(ctx, 1, pipeline.WriteConfigUpdateCmd{
+		UID:      "test",
+		Settings: pipeline.WriteSettings{Endpoint: "http://localhost:9092/api/v1/write"},
+		Version:  1,
+	})
+	require.ErrorIs(t, err, pipeline.ErrVersionConflict)
+
+	writeConfigs, err := storage.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, writeConfigs, 2)
+
+	err = storage.DeleteWriteConfig(ctx, 1, pipeline.WriteConfigDeleteCmd{UID: "test", Version: 1})
+	require.ErrorIs(t, err, pipeline.ErrVersionConflict)
+	err = storage.DeleteWriteConfig(ctx, 1, pipeline.WriteConfigDeleteCmd{UID: "test"})
+	require.NoError(t, err)
+	err = storage.DeleteWriteConfig(ctx, 1, pipeline.WriteConfigDeleteCmd{UID: "test"})
+	require.ErrorIs(t, err, pipeline.ErrWriteConfigNotFound)
+}
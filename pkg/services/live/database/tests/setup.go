@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/services/live/database"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 )
 
 // SetupTestStorage initializes a storage to used by the integration tests.
@@ -16,3 +17,10 @@ func SetupTestStorage(t *testing.T) *database.Storage {
 	localCache := localcache.New(time.Hour, time.Hour)
 	return database.NewStorage(sqlStore, localCache)
 }
+
+// SetupTestPipelineStorage initializes a pipeline storage to used by the
+// integration tests.
+func SetupTestPipelineStorage(t *testing.T) *database.PipelineStorage {
+	sqlStore := db.InitTestDB(t)
+	return database.NewPipelineStorage(sqlStore, fakes.NewFakeSecretsService())
+}
@@ -38,11 +38,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
 	"github.com/grafana/grafana/pkg/services/live/orgchannel"
 	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/live/pushlimit"
 	"github.com/grafana/grafana/pkg/services/live/pushws"
 	"github.com/grafana/grafana/pkg/services/live/runstream"
 	"github.com/grafana/grafana/pkg/services/live/survey"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -77,7 +79,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	dataSourceCache datasources.CacheService, sqlStore db.DB, secretsService secrets.Service,
 	usageStatsService usagestats.Service, queryDataService *query.Service, toggles featuremgmt.FeatureToggles,
 	accessControl accesscontrol.AccessControl, dashboardService dashboards.DashboardService, annotationsRepo annotations.Repository,
-	orgService org.Service) (*GrafanaLive, error) {
+	orgService org.Service, quotaService quota.Service) (*GrafanaLive, error) {
 	g := &GrafanaLive{
 		Cfg:                   cfg,
 		Features:              toggles,
@@ -158,6 +160,23 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	channelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, nil)
 
+	historyOptions := managedstream.HistoryOptions{
+		MaxRows: g.Cfg.LiveHistoryMaxRows,
+		MaxAge:  g.Cfg.LiveHistoryMaxAge,
+	}
+
+	pushLimitOptions := pushlimit.Options{
+		MaxMessageSize:  g.Cfg.LivePushMaxMessageSize,
+		OrgRateLimit:    g.Cfg.LivePushOrgRateLimit,
+		StreamRateLimit: g.Cfg.LivePushStreamRateLimit,
+	}
+	// With quotas enabled organization push rate limit can be overridden
+	// per organization.
+	var pushLimitQuotaService quota.Service
+	if g.Cfg.Quota.Enabled {
+		pushLimitQuotaService = quotaService
+	}
+
 	var managedStreamRunner *managedstream.Runner
 	if g.IsHA() {
 		redisClient := redis.NewClient(&redis.Options{
@@ -167,22 +186,47 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		if _, err := cmd.Result(); err != nil {
 			return nil, fmt.Errorf("error pinging Redis: %v", err)
 		}
+		var frameHistory managedstream.FrameHistory
+		if historyOptions.Enabled() {
+			frameHistory = managedstream.NewRedisFrameHistory(redisClient, historyOptions)
+		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewRedisFrameCache(redisClient),
+			frameHistory,
 		)
+		g.PushLimiter = pushlimit.NewLimiter(pushlimit.NewRedisCounter(redisClient, pushlimit.Window), pushLimitOptions, pushLimitQuotaService)
 	} else {
+		var frameHistory managedstream.FrameHistory
+		if historyOptions.Enabled() {
+			frameHistory = managedstream.NewMemoryFrameHistory(historyOptions)
+		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewMemoryFrameCache(),
+			frameHistory,
 		)
+		g.PushLimiter = pushlimit.NewLimiter(pushlimit.NewMemoryCounter(pushlimit.Window), pushLimitOptions, pushLimitQuotaService)
+	}
+
+	defaultLimits, err := pushlimit.ReadQuotaConfig(g.Cfg.LivePushOrgRateLimit)
+	if err != nil {
+		return nil, err
+	}
+	if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     pushlimit.QuotaTargetSrv,
+		DefaultLimits: defaultLimits,
+		Reporter:      g.PushLimiter.Usage,
+	}); err != nil {
+		return nil, err
 	}
 
 	g.ManagedStreamRunner = managedStreamRunner
 	if g.Features.IsEnabled(featuremgmt.FlagLivePipeline) {
 		var builder pipeline.RuleBuilder
+		var connectorBuilder pipeline.ConnectorBuilder
 		if os.Getenv("GF_LIVE_DEV_BUILDER") != "" {
 			builder = &pipeline.DevRuleBuilder{
 				Node:                 node,
@@ -191,19 +235,29 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 				ChannelHandlerGetter: g,
 			}
 		} else {
-			storage := &pipeline.FileStorage{
-				DataPath:       cfg.DataPath,
-				SecretsService: g.SecretsService,
+			var storage pipeline.Storage
+			if cfg.LivePipelineStorage == "database" {
+				storage = database.NewPipelineStorage(g.SQLStore, g.SecretsService)
+			} else {
+				storage = &pipeline.FileStorage{
+					DataPath:       cfg.DataPath,
+					SecretsService: g.SecretsService,
+				}
 			}
 			g.pipelineStorage = storage
-			builder = &pipeline.StorageRuleBuilder{
+			storageBuilder := &pipeline.StorageRuleBuilder{
 				Node:                 node,
 				ManagedStream:        g.ManagedStreamRunner,
 				FrameStorage:         pipeline.NewFrameStorage(),
 				Storage:              storage,
 				ChannelHandlerGetter: g,
 				SecretsService:       g.SecretsService,
+				AccessControl:        accessControl,
+				WindowStorage:        pipeline.NewWindowStorage(),
+				InstanceID:           setting.InstanceName,
 			}
+			builder = storageBuilder
+			connectorBuilder = storageBuilder
 		}
 		channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
 
@@ -227,6 +281,20 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		if err != nil {
 			return nil, err
 		}
+
+		if connectorBuilder != nil {
+			g.pipelineConnectorRunner = pipeline.NewConnectorRunner(connectorBuilder, g.Pipeline, func(ctx context.Context) ([]int64, error) {
+				orgs, err := orgService.Search(ctx, &org.SearchOrgsQuery{})
+				if err != nil {
+					return nil, err
+				}
+				orgIDs := make([]int64, 0, len(orgs))
+				for _, o := range orgs {
+					orgIDs = append(orgIDs, o.ID)
+				}
+				return orgIDs, nil
+			})
+		}
 	}
 
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
@@ -337,16 +405,18 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		CheckOrigin:     checkOrigin,
 	})
 
-	pushWSHandler := pushws.NewHandler(g.ManagedStreamRunner, pushws.Config{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		CheckOrigin:     checkOrigin,
+	pushWSHandler := pushws.NewHandler(g.ManagedStreamRunner, g.PushLimiter, pushws.Config{
+		ReadBufferSize:   1024,
+		WriteBufferSize:  1024,
+		MessageSizeLimit: pushWSMessageSizeLimit(g.Cfg.LivePushMaxMessageSize),
+		CheckOrigin:      checkOrigin,
 	})
 
-	pushPipelineWSHandler := pushws.NewPipelinePushHandler(g.Pipeline, pushws.Config{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		CheckOrigin:     checkOrigin,
+	pushPipelineWSHandler := pushws.NewPipelinePushHandler(g.Pipeline, g.PushLimiter, pushws.Config{
+		ReadBufferSize:   1024,
+		WriteBufferSize:  1024,
+		MessageSizeLimit: pushWSMessageSizeLimit(g.Cfg.LivePushMaxMessageSize),
+		CheckOrigin:      checkOrigin,
 	})
 
 	g.websocketHandler = func(ctx *models.ReqContext) {
@@ -384,14 +454,28 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	g.RouteRegister.Group("/api/live", func(group routing.RouteRegister) {
 		group.Get("/push/:streamId", g.pushWebsocketHandler)
-		group.Get("/pipeline/push/*", g.pushPipelineWebsocketHandler)
 	}, middleware.ReqOrgAdmin)
 
+	// Pipeline push permissions are checked according to channel rules.
+	g.RouteRegister.Group("/api/live", func(group routing.RouteRegister) {
+		group.Get("/pipeline/push/*", g.pushPipelineWebsocketHandler)
+	}, middleware.ReqSignedIn)
+
 	g.registerUsageMetrics()
 
 	return g, nil
 }
 
+// pushWSMessageSizeLimit allows WebSocket push messages larger than the
+// default limit if push_max_message_size is larger, smaller messages are
+// checked by push limiter so that rejections are reported.
+func pushWSMessageSizeLimit(maxMessageSize int) int {
+	if maxMessageSize > pushws.DefaultWebsocketMessageSizeLimit {
+		return maxMessageSize
+	}
+	return 0
+}
+
 // GrafanaLive manages live real-time connections to Grafana (over WebSocket at this moment).
 // The main concept here is Channel. Connections can subscribe to many channels. Each channel
 // can have different permissions and properties but once a connection subscribed to a channel
@@ -427,8 +511,11 @@ type GrafanaLive struct {
 
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
+	PushLimiter         *pushlimit.Limiter
 	pipelineStorage     pipeline.Storage
 
+	pipelineConnectorRunner *pipeline.ConnectorRunner
+
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
 	storage          *database.Storage
@@ -472,6 +559,12 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		})
 	}
 
+	if g.pipelineConnectorRunner != nil {
+		eGroup.Go(func() error {
+			return g.pipelineConnectorRunner.Run(eCtx)
+		})
+	}
+
 	return eGroup.Wait()
 }
 
@@ -633,67 +726,14 @@ func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.
 		return centrifuge.SubscribeReply{}, centrifuge.ErrorPermissionDenied
 	}
 
-	var reply models.SubscribeReply
-	var status backend.SubscribeStreamStatus
-	var ruleFound bool
-
-	if g.Pipeline != nil {
-		rule, ok, err := g.Pipeline.Get(user.OrgID, channel)
-		if err != nil {
-			logger.Error("Error getting channel rule", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-			return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
-		}
-		ruleFound = ok
-		if ok {
-			if rule.SubscribeAuth != nil {
-				ok, err := rule.SubscribeAuth.CanSubscribe(client.Context(), user)
-				if err != nil {
-					logger.Error("Error checking subscribe permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-					return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
-				}
-				if !ok {
-					// using HTTP error codes for WS errors too.
-					code, text := subscribeStatusToHTTPError(backend.SubscribeStreamStatusPermissionDenied)
-					return centrifuge.SubscribeReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
-				}
-			}
-			if len(rule.Subscribers) > 0 {
-				var err error
-				for _, sub := range rule.Subscribers {
-					reply, status, err = sub.Subscribe(client.Context(), pipeline.Vars{
-						OrgID:   orgID,
-						Channel: channel,
-					}, e.Data)
-					if err != nil {
-						logger.Error("Error channel rule subscribe", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-						return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
-					}
-					if status != backend.SubscribeStreamStatusOK {
-						break
-					}
-				}
-			}
-		}
-	}
-	if !ruleFound {
-		handler, addr, err := g.GetChannelHandler(ctx, user, channel)
-		if err != nil {
-			if errors.Is(err, live.ErrInvalidChannelID) {
-				logger.Info("Invalid channel ID", "user", client.UserID(), "client", client.ID(), "channel", e.Channel)
-				return centrifuge.SubscribeReply{}, &centrifuge.Error{Code: uint32(http.StatusBadRequest), Message: "invalid channel ID"}
-			}
-			logger.Error("Error getting channel handler", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-			return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
-		}
-		reply, status, err = handler.OnSubscribe(client.Context(), user, models.SubscribeEvent{
-			Channel: channel,
-			Path:    addr.Path,
-			Data:    e.Data,
-		})
-		if err != nil {
-			logger.Error("Error calling channel handler subscribe", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-			return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
+	reply, status, err := g.subscribeChannel(client.Context(), user, channel, e.Data)
+	if err != nil {
+		if errors.Is(err, live.ErrInvalidChannelID) {
+			logger.Info("Invalid channel ID", "user", client.UserID(), "client", client.ID(), "channel", e.Channel)
+			return centrifuge.SubscribeReply{}, &centrifuge.Error{Code: uint32(http.StatusBadRequest), Message: "invalid channel ID"}
 		}
+		logger.Error("Error subscribing to channel", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
+		return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
 	}
 	if status != backend.SubscribeStreamStatusOK {
 		// using HTTP error codes for WS errors too.
@@ -713,6 +753,56 @@ func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.
 	}, nil
 }
 
+// subscribeChannel checks whether the user may subscribe to the channel, which has no orgID prefix, and runs the
+// subscribers of its channel rule or its channel handler.
+func (g *GrafanaLive) subscribeChannel(ctx context.Context, user *user.SignedInUser, channel string, data json.RawMessage) (models.SubscribeReply, backend.SubscribeStreamStatus, error) {
+	if g.Pipeline != nil {
+		rule, ok, err := g.Pipeline.Get(user.OrgID, channel)
+		if err != nil {
+			return models.SubscribeReply{}, 0, fmt.Errorf("error getting channel rule: %w", err)
+		}
+		if ok {
+			if rule.SubscribeAuth != nil {
+				ok, err := rule.SubscribeAuth.CanSubscribe(ctx, user, channel)
+				if err != nil {
+					return models.SubscribeReply{}, 0, fmt.Errorf("error checking subscribe permissions: %w", err)
+				}
+				if !ok {
+					return models.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, nil
+				}
+			}
+			reply, status := models.SubscribeReply{}, backend.SubscribeStreamStatusOK
+			for _, sub := range rule.Subscribers {
+				reply, status, err = sub.Subscribe(ctx, pipeline.Vars{
+					OrgID:   user.OrgID,
+					Channel: channel,
+				}, data)
+				if err != nil {
+					return models.SubscribeReply{}, 0, fmt.Errorf("error channel rule subscribe: %w", err)
+				}
+				if status != backend.SubscribeStreamStatusOK {
+					break
+				}
+			}
+			return reply, status, nil
+		}
+	}
+
+	handler, addr, err := g.GetChannelHandler(ctx, user, channel)
+	if err != nil {
+		return models.SubscribeReply{}, 0, err
+	}
+	reply, status, err := handler.OnSubscribe(ctx, user, models.SubscribeEvent{
+		Channel: channel,
+		Path:    addr.Path,
+		Data:    data,
+	})
+	if err != nil {
+		return models.SubscribeReply{}, 0, fmt.Errorf("error calling channel handler subscribe: %w", err)
+	}
+	return reply, status, nil
+}
+
 func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Client, e centrifuge.PublishEvent) (centrifuge.PublishReply, error) {
 	logger.Debug("Client wants to publish", "user", client.UserID(), "client", client.ID(), "channel", e.Channel)
 
@@ -742,7 +832,7 @@ func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Cl
 		}
 		if ok {
 			if rule.PublishAuth != nil {
-				ok, err := rule.PublishAuth.CanPublish(client.Context(), user)
+				ok, err := rule.PublishAuth.CanPublish(client.Context(), user, channel)
 				if err != nil {
 					logger.Error("Error checking publish permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
 					return centrifuge.PublishReply{}, centrifuge.ErrorInternal
@@ -976,7 +1066,7 @@ func (g *GrafanaLive) HandleHTTPPublish(ctx *models.ReqContext) response.Respons
 		}
 		if ok {
 			if rule.PublishAuth != nil {
-				ok, err := rule.PublishAuth.CanPublish(ctx.Req.Context(), user)
+				ok, err := rule.PublishAuth.CanPublish(ctx.Req.Context(), user, channel)
 				if err != nil {
 					logger.Error("Error checking publish permissions", "user", user, "channel", channel, "error", err)
 					return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
@@ -1060,6 +1150,39 @@ func (g *GrafanaLive) HandleInfoHTTP(ctx *models.ReqContext) response.Response {
 	})
 }
 
+// HandleHistoryHTTP returns recent frames of a managed stream channel
+// merged into one frame.
+func (g *GrafanaLive) HandleHistoryHTTP(ctx *models.ReqContext) response.Response {
+	if !g.ManagedStreamRunner.HistoryEnabled() {
+		return response.Error(http.StatusNotFound, "Managed stream history is not enabled", nil)
+	}
+	channel := web.Params(ctx.Req)["*"]
+	addr, err := live.ParseChannel(channel)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid channel", err)
+	}
+	if addr.Scope != live.ScopeStream {
+		return response.Error(http.StatusBadRequest, "History is only available for stream scope channels", nil)
+	}
+	// history is only available to users who can subscribe to the channel.
+	_, status, err := g.subscribeChannel(ctx.Req.Context(), ctx.SignedInUser, channel, nil)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check channel permissions", err)
+	}
+	if status != backend.SubscribeStreamStatusOK {
+		code, text := subscribeStatusToHTTPError(status)
+		return response.Error(code, text, nil)
+	}
+	frame, ok, err := g.ManagedStreamRunner.GetHistory(ctx.Req.Context(), ctx.OrgID, channel)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get channel history", err)
+	}
+	if !ok {
+		return response.Error(http.StatusNotFound, "No history for channel", nil)
+	}
+	return response.JSON(http.StatusOK, frame)
+}
+
 // HandleChannelRulesListHTTP ...
 func (g *GrafanaLive) HandleChannelRulesListHTTP(c *models.ReqContext) response.Response {
 	result, err := g.pipelineStorage.ListChannelRules(c.Req.Context(), c.OrgID)
@@ -1083,6 +1206,7 @@ type ConvertDryRunResponse struct {
 
 type DryRunRuleStorage struct {
 	ChannelRules []pipeline.ChannelRule
+	WriteConfigs []pipeline.WriteConfig
 }
 
 func (s *DryRunRuleStorage) GetWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigGetCmd) (pipeline.WriteConfig, bool, error) {
@@ -1114,7 +1238,7 @@ func (s *DryRunRuleStorage) DeleteChannelRule(_ context.Context, _ int64, _ pipe
 }
 
 func (s *DryRunRuleStorage) ListWriteConfigs(_ context.Context, _ int64) ([]pipeline.WriteConfig, error) {
-	return nil, nil
+	return s.WriteConfigs, nil
 }
 
 func (s *DryRunRuleStorage) ListChannelRules(_ context.Context, _ int64) ([]pipeline.ChannelRule, error) {
@@ -1166,6 +1290,83 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *models.ReqContext) respon
 	})
 }
 
+type PipelineDryRunRequest struct {
+	// ChannelRules to test, if not set then stored channel rules are used.
+	ChannelRules []pipeline.ChannelRule `json:"channelRules,omitempty"`
+	Channel      string                 `json:"channel"`
+	Data         string                 `json:"data"`
+}
+
+// HandlePipelineDryRunHTTP passes sample data through channel rule converter,
+// processors and outputs and returns resulting frames. Outputs with side
+// effects are not called, frames they would receive are returned instead.
+func (g *GrafanaLive) HandlePipelineDryRunHTTP(c *models.ReqContext) response.Response {
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Error reading body", err)
+	}
+	var req PipelineDryRunRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Error decoding request", err)
+	}
+	if req.Channel == "" {
+		return response.Error(http.StatusBadRequest, "Channel required", nil)
+	}
+	channelRules := req.ChannelRules
+	if channelRules == nil {
+		channelRules, err = g.pipelineStorage.ListChannelRules(c.Req.Context(), c.OrgID)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get channel rules", err)
+		}
+	} else {
+		for _, rule := range channelRules {
+			if ok, reason := rule.Valid(); !ok {
+				return response.Error(http.StatusBadRequest, "Invalid channel rule: "+reason, nil)
+			}
+		}
+	}
+	writeConfigs, err := g.pipelineStorage.ListWriteConfigs(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get write configs", err)
+	}
+	builder := &pipeline.StorageRuleBuilder{
+		Node:          g.node,
+		ManagedStream: g.ManagedStreamRunner,
+		FrameStorage:  pipeline.NewFrameStorage(),
+		Storage: &DryRunRuleStorage{
+			ChannelRules: channelRules,
+			WriteConfigs: writeConfigs,
+		},
+		ChannelHandlerGetter: g,
+		SecretsService:       g.SecretsService,
+		WindowStorage:        pipeline.NewWindowStorage(),
+		DryRunRecorder:       &pipeline.DryRunRecorder{},
+	}
+	result, err := pipeline.DryRun(c.Req.Context(), builder, c.OrgID, req.Channel, []byte(req.Data))
+	if err != nil {
+		if errors.Is(err, pipeline.ErrDryRunRuleNotFound) {
+			return response.Error(http.StatusNotFound, "No rule found", err)
+		}
+		return response.Error(http.StatusBadRequest, "Dry run failed", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// pipelineStorageErrorResponse maps pipeline storage errors to HTTP responses.
+func pipelineStorageErrorResponse(message string, err error) response.Response {
+	switch {
+	case errors.Is(err, pipeline.ErrInvalidChannelRule), errors.Is(err, pipeline.ErrInvalidWriteConfig):
+		return response.Error(http.StatusBadRequest, message, err)
+	case errors.Is(err, pipeline.ErrChannelRuleNotFound), errors.Is(err, pipeline.ErrWriteConfigNotFound):
+		return response.Error(http.StatusNotFound, message, err)
+	case errors.Is(err, pipeline.ErrChannelRuleExists), errors.Is(err, pipeline.ErrWriteConfigExists),
+		errors.Is(err, pipeline.ErrVersionConflict):
+		return response.Error(http.StatusConflict, message, err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
+
 // HandleChannelRulesPostHTTP ...
 func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *models.ReqContext) response.Response {
 	body, err := io.ReadAll(c.Req.Body)
@@ -1179,7 +1380,7 @@ func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *models.ReqContext) response.
 	}
 	rule, err := g.pipelineStorage.CreateChannelRule(c.Req.Context(), c.OrgID, cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to create channel rule", err)
+		return pipelineStorageErrorResponse("Failed to create channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
 		"rule": rule,
@@ -1202,7 +1403,7 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *models.ReqContext) response.R
 	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.OrgID, cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
+		return pipelineStorageErrorResponse("Failed to update channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
 		"rule": rule,
@@ -1225,7 +1426,7 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *models.ReqContext) respons
 	}
 	err = g.pipelineStorage.DeleteChannelRule(c.Req.Context(), c.OrgID, cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to delete channel rule", err)
+		return pipelineStorageErrorResponse("Failed to delete channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{})
 }
@@ -1238,6 +1439,7 @@ func (g *GrafanaLive) HandlePipelineEntitiesListHTTP(_ *models.ReqContext) respo
 		"converters":      pipeline.ConvertersRegistry,
 		"frameProcessors": pipeline.FrameProcessorsRegistry,
 		"frameOutputs":    pipeline.FrameOutputsRegistry,
+		"connectors":      pipeline.ConnectorsRegistry,
 	})
 }
 
@@ -1269,7 +1471,7 @@ func (g *GrafanaLive) HandleWriteConfigsPostHTTP(c *models.ReqContext) response.
 	}
 	result, err := g.pipelineStorage.CreateWriteConfig(c.Req.Context(), c.OrgID, cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to create write config", err)
+		return pipelineStorageErrorResponse("Failed to create write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
 		"writeConfig": pipeline.WriteConfigToDto(result),
@@ -1313,7 +1515,7 @@ func (g *GrafanaLive) HandleWriteConfigsPutHTTP(c *models.ReqContext) response.R
 	}
 	result, err := g.pipelineStorage.UpdateWriteConfig(c.Req.Context(), c.OrgID, cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to update write config", err)
+		return pipelineStorageErrorResponse("Failed to update write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
 		"writeConfig": pipeline.WriteConfigToDto(result),
@@ -1336,7 +1538,7 @@ func (g *GrafanaLive) HandleWriteConfigsDeleteHTTP(c *models.ReqContext) respons
 	}
 	err = g.pipelineStorage.DeleteWriteConfig(c.Req.Context(), c.OrgID, cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to delete write config", err)
+		return pipelineStorageErrorResponse("Failed to delete write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{})
 }
@@ -2,12 +2,18 @@ package live
 
 import (
 	"context"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
 
 	"github.com/stretchr/testify/require"
 )
@@ -199,3 +205,41 @@ func Test_getHistogramMetric(t *testing.T) {
 		})
 	}
 }
+
+type testRuleGetter struct {
+	rule *pipeline.LiveChannelRule
+}
+
+func (g testRuleGetter) Get(_ int64, _ string) (*pipeline.LiveChannelRule, bool, error) {
+	return g.rule, g.rule != nil, nil
+}
+
+type testSubscribeAuth bool
+
+func (a testSubscribeAuth) CanSubscribe(_ context.Context, _ *user.SignedInUser, _ string) (bool, error) {
+	return bool(a), nil
+}
+
+func TestHandleHistoryHTTP(t *testing.T) {
+	history := func(t *testing.T, canSubscribe bool) int {
+		t.Helper()
+		p, err := pipeline.New(testRuleGetter{rule: &pipeline.LiveChannelRule{Pattern: "stream/test/path", SubscribeAuth: testSubscribeAuth(canSubscribe)}})
+		require.NoError(t, err)
+		g := &GrafanaLive{
+			Pipeline:            p,
+			ManagedStreamRunner: managedstream.NewRunner(nil, nil, nil, managedstream.NewMemoryFrameHistory(managedstream.HistoryOptions{MaxRows: 10})),
+		}
+
+		req := web.SetURLParams(httptest.NewRequest(http.MethodGet, "/api/live/history/stream/test/path", nil), map[string]string{"*": "stream/test/path"})
+		ctx := &models.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: 1}}
+		return g.HandleHistoryHTTP(ctx).Status()
+	}
+
+	t.Run("returns 403 when the user cannot subscribe to the channel", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, history(t, false))
+	})
+
+	t.Run("reads the history when the user can subscribe to the channel", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, history(t, true))
+	})
+}
@@ -0,0 +1,135 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FrameHistory keeps recent frames pushed into managed stream channels so
+// late subscribers can start with a populated window instead of a single
+// last frame.
+type FrameHistory interface {
+	// Add appends frame to a channel history. Frames without rows are ignored.
+	Add(ctx context.Context, orgID int64, channel string, frame *data.Frame) error
+	// Get returns channel history merged into one frame. Only frames with
+	// the same schema as the latest one are included.
+	Get(ctx context.Context, orgID int64, channel string) (*data.Frame, bool, error)
+}
+
+// HistoryOptions limits the size of a channel history. History is bounded by
+// row count, by age, or by both. Zero values disable a limit.
+type HistoryOptions struct {
+	// MaxRows is a maximum number of rows to keep per channel.
+	MaxRows int
+	// MaxAge is a maximum age of kept frames, based on push time.
+	MaxAge time.Duration
+}
+
+// Enabled returns true if at least one limit is set. History without limits
+// would grow unbounded, so it is not kept at all in this case.
+func (o HistoryOptions) Enabled() bool {
+	return o.MaxRows > 0 || o.MaxAge > 0
+}
+
+type historyEntry struct {
+	// Time is a push time in Unix milliseconds.
+	Time  int64           `json:"t"`
+	Rows  int             `json:"r"`
+	Frame json.RawMessage `json:"f"`
+}
+
+func newHistoryEntry(now time.Time, frame *data.Frame) (historyEntry, error) {
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	if err != nil {
+		return historyEntry{}, err
+	}
+	return historyEntry{
+		Time:  now.UnixMilli(),
+		Rows:  frame.Rows(),
+		Frame: frameJSON,
+	}, nil
+}
+
+// trimHistory drops entries which are too old or not needed to keep
+// MaxRows rows.
+func trimHistory(entries []historyEntry, opts HistoryOptions, now time.Time) []historyEntry {
+	if opts.MaxAge > 0 {
+		minTime := now.Add(-opts.MaxAge).UnixMilli()
+		for len(entries) > 0 && entries[0].Time < minTime {
+			entries = entries[1:]
+		}
+	}
+	if opts.MaxRows > 0 {
+		totalRows := 0
+		for _, e := range entries {
+			totalRows += e.Rows
+		}
+		for len(entries) > 0 && totalRows-entries[0].Rows >= opts.MaxRows {
+			totalRows -= entries[0].Rows
+			entries = entries[1:]
+		}
+	}
+	return entries
+}
+
+// mergeHistory merges entries into one frame. Merging goes from the latest
+// entry back and stops on the first schema change.
+func mergeHistory(entries []historyEntry, maxRows int) (*data.Frame, bool, error) {
+	var frames []*data.Frame
+	for i := len(entries) - 1; i >= 0; i-- {
+		var frame data.Frame
+		if err := json.Unmarshal(entries[i].Frame, &frame); err != nil {
+			return nil, false, fmt.Errorf("error unmarshaling history frame: %w", err)
+		}
+		if len(frames) > 0 && !sameSchema(frames[0], &frame) {
+			break
+		}
+		frames = append([]*data.Frame{&frame}, frames...)
+	}
+	if len(frames) == 0 {
+		return nil, false, nil
+	}
+
+	totalRows := 0
+	for _, f := range frames {
+		totalRows += f.Rows()
+	}
+	skip := 0
+	if maxRows > 0 && totalRows > maxRows {
+		skip = totalRows - maxRows
+	}
+
+	latest := frames[len(frames)-1]
+	merged := latest.EmptyCopy()
+	for _, f := range frames {
+		for row := 0; row < f.Rows(); row++ {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			for i, field := range f.Fields {
+				merged.Fields[i].Append(field.CopyAt(row))
+			}
+		}
+	}
+	return merged, true, nil
+}
+
+func sameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+		if a.Fields[i].Labels.String() != b.Fields[i].Labels.String() {
+			return false
+		}
+	}
+	return true
+}
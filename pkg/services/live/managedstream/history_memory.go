@@ -0,0 +1,59 @@
+package managedstream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// MemoryFrameHistory keeps channel history in process memory.
+type MemoryFrameHistory struct {
+	mu      sync.Mutex
+	opts    HistoryOptions
+	entries map[string][]historyEntry
+	now     func() time.Time
+}
+
+// NewMemoryFrameHistory ...
+func NewMemoryFrameHistory(opts HistoryOptions) *MemoryFrameHistory {
+	return &MemoryFrameHistory{
+		opts:    opts,
+		entries: map[string][]historyEntry{},
+		now:     time.Now,
+	}
+}
+
+func (h *MemoryFrameHistory) Add(_ context.Context, orgID int64, channel string, frame *data.Frame) error {
+	if frame.Rows() == 0 {
+		return nil
+	}
+	now := h.now()
+	entry, err := newHistoryEntry(now, frame)
+	if err != nil {
+		return err
+	}
+	key := orgchannel.PrependOrgID(orgID, channel)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entries := trimHistory(append(h.entries[key], entry), h.opts, now)
+	// Copy to release memory of trimmed entries.
+	h.entries[key] = append(make([]historyEntry, 0, len(entries)), entries...)
+	return nil
+}
+
+func (h *MemoryFrameHistory) Get(_ context.Context, orgID int64, channel string) (*data.Frame, bool, error) {
+	key := orgchannel.PrependOrgID(orgID, channel)
+	h.mu.Lock()
+	entries := trimHistory(h.entries[key], h.opts, h.now())
+	if len(entries) == 0 {
+		delete(h.entries, key)
+	} else {
+		h.entries[key] = entries
+	}
+	h.mu.Unlock()
+	return mergeHistory(entries, h.opts.MaxRows)
+}
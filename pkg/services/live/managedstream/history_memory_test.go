@@ -0,0 +1,120 @@
+package managedstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func historyTestFrame(start int, values ...float64) *data.Frame {
+	times := make([]time.Time, 0, len(values))
+	for i := range values {
+		times = append(times, time.Unix(int64(start+i), 0).UTC())
+	}
+	return data.NewFrame("test",
+		data.NewField("time", nil, times),
+		data.NewField("value", nil, values),
+	)
+}
+
+func historyValues(t *testing.T, frame *data.Frame) []float64 {
+	t.Helper()
+	values := make([]float64, 0, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		values = append(values, frame.Fields[1].At(i).(float64))
+	}
+	return values
+}
+
+func testFrameHistory(t *testing.T, h FrameHistory) {
+	ctx := context.Background()
+
+	_, ok, err := h.Get(ctx, 1, "stream/test/history")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, h.Add(ctx, 1, "stream/test/history", historyTestFrame(0, 1, 2)))
+	require.NoError(t, h.Add(ctx, 1, "stream/test/history", historyTestFrame(2, 3)))
+	// Frames without rows are not kept.
+	require.NoError(t, h.Add(ctx, 1, "stream/test/history", historyTestFrame(3)))
+
+	frame, ok, err := h.Get(ctx, 1, "stream/test/history")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "test", frame.Name)
+	require.Equal(t, []float64{1, 2, 3}, historyValues(t, frame))
+
+	// Other org does not see history.
+	_, ok, err = h.Get(ctx, 2, "stream/test/history")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestMemoryFrameHistory(t *testing.T) {
+	testFrameHistory(t, NewMemoryFrameHistory(HistoryOptions{MaxRows: 10}))
+}
+
+func TestMemoryFrameHistory_Limits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("max rows", func(t *testing.T) {
+		h := NewMemoryFrameHistory(HistoryOptions{MaxRows: 3})
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", historyTestFrame(0, 1, 2)))
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", historyTestFrame(2, 3, 4)))
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", historyTestFrame(4, 5)))
+
+		frame, ok, err := h.Get(ctx, 1, "stream/test/a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []float64{3, 4, 5}, historyValues(t, frame))
+		// First frame is not needed to keep 3 rows.
+		require.Len(t, h.entries["1/stream/test/a"], 2)
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		h := NewMemoryFrameHistory(HistoryOptions{MaxAge: time.Minute})
+		now := time.Unix(1000, 0)
+		h.now = func() time.Time { return now }
+
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", historyTestFrame(0, 1)))
+		now = now.Add(30 * time.Second)
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", historyTestFrame(1, 2)))
+		now = now.Add(45 * time.Second)
+
+		frame, ok, err := h.Get(ctx, 1, "stream/test/a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []float64{2}, historyValues(t, frame))
+
+		now = now.Add(time.Minute)
+		_, ok, err = h.Get(ctx, 1, "stream/test/a")
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Empty(t, h.entries)
+	})
+
+	t.Run("schema change", func(t *testing.T) {
+		h := NewMemoryFrameHistory(HistoryOptions{MaxRows: 10})
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", historyTestFrame(0, 1, 2)))
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(2, 0)}),
+			data.NewField("value", nil, []float64{3}),
+			data.NewField("extra", nil, []string{"x"}),
+		)))
+		require.NoError(t, h.Add(ctx, 1, "stream/test/a", data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(3, 0)}),
+			data.NewField("value", nil, []float64{4}),
+			data.NewField("extra", nil, []string{"y"}),
+		)))
+
+		frame, ok, err := h.Get(ctx, 1, "stream/test/a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, []float64{3, 4}, historyValues(t, frame))
+	})
+}
@@ -0,0 +1,102 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// redisHistoryMaxEntries is a maximum number of entries kept per channel when
+// history is only limited by age, so a busy channel can't grow unbounded.
+const redisHistoryMaxEntries = 1000
+
+// RedisFrameHistory keeps channel history in Redis sorted sets scored by push
+// time, so it's shared between Grafana instances in HA setup.
+type RedisFrameHistory struct {
+	redisClient *redis.Client
+	opts        HistoryOptions
+	now         func() time.Time
+}
+
+// NewRedisFrameHistory ...
+func NewRedisFrameHistory(redisClient *redis.Client, opts HistoryOptions) *RedisFrameHistory {
+	return &RedisFrameHistory{
+		redisClient: redisClient,
+		opts:        opts,
+		now:         time.Now,
+	}
+}
+
+func (h *RedisFrameHistory) Add(ctx context.Context, orgID int64, channel string, frame *data.Frame) error {
+	if frame.Rows() == 0 {
+		return nil
+	}
+	entry, err := newHistoryEntry(h.now(), frame)
+	if err != nil {
+		return err
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+
+	ttl := frameCacheTTL
+	if h.opts.MaxAge > 0 {
+		ttl = h.opts.MaxAge
+	}
+
+	// Every entry has at least one row.
+	maxEntries := h.opts.MaxRows
+	if maxEntries <= 0 {
+		maxEntries = redisHistoryMaxEntries
+	}
+
+	pipe := h.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(entry.Time), Member: entryJSON})
+	if h.opts.MaxAge > 0 {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+h.minScore())
+	}
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-maxEntries-1))
+	pipe.PExpire(ctx, key, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisFrameHistory) Get(ctx context.Context, orgID int64, channel string) (*data.Frame, bool, error) {
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	minScore := "-inf"
+	if h.opts.MaxAge > 0 {
+		minScore = h.minScore()
+	}
+	values, err := h.redisClient.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: minScore, Max: "+inf"}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	entries := make([]historyEntry, 0, len(values))
+	for _, v := range values {
+		var entry historyEntry
+		if err := json.Unmarshal([]byte(v), &entry); err != nil {
+			return nil, false, fmt.Errorf("error unmarshaling history entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return mergeHistory(trimHistory(entries, h.opts, h.now()), h.opts.MaxRows)
+}
+
+// minScore returns the push time of the oldest entry within MaxAge.
+func (h *RedisFrameHistory) minScore() string {
+	return strconv.FormatInt(h.now().Add(-h.opts.MaxAge).UnixMilli(), 10)
+}
+
+func getHistoryKey(channelID string) string {
+	return "gf_live.managed_stream_history." + channelID
+}
@@ -0,0 +1,51 @@
+//go:build redis
+// +build redis
+
+package managedstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisFrameHistory(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	require.NoError(t, redisClient.Del(context.Background(), getHistoryKey("1/stream/test/history")).Err())
+	h := NewRedisFrameHistory(redisClient, HistoryOptions{MaxRows: 10})
+	require.NotNil(t, h)
+	testFrameHistory(t, h)
+}
+
+func TestRedisFrameHistory_MaxAge(t *testing.T) {
+	ctx := context.Background()
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	key := getHistoryKey("1/stream/test/age")
+	require.NoError(t, redisClient.Del(ctx, key).Err())
+	h := NewRedisFrameHistory(redisClient, HistoryOptions{MaxAge: time.Minute})
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	require.NoError(t, h.Add(ctx, 1, "stream/test/age", historyTestFrame(0, 1)))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, h.Add(ctx, 1, "stream/test/age", historyTestFrame(1, 2)))
+	now = now.Add(45 * time.Second)
+
+	frame, ok, err := h.Get(ctx, 1, "stream/test/age")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []float64{2}, historyValues(t, frame))
+
+	// Old entries are removed from Redis on the next push.
+	require.NoError(t, h.Add(ctx, 1, "stream/test/age", historyTestFrame(2, 3)))
+	count, err := redisClient.ZCard(ctx, key).Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
@@ -42,22 +42,38 @@ type Runner struct {
 	publisher      models.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 }
 
 type LocalPublisher interface {
 	PublishLocal(channel string, data []byte) error
 }
 
-// NewRunner creates new Runner.
-func NewRunner(publisher models.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache) *Runner {
+// NewRunner creates new Runner. Frame history is optional, if nil then
+// subscribers only receive the last frame pushed into a channel.
+func NewRunner(publisher models.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache, frameHistory FrameHistory) *Runner {
 	return &Runner{
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		streams:        map[int64]map[string]*NamespaceStream{},
 		frameCache:     frameCache,
+		frameHistory:   frameHistory,
 	}
 }
 
+// HistoryEnabled returns true if Runner keeps frame history of channels.
+func (r *Runner) HistoryEnabled() bool {
+	return r.frameHistory != nil
+}
+
+// GetHistory returns recent frames of a channel merged into one frame.
+func (r *Runner) GetHistory(ctx context.Context, orgID int64, channel string) (*data.Frame, bool, error) {
+	if r.frameHistory == nil {
+		return nil, false, nil
+	}
+	return r.frameHistory.Get(ctx, orgID, channel)
+}
+
 func (r *Runner) GetManagedChannels(orgID int64) ([]*ManagedChannel, error) {
 	activeChannels, err := r.frameCache.GetActiveChannels(orgID)
 	if err != nil {
@@ -125,7 +141,7 @@ func (r *Runner) GetOrCreateStream(orgID int64, scope string, namespace string)
 	prefix := scope + "/" + namespace
 	s, ok := r.streams[orgID][prefix]
 	if !ok {
-		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache)
+		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache, r.frameHistory)
 		r.streams[orgID][prefix] = s
 	}
 	return s, nil
@@ -139,6 +155,7 @@ type NamespaceStream struct {
 	publisher      models.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 	rateMu         sync.RWMutex
 	rates          map[string][60]rateEntry
 }
@@ -156,7 +173,7 @@ type ManagedChannel struct {
 }
 
 // NewNamespaceStream creates new NamespaceStream.
-func NewNamespaceStream(orgID int64, scope string, namespace string, publisher models.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache) *NamespaceStream {
+func NewNamespaceStream(orgID int64, scope string, namespace string, publisher models.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache, frameHistory FrameHistory) *NamespaceStream {
 	return &NamespaceStream{
 		orgID:          orgID,
 		scope:          scope,
@@ -164,6 +181,7 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		frameCache:     schemaUpdater,
+		frameHistory:   frameHistory,
 		rates:          map[string][60]rateEntry{},
 	}
 }
@@ -171,6 +189,7 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 // Push sends frame to the stream and saves it for later retrieval by subscribers.
 // * Saves the entire frame to cache.
 // * If schema has been changed sends entire frame to channel, otherwise only data.
+// * Appends frame to channel history if history enabled.
 func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Frame) error {
 	jsonFrameCache, err := data.FrameToJSONCache(frame)
 	if err != nil {
@@ -186,6 +205,13 @@ func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Fra
 		return err
 	}
 
+	if s.frameHistory != nil {
+		if err := s.frameHistory.Add(ctx, s.orgID, channel, frame); err != nil {
+			// History is not critical for live updates, so keep publishing.
+			logger.Error("Error adding frame to managed stream history", "error", err, "channel", channel)
+		}
+	}
+
 	// When the schema has not changed, just send the data.
 	include := data.IncludeDataOnly
 	if isUpdated {
@@ -241,6 +267,19 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (models.ChannelHandler, er
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u *user.SignedInUser, e models.SubscribeEvent) (models.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := models.SubscribeReply{}
+	if s.frameHistory != nil {
+		frame, ok, err := s.frameHistory.Get(ctx, u.OrgID, e.Channel)
+		if err != nil {
+			logger.Error("Error getting managed stream history", "error", err, "channel", e.Channel)
+		} else if ok {
+			frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+			if err != nil {
+				return reply, 0, err
+			}
+			reply.Data = frameJSON
+			return reply, backend.SubscribeStreamStatusOK, nil
+		}
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.OrgID, e.Channel)
 	if err != nil {
 		return reply, 0, err
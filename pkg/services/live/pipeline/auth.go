@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 )
@@ -15,10 +16,56 @@ func NewRoleCheckAuthorizer(role org.RoleType) *RoleCheckAuthorizer {
 	return &RoleCheckAuthorizer{role: role}
 }
 
-func (s *RoleCheckAuthorizer) CanSubscribe(_ context.Context, u *user.SignedInUser) (bool, error) {
+func (s *RoleCheckAuthorizer) CanSubscribe(_ context.Context, u *user.SignedInUser, _ string) (bool, error) {
 	return u.HasRole(s.role), nil
 }
 
-func (s *RoleCheckAuthorizer) CanPublish(_ context.Context, u *user.SignedInUser) (bool, error) {
+func (s *RoleCheckAuthorizer) CanPublish(_ context.Context, u *user.SignedInUser, _ string) (bool, error) {
 	return u.HasRole(s.role), nil
 }
+
+// AccessControlAuthorizer checks channel access with access control action
+// and scope. When scope is not configured it defaults to the scope of a
+// channel, i.e. channels:uid:<channel>, so permissions can be granted for
+// channel patterns like channels:uid:stream/team-a/*. Optional role check is
+// applied before evaluating permissions.
+type AccessControlAuthorizer struct {
+	accessControl accesscontrol.AccessControl
+	role          org.RoleType
+	action        string
+	scope         string
+	// fallbackRole is required from a user if access control is disabled.
+	fallbackRole org.RoleType
+}
+
+func NewAccessControlAuthorizer(accessControl accesscontrol.AccessControl, config ChannelAuthCheckConfig, fallbackRole org.RoleType) *AccessControlAuthorizer {
+	return &AccessControlAuthorizer{
+		accessControl: accessControl,
+		role:          config.RequireRole,
+		action:        config.Action,
+		scope:         config.Scope,
+		fallbackRole:  fallbackRole,
+	}
+}
+
+func (s *AccessControlAuthorizer) CanSubscribe(ctx context.Context, u *user.SignedInUser, channel string) (bool, error) {
+	return s.check(ctx, u, channel)
+}
+
+func (s *AccessControlAuthorizer) CanPublish(ctx context.Context, u *user.SignedInUser, channel string) (bool, error) {
+	return s.check(ctx, u, channel)
+}
+
+func (s *AccessControlAuthorizer) check(ctx context.Context, u *user.SignedInUser, channel string) (bool, error) {
+	if s.role != "" && !u.HasRole(s.role) {
+		return false, nil
+	}
+	if s.accessControl == nil || s.accessControl.IsDisabled() {
+		return s.role != "" || u.HasRole(s.fallbackRole), nil
+	}
+	scope := s.scope
+	if scope == "" {
+		scope = accesscontrol.ScopeLiveChannelsProvider.GetResourceScopeUID(channel)
+	}
+	return s.accessControl.Evaluate(ctx, u, accesscontrol.EvalPermission(s.action, scope))
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessControlAuthorizer(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.RBACEnabled = true
+	ac := acimpl.ProvideAccessControl(cfg)
+
+	signedInUser := func(role org.RoleType, permissions map[string][]string) *user.SignedInUser {
+		return &user.SignedInUser{
+			OrgID:       1,
+			OrgRole:     role,
+			Permissions: map[int64]map[string][]string{1: permissions},
+		}
+	}
+
+	publisher := signedInUser(org.RoleViewer, map[string][]string{
+		accesscontrol.ActionLiveChannelsPublish: {"channels:uid:stream/team-a/*"},
+	})
+
+	t.Run("default scope matches channel", func(t *testing.T) {
+		auth := NewAccessControlAuthorizer(ac, ChannelAuthCheckConfig{Action: accesscontrol.ActionLiveChannelsPublish}, org.RoleAdmin)
+
+		ok, err := auth.CanPublish(context.Background(), publisher, "stream/team-a/cpu")
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = auth.CanPublish(context.Background(), publisher, "stream/team-b/cpu")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = auth.CanPublish(context.Background(), signedInUser(org.RoleAdmin, nil), "stream/team-a/cpu")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("configured scope", func(t *testing.T) {
+		auth := NewAccessControlAuthorizer(ac, ChannelAuthCheckConfig{
+			Action: accesscontrol.ActionLiveChannelsPublish,
+			Scope:  "channels:uid:stream/team-a/shared",
+		}, org.RoleAdmin)
+
+		ok, err := auth.CanPublish(context.Background(), publisher, "stream/other/cpu")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("role checked before permissions", func(t *testing.T) {
+		auth := NewAccessControlAuthorizer(ac, ChannelAuthCheckConfig{
+			RequireRole: org.RoleEditor,
+			Action:      accesscontrol.ActionLiveChannelsPublish,
+		}, org.RoleAdmin)
+
+		ok, err := auth.CanPublish(context.Background(), publisher, "stream/team-a/cpu")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("fallback role without access control", func(t *testing.T) {
+		auth := NewAccessControlAuthorizer(nil, ChannelAuthCheckConfig{Action: accesscontrol.ActionLiveChannelsPublish}, org.RoleAdmin)
+
+		ok, err := auth.CanPublish(context.Background(), publisher, "stream/team-a/cpu")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = auth.CanPublish(context.Background(), signedInUser(org.RoleAdmin, nil), "stream/team-a/cpu")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
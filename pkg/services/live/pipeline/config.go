@@ -8,6 +8,12 @@ import (
 // ChannelAuthCheckConfig is used to define auth rules for a channel.
 type ChannelAuthCheckConfig struct {
 	RequireRole org.RoleType `json:"role,omitempty"`
+	// Action is an access control action a user must have, for example
+	// live.channels:publish. If set then the check is evaluated with access
+	// control in addition to the role check.
+	Action string `json:"action,omitempty"`
+	// Scope of the Action. Defaults to channels:uid:<channel>.
+	Scope string `json:"scope,omitempty"`
 }
 
 type ChannelAuthConfig struct {
@@ -25,12 +31,14 @@ type ChannelRuleSettings struct {
 	Converter       *ConverterConfig        `json:"converter,omitempty"`
 	FrameProcessors []*FrameProcessorConfig `json:"frameProcessors,omitempty"`
 	FrameOutputters []*FrameOutputterConfig `json:"frameOutputs,omitempty"`
+	Connectors      []*ConnectorConfig      `json:"connectors,omitempty"`
 }
 
 type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	Version  int64               `json:"version,omitempty"`
 }
 
 type ConverterConfig struct {
@@ -39,6 +47,8 @@ type ConverterConfig struct {
 	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
 	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
 	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	PrometheusConverterConfig *PrometheusConverterConfig `json:"prometheus,omitempty"`
+	OtlpConverterConfig       *OtlpConverterConfig       `json:"otlp,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -49,11 +59,28 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type AggregateFrameProcessorConfig struct {
+	// Window duration, e.g. 10s.
+	Window string `json:"window"`
+	// Slide makes windows overlap, it must divide window. Windows are
+	// tumbling when slide is empty.
+	Slide string `json:"slide,omitempty"`
+	// Functions to apply: mean, min, max, count, last or percentile.
+	Functions []string `json:"functions"`
+	// Percentile in range [0, 100] used by percentile function.
+	Percentile float64 `json:"percentile,omitempty"`
+	// FieldNames to aggregate, all numeric fields are aggregated when empty.
+	FieldNames []string `json:"fieldNames,omitempty"`
+	// GroupBy labels, all labels are used when empty.
+	GroupBy []string `json:"groupBy,omitempty"`
+}
+
 type FrameProcessorConfig struct {
 	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
 	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
 	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
 	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	AggregateProcessorConfig  *AggregateFrameProcessorConfig  `json:"aggregate,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -151,4 +178,45 @@ type AutoInfluxConverterConfig struct {
 
 type JsonFrameConverterConfig struct{}
 
+type PrometheusConverterConfig struct{}
+
+type OtlpConverterConfig struct {
+	// ResourceAttributes to add as labels, service.name is always added.
+	ResourceAttributes []string `json:"resourceAttributes,omitempty"`
+}
+
 type ManagedStreamOutputConfig struct{}
+
+// MQTTConnectorConfig subscribes to MQTT topics. The broker address and
+// credentials are taken from the write config with the given UID. All Grafana
+// instances share the subscriptions, so each message is only processed once.
+type MQTTConnectorConfig struct {
+	UID string `json:"uid"`
+	// Topics to subscribe to, wildcards are supported.
+	Topics []string `json:"topics"`
+	// ClientID defaults to a unique ID per Grafana instance, org, channel and
+	// connector. The broker keeps the session of a configured client ID while
+	// the connector is disconnected.
+	ClientID string `json:"clientId,omitempty"`
+	QoS      byte   `json:"qos,omitempty"`
+}
+
+// KafkaConnectorConfig consumes a Kafka topic as a member of a consumer group.
+// The comma-separated broker addresses and SASL/PLAIN credentials are taken from
+// the write config with the given UID.
+type KafkaConnectorConfig struct {
+	UID   string `json:"uid"`
+	Topic string `json:"topic"`
+	// GroupID defaults to an ID per org, channel and topic, which is shared by
+	// all Grafana instances.
+	GroupID string `json:"groupId,omitempty"`
+	// StartOffset is used when the group has no committed offset, either
+	// "earliest" or "latest" (default).
+	StartOffset string `json:"startOffset,omitempty"`
+}
+
+type ConnectorConfig struct {
+	Type                 string                `json:"type" ts_type:"Omit<keyof ConnectorConfig, 'type'>"`
+	MQTTConnectorConfig  *MQTTConnectorConfig  `json:"mqtt,omitempty"`
+	KafkaConnectorConfig *KafkaConnectorConfig `json:"kafka,omitempty"`
+}
@@ -0,0 +1,218 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ConnectorMessageHandler handles a single message consumed by a Connector.
+type ConnectorMessageHandler func(ctx context.Context, data []byte) error
+
+// Connector consumes messages from an external system, like an MQTT broker or
+// a Kafka cluster, and hands them to the pipeline.
+type Connector interface {
+	Type() string
+	// Run consumes messages until the context is canceled or the connection
+	// fails. Messages are acknowledged (or their offsets committed) only after
+	// the handler returned, a handler error stops the connector.
+	Run(ctx context.Context, handle ConnectorMessageHandler) error
+}
+
+// ChannelConnector is a Connector which pushes messages into a channel.
+type ChannelConnector struct {
+	OrgID   int64
+	Channel string
+	// Key identifies the connector configuration, including credentials. A
+	// running connector is restarted when its key changes.
+	Key       string
+	Connector Connector
+}
+
+// ConnectorBuilder constructs the connectors configured in channel rules.
+type ConnectorBuilder interface {
+	BuildConnectors(ctx context.Context, orgID int64) ([]*ChannelConnector, error)
+}
+
+// InputProcessor processes raw data published into a channel.
+type InputProcessor interface {
+	ProcessInput(ctx context.Context, orgID int64, channelID string, body []byte) (bool, error)
+}
+
+// OrgIDsGetter returns the organizations connectors are built for.
+type OrgIDsGetter func(ctx context.Context) ([]int64, error)
+
+const (
+	connectorSyncInterval = 20 * time.Second
+	connectorMinBackoff   = time.Second
+	connectorMaxBackoff   = time.Minute
+)
+
+// ConnectorRunner keeps the connectors of all channel rules running. It
+// periodically rebuilds the connectors to pick up configuration changes and
+// reconnects failed connectors with exponential backoff.
+type ConnectorRunner struct {
+	builder   ConnectorBuilder
+	processor InputProcessor
+	orgIDs    OrgIDsGetter
+
+	syncInterval time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	running map[string]*runningConnector
+}
+
+type runningConnector struct {
+	orgID  int64
+	cancel context.CancelFunc
+	// done is closed when the connector stopped.
+	done chan struct{}
+}
+
+// NewConnectorRunner creates new ConnectorRunner.
+func NewConnectorRunner(builder ConnectorBuilder, processor InputProcessor, orgIDs OrgIDsGetter) *ConnectorRunner {
+	return &ConnectorRunner{
+		builder:      builder,
+		processor:    processor,
+		orgIDs:       orgIDs,
+		syncInterval: connectorSyncInterval,
+		minBackoff:   connectorMinBackoff,
+		maxBackoff:   connectorMaxBackoff,
+		running:      map[string]*runningConnector{},
+	}
+}
+
+// Run syncs connectors until the context is canceled, then stops them all.
+func (r *ConnectorRunner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		r.sync(ctx)
+		select {
+		case <-ctx.Done():
+			r.stopAll()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ConnectorRunner) sync(ctx context.Context) {
+	orgIDs, err := r.orgIDs(ctx)
+	if err != nil {
+		logger.Error("Error getting organizations for connectors", "error", err)
+		return
+	}
+
+	wanted := map[string]*ChannelConnector{}
+	// connectors of organizations with invalid configuration keep running.
+	failedOrgs := map[int64]struct{}{}
+	for _, orgID := range orgIDs {
+		connectors, err := r.builder.BuildConnectors(ctx, orgID)
+		if err != nil {
+			logger.Error("Error building connectors", "error", err, "orgId", orgID)
+			failedOrgs[orgID] = struct{}{}
+			continue
+		}
+		for _, c := range connectors {
+			wanted[c.Key] = c
+		}
+	}
+
+	r.mu.Lock()
+	var stopped []*runningConnector
+	for key, rc := range r.running {
+		if _, ok := wanted[key]; ok {
+			continue
+		}
+		if _, ok := failedOrgs[rc.orgID]; ok {
+			continue
+		}
+		rc.cancel()
+		stopped = append(stopped, rc)
+		delete(r.running, key)
+	}
+	r.mu.Unlock()
+
+	// A replacement connector usually shares the client ID or consumer group
+	// of the connector it replaces, so it is only started once the old one
+	// disconnected.
+	for _, rc := range stopped {
+		<-rc.done
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, c := range wanted {
+		if _, ok := r.running[key]; ok {
+			continue
+		}
+		connCtx, cancel := context.WithCancel(ctx)
+		rc := &runningConnector{orgID: c.OrgID, cancel: cancel, done: make(chan struct{})}
+		r.running[key] = rc
+		r.wg.Add(1)
+		go func(c *ChannelConnector) {
+			defer r.wg.Done()
+			defer close(rc.done)
+			r.runConnector(connCtx, c)
+		}(c)
+	}
+}
+
+func (r *ConnectorRunner) stopAll() {
+	r.mu.Lock()
+	for key, rc := range r.running {
+		rc.cancel()
+		delete(r.running, key)
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+}
+
+func (r *ConnectorRunner) runConnector(ctx context.Context, c *ChannelConnector) {
+	handle := func(ctx context.Context, data []byte) error {
+		ok, err := r.processor.ProcessInput(ctx, c.OrgID, c.Channel, data)
+		if err != nil {
+			// A message which can't be processed is dropped, otherwise it
+			// would be redelivered over and over again.
+			logger.Error("Error processing connector message", "error", err, "type", c.Connector.Type(), "channel", c.Channel, "orgId", c.OrgID)
+			return nil
+		}
+		if !ok {
+			logger.Debug("Connector message not processed", "type", c.Connector.Type(), "channel", c.Channel, "orgId", c.OrgID)
+		}
+		return nil
+	}
+
+	backoff := r.minBackoff
+	for {
+		logger.Debug("Starting connector", "type", c.Connector.Type(), "channel", c.Channel, "orgId", c.OrgID)
+		started := time.Now()
+		err := c.Connector.Run(ctx, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > r.maxBackoff {
+			// the connector was healthy for a while, start over.
+			backoff = r.minBackoff
+		}
+		logger.Warn("Connector stopped, reconnecting", "error", err, "type", c.Connector.Type(), "channel", c.Channel, "orgId", c.OrgID, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > r.maxBackoff {
+			backoff = r.maxBackoff
+		}
+	}
+}
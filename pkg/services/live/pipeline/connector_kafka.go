@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+const ConnectorTypeKafka = "kafka"
+
+// KafkaConnectorSettings are the resolved settings of a Kafka connector.
+type KafkaConnectorSettings struct {
+	// Brokers addresses, prefixed with tls:// to use TLS.
+	Brokers  []string
+	Username string
+	Password string
+	Topic    string
+	GroupID  string
+	// StartOffset is either "earliest" or "latest" (default).
+	StartOffset string
+}
+
+// kafkaReader is the part of kafka.Reader used by KafkaConnector.
+type kafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaConnector consumes a topic as a member of a consumer group. Offsets are
+// committed after each message was processed, so consumption resumes from the
+// last processed message after a restart.
+type KafkaConnector struct {
+	settings  KafkaConnectorSettings
+	newReader func(config kafka.ReaderConfig) kafkaReader
+}
+
+func NewKafkaConnector(settings KafkaConnectorSettings) *KafkaConnector {
+	return &KafkaConnector{
+		settings: settings,
+		newReader: func(config kafka.ReaderConfig) kafkaReader {
+			return kafka.NewReader(config)
+		},
+	}
+}
+
+func (c *KafkaConnector) Type() string {
+	return ConnectorTypeKafka
+}
+
+func (c *KafkaConnector) readerConfig() (kafka.ReaderConfig, error) {
+	if len(c.settings.Brokers) == 0 {
+		return kafka.ReaderConfig{}, errors.New("no brokers configured")
+	}
+	if c.settings.Topic == "" {
+		return kafka.ReaderConfig{}, errors.New("no topic configured")
+	}
+
+	dialer := &kafka.Dialer{
+		Timeout:   10 * time.Second,
+		DualStack: true,
+	}
+	brokers := make([]string, 0, len(c.settings.Brokers))
+	for _, broker := range c.settings.Brokers {
+		if strings.HasPrefix(broker, "tls://") {
+			dialer.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+			broker = strings.TrimPrefix(broker, "tls://")
+		}
+		brokers = append(brokers, broker)
+	}
+	if c.settings.Username != "" {
+		dialer.SASLMechanism = plain.Mechanism{
+			Username: c.settings.Username,
+			Password: c.settings.Password,
+		}
+	}
+
+	startOffset := kafka.LastOffset
+	switch c.settings.StartOffset {
+	case "", "latest":
+	case "earliest":
+		startOffset = kafka.FirstOffset
+	default:
+		return kafka.ReaderConfig{}, fmt.Errorf("unknown start offset: %s", c.settings.StartOffset)
+	}
+
+	return kafka.ReaderConfig{
+		Brokers:     brokers,
+		GroupID:     c.settings.GroupID,
+		Topic:       c.settings.Topic,
+		Dialer:      dialer,
+		StartOffset: startOffset,
+		MaxWait:     time.Second,
+	}, nil
+}
+
+func (c *KafkaConnector) Run(ctx context.Context, handle ConnectorMessageHandler) error {
+	config, err := c.readerConfig()
+	if err != nil {
+		return err
+	}
+
+	reader := c.newReader(config)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Warn("Error closing Kafka reader", "error", err, "topic", c.settings.Topic)
+		}
+	}()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error fetching Kafka message: %w", err)
+		}
+		if err := handle(ctx, msg.Value); err != nil {
+			return err
+		}
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error committing Kafka offset: %w", err)
+		}
+	}
+}
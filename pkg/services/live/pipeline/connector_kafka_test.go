@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/require"
+)
+
+type testKafkaReader struct {
+	messages []kafka.Message
+	events   []string
+	closed   bool
+}
+
+func (r *testKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *testKafkaReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		r.events = append(r.events, "commit:"+string(msg.Value))
+	}
+	return nil
+}
+
+func (r *testKafkaReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestKafkaConnector(t *testing.T) {
+	t.Run("commits offsets after processing", func(t *testing.T) {
+		reader := &testKafkaReader{messages: []kafka.Message{{Value: []byte("1")}, {Value: []byte("2")}}}
+		var config kafka.ReaderConfig
+		c := NewKafkaConnector(KafkaConnectorSettings{
+			Brokers:     []string{"tls://kafka-1:9093", "tls://kafka-2:9093"},
+			Username:    "user",
+			Password:    "secret",
+			Topic:       "metrics",
+			GroupID:     "grafana-1-stream-test",
+			StartOffset: "earliest",
+		})
+		c.newReader = func(c kafka.ReaderConfig) kafkaReader {
+			config = c
+			return reader
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := c.Run(ctx, func(_ context.Context, data []byte) error {
+			reader.events = append(reader.events, "handle:"+string(data))
+			if string(data) == "2" {
+				cancel()
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"handle:1", "commit:1", "handle:2", "commit:2"}, reader.events)
+		require.True(t, reader.closed)
+
+		require.Equal(t, []string{"kafka-1:9093", "kafka-2:9093"}, config.Brokers)
+		require.Equal(t, "metrics", config.Topic)
+		require.Equal(t, "grafana-1-stream-test", config.GroupID)
+		require.Equal(t, kafka.FirstOffset, config.StartOffset)
+		require.NotNil(t, config.Dialer.TLS)
+		require.Equal(t, plain.Mechanism{Username: "user", Password: "secret"}, config.Dialer.SASLMechanism)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		c := NewKafkaConnector(KafkaConnectorSettings{Brokers: []string{"kafka:9092"}, Topic: "metrics", StartOffset: "middle"})
+		err := c.Run(context.Background(), func(context.Context, []byte) error { return nil })
+		require.EqualError(t, err, "unknown start offset: middle")
+	})
+}
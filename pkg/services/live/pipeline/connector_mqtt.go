@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const ConnectorTypeMQTT = "mqtt"
+
+const mqttOperationTimeout = 10 * time.Second
+
+// MQTTConnectorSettings are the resolved settings of an MQTT connector.
+type MQTTConnectorSettings struct {
+	// Broker address, e.g. tcp://localhost:1883 or ssl://localhost:8883.
+	Broker   string
+	Username string
+	Password string
+	ClientID string
+	// ShareGroup subscribes to the topics with a shared subscription of the
+	// group, so messages are distributed between the clients of the group.
+	ShareGroup string
+	// PersistentSession makes the broker keep subscriptions and undelivered
+	// messages while the client is disconnected.
+	PersistentSession bool
+	Topics            []string
+	QoS               byte
+}
+
+// MQTTConnector subscribes to MQTT topics. Messages are acknowledged after
+// they were processed, so with a persistent session QoS 1 and 2 messages
+// received during an outage are delivered once the connector reconnects.
+type MQTTConnector struct {
+	settings  MQTTConnectorSettings
+	newClient func(opts *mqtt.ClientOptions) mqtt.Client
+}
+
+func NewMQTTConnector(settings MQTTConnectorSettings) *MQTTConnector {
+	return &MQTTConnector{
+		settings:  settings,
+		newClient: mqtt.NewClient,
+	}
+}
+
+func (c *MQTTConnector) Type() string {
+	return ConnectorTypeMQTT
+}
+
+func (c *MQTTConnector) Run(ctx context.Context, handle ConnectorMessageHandler) error {
+	if len(c.settings.Topics) == 0 {
+		return errors.New("no topics to subscribe to")
+	}
+
+	connectionLost := make(chan error, 1)
+	messages := make(chan mqtt.Message)
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(c.settings.Broker).
+		SetClientID(c.settings.ClientID).
+		SetUsername(c.settings.Username).
+		SetPassword(c.settings.Password).
+		SetCleanSession(!c.settings.PersistentSession).
+		// Reconnects are handled by ConnectorRunner.
+		SetAutoReconnect(false).
+		SetAutoAckDisabled(true).
+		SetConnectTimeout(mqttOperationTimeout).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			select {
+			case connectionLost <- err:
+			default:
+			}
+		})
+
+	client := c.newClient(opts)
+	if err := waitMQTTToken(client.Connect()); err != nil {
+		return fmt.Errorf("error connecting to MQTT broker: %w", err)
+	}
+	defer client.Disconnect(250)
+
+	filters := make(map[string]byte, len(c.settings.Topics))
+	for _, topic := range c.settings.Topics {
+		if c.settings.ShareGroup != "" {
+			topic = "$share/" + c.settings.ShareGroup + "/" + topic
+		}
+		filters[topic] = c.settings.QoS
+	}
+	err := waitMQTTToken(client.SubscribeMultiple(filters, func(_ mqtt.Client, msg mqtt.Message) {
+		select {
+		case messages <- msg:
+		case <-ctx.Done():
+		}
+	}))
+	if err != nil {
+		return fmt.Errorf("error subscribing to MQTT topics: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-connectionLost:
+			return fmt.Errorf("MQTT connection lost: %w", err)
+		case msg := <-messages:
+			if err := handle(ctx, msg.Payload()); err != nil {
+				return err
+			}
+			msg.Ack()
+		}
+	}
+}
+
+func waitMQTTToken(token mqtt.Token) error {
+	if !token.WaitTimeout(mqttOperationTimeout) {
+		return errors.New("timeout")
+	}
+	return token.Error()
+}
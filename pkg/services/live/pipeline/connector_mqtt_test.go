@@ -0,0 +1,166 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/require"
+)
+
+type testMQTTToken struct {
+	err error
+}
+
+func (t *testMQTTToken) Wait() bool                     { return true }
+func (t *testMQTTToken) WaitTimeout(time.Duration) bool { return true }
+func (t *testMQTTToken) Error() error                   { return t.err }
+func (t *testMQTTToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+type testMQTTMessage struct {
+	mqtt.Message
+	payload []byte
+	acked   func()
+}
+
+func (m *testMQTTMessage) Payload() []byte { return m.payload }
+func (m *testMQTTMessage) Ack()            { m.acked() }
+
+type testMQTTClient struct {
+	mqtt.Client
+	opts       *mqtt.ClientOptions
+	connectErr error
+	filters    map[string]byte
+	callback   mqtt.MessageHandler
+	subscribed chan struct{}
+}
+
+func (c *testMQTTClient) Connect() mqtt.Token {
+	return &testMQTTToken{err: c.connectErr}
+}
+
+func (c *testMQTTClient) Disconnect(uint) {}
+
+func (c *testMQTTClient) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	c.filters = filters
+	c.callback = callback
+	close(c.subscribed)
+	return &testMQTTToken{}
+}
+
+func TestMQTTConnector(t *testing.T) {
+	settings := MQTTConnectorSettings{
+		Broker:   "tcp://localhost:1883",
+		Username: "user",
+		Password: "secret",
+		ClientID: "grafana-1-stream-test",
+		// the broker keeps messages of a configured client ID while it is disconnected.
+		PersistentSession: true,
+		Topics:            []string{"sensors/#"},
+		QoS:               1,
+	}
+
+	t.Run("acks messages after processing", func(t *testing.T) {
+		client := &testMQTTClient{subscribed: make(chan struct{})}
+		c := NewMQTTConnector(settings)
+		c.newClient = func(opts *mqtt.ClientOptions) mqtt.Client {
+			client.opts = opts
+			return client
+		}
+
+		var mu sync.Mutex
+		var events []string
+		handle := func(_ context.Context, data []byte) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, "handle:"+string(data))
+			return nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- c.Run(ctx, handle) }()
+		<-client.subscribed
+
+		require.Equal(t, map[string]byte{"sensors/#": 1}, client.filters)
+		require.Equal(t, "user", client.opts.Username)
+		require.Equal(t, "grafana-1-stream-test", client.opts.ClientID)
+		require.False(t, client.opts.CleanSession)
+		require.True(t, client.opts.AutoAckDisabled)
+
+		for _, payload := range []string{"1", "2"} {
+			p := payload
+			client.callback(client, &testMQTTMessage{payload: []byte(p), acked: func() {
+				mu.Lock()
+				defer mu.Unlock()
+				events = append(events, "ack:"+p)
+			}})
+		}
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(events) == 4
+		}, time.Second, time.Millisecond)
+		require.Equal(t, []string{"handle:1", "ack:1", "handle:2", "ack:2"}, events)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("subscribes with a shared subscription and clean session", func(t *testing.T) {
+		client := &testMQTTClient{subscribed: make(chan struct{})}
+		shared := settings
+		shared.ShareGroup = "grafana-1-stream-test"
+		shared.PersistentSession = false
+		c := NewMQTTConnector(shared)
+		c.newClient = func(opts *mqtt.ClientOptions) mqtt.Client {
+			client.opts = opts
+			return client
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- c.Run(ctx, func(context.Context, []byte) error { return nil }) }()
+		<-client.subscribed
+
+		require.Equal(t, map[string]byte{"$share/grafana-1-stream-test/sensors/#": 1}, client.filters)
+		require.True(t, client.opts.CleanSession)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("connection lost", func(t *testing.T) {
+		client := &testMQTTClient{subscribed: make(chan struct{})}
+		c := NewMQTTConnector(settings)
+		c.newClient = func(opts *mqtt.ClientOptions) mqtt.Client {
+			client.opts = opts
+			return client
+		}
+
+		done := make(chan error)
+		go func() {
+			done <- c.Run(context.Background(), func(context.Context, []byte) error { return nil })
+		}()
+		<-client.subscribed
+
+		client.opts.OnConnectionLost(client, errors.New("EOF"))
+		require.EqualError(t, <-done, "MQTT connection lost: EOF")
+	})
+
+	t.Run("connect error", func(t *testing.T) {
+		c := NewMQTTConnector(settings)
+		c.newClient = func(opts *mqtt.ClientOptions) mqtt.Client {
+			return &testMQTTClient{connectErr: errors.New("not authorized")}
+		}
+		err := c.Run(context.Background(), func(context.Context, []byte) error { return nil })
+		require.EqualError(t, err, "error connecting to MQTT broker: not authorized")
+	})
+}
@@ -0,0 +1,305 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testConnector struct {
+	mu       sync.Mutex
+	runs     int
+	messages [][]byte
+	err      error
+	running  chan struct{}
+	// stopDelay delays returning after the context is canceled.
+	stopDelay time.Duration
+	stopped   bool
+	// onRun is called when the connector starts.
+	onRun func()
+}
+
+func (c *testConnector) Type() string {
+	return "test"
+}
+
+func (c *testConnector) Run(ctx context.Context, handle ConnectorMessageHandler) error {
+	c.mu.Lock()
+	c.runs++
+	messages := c.messages
+	err := c.err
+	c.mu.Unlock()
+	if c.onRun != nil {
+		c.onRun()
+	}
+
+	for _, m := range messages {
+		if err := handle(ctx, m); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if c.running != nil {
+		select {
+		case c.running <- struct{}{}:
+		default:
+		}
+	}
+	<-ctx.Done()
+	time.Sleep(c.stopDelay)
+	c.mu.Lock()
+	c.stopped = true
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *testConnector) isStopped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopped
+}
+
+func (c *testConnector) numRuns() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.runs
+}
+
+type testConnectorBuilder struct {
+	mu         sync.Mutex
+	connectors map[int64][]*ChannelConnector
+	err        error
+}
+
+func (b *testConnectorBuilder) BuildConnectors(_ context.Context, orgID int64) ([]*ChannelConnector, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.connectors[orgID], nil
+}
+
+func (b *testConnectorBuilder) set(orgID int64, connectors []*ChannelConnector, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connectors[orgID] = connectors
+	b.err = err
+}
+
+type testInputProcessor struct {
+	mu    sync.Mutex
+	input []string
+}
+
+func (p *testInputProcessor) ProcessInput(_ context.Context, _ int64, channelID string, body []byte) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.input = append(p.input, channelID+":"+string(body))
+	return true, nil
+}
+
+func (p *testInputProcessor) received() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.input...)
+}
+
+func newTestConnectorRunner(builder ConnectorBuilder, processor InputProcessor) *ConnectorRunner {
+	r := NewConnectorRunner(builder, processor, func(ctx context.Context) ([]int64, error) {
+		return []int64{1}, nil
+	})
+	r.syncInterval = 10 * time.Millisecond
+	r.minBackoff = time.Millisecond
+	r.maxBackoff = 5 * time.Millisecond
+	return r
+}
+
+func TestConnectorRunner(t *testing.T) {
+	t.Run("processes messages", func(t *testing.T) {
+		conn := &testConnector{messages: [][]byte{[]byte("a"), []byte("b")}}
+		builder := &testConnectorBuilder{connectors: map[int64][]*ChannelConnector{
+			1: {{OrgID: 1, Channel: "stream/test/a", Key: "a", Connector: conn}},
+		}}
+		processor := &testInputProcessor{}
+		r := newTestConnectorRunner(builder, processor)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- r.Run(ctx) }()
+
+		require.Eventually(t, func() bool {
+			return len(processor.received()) == 2
+		}, time.Second, time.Millisecond)
+		require.Equal(t, []string{"stream/test/a:a", "stream/test/a:b"}, processor.received())
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+		require.Equal(t, 1, conn.numRuns())
+	})
+
+	t.Run("reconnects failed connector", func(t *testing.T) {
+		conn := &testConnector{err: errors.New("connection refused")}
+		builder := &testConnectorBuilder{connectors: map[int64][]*ChannelConnector{
+			1: {{OrgID: 1, Channel: "stream/test/a", Key: "a", Connector: conn}},
+		}}
+		r := newTestConnectorRunner(builder, &testInputProcessor{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = r.Run(ctx) }()
+
+		require.Eventually(t, func() bool {
+			return conn.numRuns() >= 3
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("restarts connector on config change", func(t *testing.T) {
+		connA := &testConnector{running: make(chan struct{}, 1), stopDelay: 20 * time.Millisecond}
+		connB := &testConnector{running: make(chan struct{}, 1)}
+		var startedBeforeStop bool
+		connB.onRun = func() {
+			startedBeforeStop = !connA.isStopped()
+		}
+		builder := &testConnectorBuilder{connectors: map[int64][]*ChannelConnector{
+			1: {{OrgID: 1, Channel: "stream/test/a", Key: "a", Connector: connA}},
+		}}
+		r := newTestConnectorRunner(builder, &testInputProcessor{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- r.Run(ctx) }()
+		<-connA.running
+
+		builder.set(1, []*ChannelConnector{{OrgID: 1, Channel: "stream/test/a", Key: "b", Connector: connB}}, nil)
+		<-connB.running
+
+		r.mu.Lock()
+		_, hasA := r.running["a"]
+		_, hasB := r.running["b"]
+		r.mu.Unlock()
+		require.False(t, hasA)
+		require.True(t, hasB)
+
+		cancel()
+		<-done
+		require.Equal(t, 1, connA.numRuns())
+		require.Equal(t, 1, connB.numRuns())
+		require.False(t, startedBeforeStop, "the new connector must start after the old one stopped")
+	})
+
+	t.Run("does not hold the lock while waiting for replaced connectors to stop", func(t *testing.T) {
+		stopping := make(chan struct{})
+		connA := &testConnector{running: make(chan struct{}, 1), stopDelay: time.Second}
+		builder := &testConnectorBuilder{connectors: map[int64][]*ChannelConnector{
+			1: {{OrgID: 1, Channel: "stream/test/a", Key: "a", Connector: connA}},
+		}}
+		r := newTestConnectorRunner(builder, &testInputProcessor{})
+
+		ctx := context.Background()
+		r.sync(ctx)
+		<-connA.running
+
+		builder.set(1, nil, nil)
+		go func() {
+			r.sync(ctx)
+			close(stopping)
+		}()
+		require.Eventually(t, func() bool {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			_, ok := r.running["a"]
+			return !ok
+		}, 500*time.Millisecond, time.Millisecond)
+		require.False(t, connA.isStopped())
+		<-stopping
+		require.True(t, connA.isStopped())
+	})
+
+	t.Run("keeps connectors running on build error", func(t *testing.T) {
+		conn := &testConnector{running: make(chan struct{}, 1)}
+		builder := &testConnectorBuilder{connectors: map[int64][]*ChannelConnector{
+			1: {{OrgID: 1, Channel: "stream/test/a", Key: "a", Connector: conn}},
+		}}
+		r := newTestConnectorRunner(builder, &testInputProcessor{})
+
+		ctx := context.Background()
+		r.sync(ctx)
+		defer r.stopAll()
+		<-conn.running
+
+		builder.set(1, nil, errors.New("invalid config"))
+		r.sync(ctx)
+
+		r.mu.Lock()
+		_, ok := r.running["a"]
+		r.mu.Unlock()
+		require.True(t, ok)
+		require.Equal(t, 1, conn.numRuns())
+	})
+}
+
+func TestStorageRuleBuilder_BuildConnectors(t *testing.T) {
+	rules := []ChannelRule{{
+		Pattern: "stream/test/a",
+		Settings: ChannelRuleSettings{
+			Connectors: []*ConnectorConfig{
+				{Type: ConnectorTypeMQTT, MQTTConnectorConfig: &MQTTConnectorConfig{UID: "mqtt", Topics: []string{"a"}}},
+				{Type: ConnectorTypeMQTT, MQTTConnectorConfig: &MQTTConnectorConfig{UID: "mqtt", Topics: []string{"a"}}},
+				{Type: ConnectorTypeKafka, KafkaConnectorConfig: &KafkaConnectorConfig{UID: "kafka", Topic: "a"}},
+				{Type: ConnectorTypeKafka, KafkaConnectorConfig: &KafkaConnectorConfig{UID: "kafka", Topic: "a", GroupID: "custom"}},
+			},
+		},
+	}}
+	writeConfigs := []WriteConfig{
+		{UID: "mqtt", Settings: WriteSettings{Endpoint: "tcp://localhost:1883"}},
+		{UID: "kafka", Settings: WriteSettings{Endpoint: "localhost:9092"}},
+	}
+	build := func(instanceID string) []*ChannelConnector {
+		builder := &StorageRuleBuilder{
+			Storage:    &dryRunTestStorage{rules: rules, writeConfigs: writeConfigs},
+			InstanceID: instanceID,
+		}
+		connectors, err := builder.BuildConnectors(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, connectors, 4)
+
+		keys := map[string]struct{}{}
+		for _, c := range connectors {
+			keys[c.Key] = struct{}{}
+		}
+		require.Len(t, keys, 4, "identical connectors of a rule must not share a key")
+		return connectors
+	}
+
+	replicaA, replicaB := build("replica-a"), build("replica-b")
+	mqttA, mqttB := replicaA[0].Connector.(*MQTTConnector).settings, replicaB[0].Connector.(*MQTTConnector).settings
+	groupID := mqttA.ShareGroup
+	require.Regexp(t, `^grafana-1-stream-test-a-[0-9a-f]{8}$`, groupID)
+
+	// MQTT clients are unique per instance and connector, and share their subscriptions.
+	require.Equal(t, groupID+"-replica-a-0", mqttA.ClientID)
+	require.Equal(t, groupID+"-replica-a-1", replicaA[1].Connector.(*MQTTConnector).settings.ClientID)
+	require.Equal(t, groupID+"-replica-b-0", mqttB.ClientID)
+	require.Equal(t, groupID, mqttB.ShareGroup)
+	require.False(t, mqttA.PersistentSession)
+
+	// Kafka consumer groups are shared by all instances.
+	kafkaGroupID := replicaA[2].Connector.(*KafkaConnector).settings.GroupID
+	require.Regexp(t, `^grafana-1-stream-test-a-[0-9a-f]{8}$`, kafkaGroupID)
+	require.Equal(t, kafkaGroupID, replicaB[2].Connector.(*KafkaConnector).settings.GroupID)
+	require.Equal(t, "custom", replicaA[3].Connector.(*KafkaConnector).settings.GroupID)
+
+	// The group does not depend on the position of the connector in the rule.
+	reordered := []ChannelRule{{Pattern: "stream/test/a", Settings: ChannelRuleSettings{Connectors: rules[0].Settings.Connectors[2:3]}}}
+	builder := &StorageRuleBuilder{Storage: &dryRunTestStorage{rules: reordered, writeConfigs: writeConfigs}}
+	connectors, err := builder.BuildConnectors(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, kafkaGroupID, connectors[0].Connector.(*KafkaConnector).settings.GroupID)
+}
@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+)
+
+// OtlpConverter decodes OTLP/HTTP protobuf metrics input and transforms it
+// to several ChannelFrame objects where Channel is constructed from original
+// channel + / + <metric_name>.
+type OtlpConverter struct {
+	config    OtlpConverterConfig
+	converter *otlp.Converter
+}
+
+// NewOtlpConverter creates new OtlpConverter.
+func NewOtlpConverter(config OtlpConverterConfig) *OtlpConverter {
+	return &OtlpConverter{
+		config:    config,
+		converter: otlp.NewConverter(otlp.WithResourceAttributes(config.ResourceAttributes...)),
+	}
+}
+
+const ConverterTypeOtlp = "otlp"
+
+func (c *OtlpConverter) Type() string {
+	return ConverterTypeOtlp
+}
+
+func (c *OtlpConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	frameWrappers, err := c.converter.Convert(body)
+	if err != nil {
+		return nil, err
+	}
+	channelFrames := make([]*ChannelFrame, 0, len(frameWrappers))
+	for _, fw := range frameWrappers {
+		channelFrames = append(channelFrames, &ChannelFrame{
+			Channel: vars.Channel + "/" + fw.Key(),
+			Frame:   fw.Frame(),
+		})
+	}
+	return channelFrames, nil
+}
@@ -0,0 +1,159 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
+)
+
+// ErrDryRunRuleNotFound returned by DryRun when no rule matches a channel.
+var ErrDryRunRuleNotFound = errors.New("no rule found for channel")
+
+// DryRunOutput is a frame or raw data which an output would send during
+// real processing.
+type DryRunOutput struct {
+	Channel string      `json:"channel"`
+	Type    string      `json:"type"`
+	Frame   *data.Frame `json:"frame,omitempty"`
+	Data    string      `json:"data,omitempty"`
+}
+
+// DryRunRecorder collects everything sent to outputs with side effects
+// (managed streams, local subscribers, builtin handlers, remote write, Loki)
+// during a dry run. Outputs which only route data inside pipeline work as usual.
+type DryRunRecorder struct {
+	mu      sync.Mutex
+	outputs []DryRunOutput
+}
+
+// Outputs returns recorded outputs in the order they were produced.
+func (r *DryRunRecorder) Outputs() []DryRunOutput {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DryRunOutput{}, r.outputs...)
+}
+
+func (r *DryRunRecorder) record(out DryRunOutput) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputs = append(r.outputs, out)
+}
+
+func (r *DryRunRecorder) frameOutput(outputType string) FrameOutputter {
+	return &dryRunFrameOutput{outputType: outputType, recorder: r}
+}
+
+func (r *DryRunRecorder) dataOutput(outputType string) DataOutputter {
+	return &dryRunDataOutput{outputType: outputType, recorder: r}
+}
+
+type dryRunFrameOutput struct {
+	outputType string
+	recorder   *DryRunRecorder
+}
+
+func (out *dryRunFrameOutput) Type() string {
+	return out.outputType
+}
+
+func (out *dryRunFrameOutput) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	out.recorder.record(DryRunOutput{Channel: vars.Channel, Type: out.outputType, Frame: frame})
+	return nil, nil
+}
+
+type dryRunDataOutput struct {
+	outputType string
+	recorder   *DryRunRecorder
+}
+
+func (out *dryRunDataOutput) Type() string {
+	return out.outputType
+}
+
+func (out *dryRunDataOutput) OutputData(_ context.Context, vars Vars, data []byte) ([]*ChannelData, error) {
+	out.recorder.record(DryRunOutput{Channel: vars.Channel, Type: out.outputType, Data: string(data)})
+	return nil, nil
+}
+
+// DryRunResult is a result of passing sample data through channel rules.
+type DryRunResult struct {
+	// ChannelFrames is an output of a converter of matched rule.
+	ChannelFrames []*ChannelFrame `json:"channelFrames"`
+	// Outputs are frames and data which reached outputs with side effects.
+	Outputs []DryRunOutput `json:"outputs"`
+}
+
+// DryRun passes body through rules of an org built by builder, starting from
+// a rule matching channel. Builder must have DryRunRecorder set and must not
+// share frame or window storage with a running pipeline, otherwise dry run
+// affects real processing state.
+func DryRun(ctx context.Context, builder *StorageRuleBuilder, orgID int64, channel string, body []byte) (DryRunResult, error) {
+	if builder.DryRunRecorder == nil {
+		return DryRunResult{}, errors.New("dry run requires recorder")
+	}
+	rules, err := builder.BuildRules(ctx, orgID)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+	ruleGetter, err := newStaticRuleGetter(orgID, rules)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+	pipe, err := New(ruleGetter)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+	rule, ok, _ := ruleGetter.Get(orgID, channel)
+	if !ok {
+		return DryRunResult{}, ErrDryRunRuleNotFound
+	}
+	var channelFrames []*ChannelFrame
+	if rule.Converter != nil {
+		channelFrames, err = pipe.DataToChannelFrames(ctx, *rule, orgID, channel, body)
+		if err != nil {
+			return DryRunResult{}, fmt.Errorf("error converting data: %w", err)
+		}
+	}
+	if _, err := pipe.ProcessInput(ctx, orgID, channel, body); err != nil {
+		return DryRunResult{}, fmt.Errorf("error processing data: %w", err)
+	}
+	return DryRunResult{
+		ChannelFrames: channelFrames,
+		Outputs:       builder.DryRunRecorder.Outputs(),
+	}, nil
+}
+
+// staticRuleGetter matches channels over a fixed set of rules of one org.
+// Unlike CacheSegmentedTree it does not rebuild rules in background.
+type staticRuleGetter struct {
+	orgID int64
+	tree  *tree.Node
+}
+
+func newStaticRuleGetter(orgID int64, rules []*LiveChannelRule) (g *staticRuleGetter, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidChannelRule, r)
+		}
+	}()
+	t := tree.New()
+	for _, rule := range rules {
+		t.AddRoute("/"+rule.Pattern, rule)
+	}
+	return &staticRuleGetter{orgID: orgID, tree: t}, nil
+}
+
+func (g *staticRuleGetter) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
+	if orgID != g.orgID {
+		return nil, false, nil
+	}
+	nodeValue := g.tree.GetValue("/"+channel, true)
+	if nodeValue.Handler == nil {
+		return nil, false, nil
+	}
+	return nodeValue.Handler.(*LiveChannelRule), true, nil
+}
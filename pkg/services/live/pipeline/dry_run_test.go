@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type dryRunTestStorage struct {
+	Storage
+	rules        []ChannelRule
+	writeConfigs []WriteConfig
+}
+
+func (s *dryRunTestStorage) ListChannelRules(_ context.Context, _ int64) ([]ChannelRule, error) {
+	return s.rules, nil
+}
+
+func (s *dryRunTestStorage) ListWriteConfigs(_ context.Context, _ int64) ([]WriteConfig, error) {
+	return s.writeConfigs, nil
+}
+
+func newDryRunTestBuilder(rules []ChannelRule, writeConfigs []WriteConfig) *StorageRuleBuilder {
+	return &StorageRuleBuilder{
+		FrameStorage:   NewFrameStorage(),
+		Storage:        &dryRunTestStorage{rules: rules, writeConfigs: writeConfigs},
+		WindowStorage:  NewWindowStorage(),
+		DryRunRecorder: &DryRunRecorder{},
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	rules := []ChannelRule{
+		{
+			Pattern: "stream/test/input",
+			Settings: ChannelRuleSettings{
+				DataOutputters: []*DataOutputterConfig{{Type: DataOutputTypeBuiltin}},
+				Converter:      &ConverterConfig{Type: ConverterTypeJsonAuto},
+				FrameProcessors: []*FrameProcessorConfig{{
+					Type:                      FrameProcessorTypeKeepFields,
+					KeepFieldsProcessorConfig: &KeepFieldsFrameProcessorConfig{FieldNames: []string{"value"}},
+				}},
+				FrameOutputters: []*FrameOutputterConfig{
+					{Type: FrameOutputTypeManagedStream},
+					{Type: FrameOutputTypeRedirect, RedirectOutputConfig: &RedirectOutputConfig{Channel: "stream/test/redirect"}},
+				},
+			},
+		},
+		{
+			Pattern: "stream/test/redirect",
+			Settings: ChannelRuleSettings{
+				FrameOutputters: []*FrameOutputterConfig{
+					{Type: FrameOutputTypeRemoteWrite, RemoteWriteOutputConfig: &RemoteWriteOutputConfig{UID: "rw", SampleMilliseconds: 1000}},
+				},
+			},
+		},
+	}
+	writeConfigs := []WriteConfig{{UID: "rw", Settings: WriteSettings{Endpoint: "http://localhost:1/api/prom/push"}}}
+
+	t.Run("records outputs", func(t *testing.T) {
+		builder := newDryRunTestBuilder(rules, writeConfigs)
+		result, err := DryRun(context.Background(), builder, 1, "stream/test/input", []byte(`{"value": 1, "other": "x"}`))
+		require.NoError(t, err)
+
+		require.Len(t, result.ChannelFrames, 1)
+		require.Len(t, result.ChannelFrames[0].Frame.Fields, 3)
+
+		require.Len(t, result.Outputs, 3)
+		require.Equal(t, DataOutputTypeBuiltin, result.Outputs[0].Type)
+		require.Equal(t, "stream/test/input", result.Outputs[0].Channel)
+		require.Equal(t, `{"value": 1, "other": "x"}`, result.Outputs[0].Data)
+
+		require.Equal(t, FrameOutputTypeManagedStream, result.Outputs[1].Type)
+		require.Equal(t, "stream/test/input", result.Outputs[1].Channel)
+		require.Len(t, result.Outputs[1].Frame.Fields, 1)
+		require.Equal(t, "value", result.Outputs[1].Frame.Fields[0].Name)
+
+		require.Equal(t, FrameOutputTypeRemoteWrite, result.Outputs[2].Type)
+		require.Equal(t, "stream/test/redirect", result.Outputs[2].Channel)
+	})
+
+	t.Run("no rule", func(t *testing.T) {
+		builder := newDryRunTestBuilder(rules, writeConfigs)
+		_, err := DryRun(context.Background(), builder, 1, "stream/test/unknown", []byte(`{}`))
+		require.ErrorIs(t, err, ErrDryRunRuleNotFound)
+	})
+
+	t.Run("unknown write config", func(t *testing.T) {
+		builder := newDryRunTestBuilder(rules, nil)
+		_, err := DryRun(context.Background(), builder, 1, "stream/test/input", []byte(`{}`))
+		require.Error(t, err)
+	})
+
+	t.Run("requires recorder", func(t *testing.T) {
+		builder := newDryRunTestBuilder(rules, writeConfigs)
+		builder.DryRunRecorder = nil
+		_, err := DryRun(context.Background(), builder, 1, "stream/test/input", []byte(`{}`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,494 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const (
+	AggregateFunctionMean       = "mean"
+	AggregateFunctionMin        = "min"
+	AggregateFunctionMax        = "max"
+	AggregateFunctionCount      = "count"
+	AggregateFunctionLast       = "last"
+	AggregateFunctionPercentile = "percentile"
+)
+
+// labelsColumnName is a name of string field which holds series labels of
+// each row, as produced by Influx converter in labels_column frame format.
+const labelsColumnName = "labels"
+
+// AggregateFrameProcessor aggregates numeric fields over tumbling or sliding
+// time windows, grouped by field name and labels. Windows are aligned to
+// multiples of slide and closed by event time: once a frame with a time after
+// the window end arrives, the window is emitted as a frame row with the window
+// end time. While no window is complete the processor returns nil frame, so
+// nothing is passed to outputs. Points arriving after their windows were
+// emitted are dropped.
+type AggregateFrameProcessor struct {
+	config    AggregateFrameProcessorConfig
+	storage   *WindowStorage
+	configKey string
+	window    int64
+	slide     int64
+}
+
+// NewAggregateFrameProcessor creates new AggregateFrameProcessor.
+func NewAggregateFrameProcessor(storage *WindowStorage, config AggregateFrameProcessorConfig) (*AggregateFrameProcessor, error) {
+	window, err := time.ParseDuration(config.Window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window: %w", err)
+	}
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+	slide := window
+	if config.Slide != "" {
+		slide, err = time.ParseDuration(config.Slide)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slide: %w", err)
+		}
+		if slide <= 0 || slide > window || window%slide != 0 {
+			return nil, errors.New("window must be a multiple of slide")
+		}
+	}
+	if len(config.Functions) == 0 {
+		return nil, errors.New("no aggregate functions")
+	}
+	for _, fn := range config.Functions {
+		switch fn {
+		case AggregateFunctionMean, AggregateFunctionMin, AggregateFunctionMax, AggregateFunctionCount, AggregateFunctionLast:
+		case AggregateFunctionPercentile:
+			if config.Percentile < 0 || config.Percentile > 100 {
+				return nil, errors.New("percentile must be between 0 and 100")
+			}
+		default:
+			return nil, fmt.Errorf("unknown aggregate function: %s", fn)
+		}
+	}
+	configKey, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateFrameProcessor{
+		config:    config,
+		storage:   storage,
+		configKey: string(configKey),
+		window:    int64(window),
+		slide:     int64(slide),
+	}, nil
+}
+
+const FrameProcessorTypeAggregate = "aggregate"
+
+func (p *AggregateFrameProcessor) Type() string {
+	return FrameProcessorTypeAggregate
+}
+
+func (p *AggregateFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	points, latest, err := p.extractPoints(frame)
+	if err != nil {
+		return nil, err
+	}
+	state := p.storage.get(orgchannel.PrependOrgID(vars.OrgID, vars.Channel)+"/"+p.configKey, time.Duration(p.window)+windowStateIdleTimeout)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.initialized && len(points) > 0 {
+		minTime := points[0].time
+		for _, pt := range points[1:] {
+			if pt.time < minTime {
+				minTime = pt.time
+			}
+		}
+		// The first window which contains the earliest point.
+		state.nextWindowStart = p.paneStart(minTime) - p.window + p.slide
+		state.maxTime = minTime
+		state.initialized = true
+	}
+	for _, pt := range points {
+		p.add(state, pt)
+	}
+	// Frames without values to aggregate still move time forward.
+	if state.initialized && latest > state.maxTime {
+		state.maxTime = latest
+	}
+	windows := p.closeWindows(state)
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	return p.windowsToFrame(frame.Name, windows), nil
+}
+
+type windowPoint struct {
+	series *windowSeries
+	time   int64
+	value  float64
+}
+
+type windowSeries struct {
+	key    string
+	name   string
+	labels data.Labels
+	config *data.FieldConfig
+}
+
+type windowValue struct {
+	time  int64
+	value float64
+}
+
+type windowState struct {
+	// lastUsed and expiresAfter are guarded by the mutex of WindowStorage.
+	lastUsed     time.Time
+	expiresAfter time.Duration
+
+	mu sync.Mutex
+	// panes hold values per series for each slide interval keyed by start.
+	panes map[int64]map[string][]windowValue
+	// series seen in panes, by key.
+	series map[string]*windowSeries
+	// nextWindowStart is a start of the next window to be emitted.
+	nextWindowStart int64
+	initialized     bool
+	maxTime         int64
+}
+
+type closedWindow struct {
+	end    int64
+	series map[string]*windowSeries
+	values map[string][]windowValue
+}
+
+// extractPoints returns points to aggregate and the latest time in frame.
+func (p *AggregateFrameProcessor) extractPoints(frame *data.Frame) ([]windowPoint, int64, error) {
+	timeIndex := -1
+	labelsIndex := -1
+	for i, f := range frame.Fields {
+		switch {
+		case timeIndex < 0 && (f.Type() == data.FieldTypeTime || f.Type() == data.FieldTypeNullableTime):
+			timeIndex = i
+		case f.Name == labelsColumnName && (f.Type() == data.FieldTypeString || f.Type() == data.FieldTypeNullableString):
+			labelsIndex = i
+		}
+	}
+	if timeIndex < 0 {
+		return nil, 0, errors.New("aggregate processor requires a time field")
+	}
+	timeField := frame.Fields[timeIndex]
+
+	latest := int64(math.MinInt64)
+	for i := 0; i < timeField.Len(); i++ {
+		if t, ok := timeField.ConcreteAt(i); ok && t.(time.Time).UnixNano() > latest {
+			latest = t.(time.Time).UnixNano()
+		}
+	}
+
+	rowLabels := make([]data.Labels, timeField.Len())
+	if labelsIndex >= 0 {
+		for i := range rowLabels {
+			v, ok := frame.Fields[labelsIndex].ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			l, err := data.LabelsFromString(v.(string))
+			if err != nil {
+				return nil, 0, fmt.Errorf("error parsing labels: %w", err)
+			}
+			rowLabels[i] = l
+		}
+	}
+
+	series := map[string]*windowSeries{}
+	var points []windowPoint
+	for i, f := range frame.Fields {
+		if i == timeIndex || i == labelsIndex || !f.Type().Numeric() {
+			continue
+		}
+		if len(p.config.FieldNames) > 0 && !stringInSlice(f.Name, p.config.FieldNames) {
+			continue
+		}
+		for row := 0; row < f.Len() && row < timeField.Len(); row++ {
+			t, ok := timeField.ConcreteAt(row)
+			if !ok {
+				continue
+			}
+			v, err := f.NullableFloatAt(row)
+			if err != nil {
+				return nil, 0, err
+			}
+			if v == nil || math.IsNaN(*v) {
+				continue
+			}
+			labels := p.groupLabels(f.Labels, rowLabels[row])
+			key := f.Name + labels.String()
+			s, ok := series[key]
+			if !ok {
+				s = &windowSeries{key: key, name: f.Name, labels: labels, config: f.Config}
+				series[key] = s
+			}
+			points = append(points, windowPoint{series: s, time: t.(time.Time).UnixNano(), value: *v})
+		}
+	}
+	return points, latest, nil
+}
+
+// groupLabels merges field and row labels keeping only labels to group by.
+func (p *AggregateFrameProcessor) groupLabels(fieldLabels, rowLabels data.Labels) data.Labels {
+	labels := data.Labels{}
+	for _, l := range []data.Labels{fieldLabels, rowLabels} {
+		for k, v := range l {
+			if len(p.config.GroupBy) == 0 || stringInSlice(k, p.config.GroupBy) {
+				labels[k] = v
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+func (p *AggregateFrameProcessor) paneStart(t int64) int64 {
+	start := t - t%p.slide
+	if t < 0 && t%p.slide != 0 {
+		start -= p.slide
+	}
+	return start
+}
+
+func (p *AggregateFrameProcessor) add(state *windowState, pt windowPoint) {
+	pane := p.paneStart(pt.time)
+	if pane < state.nextWindowStart {
+		// All windows containing the point were emitted already.
+		return
+	}
+	if pt.time > state.maxTime {
+		state.maxTime = pt.time
+	}
+	if state.panes == nil {
+		state.panes = map[int64]map[string][]windowValue{}
+	}
+	if state.series == nil {
+		state.series = map[string]*windowSeries{}
+	}
+	values, ok := state.panes[pane]
+	if !ok {
+		values = map[string][]windowValue{}
+		state.panes[pane] = values
+	}
+	if _, ok := state.series[pt.series.key]; !ok {
+		state.series[pt.series.key] = pt.series
+	}
+	values[pt.series.key] = append(values[pt.series.key], windowValue{time: pt.time, value: pt.value})
+}
+
+// closeWindows collects values of all windows which ended before the latest
+// seen point and removes panes which are not needed anymore.
+func (p *AggregateFrameProcessor) closeWindows(state *windowState) []closedWindow {
+	var windows []closedWindow
+	for state.initialized && state.nextWindowStart+p.window <= state.maxTime {
+		start := state.nextWindowStart
+		end := start + p.window
+
+		minPane := int64(math.MaxInt64)
+		for pane := range state.panes {
+			if pane < minPane {
+				minPane = pane
+			}
+		}
+		if len(state.panes) == 0 || minPane >= end {
+			// Nothing in this window, skip ahead to the first window with data.
+			next := p.paneStart(state.maxTime) - p.window + p.slide
+			if len(state.panes) > 0 {
+				next = minPane - p.window + p.slide
+			}
+			if next <= start {
+				next = start + p.slide
+			}
+			state.nextWindowStart = next
+			continue
+		}
+
+		w := closedWindow{end: end, series: map[string]*windowSeries{}, values: map[string][]windowValue{}}
+		for pane, values := range state.panes {
+			if pane < start || pane >= end {
+				continue
+			}
+			for key, v := range values {
+				w.series[key] = state.series[key]
+				w.values[key] = append(w.values[key], v...)
+			}
+		}
+		windows = append(windows, w)
+
+		state.nextWindowStart = start + p.slide
+		for pane := range state.panes {
+			if pane < state.nextWindowStart {
+				delete(state.panes, pane)
+			}
+		}
+	}
+	if len(state.panes) == 0 {
+		// Forget series which are gone.
+		state.series = map[string]*windowSeries{}
+	}
+	return windows
+}
+
+func (p *AggregateFrameProcessor) windowsToFrame(name string, windows []closedWindow) *data.Frame {
+	seriesByKey := map[string]*windowSeries{}
+	for _, w := range windows {
+		for key, s := range w.series {
+			seriesByKey[key] = s
+		}
+	}
+	keys := make([]string, 0, len(seriesByKey))
+	for key := range seriesByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	timeField := data.NewField("time", nil, make([]time.Time, len(windows)))
+	for i, w := range windows {
+		timeField.Set(i, time.Unix(0, w.end).UTC())
+	}
+	fields := []*data.Field{timeField}
+
+	for _, key := range keys {
+		s := seriesByKey[key]
+		for _, fn := range p.config.Functions {
+			fieldName := s.name
+			if len(p.config.Functions) > 1 {
+				fieldName += "_" + p.functionSuffix(fn)
+			}
+			field := data.NewField(fieldName, s.labels, make([]*float64, len(windows)))
+			if fn != AggregateFunctionCount {
+				field.Config = s.config
+			}
+			for i, w := range windows {
+				values, ok := w.values[key]
+				if !ok {
+					continue
+				}
+				v := p.aggregate(fn, values)
+				field.Set(i, &v)
+			}
+			fields = append(fields, field)
+		}
+	}
+	return data.NewFrame(name, fields...)
+}
+
+func (p *AggregateFrameProcessor) functionSuffix(fn string) string {
+	if fn == AggregateFunctionPercentile {
+		return "p" + strconv.FormatFloat(p.config.Percentile, 'f', -1, 64)
+	}
+	return fn
+}
+
+func (p *AggregateFrameProcessor) aggregate(fn string, values []windowValue) float64 {
+	switch fn {
+	case AggregateFunctionCount:
+		return float64(len(values))
+	case AggregateFunctionMin:
+		result := values[0].value
+		for _, v := range values[1:] {
+			result = math.Min(result, v.value)
+		}
+		return result
+	case AggregateFunctionMax:
+		result := values[0].value
+		for _, v := range values[1:] {
+			result = math.Max(result, v.value)
+		}
+		return result
+	case AggregateFunctionLast:
+		last := values[0]
+		for _, v := range values[1:] {
+			if v.time >= last.time {
+				last = v
+			}
+		}
+		return last.value
+	case AggregateFunctionPercentile:
+		sorted := make([]float64, len(values))
+		for i, v := range values {
+			sorted[i] = v.value
+		}
+		sort.Float64s(sorted)
+		rank := p.config.Percentile / 100 * float64(len(sorted)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	default:
+		var sum float64
+		for _, v := range values {
+			sum += v.value
+		}
+		return sum / float64(len(values))
+	}
+}
+
+const (
+	// windowStateIdleTimeout is how long the state of a channel is kept after
+	// its last window could have been emitted.
+	windowStateIdleTimeout = 10 * time.Minute
+	// windowStorageCleanupInterval is how often idle states are removed.
+	windowStorageCleanupInterval = time.Minute
+)
+
+// WindowStorage keeps state of aggregation windows in memory, so it survives
+// rebuilding of channel rules. Not usable in HA setup. State of channels which
+// stopped publishing, or of changed rules, is removed once idle for longer
+// than the window.
+type WindowStorage struct {
+	mu          sync.Mutex
+	states      map[string]*windowState
+	lastCleanup time.Time
+	nowFunc     func() time.Time
+}
+
+func NewWindowStorage() *WindowStorage {
+	return &WindowStorage{
+		states:  map[string]*windowState{},
+		nowFunc: time.Now,
+	}
+}
+
+func (s *WindowStorage) get(key string, expiresAfter time.Duration) *windowState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.nowFunc()
+	if now.Sub(s.lastCleanup) >= windowStorageCleanupInterval {
+		s.removeIdle(now)
+		s.lastCleanup = now
+	}
+	state, ok := s.states[key]
+	if !ok {
+		state = &windowState{}
+		s.states[key] = state
+	}
+	state.lastUsed = now
+	state.expiresAfter = expiresAfter
+	return state
+}
+
+func (s *WindowStorage) removeIdle(now time.Time) {
+	for key, state := range s.states {
+		if now.Sub(state.lastUsed) > state.expiresAfter {
+			delete(s.states, key)
+		}
+	}
+}
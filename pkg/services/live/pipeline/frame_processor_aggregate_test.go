@@ -0,0 +1,223 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+var aggregateBaseTime = time.Unix(1000, 0).UTC()
+
+func aggregateTestFrame(offset time.Duration, value float64) *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{aggregateBaseTime.Add(offset)}),
+		data.NewField("value", nil, []float64{value}),
+	)
+}
+
+func processAggregate(t *testing.T, p *AggregateFrameProcessor, frames ...*data.Frame) []*data.Frame {
+	t.Helper()
+	var result []*data.Frame
+	for _, f := range frames {
+		out, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/aggregate"}, f)
+		require.NoError(t, err)
+		if out != nil {
+			result = append(result, out)
+		}
+	}
+	return result
+}
+
+func floatValues(t *testing.T, f *data.Field) []float64 {
+	t.Helper()
+	values := make([]float64, f.Len())
+	for i := 0; i < f.Len(); i++ {
+		v, err := f.NullableFloatAt(i)
+		require.NoError(t, err)
+		require.NotNil(t, v)
+		values[i] = *v
+	}
+	return values
+}
+
+func TestAggregateFrameProcessor_Tumbling(t *testing.T) {
+	p, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{
+		Window:    "10s",
+		Functions: []string{AggregateFunctionMean, AggregateFunctionMax, AggregateFunctionCount},
+	})
+	require.NoError(t, err)
+
+	var frames []*data.Frame
+	for i := 0; i < 10; i++ {
+		frames = append(frames, aggregateTestFrame(time.Duration(i)*time.Second, float64(i+1)))
+	}
+	require.Empty(t, processAggregate(t, p, frames...))
+
+	out := processAggregate(t, p, aggregateTestFrame(10*time.Second, 100))
+	require.Len(t, out, 1)
+	frame := out[0]
+	require.Equal(t, "test", frame.Name)
+	require.Len(t, frame.Fields, 4)
+	require.Equal(t, aggregateBaseTime.Add(10*time.Second), frame.Fields[0].At(0))
+	require.Equal(t, "value_mean", frame.Fields[1].Name)
+	require.Equal(t, []float64{5.5}, floatValues(t, frame.Fields[1]))
+	require.Equal(t, "value_max", frame.Fields[2].Name)
+	require.Equal(t, []float64{10}, floatValues(t, frame.Fields[2]))
+	require.Equal(t, "value_count", frame.Fields[3].Name)
+	require.Equal(t, []float64{10}, floatValues(t, frame.Fields[3]))
+
+	// Late point for an emitted window is dropped.
+	require.Empty(t, processAggregate(t, p, aggregateTestFrame(5*time.Second, 1000)))
+
+	// A gap emits only windows with data.
+	out = processAggregate(t, p, aggregateTestFrame(65*time.Second, 1))
+	require.Len(t, out, 1)
+	require.Equal(t, 1, out[0].Fields[0].Len())
+	require.Equal(t, aggregateBaseTime.Add(20*time.Second), out[0].Fields[0].At(0))
+	require.Equal(t, []float64{100}, floatValues(t, out[0].Fields[1]))
+}
+
+func TestAggregateFrameProcessor_Sliding(t *testing.T) {
+	p, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{
+		Window:    "10s",
+		Slide:     "5s",
+		Functions: []string{AggregateFunctionMean},
+	})
+	require.NoError(t, err)
+
+	out := processAggregate(t, p,
+		aggregateTestFrame(0, 1),
+		aggregateTestFrame(5*time.Second, 2),
+		aggregateTestFrame(10*time.Second, 3),
+		aggregateTestFrame(15*time.Second, 4),
+	)
+	require.Len(t, out, 3)
+	var times []time.Time
+	var means []float64
+	for _, f := range out {
+		require.Equal(t, "value", f.Fields[1].Name)
+		times = append(times, f.Fields[0].At(0).(time.Time))
+		means = append(means, floatValues(t, f.Fields[1])...)
+	}
+	require.Equal(t, []time.Time{
+		aggregateBaseTime.Add(5 * time.Second),
+		aggregateBaseTime.Add(10 * time.Second),
+		aggregateBaseTime.Add(15 * time.Second),
+	}, times)
+	require.Equal(t, []float64{1, 1.5, 2.5}, means)
+}
+
+func TestAggregateFrameProcessor_GroupBy(t *testing.T) {
+	p, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{
+		Window:     "10s",
+		Functions:  []string{AggregateFunctionPercentile, AggregateFunctionLast},
+		Percentile: 50,
+		FieldNames: []string{"usage"},
+		GroupBy:    []string{"host"},
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("cpu",
+		data.NewField("labels", nil, []string{"cpu=cpu0, host=a", "cpu=cpu1, host=a", "cpu=cpu0, host=b", "cpu=cpu0, host=a"}),
+		data.NewField("time", nil, []time.Time{
+			aggregateBaseTime,
+			aggregateBaseTime.Add(time.Second),
+			aggregateBaseTime.Add(2 * time.Second),
+			aggregateBaseTime.Add(3 * time.Second),
+		}),
+		data.NewField("usage", nil, []*float64{floatPtr(10), floatPtr(20), floatPtr(50), floatPtr(40)}),
+		data.NewField("idle", nil, []*float64{floatPtr(90), floatPtr(80), floatPtr(50), floatPtr(60)}),
+	)
+	out := processAggregate(t, p, frame, aggregateTestFrame(11*time.Second, 1))
+	require.Len(t, out, 1)
+
+	fields := out[0].Fields
+	require.Len(t, fields, 5)
+	require.Equal(t, "usage_p50", fields[1].Name)
+	require.Equal(t, data.Labels{"host": "a"}, fields[1].Labels)
+	require.Equal(t, []float64{20}, floatValues(t, fields[1]))
+	require.Equal(t, "usage_last", fields[2].Name)
+	require.Equal(t, []float64{40}, floatValues(t, fields[2]))
+	require.Equal(t, data.Labels{"host": "b"}, fields[3].Labels)
+	require.Equal(t, []float64{50}, floatValues(t, fields[3]))
+	require.Equal(t, []float64{50}, floatValues(t, fields[4]))
+}
+
+func TestAggregateFrameProcessor_StateSurvivesRebuild(t *testing.T) {
+	storage := NewWindowStorage()
+	config := AggregateFrameProcessorConfig{Window: "10s", Functions: []string{AggregateFunctionCount}}
+
+	p1, err := NewAggregateFrameProcessor(storage, config)
+	require.NoError(t, err)
+	require.Empty(t, processAggregate(t, p1, aggregateTestFrame(0, 1), aggregateTestFrame(time.Second, 1)))
+
+	p2, err := NewAggregateFrameProcessor(storage, config)
+	require.NoError(t, err)
+	out := processAggregate(t, p2, aggregateTestFrame(10*time.Second, 1))
+	require.Len(t, out, 1)
+	require.Equal(t, []float64{2}, floatValues(t, out[0].Fields[1]))
+}
+
+func TestAggregateFrameProcessor_RemovesIdleState(t *testing.T) {
+	storage := NewWindowStorage()
+	now := time.Now()
+	storage.nowFunc = func() time.Time { return now }
+
+	p, err := NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{Window: "10s", Functions: []string{AggregateFunctionCount}})
+	require.NoError(t, err)
+	require.Empty(t, processAggregate(t, p, aggregateTestFrame(0, 1)))
+	_, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/other"}, aggregateTestFrame(0, 1))
+	require.NoError(t, err)
+	require.Len(t, storage.states, 2)
+
+	now = now.Add(windowStateIdleTimeout)
+	require.Empty(t, processAggregate(t, p, aggregateTestFrame(time.Second, 1)))
+	require.Len(t, storage.states, 2, "state is kept while the window could still be emitted")
+
+	now = now.Add(windowStateIdleTimeout)
+	require.Empty(t, processAggregate(t, p, aggregateTestFrame(2*time.Second, 1)))
+	require.Len(t, storage.states, 1, "state of the channel which stopped publishing is removed")
+}
+
+func TestMultipleFrameProcessor_StopsOnNilFrame(t *testing.T) {
+	aggregate, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{Window: "10s", Functions: []string{AggregateFunctionCount}})
+	require.NoError(t, err)
+	p := NewMultipleFrameProcessor(aggregate, NewDropFieldsFrameProcessor(DropFieldsFrameProcessorConfig{FieldNames: []string{"value"}}))
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/aggregate"}
+	out, err := p.ProcessFrame(context.Background(), vars, aggregateTestFrame(0, 1))
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = p.ProcessFrame(context.Background(), vars, aggregateTestFrame(10*time.Second, 1))
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Len(t, out.Fields, 1)
+}
+
+func TestNewAggregateFrameProcessor_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config AggregateFrameProcessorConfig
+		err    string
+	}{
+		{name: "window", config: AggregateFrameProcessorConfig{Window: "10", Functions: []string{"mean"}}, err: "invalid window: time: missing unit in duration \"10\""},
+		{name: "slide", config: AggregateFrameProcessorConfig{Window: "10s", Slide: "3s", Functions: []string{"mean"}}, err: "window must be a multiple of slide"},
+		{name: "no functions", config: AggregateFrameProcessorConfig{Window: "10s"}, err: "no aggregate functions"},
+		{name: "unknown function", config: AggregateFrameProcessorConfig{Window: "10s", Functions: []string{"median"}}, err: "unknown aggregate function: median"},
+		{name: "percentile", config: AggregateFrameProcessorConfig{Window: "10s", Functions: []string{"percentile"}, Percentile: 101}, err: "percentile must be between 0 and 100"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAggregateFrameProcessor(NewWindowStorage(), tc.config)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
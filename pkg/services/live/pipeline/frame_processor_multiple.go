@@ -26,6 +26,11 @@ func (p *MultipleFrameProcessor) ProcessFrame(ctx context.Context, vars Vars, fr
 			logger.Error("Error processing frame", "error", err)
 			return nil, err
 		}
+		if frame == nil {
+			// Processors like aggregate return nil frame when there is
+			// nothing to pass further.
+			return nil, nil
+		}
 	}
 	return frame, nil
 }
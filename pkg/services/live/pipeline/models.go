@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/live/pipeline/pattern"
 	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
@@ -38,6 +39,32 @@ func (r ChannelRule) Valid() (bool, string) {
 			}
 		}
 	}
+	if r.Settings.Auth != nil {
+		for _, check := range []*ChannelAuthCheckConfig{r.Settings.Auth.Subscribe, r.Settings.Auth.Publish} {
+			if check == nil {
+				continue
+			}
+			if check.RequireRole != "" && !check.RequireRole.IsValid() {
+				return false, fmt.Sprintf("invalid role: %s", check.RequireRole)
+			}
+			if check.Scope != "" && check.Action == "" {
+				return false, "auth scope requires action"
+			}
+		}
+	}
+	if len(r.Settings.Connectors) > 0 {
+		if strings.Contains(r.Pattern, ":") || strings.Contains(r.Pattern, "*") {
+			return false, "connectors require a pattern without parameters"
+		}
+		if r.Settings.Converter == nil {
+			return false, "connectors require a converter"
+		}
+		for _, conn := range r.Settings.Connectors {
+			if !typeRegistered(conn.Type, ConnectorsRegistry) {
+				return false, fmt.Sprintf("unknown connector type: %s", conn.Type)
+			}
+		}
+	}
 	return true, ""
 }
 
@@ -59,6 +86,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 		UID:          b.UID,
 		Settings:     b.Settings,
 		SecureFields: secureFields,
+		Version:      b.Version,
 	}
 }
 
@@ -66,6 +94,7 @@ type WriteConfigDto struct {
 	UID          string          `json:"uid"`
 	Settings     WriteSettings   `json:"settings"`
 	SecureFields map[string]bool `json:"secureFields"`
+	Version      int64           `json:"version,omitempty"`
 }
 
 type WriteConfigGetCmd struct {
@@ -78,15 +107,18 @@ type WriteConfigCreateCmd struct {
 	SecureSettings map[string]string `json:"secureSettings"`
 }
 
-// TODO: add version field later.
 type WriteConfigUpdateCmd struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string]string `json:"secureSettings"`
+	// Version is an expected version of stored write config, zero skips the check.
+	Version int64 `json:"version,omitempty"`
 }
 
 type WriteConfigDeleteCmd struct {
 	UID string `json:"uid"`
+	// Version is an expected version of stored write config, zero skips the check.
+	Version int64 `json:"version,omitempty"`
 }
 
 type WriteConfig struct {
@@ -94,6 +126,7 @@ type WriteConfig struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string][]byte `json:"secureSettings,omitempty"`
+	Version        int64             `json:"version,omitempty"`
 }
 
 func (r WriteConfig) Valid() (bool, string) {
@@ -129,7 +162,8 @@ type ChannelRules struct {
 	Rules []ChannelRule `json:"rules"`
 }
 
-func checkRulesValid(orgID int64, rules []ChannelRule) (ok bool, reason string) {
+// CheckRulesValid checks that patterns of org rules do not conflict with each other.
+func CheckRulesValid(orgID int64, rules []ChannelRule) (ok bool, reason string) {
 	t := tree.New()
 	defer func() {
 		if r := recover(); r != nil {
@@ -154,8 +188,12 @@ type ChannelRuleCreateCmd struct {
 type ChannelRuleUpdateCmd struct {
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Version is an expected version of stored channel rule, zero skips the check.
+	Version int64 `json:"version,omitempty"`
 }
 
 type ChannelRuleDeleteCmd struct {
 	Pattern string `json:"pattern"`
+	// Version is an expected version of stored channel rule, zero skips the check.
+	Version int64 `json:"version,omitempty"`
 }
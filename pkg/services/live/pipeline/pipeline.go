@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -113,12 +114,12 @@ type Subscriber interface {
 
 // PublishAuthChecker checks whether current user can publish to a channel.
 type PublishAuthChecker interface {
-	CanPublish(ctx context.Context, u *user.SignedInUser) (bool, error)
+	CanPublish(ctx context.Context, u *user.SignedInUser, channel string) (bool, error)
 }
 
 // SubscribeAuthChecker checks whether current user can subscribe to a channel.
 type SubscribeAuthChecker interface {
-	CanSubscribe(ctx context.Context, u *user.SignedInUser) (bool, error)
+	CanSubscribe(ctx context.Context, u *user.SignedInUser, channel string) (bool, error)
 }
 
 // LiveChannelRule is an in-memory representation of each specific rule to be executed by Pipeline.
@@ -214,6 +215,20 @@ func (p *Pipeline) Get(orgID int64, channel string) (*LiveChannelRule, bool, err
 	return p.ruleGetter.Get(orgID, channel)
 }
 
+// CanPublish checks whether user can push data into a channel. It uses
+// PublishAuth of a channel rule, RoleAdmin is required if the rule has no
+// PublishAuth or there is no rule for a channel.
+func (p *Pipeline) CanPublish(ctx context.Context, u *user.SignedInUser, channelID string) (bool, error) {
+	rule, ok, err := p.ruleGetter.Get(u.OrgID, channelID)
+	if err != nil {
+		return false, err
+	}
+	if !ok || rule.PublishAuth == nil {
+		return u.HasRole(org.RoleAdmin), nil
+	}
+	return rule.PublishAuth.CanPublish(ctx, u, channelID)
+}
+
 func (p *Pipeline) ProcessInput(ctx context.Context, orgID int64, channelID string, body []byte) (bool, error) {
 	var span trace.Span
 	if p.tracer != nil {
@@ -66,6 +66,17 @@ var ConvertersRegistry = []EntityInfo{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
 	},
+	{
+		Type:        ConverterTypePrometheus,
+		Description: "accept Prometheus text exposition format",
+	},
+	{
+		Type:        ConverterTypeOtlp,
+		Description: "accept OTLP/HTTP protobuf metrics",
+		Example: OtlpConverterConfig{
+			ResourceAttributes: []string{"service.instance.id"},
+		},
+	},
 }
 
 var FrameProcessorsRegistry = []EntityInfo{
@@ -79,6 +90,14 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeAggregate,
+		Description: "aggregate values over time windows grouped by labels",
+		Example: AggregateFrameProcessorConfig{
+			Window:    "10s",
+			Functions: []string{AggregateFunctionMean, AggregateFunctionMax},
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -95,3 +114,21 @@ var DataOutputsRegistry = []EntityInfo{
 		Description: "output data to Loki as logs",
 	},
 }
+
+var ConnectorsRegistry = []EntityInfo{
+	{
+		Type:        ConnectorTypeMQTT,
+		Description: "subscribe to MQTT topics and push messages into the channel",
+		Example: MQTTConnectorConfig{
+			Topics: []string{"sensors/+/temperature"},
+		},
+	},
+	{
+		Type:        ConnectorTypeKafka,
+		Description: "consume a Kafka topic and push messages into the channel",
+		Example: KafkaConnectorConfig{
+			Topic:       "metrics",
+			StartOffset: "latest",
+		},
+	},
+}
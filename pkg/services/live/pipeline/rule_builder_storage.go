@@ -2,10 +2,15 @@ package pipeline
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/centrifugal/centrifuge"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
 
@@ -16,6 +21,27 @@ type StorageRuleBuilder struct {
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
+	AccessControl        accesscontrol.AccessControl
+	WindowStorage        *WindowStorage
+	// InstanceID identifies the Grafana instance in default MQTT client IDs,
+	// so replicas do not disconnect each other.
+	InstanceID string
+	// DryRunRecorder if set replaces outputs with side effects by outputs
+	// which only record what would be sent.
+	DryRunRecorder *DryRunRecorder
+}
+
+// authChecker implements both PublishAuthChecker and SubscribeAuthChecker.
+type authChecker interface {
+	PublishAuthChecker
+	SubscribeAuthChecker
+}
+
+func (f *StorageRuleBuilder) extractAuthChecker(config ChannelAuthCheckConfig, fallbackRole org.RoleType) authChecker {
+	if config.Action == "" {
+		return NewRoleCheckAuthorizer(config.RequireRole)
+	}
+	return NewAccessControlAuthorizer(f.AccessControl, config, fallbackRole)
 }
 
 func (f *StorageRuleBuilder) extractSubscriber(config *SubscriberConfig) (Subscriber, error) {
@@ -68,6 +94,16 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheus:
+		if config.PrometheusConverterConfig == nil {
+			config.PrometheusConverterConfig = &PrometheusConverterConfig{}
+		}
+		return NewPrometheusConverter(*config.PrometheusConverterConfig), nil
+	case ConverterTypeOtlp:
+		if config.OtlpConverterConfig == nil {
+			config.OtlpConverterConfig = &OtlpConverterConfig{}
+		}
+		return NewOtlpConverter(*config.OtlpConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
@@ -103,6 +139,15 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			processors = append(processors, proc)
 		}
 		return NewMultipleFrameProcessor(processors...), nil
+	case FrameProcessorTypeAggregate:
+		if config.AggregateProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		windowStorage := f.WindowStorage
+		if windowStorage == nil {
+			windowStorage = NewWindowStorage()
+		}
+		return NewAggregateFrameProcessor(windowStorage, *config.AggregateProcessorConfig)
 	default:
 		return nil, fmt.Errorf("unknown processor type: %s", config.Type)
 	}
@@ -189,8 +234,14 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		}
 		return NewMultipleFrameOutput(outputters...), nil
 	case FrameOutputTypeManagedStream:
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.frameOutput(config.Type), nil
+		}
 		return NewManagedStreamFrameOutput(f.ManagedStream), nil
 	case FrameOutputTypeLocalSubscribers:
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.frameOutput(config.Type), nil
+		}
 		return NewLocalSubscribersFrameOutput(f.Node), nil
 	case FrameOutputTypeConditional:
 		if config.ConditionalOutputConfig == nil {
@@ -222,6 +273,9 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		if err != nil {
 			return nil, fmt.Errorf("error getting password: %w", err)
 		}
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.frameOutput(config.Type), nil
+		}
 		return NewRemoteWriteFrameOutput(
 			writeConfig.Settings.Endpoint,
 			basicAuth,
@@ -239,6 +293,9 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		if err != nil {
 			return nil, fmt.Errorf("error getting password: %w", err)
 		}
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.frameOutput(config.Type), nil
+		}
 		return NewLokiFrameOutput(
 			writeConfig.Settings.Endpoint,
 			basicAuth,
@@ -276,13 +333,22 @@ func (f *StorageRuleBuilder) extractDataOutputter(config *DataOutputterConfig, w
 		if err != nil {
 			return nil, fmt.Errorf("error constructing basicAuth: %w", err)
 		}
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.dataOutput(config.Type), nil
+		}
 		return NewLokiDataOutput(
 			writeConfig.Settings.Endpoint,
 			basicAuth,
 		), nil
 	case DataOutputTypeBuiltin:
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.dataOutput(config.Type), nil
+		}
 		return NewBuiltinDataOutput(f.ChannelHandlerGetter), nil
 	case DataOutputTypeLocalSubscribers:
+		if f.DryRunRecorder != nil {
+			return f.DryRunRecorder.dataOutput(config.Type), nil
+		}
 		return NewLocalSubscribersDataOutput(f.Node), nil
 	default:
 		return nil, fmt.Errorf("unknown data output type: %s", config.Type)
@@ -318,11 +384,13 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 		}
 
 		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Subscribe != nil {
-			rule.SubscribeAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Subscribe.RequireRole)
+			// Without access control anyone can subscribe by default.
+			rule.SubscribeAuth = f.extractAuthChecker(*ruleConfig.Settings.Auth.Subscribe, org.RoleViewer)
 		}
 
 		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Publish != nil {
-			rule.PublishAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Publish.RequireRole)
+			// Without access control admin permissions required to publish by default.
+			rule.PublishAuth = f.extractAuthChecker(*ruleConfig.Settings.Auth.Publish, org.RoleAdmin)
 		}
 
 		var err error
@@ -377,3 +445,157 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 
 	return rules, nil
 }
+
+func (f *StorageRuleBuilder) extractConnector(orgID int64, pattern string, index int, config *ConnectorConfig, writeConfigs []WriteConfig) (Connector, string, error) {
+	if config == nil {
+		return nil, "", nil
+	}
+	missingConfiguration := fmt.Errorf("missing configuration for %s", config.Type)
+
+	var uid string
+	switch config.Type {
+	case ConnectorTypeMQTT:
+		if config.MQTTConnectorConfig == nil {
+			return nil, "", missingConfiguration
+		}
+		uid = config.MQTTConnectorConfig.UID
+	case ConnectorTypeKafka:
+		if config.KafkaConnectorConfig == nil {
+			return nil, "", missingConfiguration
+		}
+		uid = config.KafkaConnectorConfig.UID
+	default:
+		return nil, "", fmt.Errorf("unknown connector type: %s", config.Type)
+	}
+
+	writeConfig, ok := f.getWriteConfig(uid, writeConfigs)
+	if !ok {
+		return nil, "", fmt.Errorf("write config not found: %s", uid)
+	}
+	basicAuth, err := f.constructBasicAuth(writeConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	var username, password string
+	if basicAuth != nil {
+		username, password = basicAuth.User, basicAuth.Password
+	}
+
+	// The key covers resolved credentials, so a connector is restarted when
+	// either the rule or the write config changes.
+	keyData, err := json.Marshal(struct {
+		OrgID    int64            `json:"orgId"`
+		Pattern  string           `json:"pattern"`
+		Index    int              `json:"index"`
+		Config   *ConnectorConfig `json:"config"`
+		Endpoint string           `json:"endpoint"`
+		Username string           `json:"username"`
+		Password string           `json:"password"`
+	}{orgID, pattern, index, config, writeConfig.Settings.Endpoint, username, password})
+	if err != nil {
+		return nil, "", err
+	}
+	key := fmt.Sprintf("%x", sha256.Sum256(keyData))
+
+	groupID := defaultConnectorGroupID(orgID, pattern, config)
+	switch config.Type {
+	case ConnectorTypeMQTT:
+		c := config.MQTTConnectorConfig
+		clientID := c.ClientID
+		if clientID == "" {
+			clientID = f.defaultMQTTClientID(groupID, index)
+		}
+		return NewMQTTConnector(MQTTConnectorSettings{
+			Broker:     writeConfig.Settings.Endpoint,
+			Username:   username,
+			Password:   password,
+			ClientID:   clientID,
+			ShareGroup: groupID,
+			// Sessions of generated client IDs are not kept by the broker, as
+			// they would be orphaned when the instance goes away.
+			PersistentSession: c.ClientID != "",
+			Topics:            c.Topics,
+			QoS:               c.QoS,
+		}), key, nil
+	default:
+		c := config.KafkaConnectorConfig
+		if c.GroupID != "" {
+			groupID = c.GroupID
+		}
+		var brokers []string
+		for _, broker := range strings.Split(writeConfig.Settings.Endpoint, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				brokers = append(brokers, broker)
+			}
+		}
+		return NewKafkaConnector(KafkaConnectorSettings{
+			Brokers:     brokers,
+			Username:    username,
+			Password:    password,
+			Topic:       c.Topic,
+			GroupID:     groupID,
+			StartOffset: c.StartOffset,
+		}), key, nil
+	}
+}
+
+// defaultConnectorGroupID returns the Kafka consumer group or MQTT shared
+// subscription group of a connector. It is the same on all Grafana instances
+// and only depends on the org, channel and what the connector consumes, so
+// each message is processed once and Kafka offsets survive restarts and
+// reordered connectors.
+func defaultConnectorGroupID(orgID int64, pattern string, config *ConnectorConfig) string {
+	var source []string
+	switch config.Type {
+	case ConnectorTypeMQTT:
+		source = append([]string{config.MQTTConnectorConfig.UID}, config.MQTTConnectorConfig.Topics...)
+	case ConnectorTypeKafka:
+		source = []string{config.KafkaConnectorConfig.UID, config.KafkaConnectorConfig.Topic}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(source, "\n")))
+	return fmt.Sprintf("grafana-%d-%s-%x", orgID, strings.ReplaceAll(pattern, "/", "-"), sum[:4])
+}
+
+// defaultMQTTClientID returns the MQTT client ID of a connector without one
+// configured. Brokers disconnect a client when another one connects with the
+// same ID, so it is unique per Grafana instance and connector of a rule.
+func (f *StorageRuleBuilder) defaultMQTTClientID(groupID string, index int) string {
+	instanceID := f.InstanceID
+	if instanceID == "" {
+		instanceID = "grafana"
+	}
+	return fmt.Sprintf("%s-%s-%d", groupID, instanceID, index)
+}
+
+// BuildConnectors builds connectors configured in channel rules of an organization.
+func (f *StorageRuleBuilder) BuildConnectors(ctx context.Context, orgID int64) ([]*ChannelConnector, error) {
+	channelRules, err := f.Storage.ListChannelRules(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	writeConfigs, err := f.Storage.ListWriteConfigs(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var connectors []*ChannelConnector
+	for _, ruleConfig := range channelRules {
+		for i, connConfig := range ruleConfig.Settings.Connectors {
+			conn, key, err := f.extractConnector(orgID, ruleConfig.Pattern, i, connConfig, writeConfigs)
+			if err != nil {
+				return nil, fmt.Errorf("error building connector for %s: %w", ruleConfig.Pattern, err)
+			}
+			if conn == nil {
+				continue
+			}
+			connectors = append(connectors, &ChannelConnector{
+				OrgID:     orgID,
+				Channel:   ruleConfig.Pattern,
+				Key:       key,
+				Connector: conn,
+			})
+		}
+	}
+	return connectors, nil
+}
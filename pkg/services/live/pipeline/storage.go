@@ -1,8 +1,39 @@
 package pipeline
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrChannelRuleNotFound = errors.New("channel rule not found")
+	ErrChannelRuleExists   = errors.New("pattern already exists in org")
+	ErrInvalidChannelRule  = errors.New("invalid channel rule")
+	ErrWriteConfigNotFound = errors.New("write config not found")
+	ErrWriteConfigExists   = errors.New("write config already exists in org")
+	ErrInvalidWriteConfig  = errors.New("invalid write config")
+	// ErrVersionConflict is returned when an update or delete command carries
+	// a version which does not match the stored one, i.e. the entity was
+	// modified concurrently.
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+// CheckVersion returns ErrVersionConflict if expected version is set and
+// does not match the stored one.
+func CheckVersion(expected, stored int64) error {
+	if expected != 0 && expected != stored {
+		return fmt.Errorf("%w: expected version %d, got %d", ErrVersionConflict, expected, stored)
+	}
+	return nil
+}
 
 // Storage describes all methods to manage Live pipeline persistent data.
+//
+// Update and delete commands have an optional Version field. When set to a
+// non-zero value storage must reject the change with ErrVersionConflict if the
+// stored entity has a different version. Every successful write increments the
+// version of the entity.
 type Storage interface {
 	ListWriteConfigs(_ context.Context, orgID int64) ([]WriteConfig, error)
 	GetWriteConfig(_ context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error)
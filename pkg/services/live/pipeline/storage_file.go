@@ -3,7 +3,6 @@ package pipeline
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -68,13 +67,14 @@ func (f *FileStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd Wr
 
 	ok, reason := backend.Valid()
 	if !ok {
-		return WriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+		return WriteConfig{}, fmt.Errorf("%w: %s", ErrInvalidWriteConfig, reason)
 	}
 	for _, existingBackend := range writeConfigs.Configs {
 		if uidMatch(orgID, backend.UID, existingBackend) {
-			return WriteConfig{}, fmt.Errorf("backend already exists in org: %s", backend.UID)
+			return WriteConfig{}, fmt.Errorf("%w: %s", ErrWriteConfigExists, backend.UID)
 		}
 	}
+	backend.Version = 1
 	writeConfigs.Configs = append(writeConfigs.Configs, backend)
 	err = f.saveWriteConfigs(orgID, writeConfigs)
 	return backend, err
@@ -100,7 +100,7 @@ func (f *FileStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd Wr
 
 	ok, reason := backend.Valid()
 	if !ok {
-		return WriteConfig{}, fmt.Errorf("invalid channel rule: %s", reason)
+		return WriteConfig{}, fmt.Errorf("%w: %s", ErrInvalidWriteConfig, reason)
 	}
 
 	index := -1
@@ -112,9 +112,20 @@ func (f *FileStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd Wr
 		}
 	}
 	if index > -1 {
+		if err := CheckVersion(cmd.Version, writeConfigs.Configs[index].Version); err != nil {
+			return WriteConfig{}, err
+		}
+		backend.Version = writeConfigs.Configs[index].Version + 1
 		writeConfigs.Configs[index] = backend
 	} else {
-		return f.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd(cmd))
+		if cmd.Version != 0 {
+			return WriteConfig{}, ErrWriteConfigNotFound
+		}
+		return f.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd{
+			UID:            cmd.UID,
+			Settings:       cmd.Settings,
+			SecureSettings: cmd.SecureSettings,
+		})
 	}
 
 	err = f.saveWriteConfigs(orgID, writeConfigs)
@@ -136,9 +147,12 @@ func (f *FileStorage) DeleteWriteConfig(_ context.Context, orgID int64, cmd Writ
 	}
 
 	if index > -1 {
+		if err := CheckVersion(cmd.Version, writeConfigs.Configs[index].Version); err != nil {
+			return err
+		}
 		writeConfigs.Configs = removeWriteConfigByIndex(writeConfigs.Configs, index)
 	} else {
-		return fmt.Errorf("write config not found")
+		return ErrWriteConfigNotFound
 	}
 
 	return f.saveWriteConfigs(orgID, writeConfigs)
@@ -172,13 +186,14 @@ func (f *FileStorage) CreateChannelRule(_ context.Context, orgID int64, cmd Chan
 
 	ok, reason := rule.Valid()
 	if !ok {
-		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+		return rule, fmt.Errorf("%w: %s", ErrInvalidChannelRule, reason)
 	}
 	for _, existingRule := range channelRules.Rules {
 		if patternMatch(orgID, rule.Pattern, existingRule) {
-			return rule, fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+			return rule, fmt.Errorf("%w: %s", ErrChannelRuleExists, rule.Pattern)
 		}
 	}
+	rule.Version = 1
 	channelRules.Rules = append(channelRules.Rules, rule)
 	err = f.saveChannelRules(orgID, channelRules)
 	return rule, err
@@ -206,7 +221,7 @@ func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd Ch
 
 	ok, reason := rule.Valid()
 	if !ok {
-		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+		return rule, fmt.Errorf("%w: %s", ErrInvalidChannelRule, reason)
 	}
 
 	index := -1
@@ -218,9 +233,19 @@ func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd Ch
 		}
 	}
 	if index > -1 {
+		if err := CheckVersion(cmd.Version, channelRules.Rules[index].Version); err != nil {
+			return rule, err
+		}
+		rule.Version = channelRules.Rules[index].Version + 1
 		channelRules.Rules[index] = rule
 	} else {
-		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
+		if cmd.Version != 0 {
+			return rule, ErrChannelRuleNotFound
+		}
+		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd{
+			Pattern:  cmd.Pattern,
+			Settings: cmd.Settings,
+		})
 	}
 
 	err = f.saveChannelRules(orgID, channelRules)
@@ -252,9 +277,9 @@ func (f *FileStorage) readRules() (ChannelRules, error) {
 }
 
 func (f *FileStorage) saveChannelRules(orgID int64, rules ChannelRules) error {
-	ok, reason := checkRulesValid(orgID, rules.Rules)
+	ok, reason := CheckRulesValid(orgID, rules.Rules)
 	if !ok {
-		return errors.New(reason)
+		return fmt.Errorf("%w: %s", ErrInvalidChannelRule, reason)
 	}
 	ruleFile := f.ruleFilePath()
 	// Safe to ignore gosec warning G304.
@@ -288,9 +313,12 @@ func (f *FileStorage) DeleteChannelRule(_ context.Context, orgID int64, cmd Chan
 	}
 
 	if index > -1 {
+		if err := CheckVersion(cmd.Version, channelRules.Rules[index].Version); err != nil {
+			return err
+		}
 		channelRules.Rules = removeChannelRuleByIndex(channelRules.Rules, index)
 	} else {
-		return fmt.Errorf("rule not found")
+		return ErrChannelRuleNotFound
 	}
 
 	return f.saveChannelRules(orgID, channelRules)
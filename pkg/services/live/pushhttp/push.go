@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/live/convert"
+	"github.com/grafana/grafana/pkg/services/live/pushlimit"
 	"github.com/grafana/grafana/pkg/services/live/pushurl"
 	"github.com/grafana/grafana/pkg/setting"
 
@@ -58,8 +59,9 @@ func (g *Gateway) Handle(ctx *models.ReqContext) {
 	// TODO Grafana 8: decide which formats to use or keep all.
 	urlValues := ctx.Req.URL.Query()
 	frameFormat := pushurl.FrameFormatFromValues(urlValues)
+	inputFormat := pushurl.InputFormatFromValues(urlValues)
 
-	body, err := io.ReadAll(ctx.Req.Body)
+	body, err := g.readBody(ctx.Req.Body)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
 		ctx.Resp.WriteHeader(http.StatusInternalServerError)
@@ -70,12 +72,17 @@ func (g *Gateway) Handle(ctx *models.ReqContext) {
 		"streamId", streamID,
 		"bodyLength", len(body),
 		"frameFormat", frameFormat,
+		"inputFormat", inputFormat,
 	)
 
-	metricFrames, err := g.converter.Convert(body, frameFormat)
+	if !g.checkPushLimits(ctx, liveDto.ScopeStream+"/"+streamID, body) {
+		return
+	}
+
+	metricFrames, err := g.converter.ConvertInput(body, inputFormat, frameFormat)
 	if err != nil {
-		logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat)
-		if errors.Is(err, convert.ErrUnsupportedFrameFormat) {
+		logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat, "inputFormat", inputFormat)
+		if errors.Is(err, convert.ErrUnsupportedFrameFormat) || errors.Is(err, convert.ErrUnsupportedInputFormat) {
 			ctx.Resp.WriteHeader(http.StatusBadRequest)
 		} else {
 			ctx.Resp.WriteHeader(http.StatusInternalServerError)
@@ -101,7 +108,7 @@ func (g *Gateway) Handle(ctx *models.ReqContext) {
 func (g *Gateway) HandlePipelinePush(ctx *models.ReqContext) {
 	channelID := web.Params(ctx.Req)["*"]
 
-	body, err := io.ReadAll(ctx.Req.Body)
+	body, err := g.readBody(ctx.Req.Body)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
 		ctx.Resp.WriteHeader(http.StatusInternalServerError)
@@ -113,6 +120,21 @@ func (g *Gateway) HandlePipelinePush(ctx *models.ReqContext) {
 		"bodyLength", len(body),
 	)
 
+	allowed, err := g.GrafanaLive.Pipeline.CanPublish(ctx.Req.Context(), ctx.SignedInUser, channelID)
+	if err != nil {
+		logger.Error("Error checking publish permissions", "error", err, "channel", channelID)
+		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		ctx.Resp.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if !g.checkPushLimits(ctx, channelID, body) {
+		return
+	}
+
 	ruleFound, err := g.GrafanaLive.Pipeline.ProcessInput(ctx.Req.Context(), ctx.OrgID, channelID, body)
 	if err != nil {
 		logger.Error("Pipeline input processing error", "error", err, "body", string(body))
@@ -131,3 +153,30 @@ func (g *Gateway) HandlePipelinePush(ctx *models.ReqContext) {
 
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
+
+// readBody reads at most one byte over push_max_message_size, so large
+// bodies are not loaded into memory only to be rejected.
+func (g *Gateway) readBody(body io.Reader) ([]byte, error) {
+	if maxSize := g.GrafanaLive.PushLimiter.MaxMessageSize(); maxSize > 0 {
+		body = io.LimitReader(body, int64(maxSize)+1)
+	}
+	return io.ReadAll(body)
+}
+
+// checkPushLimits writes an error response and returns false if a message
+// is rejected by push limits.
+func (g *Gateway) checkPushLimits(ctx *models.ReqContext, stream string, body []byte) bool {
+	err := g.GrafanaLive.PushLimiter.Check(ctx.Req.Context(), ctx.SignedInUser.OrgID, stream, len(body))
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, pushlimit.ErrMessageTooLarge):
+		ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+	case errors.Is(err, pushlimit.ErrOrgRateLimitExceeded), errors.Is(err, pushlimit.ErrStreamRateLimitExceeded):
+		ctx.Resp.WriteHeader(http.StatusTooManyRequests)
+	default:
+		logger.Error("Error checking push limits", "error", err, "stream", stream)
+		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+	}
+	return false
+}
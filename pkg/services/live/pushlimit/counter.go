@@ -0,0 +1,22 @@
+package pushlimit
+
+import (
+	"context"
+	"time"
+)
+
+// Counter counts push messages in fixed time windows.
+type Counter interface {
+	// Incr increments the counter of key in the window which contains now and
+	// returns the new value.
+	Incr(ctx context.Context, key string, now time.Time) (int64, error)
+	// Decr decrements the counter of key in the window which contains now,
+	// it takes back an increment of a rejected message.
+	Decr(ctx context.Context, key string, now time.Time) error
+	// Get returns the counter of key in the window which contains now.
+	Get(ctx context.Context, key string, now time.Time) (int64, error)
+}
+
+func windowStart(now time.Time, window time.Duration) int64 {
+	return now.UnixNano() / int64(window)
+}
@@ -0,0 +1,80 @@
+package pushlimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCounter keeps counters in memory, so limits are only enforced per
+// Grafana instance.
+type MemoryCounter struct {
+	window time.Duration
+
+	mu       sync.Mutex
+	counters map[string]memoryCounterEntry
+	current  int64
+}
+
+type memoryCounterEntry struct {
+	window int64
+	count  int64
+}
+
+// NewMemoryCounter ...
+func NewMemoryCounter(window time.Duration) *MemoryCounter {
+	return &MemoryCounter{
+		window:   window,
+		counters: map[string]memoryCounterEntry{},
+	}
+}
+
+func (c *MemoryCounter) Incr(_ context.Context, key string, now time.Time) (int64, error) {
+	w := windowStart(now, c.window)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cleanup(w)
+	entry := c.counters[key]
+	if entry.window != w {
+		entry = memoryCounterEntry{window: w}
+	}
+	entry.count++
+	c.counters[key] = entry
+	return entry.count, nil
+}
+
+func (c *MemoryCounter) Decr(_ context.Context, key string, now time.Time) error {
+	w := windowStart(now, c.window)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.counters[key]
+	if ok && entry.window == w && entry.count > 0 {
+		entry.count--
+		c.counters[key] = entry
+	}
+	return nil
+}
+
+func (c *MemoryCounter) Get(_ context.Context, key string, now time.Time) (int64, error) {
+	w := windowStart(now, c.window)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.counters[key]
+	if !ok || entry.window != w {
+		return 0, nil
+	}
+	return entry.count, nil
+}
+
+// cleanup removes counters of previous windows once a new window starts.
+func (c *MemoryCounter) cleanup(w int64) {
+	if w == c.current {
+		return
+	}
+	c.current = w
+	for key, entry := range c.counters {
+		if entry.window != w {
+			delete(c.counters, key)
+		}
+	}
+}
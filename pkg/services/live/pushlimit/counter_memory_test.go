@@ -0,0 +1,48 @@
+package pushlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCounter(t *testing.T) {
+	c := NewMemoryCounter(time.Minute)
+	testCounter(t, c)
+}
+
+func testCounter(t *testing.T, c Counter) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Unix(1600000020, 0)
+
+	count, err := c.Get(ctx, "test", now)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	for i := 1; i <= 3; i++ {
+		count, err = c.Incr(ctx, "test", now.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int64(i), count)
+	}
+
+	count, err = c.Get(ctx, "test", now)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	require.NoError(t, c.Decr(ctx, "test", now))
+	count, err = c.Get(ctx, "test", now)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = c.Get(ctx, "other", now)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	// Next window starts from zero.
+	count, err = c.Incr(ctx, "test", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
@@ -0,0 +1,65 @@
+package pushlimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisCounter keeps counters in Redis, so limits are shared between
+// Grafana instances in HA setup.
+type RedisCounter struct {
+	redisClient *redis.Client
+	window      time.Duration
+}
+
+// NewRedisCounter ...
+func NewRedisCounter(redisClient *redis.Client, window time.Duration) *RedisCounter {
+	return &RedisCounter{
+		redisClient: redisClient,
+		window:      window,
+	}
+}
+
+func getCounterKey(key string, window int64) string {
+	return "gf_live.push_limit." + key + "." + strconv.FormatInt(window, 10)
+}
+
+func (c *RedisCounter) Incr(ctx context.Context, key string, now time.Time) (int64, error) {
+	counterKey := getCounterKey(key, windowStart(now, c.window))
+	pipe := c.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+	incr := pipe.Incr(ctx, counterKey)
+	// Keep counter a bit longer than a window to tolerate clock skew
+	// between instances.
+	pipe.PExpire(ctx, counterKey, 2*c.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (c *RedisCounter) Decr(ctx context.Context, key string, now time.Time) error {
+	counterKey := getCounterKey(key, windowStart(now, c.window))
+	pipe := c.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+	pipe.Decr(ctx, counterKey)
+	pipe.PExpire(ctx, counterKey, 2*c.window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisCounter) Get(ctx context.Context, key string, now time.Time) (int64, error) {
+	counterKey := getCounterKey(key, windowStart(now, c.window))
+	count, err := c.redisClient.Get(ctx, counterKey).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return count, nil
+}
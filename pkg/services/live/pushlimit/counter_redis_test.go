@@ -0,0 +1,25 @@
+//go:build redis
+// +build redis
+
+package pushlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisCounter(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	now := time.Unix(1600000020, 0)
+	for _, w := range []time.Time{now, now.Add(time.Minute)} {
+		require.NoError(t, redisClient.Del(context.Background(), getCounterKey("test", windowStart(w, time.Minute))).Err())
+	}
+	c := NewRedisCounter(redisClient, time.Minute)
+	testCounter(t, c)
+}
@@ -0,0 +1,182 @@
+package pushlimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+var logger = log.New("live.pushlimit")
+
+var (
+	ErrMessageTooLarge         = errors.New("push message too large")
+	ErrOrgRateLimitExceeded    = errors.New("organization push rate limit exceeded")
+	ErrStreamRateLimitExceeded = errors.New("stream push rate limit exceeded")
+)
+
+// Rejection reasons reported in metrics.
+const (
+	ReasonMessageSize = "message_size"
+	ReasonOrgRate     = "org_rate"
+	ReasonStreamRate  = "stream_rate"
+)
+
+// Window is a period push rate limits apply to.
+const Window = time.Minute
+
+// quotaCheckInterval is how long the result of organization quota check is
+// reused, so quota store is not queried on every message.
+const quotaCheckInterval = time.Second
+
+// Options of Limiter. Zero values disable corresponding limits.
+type Options struct {
+	// MaxMessageSize is a maximum size of a push message in bytes.
+	MaxMessageSize int
+	// OrgRateLimit is a maximum number of messages an organization can push
+	// during Window. Not used when organization limit is checked by quota service.
+	OrgRateLimit int64
+	// StreamRateLimit is a maximum number of messages which can be pushed to
+	// one stream or pipeline channel during Window.
+	StreamRateLimit int64
+}
+
+// Limiter enforces push message size and rate limits. Rate counters are kept
+// in Counter, which is shared between instances in HA setup.
+type Limiter struct {
+	opts         Options
+	counter      Counter
+	quotaService quota.Service
+	now          func() time.Time
+
+	quotaMu     sync.Mutex
+	quotaChecks map[int64]quotaCheck
+}
+
+type quotaCheck struct {
+	reached bool
+	expires time.Time
+}
+
+// NewLimiter creates new Limiter. If quotaService is not nil then the
+// organization rate limit is checked with it instead of Options.OrgRateLimit,
+// so it can be overridden per organization.
+func NewLimiter(counter Counter, opts Options, quotaService quota.Service) *Limiter {
+	return &Limiter{
+		opts:         opts,
+		counter:      counter,
+		quotaService: quotaService,
+		now:          time.Now,
+		quotaChecks:  map[int64]quotaCheck{},
+	}
+}
+
+// MaxMessageSize returns maximum size of a push message, 0 means unlimited.
+func (l *Limiter) MaxMessageSize() int {
+	return l.opts.MaxMessageSize
+}
+
+// Check is called for every incoming push message of size bytes. Stream is
+// a managed stream or a pipeline channel the message is pushed to. Returns
+// one of ErrMessageTooLarge, ErrOrgRateLimitExceeded, ErrStreamRateLimitExceeded
+// if the message should be rejected.
+func (l *Limiter) Check(ctx context.Context, orgID int64, stream string, size int) error {
+	if l.opts.MaxMessageSize > 0 && size > l.opts.MaxMessageSize {
+		telemetry.PushRejectedTotal.WithLabelValues(ReasonMessageSize).Inc()
+		return ErrMessageTooLarge
+	}
+
+	now := l.now()
+
+	// Counters are incremented before they are compared with the limits, so
+	// concurrent pushes, also on other instances, cannot all pass the check.
+	// Increments of rejected messages are taken back, so they do not use up
+	// the budget of the stream or organization.
+	var streamCounted bool
+	if l.opts.StreamRateLimit > 0 {
+		count, err := l.counter.Incr(ctx, streamKey(orgID, stream), now)
+		if err != nil {
+			return err
+		}
+		streamCounted = true
+		if count > l.opts.StreamRateLimit {
+			l.reject(ctx, ReasonStreamRate, now, streamKey(orgID, stream))
+			return ErrStreamRateLimitExceeded
+		}
+	}
+	rejectOrg := func(keys ...string) error {
+		if streamCounted {
+			keys = append(keys, streamKey(orgID, stream))
+		}
+		l.reject(ctx, ReasonOrgRate, now, keys...)
+		return ErrOrgRateLimitExceeded
+	}
+
+	if l.quotaService != nil {
+		reached, err := l.orgQuotaReached(ctx, orgID, now)
+		if err != nil {
+			return err
+		}
+		if reached {
+			return rejectOrg()
+		}
+		if _, err := l.counter.Incr(ctx, orgKey(orgID), now); err != nil {
+			return err
+		}
+	} else if l.opts.OrgRateLimit > 0 {
+		count, err := l.counter.Incr(ctx, orgKey(orgID), now)
+		if err != nil {
+			return err
+		}
+		if count > l.opts.OrgRateLimit {
+			return rejectOrg(orgKey(orgID))
+		}
+	}
+
+	telemetry.PushMessagesTotal.Inc()
+	telemetry.PushBytesTotal.Add(float64(size))
+	return nil
+}
+
+// reject records a rejected message and takes back its increments of the
+// counters of keys.
+func (l *Limiter) reject(ctx context.Context, reason string, now time.Time, keys ...string) {
+	telemetry.PushRejectedTotal.WithLabelValues(reason).Inc()
+	for _, key := range keys {
+		if err := l.counter.Decr(ctx, key, now); err != nil {
+			logger.Warn("Error taking back push counter", "key", key, "error", err)
+		}
+	}
+}
+
+func (l *Limiter) orgQuotaReached(ctx context.Context, orgID int64, now time.Time) (bool, error) {
+	l.quotaMu.Lock()
+	check, ok := l.quotaChecks[orgID]
+	l.quotaMu.Unlock()
+	if ok && now.Before(check.expires) {
+		return check.reached, nil
+	}
+
+	reached, err := l.quotaService.CheckQuotaReached(ctx, QuotaTargetSrv, &quota.ScopeParameters{OrgID: orgID})
+	if err != nil {
+		return false, err
+	}
+
+	l.quotaMu.Lock()
+	l.quotaChecks[orgID] = quotaCheck{reached: reached, expires: now.Add(quotaCheckInterval)}
+	l.quotaMu.Unlock()
+	return reached, nil
+}
+
+func orgKey(orgID int64) string {
+	return "org." + strconv.FormatInt(orgID, 10)
+}
+
+func streamKey(orgID int64, stream string) string {
+	return "stream." + strconv.FormatInt(orgID, 10) + "." + stream
+}
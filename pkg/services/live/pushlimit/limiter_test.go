@@ -0,0 +1,137 @@
+package pushlimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(opts Options, quotaService quota.Service) *Limiter {
+	l := NewLimiter(NewMemoryCounter(Window), opts, quotaService)
+	now := time.Unix(1600000020, 0)
+	l.now = func() time.Time { return now }
+	return l
+}
+
+func TestLimiter_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no limits", func(t *testing.T) {
+		l := newTestLimiter(Options{}, nil)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.Check(ctx, 1, "stream/test", 1024*1024))
+		}
+	})
+
+	t.Run("message size", func(t *testing.T) {
+		l := newTestLimiter(Options{MaxMessageSize: 10}, nil)
+		require.NoError(t, l.Check(ctx, 1, "stream/test", 10))
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/test", 11), ErrMessageTooLarge)
+	})
+
+	t.Run("stream rate", func(t *testing.T) {
+		l := newTestLimiter(Options{StreamRateLimit: 2}, nil)
+		require.NoError(t, l.Check(ctx, 1, "stream/test", 1))
+		require.NoError(t, l.Check(ctx, 1, "stream/test", 1))
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/test", 1), ErrStreamRateLimitExceeded)
+		// Other streams and organizations have own limits.
+		require.NoError(t, l.Check(ctx, 1, "stream/other", 1))
+		require.NoError(t, l.Check(ctx, 2, "stream/test", 1))
+		// Limit resets in the next window.
+		l.now = func() time.Time { return time.Unix(1600000020, 0).Add(Window) }
+		require.NoError(t, l.Check(ctx, 1, "stream/test", 1))
+	})
+
+	t.Run("org rate", func(t *testing.T) {
+		l := newTestLimiter(Options{OrgRateLimit: 2}, nil)
+		require.NoError(t, l.Check(ctx, 1, "stream/a", 1))
+		require.NoError(t, l.Check(ctx, 1, "stream/b", 1))
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/c", 1), ErrOrgRateLimitExceeded)
+		require.NoError(t, l.Check(ctx, 2, "stream/a", 1))
+	})
+
+	t.Run("rejected messages do not count", func(t *testing.T) {
+		l := newTestLimiter(Options{StreamRateLimit: 2, OrgRateLimit: 1}, nil)
+		require.NoError(t, l.Check(ctx, 1, "stream/a", 1))
+		// Rejected by the organization limit, the stream budget is kept.
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/b", 1), ErrOrgRateLimitExceeded)
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/b", 1), ErrOrgRateLimitExceeded)
+		count, err := l.counter.Get(ctx, streamKey(1, "stream/b"), l.now())
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		l = newTestLimiter(Options{StreamRateLimit: 1, OrgRateLimit: 2}, nil)
+		require.NoError(t, l.Check(ctx, 1, "stream/a", 1))
+		// Rejected by the stream limit, the organization budget is kept.
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/a", 1), ErrStreamRateLimitExceeded)
+		require.NoError(t, l.Check(ctx, 1, "stream/b", 1))
+	})
+
+	t.Run("concurrent pushes do not exceed the limits", func(t *testing.T) {
+		l := newTestLimiter(Options{StreamRateLimit: 10, OrgRateLimit: 15}, nil)
+		var accepted int64
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			stream := "stream/a"
+			if i%2 == 0 {
+				stream = "stream/b"
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if l.Check(ctx, 1, stream, 1) == nil {
+					atomic.AddInt64(&accepted, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int64(15), accepted)
+
+		count, err := l.counter.Get(ctx, orgKey(1), l.now())
+		require.NoError(t, err)
+		require.Equal(t, int64(15), count)
+	})
+
+	t.Run("org quota reached", func(t *testing.T) {
+		l := newTestLimiter(Options{OrgRateLimit: 100}, quotatest.New(true, nil))
+		require.ErrorIs(t, l.Check(ctx, 1, "stream/test", 1), ErrOrgRateLimitExceeded)
+	})
+
+	t.Run("org quota not reached", func(t *testing.T) {
+		l := newTestLimiter(Options{OrgRateLimit: 1}, quotatest.New(false, nil))
+		// Quota service overrides OrgRateLimit.
+		require.NoError(t, l.Check(ctx, 1, "stream/test", 1))
+		require.NoError(t, l.Check(ctx, 1, "stream/test", 1))
+
+		u, err := l.Usage(ctx, &quota.ScopeParameters{OrgID: 1})
+		require.NoError(t, err)
+		tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.OrgScope)
+		require.NoError(t, err)
+		used, ok := u.Get(tag)
+		require.True(t, ok)
+		require.Equal(t, int64(2), used)
+	})
+}
+
+func TestReadQuotaConfig(t *testing.T) {
+	tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.OrgScope)
+	require.NoError(t, err)
+
+	limits, err := ReadQuotaConfig(0)
+	require.NoError(t, err)
+	limit, ok := limits.Get(tag)
+	require.True(t, ok)
+	require.Equal(t, int64(-1), limit)
+
+	limits, err = ReadQuotaConfig(600)
+	require.NoError(t, err)
+	limit, ok = limits.Get(tag)
+	require.True(t, ok)
+	require.Equal(t, int64(600), limit)
+}
@@ -0,0 +1,53 @@
+package pushlimit
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+const (
+	QuotaTargetSrv quota.TargetSrv = "live"
+	// QuotaTarget limits the number of messages an organization can push
+	// during Window.
+	QuotaTarget quota.Target = "live_push_message"
+)
+
+// ReadQuotaConfig returns default quota limits of Live push. The organization
+// limit defaults to orgRateLimit, 0 means unlimited.
+func ReadQuotaConfig(orgRateLimit int64) (*quota.Map, error) {
+	limits := &quota.Map{}
+
+	orgQuotaTag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.OrgScope)
+	if err != nil {
+		return limits, err
+	}
+
+	limit := orgRateLimit
+	if limit <= 0 {
+		limit = -1
+	}
+	limits.Set(orgQuotaTag, limit)
+	return limits, nil
+}
+
+// Usage reports the number of messages pushed by an organization during
+// the current Window.
+func (l *Limiter) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	u := &quota.Map{}
+	if scopeParams == nil || scopeParams.OrgID == 0 {
+		return u, nil
+	}
+
+	count, err := l.counter.Get(ctx, orgKey(scopeParams.OrgID), l.now())
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.OrgScope)
+	if err != nil {
+		return nil, err
+	}
+	u.Set(tag, count)
+	return u, nil
+}
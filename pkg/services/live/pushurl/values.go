@@ -7,6 +7,7 @@ import (
 
 const (
 	frameFormatParam = "gf_live_frame_format"
+	inputFormatParam = "gf_live_input_format"
 )
 
 // FrameFormatFromValues extracts frame format tip from url values.
@@ -17,3 +18,13 @@ func FrameFormatFromValues(values url.Values) string {
 	}
 	return frameFormat
 }
+
+// InputFormatFromValues extracts input format from url values: influx
+// (default), prometheus or otlp.
+func InputFormatFromValues(values url.Values) string {
+	inputFormat := strings.ToLower(values.Get(inputFormatParam))
+	if inputFormat == "" {
+		inputFormat = "influx"
+	}
+	return inputFormat
+}
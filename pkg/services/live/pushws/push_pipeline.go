@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/live/convert"
 	"github.com/grafana/grafana/pkg/services/live/livecontext"
 	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/live/pushlimit"
 
 	"github.com/gorilla/websocket"
 )
@@ -13,13 +14,14 @@ import (
 // PipelinePushHandler handles WebSocket client connections that push data to Live Pipeline.
 type PipelinePushHandler struct {
 	pipeline  *pipeline.Pipeline
+	limiter   *pushlimit.Limiter
 	config    Config
 	upgrade   *websocket.Upgrader
 	converter *convert.Converter
 }
 
 // NewPathHandler creates new PipelinePushHandler.
-func NewPipelinePushHandler(pipeline *pipeline.Pipeline, c Config) *PipelinePushHandler {
+func NewPipelinePushHandler(pipeline *pipeline.Pipeline, limiter *pushlimit.Limiter, c Config) *PipelinePushHandler {
 	if c.CheckOrigin == nil {
 		c.CheckOrigin = sameHostOriginCheck()
 	}
@@ -30,6 +32,7 @@ func NewPipelinePushHandler(pipeline *pipeline.Pipeline, c Config) *PipelinePush
 	}
 	return &PipelinePushHandler{
 		pipeline:  pipeline,
+		limiter:   limiter,
 		config:    c,
 		upgrade:   upgrade,
 		converter: convert.NewConverter(),
@@ -51,6 +54,17 @@ func (s *PipelinePushHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	allowed, err := s.pipeline.CanPublish(r.Context(), user, channelID)
+	if err != nil {
+		logger.Error("Error checking publish permissions", "error", err, "channel", channelID)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	conn, err := s.upgrade.Upgrade(rw, r, nil)
 	if err != nil {
 		return
@@ -71,6 +85,14 @@ func (s *PipelinePushHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request)
 			"bodyLength", len(body),
 		)
 
+		allowed, closeConn := checkPushLimits(r.Context(), conn, s.limiter, user.OrgID, channelID, body)
+		if closeConn {
+			return
+		}
+		if !allowed {
+			continue
+		}
+
 		ruleFound, err := s.pipeline.ProcessInput(r.Context(), user.OrgID, channelID, body)
 		if err != nil {
 			logger.Error("Pipeline input processing error", "error", err, "body", string(body))
@@ -2,6 +2,7 @@ package pushws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,6 +12,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pushlimit"
 )
 
 var (
@@ -111,3 +113,28 @@ func setupWSConn(ctx context.Context, conn *websocket.Conn, config Config) {
 		}
 	}()
 }
+
+// checkPushLimits returns whether a message can be processed and whether the
+// connection must be closed. Messages over rate limits are dropped, too large
+// messages close the connection.
+func checkPushLimits(ctx context.Context, conn *websocket.Conn, limiter *pushlimit.Limiter, orgID int64, stream string, body []byte) (allowed bool, closeConn bool) {
+	if limiter == nil {
+		return true, false
+	}
+	err := limiter.Check(ctx, orgID, stream, len(body))
+	switch {
+	case err == nil:
+		return true, false
+	case errors.Is(err, pushlimit.ErrMessageTooLarge):
+		logger.Warn("Push message too large", "stream", stream, "bodyLength", len(body))
+		deadline := time.Now().Add(time.Second)
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseMessageTooBig, err.Error()), deadline)
+		return false, true
+	case errors.Is(err, pushlimit.ErrOrgRateLimitExceeded), errors.Is(err, pushlimit.ErrStreamRateLimitExceeded):
+		logger.Debug("Push message dropped", "error", err, "stream", stream)
+		return false, false
+	default:
+		logger.Error("Error checking push limits", "error", err, "stream", stream)
+		return false, true
+	}
+}
@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// PushMessagesTotal counts push messages accepted by Live push endpoints.
+	PushMessagesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "push_messages_total",
+		Help:      "number of messages accepted by Live push endpoints",
+		Namespace: "grafana",
+		Subsystem: "live",
+	})
+
+	// PushBytesTotal counts payload bytes accepted by Live push endpoints.
+	PushBytesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "push_bytes_total",
+		Help:      "number of payload bytes accepted by Live push endpoints",
+		Namespace: "grafana",
+		Subsystem: "live",
+	})
+
+	// PushRejectedTotal counts push messages rejected by Live push limits.
+	PushRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "push_rejected_total",
+		Help:      "number of messages rejected by Live push limits",
+		Namespace: "grafana",
+		Subsystem: "live",
+	}, []string{"reason"})
+)
@@ -0,0 +1,232 @@
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	metrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// serviceNameAttribute is always copied from resource to labels, otherwise
+// series of different services would collide.
+const serviceNameAttribute = "service.name"
+
+// Converter converts OTLP/HTTP protobuf metrics export requests to Grafana
+// frames. Data point attributes become labels. Histograms and summaries are
+// split into _bucket (or quantiles), _sum and _count series the same way
+// Prometheus does. Exponential histograms only produce _sum and _count.
+type Converter struct {
+	resourceAttributes []string
+	nowTimeFunc        func() time.Time
+}
+
+// ConverterOption ...
+type ConverterOption func(*Converter)
+
+// WithResourceAttributes copies the given resource attributes to labels in
+// addition to service.name.
+func WithResourceAttributes(attributes ...string) ConverterOption {
+	return func(c *Converter) {
+		c.resourceAttributes = attributes
+	}
+}
+
+// WithNowTimeFunc sets the time used for data points without timestamp.
+func WithNowTimeFunc(nowTimeFunc func() time.Time) ConverterOption {
+	return func(c *Converter) {
+		c.nowTimeFunc = nowTimeFunc
+	}
+}
+
+// NewConverter creates new Converter from OTLP metrics to Grafana Data Frames.
+// This converter generates one frame for each metric name and time combination.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{nowTimeFunc: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	var req collectormetrics.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	now := c.nowTimeFunc()
+	var samples []telemetry.Sample
+	for _, rm := range req.GetResourceMetrics() {
+		resourceLabels := data.Labels{}
+		for _, kv := range rm.GetResource().GetAttributes() {
+			if c.isResourceLabel(kv.GetKey()) {
+				resourceLabels[kv.GetKey()] = anyValueToString(kv.GetValue())
+			}
+		}
+		var ms []*metrics.Metric
+		for _, sm := range rm.GetScopeMetrics() {
+			ms = append(ms, sm.GetMetrics()...)
+		}
+		// Deprecated, but still sent by older SDKs.
+		//nolint:staticcheck
+		for _, ilm := range rm.GetInstrumentationLibraryMetrics() {
+			ms = append(ms, ilm.GetMetrics()...)
+		}
+		for _, m := range ms {
+			samples = appendMetricSamples(samples, m, resourceLabels, now)
+		}
+	}
+	return telemetry.FramesFromSamples(samples), nil
+}
+
+func (c *Converter) isResourceLabel(key string) bool {
+	if key == serviceNameAttribute {
+		return true
+	}
+	for _, attr := range c.resourceAttributes {
+		if attr == key {
+			return true
+		}
+	}
+	return false
+}
+
+type dataPoint interface {
+	GetAttributes() []*common.KeyValue
+	GetTimeUnixNano() uint64
+	GetFlags() uint32
+}
+
+func appendMetricSamples(samples []telemetry.Sample, m *metrics.Metric, resourceLabels data.Labels, now time.Time) []telemetry.Sample {
+	name := m.GetName()
+
+	// point returns a function creating samples of a data point, or nil if the
+	// data point has no recorded value.
+	point := func(dp dataPoint) func(name string, value float64, extra ...string) telemetry.Sample {
+		if dp.GetFlags()&uint32(metrics.DataPointFlags_FLAG_NO_RECORDED_VALUE) != 0 {
+			return nil
+		}
+		ts := now
+		if dp.GetTimeUnixNano() > 0 {
+			ts = time.Unix(0, int64(dp.GetTimeUnixNano())).UTC()
+		}
+		labels := resourceLabels.Copy()
+		for _, kv := range dp.GetAttributes() {
+			labels[kv.GetKey()] = anyValueToString(kv.GetValue())
+		}
+		return func(name string, value float64, extra ...string) telemetry.Sample {
+			l := labels
+			if len(extra) > 0 {
+				l = labels.Copy()
+				l[extra[0]] = extra[1]
+			}
+			return telemetry.Sample{Name: name, Labels: l, Time: ts, Value: value}
+		}
+	}
+
+	switch d := m.GetData().(type) {
+	case *metrics.Metric_Gauge:
+		for _, dp := range d.Gauge.GetDataPoints() {
+			if sample := point(dp); sample != nil {
+				samples = append(samples, sample(name, numberValue(dp)))
+			}
+		}
+	case *metrics.Metric_Sum:
+		for _, dp := range d.Sum.GetDataPoints() {
+			if sample := point(dp); sample != nil {
+				samples = append(samples, sample(name, numberValue(dp)))
+			}
+		}
+	case *metrics.Metric_Histogram:
+		for _, dp := range d.Histogram.GetDataPoints() {
+			sample := point(dp)
+			if sample == nil {
+				continue
+			}
+			var cumulative uint64
+			bounds := dp.GetExplicitBounds()
+			for i, count := range dp.GetBucketCounts() {
+				cumulative += count
+				le := "+Inf"
+				if i < len(bounds) {
+					le = formatFloat(bounds[i])
+				}
+				samples = append(samples, sample(name+"_bucket", float64(cumulative), "le", le))
+			}
+			if dp.Sum != nil {
+				samples = append(samples, sample(name+"_sum", dp.GetSum()))
+			}
+			samples = append(samples, sample(name+"_count", float64(dp.GetCount())))
+		}
+	case *metrics.Metric_ExponentialHistogram:
+		for _, dp := range d.ExponentialHistogram.GetDataPoints() {
+			if sample := point(dp); sample != nil {
+				samples = append(samples,
+					sample(name+"_sum", dp.GetSum()),
+					sample(name+"_count", float64(dp.GetCount())),
+				)
+			}
+		}
+	case *metrics.Metric_Summary:
+		for _, dp := range d.Summary.GetDataPoints() {
+			sample := point(dp)
+			if sample == nil {
+				continue
+			}
+			for _, q := range dp.GetQuantileValues() {
+				samples = append(samples, sample(name, q.GetValue(), "quantile", formatFloat(q.GetQuantile())))
+			}
+			samples = append(samples,
+				sample(name+"_sum", dp.GetSum()),
+				sample(name+"_count", float64(dp.GetCount())),
+			)
+		}
+	}
+	return samples
+}
+
+func numberValue(dp *metrics.NumberDataPoint) float64 {
+	switch v := dp.GetValue().(type) {
+	case *metrics.NumberDataPoint_AsDouble:
+		return v.AsDouble
+	case *metrics.NumberDataPoint_AsInt:
+		return float64(v.AsInt)
+	}
+	return math.NaN()
+}
+
+func anyValueToString(v *common.AnyValue) string {
+	switch val := v.GetValue().(type) {
+	case *common.AnyValue_StringValue:
+		return val.StringValue
+	case *common.AnyValue_BoolValue:
+		return strconv.FormatBool(val.BoolValue)
+	case *common.AnyValue_IntValue:
+		return strconv.FormatInt(val.IntValue, 10)
+	case *common.AnyValue_DoubleValue:
+		return formatFloat(val.DoubleValue)
+	case *common.AnyValue_BytesValue:
+		return string(val.BytesValue)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
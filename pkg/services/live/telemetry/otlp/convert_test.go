@@ -0,0 +1,104 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	metrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resource "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func stringAttr(key, value string) *common.KeyValue {
+	return &common.KeyValue{Key: key, Value: &common.AnyValue{Value: &common.AnyValue_StringValue{StringValue: value}}}
+}
+
+func TestConverter_Convert(t *testing.T) {
+	ts := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	sum := 12.5
+	req := &collectormetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metrics.ResourceMetrics{{
+			Resource: &resource.Resource{Attributes: []*common.KeyValue{
+				stringAttr("service.name", "checkout"),
+				stringAttr("service.instance.id", "pod-1"),
+				stringAttr("telemetry.sdk.language", "go"),
+			}},
+			ScopeMetrics: []*metrics.ScopeMetrics{{
+				Metrics: []*metrics.Metric{
+					{
+						Name: "http.server.active_requests",
+						Data: &metrics.Metric_Gauge{Gauge: &metrics.Gauge{DataPoints: []*metrics.NumberDataPoint{
+							{
+								TimeUnixNano: uint64(ts.UnixNano()),
+								Attributes:   []*common.KeyValue{stringAttr("http.method", "GET")},
+								Value:        &metrics.NumberDataPoint_AsInt{AsInt: 3},
+							},
+							{
+								TimeUnixNano: uint64(ts.UnixNano()),
+								Attributes:   []*common.KeyValue{stringAttr("http.method", "POST")},
+								Flags:        uint32(metrics.DataPointFlags_FLAG_NO_RECORDED_VALUE),
+							},
+						}}},
+					},
+					{
+						Name: "http.server.duration",
+						Data: &metrics.Metric_Histogram{Histogram: &metrics.Histogram{DataPoints: []*metrics.HistogramDataPoint{{
+							TimeUnixNano:   uint64(ts.UnixNano()),
+							Count:          6,
+							Sum:            &sum,
+							BucketCounts:   []uint64{1, 2, 3},
+							ExplicitBounds: []float64{0.5, 1},
+						}}}},
+					},
+				},
+			}},
+		}},
+	}
+	body, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	converter := NewConverter(WithResourceAttributes("service.instance.id"))
+	frameWrappers, err := converter.Convert(body)
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(frameWrappers))
+	for _, w := range frameWrappers {
+		keys = append(keys, w.Key())
+	}
+	require.Equal(t, []string{
+		"http.server.active_requests",
+		"http.server.duration_bucket",
+		"http.server.duration_sum",
+		"http.server.duration_count",
+	}, keys)
+
+	gauge := frameWrappers[0].Frame()
+	require.Len(t, gauge.Fields, 2)
+	require.Equal(t, ts, gauge.Fields[0].At(0))
+	require.Equal(t, data.Labels{
+		"service.name":        "checkout",
+		"service.instance.id": "pod-1",
+		"http.method":         "GET",
+	}, gauge.Fields[1].Labels)
+	require.Equal(t, 3.0, *gauge.Fields[1].At(0).(*float64))
+
+	buckets := frameWrappers[1].Frame()
+	require.Len(t, buckets.Fields, 4)
+	values := map[string]float64{}
+	for _, f := range buckets.Fields[1:] {
+		values[f.Labels["le"]] = *f.At(0).(*float64)
+	}
+	require.Equal(t, map[string]float64{"0.5": 1, "1": 3, "+Inf": 6}, values)
+
+	require.Equal(t, 12.5, *frameWrappers[2].Frame().Fields[1].At(0).(*float64))
+	require.Equal(t, 6.0, *frameWrappers[3].Frame().Fields[1].At(0).(*float64))
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte("not a protobuf"))
+	require.Error(t, err)
+}
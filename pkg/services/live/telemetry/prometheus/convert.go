@@ -0,0 +1,137 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts metrics in Prometheus text exposition format to Grafana
+// frames. Histograms and summaries are split into the series Prometheus would
+// store for them: _bucket (or quantiles), _sum and _count.
+type Converter struct {
+	nowTimeFunc func() time.Time
+}
+
+// ConverterOption ...
+type ConverterOption func(*Converter)
+
+// WithNowTimeFunc sets the time used for samples without timestamp.
+func WithNowTimeFunc(nowTimeFunc func() time.Time) ConverterOption {
+	return func(c *Converter) {
+		c.nowTimeFunc = nowTimeFunc
+	}
+}
+
+// NewConverter creates new Converter from Prometheus text exposition format
+// to Grafana Data Frames. This converter generates one frame for each metric
+// name and time combination.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{nowTimeFunc: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	// Metric families are returned as a map, keep output stable.
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := c.nowTimeFunc()
+	var samples []telemetry.Sample
+	for _, name := range names {
+		samples = appendFamilySamples(samples, families[name], now)
+	}
+	return telemetry.FramesFromSamples(samples), nil
+}
+
+func appendFamilySamples(samples []telemetry.Sample, mf *dto.MetricFamily, now time.Time) []telemetry.Sample {
+	name := mf.GetName()
+	for _, m := range mf.GetMetric() {
+		ts := now
+		if m.TimestampMs != nil {
+			ts = time.UnixMilli(m.GetTimestampMs()).UTC()
+		}
+		labels := data.Labels{}
+		for _, lp := range m.GetLabel() {
+			labels[lp.GetName()] = lp.GetValue()
+		}
+		sample := func(name string, value float64, extra ...string) telemetry.Sample {
+			return telemetry.Sample{Name: name, Labels: withLabel(labels, extra...), Time: ts, Value: value}
+		}
+
+		switch mf.GetType() {
+		case dto.MetricType_COUNTER:
+			samples = append(samples, sample(name, m.GetCounter().GetValue()))
+		case dto.MetricType_GAUGE:
+			samples = append(samples, sample(name, m.GetGauge().GetValue()))
+		case dto.MetricType_UNTYPED:
+			samples = append(samples, sample(name, m.GetUntyped().GetValue()))
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			for _, q := range s.GetQuantile() {
+				samples = append(samples, sample(name, q.GetValue(), "quantile", formatFloat(q.GetQuantile())))
+			}
+			samples = append(samples,
+				sample(name+"_sum", s.GetSampleSum()),
+				sample(name+"_count", float64(s.GetSampleCount())),
+			)
+		case dto.MetricType_HISTOGRAM:
+			h := m.GetHistogram()
+			hasInf := false
+			for _, b := range h.GetBucket() {
+				if math.IsInf(b.GetUpperBound(), 1) {
+					hasInf = true
+				}
+				samples = append(samples, sample(name+"_bucket", float64(b.GetCumulativeCount()), "le", formatFloat(b.GetUpperBound())))
+			}
+			if !hasInf {
+				samples = append(samples, sample(name+"_bucket", float64(h.GetSampleCount()), "le", "+Inf"))
+			}
+			samples = append(samples,
+				sample(name+"_sum", h.GetSampleSum()),
+				sample(name+"_count", float64(h.GetSampleCount())),
+			)
+		}
+	}
+	return samples
+}
+
+// withLabel returns a copy of labels with an extra label pair.
+func withLabel(labels data.Labels, pair ...string) data.Labels {
+	if len(pair) == 0 {
+		return labels
+	}
+	l := labels.Copy()
+	l[pair[0]] = pair[1]
+	return l
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,73 @@
+package prometheus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_Convert(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "metrics.txt"))
+	require.NoError(t, err)
+
+	now := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	converter := NewConverter(WithNowTimeFunc(func() time.Time { return now }))
+	frameWrappers, err := converter.Convert(content)
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(frameWrappers))
+	dr := &backend.DataResponse{}
+	for _, w := range frameWrappers {
+		keys = append(keys, w.Key())
+		dr.Frames = append(dr.Frames, w.Frame())
+	}
+	require.Equal(t, []string{
+		"http_requests_total",
+		"request_duration_seconds_bucket",
+		"request_duration_seconds_sum",
+		"request_duration_seconds_count",
+		"rpc_duration_seconds",
+		"rpc_duration_seconds_sum",
+		"rpc_duration_seconds_count",
+		"temperature_celsius",
+	}, keys)
+
+	requests := dr.Frames[0]
+	require.Len(t, requests.Fields, 3)
+	require.Equal(t, now, requests.Fields[0].At(0))
+	require.Equal(t, data.Labels{"code": "200", "method": "post"}, requests.Fields[1].Labels)
+	require.Equal(t, 1027.0, *requests.Fields[1].At(0).(*float64))
+
+	temperature := dr.Frames[7]
+	require.Equal(t, time.UnixMilli(1670000000000).UTC(), temperature.Fields[0].At(0))
+
+	experimental.CheckGoldenJSONResponse(t, "testdata", "metrics", dr, false)
+}
+
+func TestConverter_Convert_AddsInfBucket(t *testing.T) {
+	content := []byte(`# TYPE latency histogram
+latency_bucket{le="1"} 2
+latency_sum 1.5
+latency_count 3
+`)
+	frameWrappers, err := NewConverter().Convert(content)
+	require.NoError(t, err)
+	require.Equal(t, "latency_bucket", frameWrappers[0].Key())
+
+	frame := frameWrappers[0].Frame()
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, data.Labels{"le": "+Inf"}, frame.Fields[1].Labels)
+	require.Equal(t, 3.0, *frame.Fields[1].At(0).(*float64))
+	require.Equal(t, data.Labels{"le": "1"}, frame.Fields[2].Labels)
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte("metric{ 1\n"))
+	require.Error(t, err)
+}
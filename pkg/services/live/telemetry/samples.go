@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Sample is a single value of a labeled metric series.
+type Sample struct {
+	Name   string
+	Labels data.Labels
+	Time   time.Time
+	Value  float64
+}
+
+var invalidKeyChars = regexp.MustCompile(`[^A-Za-z0-9_\-=.]`)
+
+// FramesFromSamples groups samples into one frame per metric name and time
+// combination. Each frame has a time field and a value field per series, the
+// series labels are set on the value fields. Fields are ordered by labels so
+// frames of the same metric keep the same schema across pushes.
+func FramesFromSamples(samples []Sample) []FrameWrapper {
+	// maintain the order of frames as they appear in input.
+	var frameKeyOrder []string
+	frames := map[string]*sampleFrame{}
+
+	for _, s := range samples {
+		frameKey := s.Name + "_" + s.Time.String()
+		frame, ok := frames[frameKey]
+		if !ok {
+			frame = &sampleFrame{
+				name:   s.Name,
+				time:   s.Time,
+				series: map[string]*sampleSeries{},
+			}
+			frames[frameKey] = frame
+			frameKeyOrder = append(frameKeyOrder, frameKey)
+		}
+		labelsKey := s.Labels.String()
+		if series, ok := frame.series[labelsKey]; ok {
+			// Last value wins for duplicate series.
+			series.value = s.Value
+			continue
+		}
+		frame.series[labelsKey] = &sampleSeries{labels: s.Labels, value: s.Value}
+	}
+
+	frameWrappers := make([]FrameWrapper, 0, len(frames))
+	for _, key := range frameKeyOrder {
+		frameWrappers = append(frameWrappers, frames[key])
+	}
+	return frameWrappers
+}
+
+type sampleSeries struct {
+	labels data.Labels
+	value  float64
+}
+
+type sampleFrame struct {
+	name   string
+	time   time.Time
+	series map[string]*sampleSeries
+}
+
+// Key returns metric name with characters not allowed in channel paths
+// replaced by underscores.
+func (f *sampleFrame) Key() string {
+	return invalidKeyChars.ReplaceAllString(f.name, "_")
+}
+
+// Frame transforms sampleFrame to Grafana data.Frame.
+func (f *sampleFrame) Frame() *data.Frame {
+	labelKeys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		labelKeys = append(labelKeys, key)
+	}
+	sort.Strings(labelKeys)
+
+	fields := make([]*data.Field, 0, len(f.series)+1)
+	fields = append(fields, data.NewField("time", nil, []time.Time{f.time}))
+	for _, key := range labelKeys {
+		s := f.series[key]
+		value := s.value
+		fields = append(fields, data.NewField(f.name, s.labels, []*float64{&value}))
+	}
+	return data.NewFrame(f.name, fields...)
+}
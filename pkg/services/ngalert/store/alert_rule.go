@@ -13,6 +13,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	entitystore "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -62,7 +63,8 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("deleted alert instances", "count", rows)
-		return nil
+
+		return entitystore.InsertEntityEvents(sess, st.FeatureToggles, orgID, entitystore.EntityTypeAlertRule, entitystore.EntityEventTypeDelete, ruleUID...)
 	})
 }
 
@@ -162,6 +164,9 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 					return fmt.Errorf("failed to create new rules: %w", err)
 				}
 				ids[newRules[i].UID] = newRules[i].ID
+				if err := entitystore.InsertEntityEvents(sess, st.FeatureToggles, newRules[i].OrgID, entitystore.EntityTypeAlertRule, entitystore.EntityEventTypeCreate, newRules[i].UID); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -198,6 +203,9 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				}
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
+			if err := entitystore.InsertEntityEvents(sess, st.FeatureToggles, r.New.OrgID, entitystore.EntityTypeAlertRule, entitystore.EntityEventTypeUpdate, r.New.UID); err != nil {
+				return err
+			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
 				RuleOrgID:        r.New.OrgID,
@@ -21,11 +21,13 @@ func ProvideService(db db.DB, toggles featuremgmt.FeatureToggles, objserver enti
 	// 🐢🐢🐢 pick the store
 	if toggles.IsEnabled(featuremgmt.FlagNewDBLibrary) { // hymmm not a registered feature flag
 		sqlstore = &sqlxStore{
-			sess: db.GetSqlxSession(),
+			sess:     db.GetSqlxSession(),
+			features: toggles,
 		}
 	} else {
 		sqlstore = &sqlStore{
-			db: db,
+			db:       db,
+			features: toggles,
 		}
 	}
 	svc := &Service{store: sqlstore}
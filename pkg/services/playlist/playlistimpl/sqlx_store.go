@@ -6,12 +6,15 @@ import (
 	"errors"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/sqlstore/session"
+	entitystore "github.com/grafana/grafana/pkg/services/store"
 )
 
 type sqlxStore struct {
-	sess *session.SessionDB
+	sess     *session.SessionDB
+	features featuremgmt.FeatureToggles
 }
 
 func (s *sqlxStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
@@ -54,7 +57,7 @@ func (s *sqlxStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComm
 				return err
 			}
 		}
-		return nil
+		return entitystore.InsertEntityEventsTx(ctx, tx, s.features, p.OrgId, entitystore.EntityTypePlaylist, entitystore.EntityEventTypeCreate, p.UID)
 	})
 
 	return &p, err
@@ -102,7 +105,10 @@ func (s *sqlxStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComm
 		}
 		query = `INSERT INTO playlist_item (playlist_id, type, value, title, "order") VALUES (:playlist_id, :type, :value, :title, :order)`
 		_, err = tx.NamedExec(ctx, query, playlistItems)
-		return err
+		if err != nil {
+			return err
+		}
+		return entitystore.InsertEntityEventsTx(ctx, tx, s.features, p.OrgId, entitystore.EntityTypePlaylist, entitystore.EntityEventTypeUpdate, p.UID)
 	})
 
 	return &dto, err
@@ -145,7 +151,7 @@ func (s *sqlxStore) Delete(ctx context.Context, cmd *playlist.DeletePlaylistComm
 		if _, err := tx.Exec(ctx, "DELETE FROM playlist_item WHERE playlist_id = ?", p.Id); err != nil {
 			return err
 		}
-		return nil
+		return entitystore.InsertEntityEventsTx(ctx, tx, s.features, cmd.OrgId, entitystore.EntityTypePlaylist, entitystore.EntityEventTypeDelete, cmd.UID)
 	})
 
 	return err
@@ -5,12 +5,15 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	entitystore "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 type sqlStore struct {
-	db db.DB
+	db       db.DB
+	features featuremgmt.FeatureToggles
 }
 
 func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
@@ -45,8 +48,11 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 		}
 
 		_, err = sess.Insert(&playlistItems)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return entitystore.InsertEntityEvents(sess, s.features, p.OrgId, entitystore.EntityTypePlaylist, entitystore.EntityEventTypeCreate, p.UID)
 	})
 	return &p, err
 }
@@ -99,7 +105,11 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 		}
 
 		_, err = sess.Insert(&playlistItems)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return entitystore.InsertEntityEvents(sess, s.features, p.OrgId, entitystore.EntityTypePlaylist, entitystore.EntityEventTypeUpdate, p.UID)
 	})
 	return &dto, err
 }
@@ -142,8 +152,11 @@ func (s *sqlStore) Delete(ctx context.Context, cmd *playlist.DeletePlaylistComma
 
 		var rawItemSQL = "DELETE FROM playlist_item WHERE playlist_id = ?"
 		_, err = sess.Exec(rawItemSQL, playlist.Id)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return entitystore.InsertEntityEvents(sess, s.features, cmd.OrgId, entitystore.EntityTypePlaylist, entitystore.EntityEventTypeDelete, cmd.UID)
 	})
 }
 
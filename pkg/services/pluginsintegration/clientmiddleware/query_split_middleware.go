@@ -0,0 +1,298 @@
+package clientmiddleware
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/plugins"
+	"golang.org/x/sync/errgroup"
+)
+
+// querySplitIntervalKey is the data source JSON data key used by data sources
+// to declare support for query splitting, e.g. "querySplitInterval": "1d".
+const querySplitIntervalKey = "querySplitInterval"
+
+// NewQuerySplittingMiddleware creates a new plugins.ClientMiddleware that will
+// split queries with a long time range into smaller sub-ranges for data
+// sources that declare support by setting querySplitInterval in their JSON
+// data. The sub-ranges are queried concurrently, with at most maxConcurrency
+// requests in flight, and the resulting frames are concatenated per query.
+func NewQuerySplittingMiddleware(maxConcurrency int) plugins.ClientMiddleware {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	return plugins.ClientMiddlewareFunc(func(next plugins.Client) plugins.Client {
+		return &QuerySplittingMiddleware{
+			next:           next,
+			maxConcurrency: maxConcurrency,
+		}
+	})
+}
+
+type QuerySplittingMiddleware struct {
+	next           plugins.Client
+	maxConcurrency int
+}
+
+func (m *QuerySplittingMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil {
+		return m.next.QueryData(ctx, req)
+	}
+
+	interval := querySplitInterval(req.PluginContext.DataSourceInstanceSettings)
+	if interval <= 0 {
+		return m.next.QueryData(ctx, req)
+	}
+
+	// chunks[i] holds the i-th sub-range of every query that has one.
+	chunks := [][]backend.DataQuery{}
+	split := false
+	for _, q := range req.Queries {
+		ranges := []backend.TimeRange{q.TimeRange}
+		if isRangeQuery(q) {
+			ranges = splitTimeRange(q.TimeRange, interval, q.Interval)
+		}
+		if len(ranges) > 1 {
+			split = true
+		}
+		for i, tr := range ranges {
+			if i == len(chunks) {
+				chunks = append(chunks, []backend.DataQuery{})
+			}
+			sq := q
+			sq.TimeRange = tr
+			chunks[i] = append(chunks[i], sq)
+		}
+	}
+
+	if !split {
+		return m.next.QueryData(ctx, req)
+	}
+
+	responses := make([]*backend.QueryDataResponse, len(chunks))
+	errs := make([]error, len(chunks))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(m.maxConcurrency)
+	for i, queries := range chunks {
+		i, queries := i, queries
+		g.Go(func() error {
+			chunkReq := &backend.QueryDataRequest{
+				PluginContext: req.PluginContext,
+				Headers:       make(map[string]string, len(req.Headers)),
+				Queries:       queries,
+			}
+			for k, v := range req.Headers {
+				chunkReq.Headers[k] = v
+			}
+			responses[i], errs[i] = m.next.QueryData(gctx, chunkReq)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	// Failing the whole request only makes sense when every sub-range failed,
+	// otherwise the error is reported on the affected queries.
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(errs) {
+		return nil, errs[0]
+	}
+
+	return mergeSplitResponses(req.Queries, chunks, responses, errs), nil
+}
+
+func (m *QuerySplittingMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return m.next.CallResource(ctx, req, sender)
+}
+
+func (m *QuerySplittingMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return m.next.CheckHealth(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) CollectMetrics(ctx context.Context, req *backend.CollectMetricsRequest) (*backend.CollectMetricsResult, error) {
+	return m.next.CollectMetrics(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return m.next.SubscribeStream(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return m.next.PublishStream(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return m.next.RunStream(ctx, req, sender)
+}
+
+// querySplitInterval returns the split interval declared by the data source,
+// or zero if the data source does not support query splitting.
+func querySplitInterval(settings *backend.DataSourceInstanceSettings) time.Duration {
+	if settings == nil || len(settings.JSONData) == 0 {
+		return 0
+	}
+
+	jsonData := map[string]interface{}{}
+	if err := json.Unmarshal(settings.JSONData, &jsonData); err != nil {
+		return 0
+	}
+
+	raw, ok := jsonData[querySplitIntervalKey].(string)
+	if !ok || raw == "" {
+		return 0
+	}
+
+	interval, err := gtime.ParseDuration(raw)
+	if err != nil || interval <= 0 {
+		return 0
+	}
+	return interval
+}
+
+// isRangeQuery returns false for instant queries, which return a single point
+// at the end of the time range and must not be split.
+func isRangeQuery(q backend.DataQuery) bool {
+	model := struct {
+		Range   *bool `json:"range"`
+		Instant bool  `json:"instant"`
+	}{}
+	if err := json.Unmarshal(q.JSON, &model); err != nil {
+		return true
+	}
+	if model.Range != nil {
+		return *model.Range
+	}
+	return !model.Instant
+}
+
+// splitTimeRange splits tr into sub-ranges whose boundaries are aligned to
+// multiples of interval since the Unix epoch. The interval is rounded up to a
+// multiple of step so that every boundary is also step aligned. Each sub-range
+// ends one millisecond before the next boundary, so step aligned data sources
+// never return the same point twice.
+func splitTimeRange(tr backend.TimeRange, interval, step time.Duration) []backend.TimeRange {
+	if step > 0 && interval%step != 0 {
+		interval = (interval/step + 1) * step
+	}
+
+	if interval <= 0 || tr.To.Sub(tr.From) <= interval {
+		return []backend.TimeRange{tr}
+	}
+
+	ranges := []backend.TimeRange{}
+	from := tr.From
+	for from.Before(tr.To) {
+		next := alignTime(from, interval).Add(interval)
+		if !next.Before(tr.To) {
+			ranges = append(ranges, backend.TimeRange{From: from, To: tr.To})
+			break
+		}
+		ranges = append(ranges, backend.TimeRange{From: from, To: next.Add(-time.Millisecond)})
+		from = next
+	}
+	return ranges
+}
+
+func alignTime(t time.Time, d time.Duration) time.Time {
+	ns := t.UnixNano()
+	return time.Unix(0, ns-ns%int64(d)).In(t.Location())
+}
+
+// mergeSplitResponses concatenates the frames of every sub-range response into
+// one response per query. Responses must be ordered by time range.
+func mergeSplitResponses(queries []backend.DataQuery, chunks [][]backend.DataQuery, responses []*backend.QueryDataResponse, errs []error) *backend.QueryDataResponse {
+	resp := backend.NewQueryDataResponse()
+	for _, q := range queries {
+		merged := backend.DataResponse{}
+		for i, r := range responses {
+			if !chunkHasQuery(chunks[i], q.RefID) {
+				continue
+			}
+			if errs[i] != nil {
+				merged.Error = errs[i]
+				continue
+			}
+			if r == nil {
+				continue
+			}
+			dr, ok := r.Responses[q.RefID]
+			if !ok {
+				continue
+			}
+			if dr.Error != nil {
+				merged.Error = dr.Error
+				continue
+			}
+			merged.Frames = mergeFrames(q.RefID, merged.Frames, dr.Frames)
+		}
+		resp.Responses[q.RefID] = merged
+	}
+	return resp
+}
+
+func chunkHasQuery(chunk []backend.DataQuery, refID string) bool {
+	for _, q := range chunk {
+		if q.RefID == refID {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeFrames appends the rows of every frame in src to the frame in dst with
+// the same query, name and field schema. Frames without a match are added to
+// dst. Frames without a RefID belong to the query with the given refID.
+func mergeFrames(refID string, dst data.Frames, src data.Frames) data.Frames {
+	index := make(map[string]*data.Frame, len(dst))
+	for _, f := range dst {
+		index[frameKey(refID, f)] = f
+	}
+
+	for _, f := range src {
+		key := frameKey(refID, f)
+		existing, ok := index[key]
+		if !ok {
+			dst = append(dst, f)
+			index[key] = f
+			continue
+		}
+		for i, field := range existing.Fields {
+			from := f.Fields[i]
+			for r := 0; r < from.Len(); r++ {
+				field.Append(from.At(r))
+			}
+		}
+	}
+	return dst
+}
+
+func frameKey(refID string, f *data.Frame) string {
+	if f.RefID != "" {
+		refID = f.RefID
+	}
+
+	var sb strings.Builder
+	sb.WriteString(refID)
+	sb.WriteString("|")
+	sb.WriteString(f.Name)
+	for _, field := range f.Fields {
+		sb.WriteString("|")
+		sb.WriteString(field.Name)
+		sb.WriteString("|")
+		sb.WriteString(field.Type().ItemTypeString())
+		sb.WriteString("|")
+		sb.WriteString(field.Labels.String())
+	}
+	return sb.String()
+}
@@ -0,0 +1,186 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTimeRange(t *testing.T) {
+	from := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should not split ranges shorter than the interval", func(t *testing.T) {
+		tr := backend.TimeRange{From: from, To: from.Add(6 * time.Hour)}
+		ranges := splitTimeRange(tr, 24*time.Hour, time.Minute)
+		require.Equal(t, []backend.TimeRange{tr}, ranges)
+	})
+
+	t.Run("should split on aligned boundaries", func(t *testing.T) {
+		tr := backend.TimeRange{From: from, To: from.Add(48 * time.Hour)}
+		ranges := splitTimeRange(tr, 24*time.Hour, time.Minute)
+		require.Len(t, ranges, 3)
+
+		midnight := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+		require.Equal(t, from, ranges[0].From)
+		require.Equal(t, midnight.Add(-time.Millisecond), ranges[0].To)
+		require.Equal(t, midnight, ranges[1].From)
+		require.Equal(t, midnight.Add(24*time.Hour-time.Millisecond), ranges[1].To)
+		require.Equal(t, midnight.Add(24*time.Hour), ranges[2].From)
+		require.Equal(t, tr.To, ranges[2].To)
+	})
+
+	t.Run("should round the interval up to a multiple of the step", func(t *testing.T) {
+		tr := backend.TimeRange{From: time.Unix(0, 0), To: time.Unix(100, 0)}
+		ranges := splitTimeRange(tr, 25*time.Second, 10*time.Second)
+		require.Len(t, ranges, 4)
+		for _, r := range ranges[1:] {
+			require.Zero(t, r.From.UnixNano()%int64(30*time.Second))
+		}
+	})
+}
+
+func TestQuerySplittingMiddleware(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72*time.Hour - time.Millisecond)
+	splitSettings := &backend.DataSourceInstanceSettings{JSONData: []byte(`{"querySplitInterval":"1d"}`)}
+
+	seriesClient := func(calls *int, mu *sync.Mutex) *clienttest.TestClient {
+		return &clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				mu.Lock()
+				*calls++
+				mu.Unlock()
+
+				resp := backend.NewQueryDataResponse()
+				for _, q := range req.Queries {
+					times := []time.Time{}
+					values := []float64{}
+					for ts := q.TimeRange.From; !ts.After(q.TimeRange.To); ts = ts.Add(q.Interval) {
+						times = append(times, ts)
+						values = append(values, float64(ts.Unix()))
+					}
+					resp.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{
+						data.NewFrame("",
+							data.NewField("time", nil, times),
+							data.NewField("value", data.Labels{"job": "a"}, values),
+						),
+					}}
+				}
+				return resp, nil
+			},
+		}
+	}
+
+	t.Run("should not split when the data source does not declare support", func(t *testing.T) {
+		calls := 0
+		mu := sync.Mutex{}
+		c := NewQuerySplittingMiddleware(2).CreateClientMiddleware(seriesClient(&calls, &mu))
+
+		res, err := c.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{}},
+			Queries: []backend.DataQuery{
+				{RefID: "A", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+		require.Equal(t, 72, res.Responses["A"].Frames[0].Rows())
+	})
+
+	t.Run("should split long ranges and merge frames in order", func(t *testing.T) {
+		calls := 0
+		mu := sync.Mutex{}
+		c := NewQuerySplittingMiddleware(2).CreateClientMiddleware(seriesClient(&calls, &mu))
+
+		res, err := c.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: splitSettings},
+			Queries: []backend.DataQuery{
+				{RefID: "A", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}},
+				{RefID: "B", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+
+		for _, refID := range []string{"A", "B"} {
+			frames := res.Responses[refID].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, 72, frames[0].Rows())
+
+			timeField := frames[0].Fields[0]
+			for i := 1; i < timeField.Len(); i++ {
+				prev := timeField.At(i - 1).(time.Time)
+				cur := timeField.At(i).(time.Time)
+				require.Equal(t, time.Hour, cur.Sub(prev))
+			}
+		}
+	})
+
+	t.Run("should not split instant queries", func(t *testing.T) {
+		calls := 0
+		mu := sync.Mutex{}
+		c := NewQuerySplittingMiddleware(2).CreateClientMiddleware(seriesClient(&calls, &mu))
+
+		res, err := c.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: splitSettings},
+			Queries: []backend.DataQuery{
+				{RefID: "A", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}, JSON: []byte(`{"instant":true}`)},
+				{RefID: "B", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}, JSON: []byte(`{"range":false}`)},
+				{RefID: "C", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}, JSON: []byte(`{"range":true,"instant":true}`)},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+		for _, refID := range []string{"A", "B", "C"} {
+			require.Len(t, res.Responses[refID].Frames, 1)
+			require.Equal(t, 72, res.Responses[refID].Frames[0].Rows())
+		}
+	})
+
+	t.Run("should report sub-range errors on the affected queries", func(t *testing.T) {
+		c := NewQuerySplittingMiddleware(1).CreateClientMiddleware(&clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				if req.Queries[0].TimeRange.From.Equal(from) {
+					return nil, errors.New("timeout")
+				}
+				resp := backend.NewQueryDataResponse()
+				resp.Responses["A"] = backend.DataResponse{Frames: data.Frames{data.NewFrame("")}}
+				return resp, nil
+			},
+		})
+
+		res, err := c.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: splitSettings},
+			Queries: []backend.DataQuery{
+				{RefID: "A", Interval: time.Hour, TimeRange: backend.TimeRange{From: from, To: to}},
+			},
+		})
+		require.NoError(t, err)
+		require.EqualError(t, res.Responses["A"].Error, "timeout")
+	})
+}
+
+func TestMergeFrames(t *testing.T) {
+	t.Run("should only merge frames of the same query", func(t *testing.T) {
+		newFrame := func(refID string, value float64) *data.Frame {
+			f := data.NewFrame("", data.NewField("value", nil, []float64{value}))
+			f.RefID = refID
+			return f
+		}
+
+		frames := mergeFrames("A", nil, data.Frames{newFrame("", 1), newFrame("B", 1)})
+		frames = mergeFrames("A", frames, data.Frames{newFrame("A", 2), newFrame("B", 2)})
+
+		require.Len(t, frames, 2)
+		require.Equal(t, []float64{1, 2}, []float64{frames[0].Fields[0].At(0).(float64), frames[0].Fields[0].At(1).(float64)})
+		require.Equal(t, "B", frames[1].RefID)
+		require.Equal(t, 2, frames[1].Rows())
+	})
+}
@@ -82,7 +82,10 @@ func CreateMiddlewares(cfg *setting.Cfg, oAuthTokenService oauthtoken.OAuthToken
 		middlewares = append(middlewares, clientmiddleware.NewUserHeaderMiddleware())
 	}
 
-	middlewares = append(middlewares, clientmiddleware.NewHTTPClientMiddleware())
+	middlewares = append(middlewares,
+		clientmiddleware.NewHTTPClientMiddleware(),
+		clientmiddleware.NewQuerySplittingMiddleware(cfg.DataSourceQuerySplitConcurrency),
+	)
 
 	return middlewares
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -24,6 +25,7 @@ type Api struct {
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
 	Log                    log.Logger
+	Cfg                    *setting.Cfg
 }
 
 func ProvideApi(
@@ -31,6 +33,7 @@ func ProvideApi(
 	rr routing.RouteRegister,
 	ac accesscontrol.AccessControl,
 	features *featuremgmt.FeatureManager,
+	cfg *setting.Cfg,
 ) *Api {
 	api := &Api{
 		PublicDashboardService: pd,
@@ -38,6 +41,7 @@ func ProvideApi(
 		AccessControl:          ac,
 		Features:               features,
 		Log:                    log.New("publicdashboards.api"),
+		Cfg:                    cfg,
 	}
 
 	// attach api if PublicDashboards feature flag is enabled
@@ -55,9 +59,10 @@ func (api *Api) RegisterAPIEndpoints() {
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
 
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
+	requiresAllowedIP := RequiresAllowedIP(api.PublicDashboardService, api.Cfg.PublicDashboards.TrustedProxies)
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", requiresAllowedIP, routing.Wrap(api.ViewPublicDashboard))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", requiresAllowedIP, routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", requiresAllowedIP, routing.Wrap(api.GetAnnotations))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.AccessControl)
@@ -85,6 +90,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// Get query usage of public dashboard
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/usage",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardQueryUsage))
 }
 
 // ListPublicDashboards Gets list of public dashboards by orgId
@@ -204,6 +214,27 @@ func (api *Api) DeletePublicDashboard(c *models.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, nil)
 }
 
+// GetPublicDashboardQueryUsage Gets the query volume generated by the access token of a public dashboard by day
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/usage
+func (api *Api) GetPublicDashboardQueryUsage(c *models.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !tokens.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardQueryUsage: invalid Uid %s", uid))
+	}
+
+	usage, err := api.PublicDashboardService.FindQueryUsage(c.Req.Context(), QueryUsageQuery{
+		OrgId:              c.OrgID,
+		PublicDashboardUid: uid,
+		From:               c.Query("from"),
+		To:                 c.Query("to"),
+	})
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
+
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
@@ -294,6 +294,78 @@ func TestAPIDeletePublicDashboard(t *testing.T) {
 	}
 }
 
+func TestAPIGetPublicDashboardQueryUsage(t *testing.T) {
+	dashboardUid := "abc1234"
+	publicDashboardUid := "1234asdfasdf"
+	usage := []QueryUsage{{PublicDashboardUid: publicDashboardUid, Day: "2022-10-02", RequestCount: 3, QueryCount: 6, ErrorCount: 1}}
+
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		Path                 string
+		ServiceErr           error
+		ExpectedHttpResponse int
+		ShouldCallService    bool
+	}{
+		{
+			Name:                 "User admin can get the query usage",
+			User:                 userAdmin,
+			Path:                 fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s/usage?from=2022-10-01&to=2022-10-31", dashboardUid, publicDashboardUid),
+			ExpectedHttpResponse: http.StatusOK,
+			ShouldCallService:    true,
+		},
+		{
+			Name:                 "User viewer cannot get the query usage",
+			User:                 userViewer,
+			Path:                 fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s/usage", dashboardUid, publicDashboardUid),
+			ExpectedHttpResponse: http.StatusForbidden,
+			ShouldCallService:    false,
+		},
+		{
+			Name:                 "Invalid days return an error",
+			User:                 userAdmin,
+			Path:                 fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s/usage?from=yesterday", dashboardUid, publicDashboardUid),
+			ServiceErr:           ErrInvalidDay.Errorf(""),
+			ExpectedHttpResponse: http.StatusBadRequest,
+			ShouldCallService:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+
+			if test.ShouldCallService {
+				service.On("FindQueryUsage", mock.Anything, mock.Anything).
+					Return(usage, test.ServiceErr)
+			}
+
+			cfg := setting.NewCfg()
+			cfg.RBACEnabled = false
+
+			features := featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards)
+			testServer := setupTestServer(t, cfg, features, service, nil, test.User)
+
+			response := callAPI(testServer, http.MethodGet, test.Path, nil, t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if test.ExpectedHttpResponse == http.StatusOK {
+				var jsonResp []QueryUsage
+				err := json.Unmarshal(response.Body.Bytes(), &jsonResp)
+				require.NoError(t, err)
+				assert.Equal(t, usage, jsonResp)
+
+				service.AssertCalled(t, "FindQueryUsage", mock.Anything, QueryUsageQuery{
+					OrgId:              test.User.OrgID,
+					PublicDashboardUid: publicDashboardUid,
+					From:               "2022-10-01",
+					To:                 "2022-10-31",
+				})
+			}
+		})
+	}
+}
+
 func TestAPIGetPublicDashboard(t *testing.T) {
 	pubdash := &PublicDashboard{IsEnabled: true}
 
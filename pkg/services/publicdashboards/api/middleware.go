@@ -1,12 +1,16 @@
 package api
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -62,6 +66,66 @@ func RequiresExistingAccessToken(publicDashboardService publicdashboards.Service
 	}
 }
 
+// RequiresAllowedIP Middleware to enforce the IP allow list of a public dashboard. Requests for public dashboards
+// which do not exist, are disabled or expired are left to the handler. Forwarding headers are only used for requests
+// from trusted proxies, as any client can set them.
+func RequiresAllowedIP(publicDashboardService publicdashboards.Service, trustedProxies IPAllowList) func(c *models.ReqContext) {
+	return func(c *models.ReqContext) {
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !tokens.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		pubdash, err := publicDashboardService.FindByAccessToken(c.Req.Context(), accessToken)
+		if errors.Is(err, ErrPublicDashboardNotFound) || errors.Is(err, ErrPublicDashboardExpired) {
+			return
+		}
+		if err != nil {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to query access token", nil)
+			return
+		}
+
+		if len(pubdash.IPAllowList) == 0 {
+			return
+		}
+
+		ip := clientIP(c.Req, trustedProxies)
+		if ip == nil || !pubdash.IPAllowList.Allows(ip) {
+			c.JsonApiErr(http.StatusForbidden, "Access to public dashboard not allowed from this IP address", nil)
+			return
+		}
+	}
+}
+
+// clientIP returns the address of the connection, or the client address forwarded by trusted proxies. The
+// X-Forwarded-For header is read from the right, the first address which is not a trusted proxy is the client.
+func clientIP(req *http.Request, trustedProxies IPAllowList) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !trustedProxies.Allows(ip) {
+		return ip
+	}
+
+	if forwardedFor := req.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+		addrs := strings.Split(strings.Join(forwardedFor, ","), ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			ip = net.ParseIP(strings.TrimSpace(addrs[i]))
+			if ip == nil || !trustedProxies.Allows(ip) {
+				return ip
+			}
+		}
+		return ip
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP
+	}
+	return ip
+}
+
 func CountPublicDashboardRequest() func(c *models.ReqContext) {
 	return func(c *models.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
@@ -152,6 +153,115 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestRequiresAllowedIP(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		AccessToken          string
+		RemoteAddr           string
+		Headers              map[string]string
+		TrustedProxies       IPAllowList
+		PublicDashboard      *PublicDashboard
+		ServiceErr           error
+		ExpectedResponseCode int
+	}{
+		{
+			Name:                 "Allows any address without IP allow list",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "203.0.113.7:4321",
+			PublicDashboard:      &PublicDashboard{},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Allows addresses in the IP allow list",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "10.1.2.3:4321",
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.0/8"}},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Allows IPv6 addresses in the IP allow list",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "[2001:db8::1]:4321",
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"2001:db8::1"}},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 403 for addresses not in the IP allow list",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "203.0.113.7:4321",
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.0/8", "192.168.1.1"}},
+			ExpectedResponseCode: http.StatusForbidden,
+		},
+		{
+			Name:                 "Ignores forwarding headers of requests which are not from a trusted proxy",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "203.0.113.7:4321",
+			Headers:              map[string]string{"X-Forwarded-For": "10.1.2.3", "X-Real-IP": "10.1.2.3"},
+			TrustedProxies:       IPAllowList{"192.168.0.0/16"},
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.0/8"}},
+			ExpectedResponseCode: http.StatusForbidden,
+		},
+		{
+			Name:                 "Uses the X-Forwarded-For header of requests from a trusted proxy",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "192.168.1.1:4321",
+			Headers:              map[string]string{"X-Forwarded-For": "10.1.2.3"},
+			TrustedProxies:       IPAllowList{"192.168.0.0/16"},
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.0/8"}},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Uses the last address of X-Forwarded-For which is not a trusted proxy",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "192.168.1.1:4321",
+			Headers:              map[string]string{"X-Forwarded-For": "10.1.2.3, 203.0.113.7, 192.168.1.2"},
+			TrustedProxies:       IPAllowList{"192.168.0.0/16"},
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.0/8"}},
+			ExpectedResponseCode: http.StatusForbidden,
+		},
+		{
+			Name:                 "Uses the X-Real-IP header of requests from a trusted proxy",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "192.168.1.1:4321",
+			Headers:              map[string]string{"X-Real-IP": "203.0.113.7"},
+			TrustedProxies:       IPAllowList{"192.168.0.0/16"},
+			PublicDashboard:      &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.0/8", "192.168.0.0/16"}},
+			ExpectedResponseCode: http.StatusForbidden,
+		},
+		{
+			Name:                 "Leaves expired public dashboards to the handler",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "203.0.113.7:4321",
+			ServiceErr:           ErrPublicDashboardExpired.Errorf("expired"),
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 500 when public dashboard service gives an error",
+			AccessToken:          validAccessToken,
+			RemoteAddr:           "203.0.113.7:4321",
+			ServiceErr:           ErrInternalServerError.Errorf("database error"),
+			ExpectedResponseCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("FindByAccessToken", mock.Anything, tt.AccessToken).Return(tt.PublicDashboard, tt.ServiceErr)
+			params := map[string]string{":accessToken": tt.AccessToken}
+			mw := func(c *models.ReqContext) {
+				c.Req.RemoteAddr = tt.RemoteAddr
+				for name, value := range tt.Headers {
+					c.Req.Header.Set(name, value)
+				}
+				RequiresAllowedIP(publicdashboardService, tt.TrustedProxies)(c)
+			}
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccesstoken", params, mw)
+			require.Equal(t, tt.ExpectedResponseCode, resp.Code)
+		})
+	}
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper models.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -85,6 +86,8 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 			service := publicdashboards.NewFakePublicDashboardService(t)
 			service.On("FindPublicDashboardAndDashboardByAccessToken", mock.Anything, mock.AnythingOfType("string")).
 				Return(&PublicDashboard{}, test.DashboardResult, test.Err).Maybe()
+			service.On("FindByAccessToken", mock.Anything, mock.AnythingOfType("string")).
+				Return(&PublicDashboard{}, nil).Maybe()
 
 			cfg := setting.NewCfg()
 			cfg.RBACEnabled = false
@@ -185,6 +188,8 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 
 	setup := func(enabled bool) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
 		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("FindByAccessToken", mock.Anything, mock.AnythingOfType("string")).
+			Return(&PublicDashboard{}, nil).Maybe()
 		cfg := setting.NewCfg()
 		cfg.RBACEnabled = false
 
@@ -242,6 +247,14 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 		require.Equal(t, http.StatusOK, resp.Code)
 	})
 
+	t.Run("Status code is 429 when the query rate limit is exceeded", func(t *testing.T) {
+		server, fakeDashboardService := setup(true)
+		fakeDashboardService.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), validAccessToken).Return(nil, ErrQueryRateLimitExceeded.Errorf(""))
+
+		resp := callAPI(server, http.MethodPost, getValidQueryPath(validAccessToken), strings.NewReader("{}"), t)
+		require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+
 	t.Run("Status code is 500 when the query fails", func(t *testing.T) {
 		server, fakeDashboardService := setup(true)
 		fakeDashboardService.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), validAccessToken).Return(&backend.QueryDataResponse{}, fmt.Errorf("error"))
@@ -320,6 +333,7 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
 	// create public dashboard
 	store := publicdashboardsStore.ProvideStore(db)
 	cfg := setting.NewCfg()
+	cfg.PublicDashboards = setting.PublicDashboardsSettings{QueryRateLimit: 0.001, QueryRateLimitBurst: 1, QueryCacheTTL: time.Minute}
 	ac := acmock.New()
 	cfg.RBACEnabled = false
 	service := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac)
@@ -363,6 +377,41 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
       }`,
 		resp.Body.String(),
 	)
+
+	// the query volume of the access token is counted in memory and added to the database when the service stops
+	stopped, stop := context.WithCancel(context.Background())
+	stop()
+	require.ErrorIs(t, service.Run(stopped), context.Canceled)
+	usage, err := service.FindQueryUsage(context.Background(), QueryUsageQuery{OrgId: pubdash.OrgId, PublicDashboardUid: pubdash.Uid})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.EqualValues(t, 1, usage[0].RequestCount)
+	assert.EqualValues(t, 1, usage[0].QueryCount)
+	assert.EqualValues(t, 0, usage[0].ErrorCount)
+
+	// identical queries are served from the cache and do not count against the rate limit
+	resp = callAPI(server, http.MethodPost,
+		fmt.Sprintf("/api/public/dashboards/%s/panels/1/query", pubdash.AccessToken),
+		strings.NewReader(`{}`),
+		t,
+	)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.ErrorIs(t, service.Run(stopped), context.Canceled)
+	usage, err = service.FindQueryUsage(context.Background(), QueryUsageQuery{OrgId: pubdash.OrgId, PublicDashboardUid: pubdash.Uid})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.EqualValues(t, 1, usage[0].RequestCount)
+
+	// other queries of the access token are rate limited
+	resp = callAPI(server, http.MethodPost,
+		fmt.Sprintf("/api/public/dashboards/%s/panels/1/query", pubdash.AccessToken),
+		strings.NewReader(`{"intervalMs":1000}`),
+		t,
+	)
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	var errResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+	assert.Equal(t, "publicdashboards.queryRateLimitExceeded", errResp["messageId"])
 }
 
 func TestAPIGetAnnotations(t *testing.T) {
@@ -412,6 +461,8 @@ func TestAPIGetAnnotations(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.RBACEnabled = false
 			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("FindByAccessToken", mock.Anything, mock.AnythingOfType("string")).
+				Return(&PublicDashboard{}, nil).Maybe()
 
 			if test.ExpectedServiceCalled {
 				service.On("FindAnnotations", mock.Anything, mock.Anything, mock.AnythingOfType("string")).
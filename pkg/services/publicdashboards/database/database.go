@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -173,13 +174,13 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 	return hasPublicDashboard, err
 }
 
-// ExistsEnabledByAccessToken Responds true if the accessToken exists and the public dashboard is enabled
+// ExistsEnabledByAccessToken Responds true if the accessToken exists, has not expired and the public dashboard is enabled
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true AND (expires_at IS NULL OR expires_at > ?)"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessToken, time.Now().UTC()).Count()
 		if err != nil {
 			return err
 		}
@@ -191,13 +192,13 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Contex
 	return hasPublicDashboard, err
 }
 
-// GetOrgIdByAccessToken Returns the public dashboard OrgId if exists and is enabled.
+// GetOrgIdByAccessToken Returns the public dashboard OrgId if exists, has not expired and is enabled.
 func (d *PublicDashboardStoreImpl) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	var orgId int64
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT org_id FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT org_id FROM dashboard_public WHERE access_token=? AND is_enabled=true AND (expires_at IS NULL OR expires_at > ?)"
 
-		_, err := dbSession.SQL(sql, accessToken).Get(&orgId)
+		_, err := dbSession.SQL(sql, accessToken, time.Now().UTC()).Get(&orgId)
 		if err != nil {
 			return err
 		}
@@ -233,11 +234,29 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		templateVariablesJSON, err := json.Marshal(cmd.PublicDashboard.TemplateVariables)
+		if err != nil {
+			return err
+		}
+
+		ipAllowListJSON, err := json.Marshal(cmd.PublicDashboard.IPAllowList)
+		if err != nil {
+			return err
+		}
+
+		var expiresAt interface{}
+		if cmd.PublicDashboard.ExpiresAt != nil {
+			expiresAt = cmd.PublicDashboard.ExpiresAt.UTC().Format("2006-01-02 15:04:05")
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, time_settings = ?, template_variables = ?, expires_at = ?, ip_allow_list = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			string(timeSettingsJSON),
+			string(templateVariablesJSON),
+			expiresAt,
+			string(ipAllowListJSON),
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -254,16 +273,80 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 	return affectedRows, err
 }
 
-// Deletes a public dashboard
+// Deletes a public dashboard and its query usage
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, orgId int64, uid string) (int64, error) {
 	dashboard := &PublicDashboard{OrgId: orgId, Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil || affectedRows == 0 {
+			return err
+		}
 
+		_, err = sess.Exec("DELETE FROM dashboard_public_usage WHERE org_id = ? AND public_dashboard_uid = ?", orgId, uid)
 		return err
 	})
 
 	return affectedRows, err
 }
+
+// RecordQueryUsage Adds query requests to the usage of a public dashboard on a day
+func (d *PublicDashboardStoreImpl) RecordQueryUsage(ctx context.Context, cmd RecordQueryUsageCommand) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		update := func() (bool, error) {
+			sqlResult, err := sess.Exec("UPDATE dashboard_public_usage SET request_count = request_count + ?, query_count = query_count + ?, error_count = error_count + ? WHERE public_dashboard_uid = ? AND day = ?",
+				cmd.Requests,
+				cmd.Queries,
+				cmd.Errors,
+				cmd.PublicDashboardUid,
+				cmd.Day)
+			if err != nil {
+				return false, err
+			}
+			affectedRows, err := sqlResult.RowsAffected()
+			return affectedRows > 0, err
+		}
+
+		updated, err := update()
+		if err != nil || updated {
+			return err
+		}
+
+		_, err = sess.Insert(&QueryUsage{
+			OrgId:              cmd.OrgId,
+			PublicDashboardUid: cmd.PublicDashboardUid,
+			Day:                cmd.Day,
+			RequestCount:       cmd.Requests,
+			QueryCount:         cmd.Queries,
+			ErrorCount:         cmd.Errors,
+		})
+		if err != nil && d.sqlStore.GetDialect().IsUniqueConstraintViolation(err) {
+			// another Grafana instance inserted the usage of the day in the meantime
+			_, err = update()
+		}
+		return err
+	})
+}
+
+// FindQueryUsage Returns the query usage of a public dashboard by day, the most recent day first
+func (d *PublicDashboardStoreImpl) FindQueryUsage(ctx context.Context, query QueryUsageQuery) ([]QueryUsage, error) {
+	resp := make([]QueryUsage, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ? AND public_dashboard_uid = ?", query.OrgId, query.PublicDashboardUid)
+		if query.From != "" {
+			sess.And("day >= ?", query.From)
+		}
+		if query.To != "" {
+			sess.And("day <= ?", query.To)
+		}
+
+		return sess.OrderBy("day DESC").Find(&resp)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
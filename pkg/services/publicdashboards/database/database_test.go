@@ -162,6 +162,54 @@ func TestIntegrationExistsEnabledByAccessToken(t *testing.T) {
 		require.False(t, res)
 	})
 
+	t.Run("ExistsEnabledByAccessToken will return false when the access token expired", func(t *testing.T) {
+		setup()
+
+		expiresAt := time.Now().Add(-time.Minute)
+		_, err := publicdashboardStore.Create(context.Background(), SavePublicDashboardCommand{
+			PublicDashboard: PublicDashboard{
+				IsEnabled:    true,
+				Uid:          "abc123",
+				DashboardUid: savedDashboard.Uid,
+				OrgId:        savedDashboard.OrgId,
+				CreatedAt:    time.Now(),
+				CreatedBy:    7,
+				AccessToken:  "accessToken",
+				ExpiresAt:    &expiresAt,
+			},
+		})
+		require.NoError(t, err)
+
+		res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), "accessToken")
+		require.NoError(t, err)
+
+		require.False(t, res)
+	})
+
+	t.Run("ExistsEnabledByAccessToken will return true when the access token expires in the future", func(t *testing.T) {
+		setup()
+
+		expiresAt := time.Now().Add(time.Hour)
+		_, err := publicdashboardStore.Create(context.Background(), SavePublicDashboardCommand{
+			PublicDashboard: PublicDashboard{
+				IsEnabled:    true,
+				Uid:          "abc123",
+				DashboardUid: savedDashboard.Uid,
+				OrgId:        savedDashboard.OrgId,
+				CreatedAt:    time.Now(),
+				CreatedBy:    7,
+				AccessToken:  "accessToken",
+				ExpiresAt:    &expiresAt,
+			},
+		})
+		require.NoError(t, err)
+
+		res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), "accessToken")
+		require.NoError(t, err)
+
+		require.True(t, res)
+	})
+
 	t.Run("ExistsEnabledByAccessToken will return false when no public dashboard has matching access token", func(t *testing.T) {
 		setup()
 
@@ -513,6 +561,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		require.NoError(t, err)
 		assert.EqualValues(t, affectedRows, 1)
 
+		expiresAt := time.Now().UTC().Add(time.Hour).Round(time.Second)
 		updatedPublicDashboard := PublicDashboard{
 			Uid:                  pdUid,
 			DashboardUid:         savedDashboard.Uid,
@@ -521,6 +570,9 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			AnnotationsEnabled:   true,
 			TimeSelectionEnabled: true,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			TemplateVariables:    TemplateVariables{"job": {"api"}},
+			ExpiresAt:            &expiresAt,
+			IPAllowList:          IPAllowList{"10.0.0.0/8"},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -541,6 +593,10 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.IsEnabled, pdRetrieved.IsEnabled)
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
+		assert.Equal(t, updatedPublicDashboard.TemplateVariables, pdRetrieved.TemplateVariables)
+		assert.Equal(t, updatedPublicDashboard.IPAllowList, pdRetrieved.IPAllowList)
+		require.NotNil(t, pdRetrieved.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*pdRetrieved.ExpiresAt))
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgId, anotherSavedDashboard.Uid)
@@ -657,6 +713,25 @@ func TestIntegrationDelete(t *testing.T) {
 		require.Nil(t, deletedDashboard)
 	})
 
+	t.Run("Delete removes the query usage", func(t *testing.T) {
+		setup()
+		err := publicdashboardStore.RecordQueryUsage(context.Background(), RecordQueryUsageCommand{
+			OrgId:              savedPublicDashboard.OrgId,
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			Day:                time.Now().UTC().Format(QueryUsageDayFormat),
+			Requests:           1,
+			Queries:            1,
+		})
+		require.NoError(t, err)
+
+		_, err = publicdashboardStore.Delete(context.Background(), savedPublicDashboard.OrgId, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		usage, err := publicdashboardStore.FindQueryUsage(context.Background(), QueryUsageQuery{OrgId: savedPublicDashboard.OrgId, PublicDashboardUid: savedPublicDashboard.Uid})
+		require.NoError(t, err)
+		require.Empty(t, usage)
+	})
+
 	t.Run("Non-existent public dashboard deletion doesn't throw an error", func(t *testing.T) {
 		setup()
 
@@ -666,6 +741,73 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationQueryUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore)
+	savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+	savedPublicDashboard := insertPublicDashboard(t, publicdashboardStore, savedDashboard.Uid, savedDashboard.OrgId, true)
+
+	record := func(day string, requests, queries, errors int64) {
+		err := publicdashboardStore.RecordQueryUsage(context.Background(), RecordQueryUsageCommand{
+			OrgId:              savedPublicDashboard.OrgId,
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			Day:                day,
+			Requests:           requests,
+			Queries:            queries,
+			Errors:             errors,
+		})
+		require.NoError(t, err)
+	}
+	record("2022-10-01", 1, 2, 0)
+	record("2022-10-01", 1, 3, 1)
+	record("2022-10-02", 1, 1, 0)
+
+	t.Run("FindQueryUsage returns the usage by day, the most recent day first", func(t *testing.T) {
+		usage, err := publicdashboardStore.FindQueryUsage(context.Background(), QueryUsageQuery{
+			OrgId:              savedPublicDashboard.OrgId,
+			PublicDashboardUid: savedPublicDashboard.Uid,
+		})
+		require.NoError(t, err)
+		require.Len(t, usage, 2)
+
+		assert.Equal(t, "2022-10-02", usage[0].Day)
+		assert.EqualValues(t, 1, usage[0].RequestCount)
+		assert.EqualValues(t, 1, usage[0].QueryCount)
+		assert.EqualValues(t, 0, usage[0].ErrorCount)
+
+		assert.Equal(t, "2022-10-01", usage[1].Day)
+		assert.EqualValues(t, 2, usage[1].RequestCount)
+		assert.EqualValues(t, 5, usage[1].QueryCount)
+		assert.EqualValues(t, 1, usage[1].ErrorCount)
+	})
+
+	t.Run("FindQueryUsage filters by day", func(t *testing.T) {
+		usage, err := publicdashboardStore.FindQueryUsage(context.Background(), QueryUsageQuery{
+			OrgId:              savedPublicDashboard.OrgId,
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			From:               "2022-10-01",
+			To:                 "2022-10-01",
+		})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		assert.Equal(t, "2022-10-01", usage[0].Day)
+	})
+
+	t.Run("FindQueryUsage returns no usage for other orgs", func(t *testing.T) {
+		usage, err := publicdashboardStore.FindQueryUsage(context.Background(), QueryUsageQuery{
+			OrgId:              savedPublicDashboard.OrgId + 1,
+			PublicDashboardUid: savedPublicDashboard.Uid,
+		})
+		require.NoError(t, err)
+		require.Empty(t, usage)
+	})
+}
+
 // helper function to insert a dashboard
 func insertTestDashboard(t *testing.T, dashboardStore *dashboardsDB.DashboardStore, title string, orgId int64,
 	folderId int64, isFolder bool, tags ...interface{}) *models.Dashboard {
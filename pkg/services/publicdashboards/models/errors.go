@@ -6,6 +6,7 @@ var (
 	ErrInternalServerError = errutil.NewBase(errutil.StatusInternal, "publicdashboards.internalServerError", errutil.WithPublicMessage("Internal server error"))
 
 	ErrPublicDashboardNotFound = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.notFound", errutil.WithPublicMessage("Public dashboard not found"))
+	ErrPublicDashboardExpired  = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.expired", errutil.WithPublicMessage("Public dashboard has expired"))
 	ErrDashboardNotFound       = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.dashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
 	ErrPanelNotFound           = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.panelNotFound", errutil.WithPublicMessage("Public dashboard panel not found"))
 
@@ -20,4 +21,10 @@ var (
 	ErrInvalidInterval                     = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidInterval", errutil.WithPublicMessage("intervalMS should be greater than 0"))
 	ErrInvalidMaxDataPoints                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
+	ErrInvalidTemplateVariables            = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTemplateVariables", errutil.WithPublicMessage("Invalid template variables"))
+	ErrTemplateVariableValueNotAllowed     = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.templateVariableValueNotAllowed", errutil.WithPublicMessage("Template variable value is not allowed"))
+	ErrInvalidIPAllowList                  = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidIpAllowList", errutil.WithPublicMessage("Invalid IP allow list"))
+	ErrInvalidDay                          = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidDay", errutil.WithPublicMessage("Invalid day"))
+
+	ErrQueryRateLimitExceeded = errutil.NewBase(errutil.StatusTooManyRequests, "publicdashboards.queryRateLimitExceeded", errutil.WithPublicMessage("Too many queries for this public dashboard, try again later"))
 )
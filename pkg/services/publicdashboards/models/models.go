@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"net"
 	"strconv"
 	"time"
 
@@ -40,6 +41,13 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
 
+	// TemplateVariables are the values viewers may select for the template variables of the dashboard
+	TemplateVariables TemplateVariables `json:"templateVariables" xorm:"template_variables"`
+	// ExpiresAt is the time after which the access token can no longer be used, it never expires when not set
+	ExpiresAt *time.Time `json:"expiresAt" xorm:"expires_at"`
+	// IPAllowList restricts the access to the given IP addresses and CIDR ranges, when not empty
+	IPAllowList IPAllowList `json:"ipAllowList" xorm:"ip_allow_list"`
+
 	CreatedBy int64 `json:"createdBy" xorm:"created_by"`
 	UpdatedBy int64 `json:"updatedBy" xorm:"updated_by"`
 
@@ -94,6 +102,59 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// TemplateVariables maps the names of dashboard template variables to the values viewers are allowed to select
+type TemplateVariables map[string][]string
+
+func (tv *TemplateVariables) FromDB(data []byte) error {
+	return json.Unmarshal(data, tv)
+}
+
+func (tv *TemplateVariables) ToDB() ([]byte, error) {
+	return json.Marshal(tv)
+}
+
+// IsAllowed returns true if viewers may select the value for the template variable
+func (tv TemplateVariables) IsAllowed(name string, value string) bool {
+	for _, allowedValue := range tv[name] {
+		if allowedValue == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IPAllowList is a list of IP addresses and CIDR ranges
+type IPAllowList []string
+
+func (l *IPAllowList) FromDB(data []byte) error {
+	return json.Unmarshal(data, l)
+}
+
+func (l *IPAllowList) ToDB() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+// Allows returns true if the IP address is in the allow list
+func (l IPAllowList) Allows(ip net.IP) bool {
+	for _, entry := range l {
+		if allowedIP := net.ParseIP(entry); allowedIP != nil {
+			if allowedIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the access token of the public dashboard expired
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !now.Before(*pd.ExpiresAt)
+}
+
 // BuildTimeSettings build time settings object using selected values if enabled and are valid or dashboard default values
 func (pd PublicDashboard) BuildTimeSettings(dashboard *models.Dashboard, reqDTO PublicDashboardQueryDTO) TimeSettings {
 	from := dashboard.Data.GetPath("time", "from").MustString()
@@ -125,6 +186,8 @@ type PublicDashboardQueryDTO struct {
 	IntervalMs    int64
 	MaxDataPoints int64
 	TimeRange     TimeSettings
+	// Variables are the values selected by the viewer for the template variables of the dashboard
+	Variables map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
@@ -132,6 +195,32 @@ type AnnotationsQueryDTO struct {
 	To   int64
 }
 
+// QueryUsageDayFormat is the format of the days query usage is aggregated by
+const QueryUsageDayFormat = "2006-01-02"
+
+// QueryUsage is the query volume generated by the access token of a public dashboard on a day
+type QueryUsage struct {
+	Id                 int64  `json:"-" xorm:"pk autoincr 'id'"`
+	OrgId              int64  `json:"-" xorm:"org_id"`
+	PublicDashboardUid string `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	Day                string `json:"day" xorm:"day"`
+	RequestCount       int64  `json:"requestCount" xorm:"request_count"`
+	QueryCount         int64  `json:"queryCount" xorm:"query_count"`
+	ErrorCount         int64  `json:"errorCount" xorm:"error_count"`
+}
+
+func (u QueryUsage) TableName() string {
+	return "dashboard_public_usage"
+}
+
+type QueryUsageQuery struct {
+	OrgId              int64
+	PublicDashboardUid string
+	// From and To are inclusive days in the QueryUsageDayFormat, they are ignored when empty
+	From string
+	To   string
+}
+
 //
 // COMMANDS
 //
@@ -139,3 +228,14 @@ type AnnotationsQueryDTO struct {
 type SavePublicDashboardCommand struct {
 	PublicDashboard PublicDashboard
 }
+
+// RecordQueryUsageCommand adds query requests made with the access token of a public dashboard on a day to its usage
+type RecordQueryUsageCommand struct {
+	OrgId              int64
+	PublicDashboardUid string
+	// Day is in the QueryUsageDayFormat
+	Day      string
+	Requests int64
+	Queries  int64
+	Errors   int64
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"net"
 	"strconv"
 	"testing"
 	"time"
@@ -72,3 +73,23 @@ func TestBuildTimeSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestIPAllowListAllows(t *testing.T) {
+	allowList := IPAllowList{"192.168.1.10", "10.0.0.0/8", "2001:db8::/32"}
+
+	assert.True(t, allowList.Allows(net.ParseIP("192.168.1.10")))
+	assert.True(t, allowList.Allows(net.ParseIP("10.20.30.40")))
+	assert.True(t, allowList.Allows(net.ParseIP("2001:db8::1")))
+	assert.False(t, allowList.Allows(net.ParseIP("192.168.1.11")))
+	assert.False(t, allowList.Allows(net.ParseIP("2001:db9::1")))
+}
+
+func TestPublicDashboardIsExpired(t *testing.T) {
+	now := time.Now()
+	before, after := now.Add(-time.Minute), now.Add(time.Minute)
+
+	assert.False(t, PublicDashboard{}.IsExpired(now))
+	assert.True(t, PublicDashboard{ExpiresAt: &before}.IsExpired(now))
+	assert.True(t, PublicDashboard{ExpiresAt: &now}.IsExpired(now))
+	assert.False(t, PublicDashboard{ExpiresAt: &after}.IsExpired(now))
+}
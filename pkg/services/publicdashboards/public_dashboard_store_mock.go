@@ -217,6 +217,29 @@ func (_m *FakePublicDashboardStore) FindDashboard(ctx context.Context, orgId int
 	return r0, r1
 }
 
+// FindQueryUsage provides a mock function with given fields: ctx, query
+func (_m *FakePublicDashboardStore) FindQueryUsage(ctx context.Context, query models.QueryUsageQuery) ([]models.QueryUsage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []models.QueryUsage
+	if rf, ok := ret.Get(0).(func(context.Context, models.QueryUsageQuery) []models.QueryUsage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.QueryUsage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.QueryUsageQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrgIdByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -238,6 +261,20 @@ func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, a
 	return r0, r1
 }
 
+// RecordQueryUsage provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) RecordQueryUsage(ctx context.Context, cmd models.RecordQueryUsageCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.RecordQueryUsageCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SavePublicDashboardCommand) (int64, error) {
 	ret := _m.Called(ctx, cmd)
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -10,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/templating"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
@@ -134,8 +137,25 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 		return nil, models.ErrPanelQueriesNotFound.Errorf("GetQueryDataResponse: failed to extract queries from panel")
 	}
 
+	// all viewers of a public dashboard run the same queries, so responses are shared between them for a short time.
+	// Viewers are anonymous, so they cannot skip this cache.
+	cacheKey, err := queryCacheKey(dashboard, publicDashboard, panelId, queryDto)
+	if err != nil {
+		return nil, models.ErrInternalServerError.Errorf("GetQueryDataResponse: failed to build cache key: %w", err)
+	}
+	if pd.queryCache != nil {
+		if cached, ok := pd.queryCache.Get(cacheKey); ok {
+			return cached.(*backend.QueryDataResponse), nil
+		}
+	}
+
+	if !pd.queryRateLimiter.Allow(accessToken) {
+		return nil, models.ErrQueryRateLimitExceeded.Errorf("GetQueryDataResponse: query rate limit exceeded for public dashboard %s", publicDashboard.Uid)
+	}
+
 	anonymousUser := buildAnonymousUser(ctx, dashboard)
 	res, err := pd.QueryDataService.QueryData(ctx, anonymousUser, skipCache, metricReq)
+	pd.recordQueryUsage(publicDashboard, int64(len(metricReq.Queries)), err != nil)
 
 	reqDatasources := metricReq.GetUniqueDatasourceTypes()
 	if err != nil {
@@ -146,9 +166,47 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 
 	sanitizeMetadataFromQueryData(res)
 
+	if pd.queryCache != nil {
+		pd.queryCache.SetDefault(cacheKey, res)
+	}
+
 	return res, nil
 }
 
+// queryCacheKey identifies the query responses of a panel which can be shared between viewers. The time range is
+// used as requested, so that relative time ranges like now-6h map to the same key.
+func queryCacheKey(dashboard *dashmodels.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (string, error) {
+	from := dashboard.Data.GetPath("time", "from").MustString()
+	to := dashboard.Data.GetPath("time", "to").MustString()
+	if publicDashboard.TimeSelectionEnabled {
+		from = reqDTO.TimeRange.From
+		to = reqDTO.TimeRange.To
+	}
+
+	key, err := json.Marshal(struct {
+		AccessToken   string
+		PanelId       int64
+		From          string
+		To            string
+		IntervalMs    int64
+		MaxDataPoints int64
+		Variables     map[string][]string
+	}{
+		AccessToken:   publicDashboard.AccessToken,
+		PanelId:       panelId,
+		From:          from,
+		To:            to,
+		IntervalMs:    reqDTO.IntervalMs,
+		MaxDataPoints: reqDTO.MaxDataPoints,
+		Variables:     reqDTO.Variables,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(key), nil
+}
+
 // buildMetricRequest merges public dashboard parameters with dashboard and returns a metrics request to be sent to query backend
 func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, dashboard *dashmodels.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	// group queries by panel
@@ -159,10 +217,12 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, da
 	}
 
 	ts := publicDashboard.BuildTimeSettings(dashboard, reqDTO)
+	variables := buildTemplateVariables(dashboard, publicDashboard, reqDTO)
 
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
 	for i := range queries {
+		queries[i] = simplejson.NewFromAny(variables.InterpolateValue(queries[i].Interface()))
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 	}
@@ -174,6 +234,41 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, da
 	}, nil
 }
 
+// buildTemplateVariables returns the values of the template variables of the dashboard. Viewers may select from the
+// allowed values of a variable, the value saved with the dashboard is used for the others. When the saved value of a
+// variable is not allowed, its first allowed value is used instead.
+func buildTemplateVariables(dashboard *dashmodels.Dashboard, publicDashboard *models.PublicDashboard, reqDTO models.PublicDashboardQueryDTO) templating.Variables {
+	variables := templating.FromDashboard(dashboard.Data)
+
+	for name := range publicDashboard.TemplateVariables {
+		values := reqDTO.Variables[name]
+		if len(values) == 0 {
+			values = allowedDefaultValues(variables[name], publicDashboard.TemplateVariables, name)
+		}
+		variables[name] = templating.Variable{Values: values}
+	}
+
+	return variables
+}
+
+func allowedDefaultValues(variable templating.Variable, templateVariables models.TemplateVariables, name string) []string {
+	if variable.Raw || len(variable.Values) == 0 {
+		return templateVariables[name][:1]
+	}
+
+	for _, value := range variable.Values {
+		if !templateVariables.IsAllowed(name, value) {
+			return templateVariables[name][:1]
+		}
+	}
+	return variable.Values
+}
+
+// recordQueryUsage counts a query request in the usage of the public dashboard, it is added to the database by Run
+func (pd *PublicDashboardServiceImpl) recordQueryUsage(publicDashboard *models.PublicDashboard, queries int64, failed bool) {
+	pd.queryUsage.Add(publicDashboard.OrgId, publicDashboard.Uid, time.Now(), queries, failed)
+}
+
 // buildAnonymousUser creates a user with permissions to read from all datasources used in the dashboard
 func buildAnonymousUser(ctx context.Context, dashboard *dashmodels.Dashboard) *user.SignedInUser {
 	datasourceUids := getUniqueDashboardDatasourceUids(dashboard.Data)
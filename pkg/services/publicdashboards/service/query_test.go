@@ -977,7 +977,15 @@ func TestBuildMetricRequest(t *testing.T) {
 
 		require.Equal(
 			t,
-			simplejson.NewFromAny(nonHiddenQuery),
+			simplejson.NewFromAny(map[string]interface{}{
+				"datasource": map[string]interface{}{
+					"type": "prometheus",
+					"uid":  "ds2",
+				},
+				"intervalMs":    int64(10000000),
+				"maxDataPoints": int64(200),
+				"refId":         "B",
+			}),
 			reqDTO.Queries[0],
 		)
 	})
@@ -1024,6 +1032,65 @@ func TestBuildMetricRequest(t *testing.T) {
 	})
 }
 
+func TestBuildMetricRequestWithTemplateVariables(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	templateVars := []map[string]interface{}{
+		{"name": "job", "type": "query", "multi": true, "current": map[string]interface{}{"value": []interface{}{"api", "web"}}},
+		{"name": "env", "type": "custom", "current": map[string]interface{}{"value": "staging"}},
+		{"name": "cluster", "type": "constant", "current": map[string]interface{}{"value": "eu"}},
+	}
+	customPanels := []interface{}{
+		map[string]interface{}{
+			"id": 1,
+			"datasource": map[string]interface{}{
+				"uid": "ds1",
+			},
+			"targets": []interface{}{
+				map[string]interface{}{
+					"refId": "A",
+					"expr":  `up{job=~"${job:regex}", env="$env", cluster="$cluster"}`,
+				},
+			},
+		}}
+	dashboard := insertTestDashboard(t, dashboardStore, "testDashWithVariables", 1, 0, true, templateVars, customPanels)
+	// decode the dashboard like it is loaded from the database
+	dashboardJSON, err := dashboard.Data.Encode()
+	require.NoError(t, err)
+	dashboard.Data, err = simplejson.NewJson(dashboardJSON)
+	require.NoError(t, err)
+
+	service := &PublicDashboardServiceImpl{
+		log:                log.New("test.logger"),
+		intervalCalculator: intervalv2.NewCalculator(),
+	}
+
+	publicDashboard := &PublicDashboard{
+		IsEnabled:         true,
+		TemplateVariables: TemplateVariables{"job": {"api", "web", "db"}, "env": {"prod", "dev"}},
+	}
+
+	t.Run("uses the saved values when they are allowed and the first allowed value otherwise", func(t *testing.T) {
+		reqDTO, err := service.buildMetricRequest(context.Background(), dashboard, publicDashboard, 1, PublicDashboardQueryDTO{})
+		require.NoError(t, err)
+
+		require.Len(t, reqDTO.Queries, 1)
+		assert.Equal(t, `up{job=~"(api|web)", env="prod", cluster="eu"}`, reqDTO.Queries[0].Get("expr").MustString())
+	})
+
+	t.Run("uses the values selected by the viewer", func(t *testing.T) {
+		reqDTO, err := service.buildMetricRequest(context.Background(), dashboard, publicDashboard, 1, PublicDashboardQueryDTO{
+			Variables: map[string][]string{"job": {"db"}, "env": {"dev"}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, reqDTO.Queries, 1)
+		assert.Equal(t, `up{job=~"db", env="dev", cluster="eu"}`, reqDTO.Queries[0].Get("expr").MustString())
+	})
+}
+
 func TestBuildAnonymousUser(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"math"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// queryRateLimiter limits the query requests of each public dashboard access token, so that a widely shared public
+// dashboard cannot overload its datasources. A nil limiter allows all requests. Requests are counted in memory, so
+// every Grafana instance behind a load balancer allows the configured rate on its own.
+type queryRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newQueryRateLimiter(limit float64, burst int) *queryRateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = int(math.Ceil(limit))
+	}
+
+	return &queryRateLimiter{
+		limit:    rate.Limit(limit),
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow reports whether a query request for the access token may run now
+func (l *queryRateLimiter) Allow(accessToken string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	limiter, ok := l.limiters[accessToken]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[accessToken] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Allow()
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRateLimiter(t *testing.T) {
+	t.Run("allows all requests when the rate limit is disabled", func(t *testing.T) {
+		limiter := newQueryRateLimiter(0, 10)
+		for i := 0; i < 100; i++ {
+			assert.True(t, limiter.Allow("abc"))
+		}
+	})
+
+	t.Run("limits each access token separately", func(t *testing.T) {
+		limiter := newQueryRateLimiter(0.001, 2)
+
+		assert.True(t, limiter.Allow("abc"))
+		assert.True(t, limiter.Allow("abc"))
+		assert.False(t, limiter.Allow("abc"))
+
+		assert.True(t, limiter.Allow("def"))
+	})
+
+	t.Run("allows a burst of one second of queries by default", func(t *testing.T) {
+		limiter := newQueryRateLimiter(2.5, 0)
+
+		for i := 0; i < 3; i++ {
+			assert.True(t, limiter.Allow("abc"))
+		}
+		assert.False(t, limiter.Allow("abc"))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -33,6 +34,9 @@ type PublicDashboardServiceImpl struct {
 	QueryDataService   *query.Service
 	AnnotationsRepo    annotations.Repository
 	ac                 accesscontrol.AccessControl
+	queryRateLimiter   *queryRateLimiter
+	queryCache         *localcache.CacheService
+	queryUsage         *queryUsageCounter
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -50,7 +54,7 @@ func ProvideService(
 	anno annotations.Repository,
 	ac accesscontrol.AccessControl,
 ) *PublicDashboardServiceImpl {
-	return &PublicDashboardServiceImpl{
+	s := &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
 		cfg:                cfg,
 		store:              store,
@@ -58,6 +62,41 @@ func ProvideService(
 		QueryDataService:   qds,
 		AnnotationsRepo:    anno,
 		ac:                 ac,
+		queryRateLimiter:   newQueryRateLimiter(cfg.PublicDashboards.QueryRateLimit, cfg.PublicDashboards.QueryRateLimitBurst),
+		queryUsage:         newQueryUsageCounter(),
+	}
+
+	if ttl := cfg.PublicDashboards.QueryCacheTTL; ttl > 0 {
+		s.queryCache = localcache.New(ttl, 2*ttl)
+	}
+
+	return s
+}
+
+// Run periodically adds the query usage counted in memory to the database, until the context is canceled
+func (pd *PublicDashboardServiceImpl) Run(ctx context.Context) error {
+	ticker := time.NewTicker(queryUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// the context is canceled on shutdown, the remaining usage is still added
+			pd.flushQueryUsage(context.Background())
+			return ctx.Err()
+		case <-ticker.C:
+			pd.flushQueryUsage(ctx)
+		}
+	}
+}
+
+// flushQueryUsage adds the query usage counted in memory to the database. Failures are logged because the usage is
+// only informational.
+func (pd *PublicDashboardServiceImpl) flushQueryUsage(ctx context.Context) {
+	for _, cmd := range pd.queryUsage.Take() {
+		if err := pd.store.RecordQueryUsage(ctx, cmd); err != nil {
+			pd.log.Error("Failed to record query usage", "publicDashboardUid", cmd.PublicDashboardUid, "day", cmd.Day, "error", err)
+		}
 	}
 }
 
@@ -75,19 +114,33 @@ func (pd *PublicDashboardServiceImpl) FindDashboard(ctx context.Context, orgId i
 	return dash, nil
 }
 
-// FindPublicDashboardAndDashboardByAccessToken Gets public dashboard and a dashboard by access token
-func (pd *PublicDashboardServiceImpl) FindPublicDashboardAndDashboardByAccessToken(ctx context.Context, accessToken string) (*PublicDashboard, *models.Dashboard, error) {
+// FindByAccessToken Gets an enabled public dashboard by access token, which has not expired
+func (pd *PublicDashboardServiceImpl) FindByAccessToken(ctx context.Context, accessToken string) (*PublicDashboard, error) {
 	pubdash, err := pd.store.FindByAccessToken(ctx, accessToken)
 	if err != nil {
-		return nil, nil, ErrInternalServerError.Errorf("FindPublicDashboardAndDashboardByAccessToken: failed to find a public dashboard: %w", err)
+		return nil, ErrInternalServerError.Errorf("FindByAccessToken: failed to find a public dashboard: %w", err)
 	}
 
 	if pubdash == nil {
-		return nil, nil, ErrPublicDashboardNotFound.Errorf("FindPublicDashboardAndDashboardByAccessToken: Public dashboard not found accessToken: %s", accessToken)
+		return nil, ErrPublicDashboardNotFound.Errorf("FindByAccessToken: Public dashboard not found accessToken: %s", accessToken)
 	}
 
 	if !pubdash.IsEnabled {
-		return nil, nil, ErrPublicDashboardNotFound.Errorf("FindPublicDashboardAndDashboardByAccessToken: Public dashboard is disabled accessToken: %s", accessToken)
+		return nil, ErrPublicDashboardNotFound.Errorf("FindByAccessToken: Public dashboard is disabled accessToken: %s", accessToken)
+	}
+
+	if pubdash.IsExpired(time.Now()) {
+		return nil, ErrPublicDashboardExpired.Errorf("FindByAccessToken: Public dashboard expired at %s accessToken: %s", pubdash.ExpiresAt, accessToken)
+	}
+
+	return pubdash, nil
+}
+
+// FindPublicDashboardAndDashboardByAccessToken Gets public dashboard and a dashboard by access token
+func (pd *PublicDashboardServiceImpl) FindPublicDashboardAndDashboardByAccessToken(ctx context.Context, accessToken string) (*PublicDashboard, *models.Dashboard, error) {
+	pubdash, err := pd.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	dash, err := pd.store.FindDashboard(ctx, pubdash.OrgId, pubdash.DashboardUid)
@@ -162,6 +215,9 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 			IsEnabled:          dto.PublicDashboard.IsEnabled,
 			AnnotationsEnabled: dto.PublicDashboard.AnnotationsEnabled,
 			TimeSettings:       dto.PublicDashboard.TimeSettings,
+			TemplateVariables:  dto.PublicDashboard.TemplateVariables,
+			ExpiresAt:          dto.PublicDashboard.ExpiresAt,
+			IPAllowList:        dto.PublicDashboard.IPAllowList,
 			CreatedBy:          dto.UserId,
 			CreatedAt:          time.Now(),
 			AccessToken:        accessToken,
@@ -225,6 +281,9 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 			AnnotationsEnabled:   dto.PublicDashboard.AnnotationsEnabled,
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
+			TemplateVariables:    dto.PublicDashboard.TemplateVariables,
+			ExpiresAt:            dto.PublicDashboard.ExpiresAt,
+			IPAllowList:          dto.PublicDashboard.IPAllowList,
 			UpdatedBy:            dto.UserId,
 			UpdatedAt:            time.Now(),
 		},
@@ -319,6 +378,31 @@ func (pd *PublicDashboardServiceImpl) Delete(ctx context.Context, orgId int64, u
 	return nil
 }
 
+// FindQueryUsage Returns the query volume generated by the access token of a public dashboard by day
+func (pd *PublicDashboardServiceImpl) FindQueryUsage(ctx context.Context, query QueryUsageQuery) ([]QueryUsage, error) {
+	for _, day := range []string{query.From, query.To} {
+		if _, err := time.Parse(QueryUsageDayFormat, day); day != "" && err != nil {
+			return nil, ErrInvalidDay.Errorf("FindQueryUsage: invalid day %s: %w", day, err)
+		}
+	}
+
+	pubdash, err := pd.store.Find(ctx, query.PublicDashboardUid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindQueryUsage: failed to find public dashboard by uid: %s: %w", query.PublicDashboardUid, err)
+	}
+
+	if pubdash == nil || pubdash.OrgId != query.OrgId {
+		return nil, ErrPublicDashboardNotFound.Errorf("FindQueryUsage: Public dashboard not found by orgId: %d and Uid: %s", query.OrgId, query.PublicDashboardUid)
+	}
+
+	usage, err := pd.store.FindQueryUsage(ctx, query)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindQueryUsage: %w", err)
+	}
+
+	return usage, nil
+}
+
 // intervalMS and maxQueryData values are being calculated on the frontend for regular dashboards
 // we are doing the same for public dashboards but because this access would be public, we need a way to keep this
 // values inside reasonable bounds to avoid an attack that could hit data sources with a small interval and a big
@@ -42,6 +42,8 @@ func TestLogPrefix(t *testing.T) {
 }
 
 func TestGetPublicDashboard(t *testing.T) {
+	expiredAt := time.Now().Add(-time.Minute)
+
 	type storeResp struct {
 		pd  *PublicDashboard
 		d   *models.Dashboard
@@ -77,6 +79,17 @@ func TestGetPublicDashboard(t *testing.T) {
 			ErrResp:  ErrPublicDashboardNotFound,
 			DashResp: nil,
 		},
+		{
+			Name:        "returns ErrPublicDashboardExpired when the access token expired",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, ExpiresAt: &expiredAt},
+				d:   &models.Dashboard{Uid: "mydashboard"},
+				err: nil,
+			},
+			ErrResp:  ErrPublicDashboardExpired,
+			DashResp: nil,
+		},
 		{
 			Name:        "returns ErrPublicDashboardNotFound if PublicDashboard missing",
 			AccessToken: "abc123",
@@ -204,13 +217,51 @@ func TestCreatePublicDashboard(t *testing.T) {
 		assert.Equal(t, defaultPubdashTimeSettings, pubdash.TimeSettings)
 	})
 
-	t.Run("Validate pubdash whose dashboard has template variables returns error", func(t *testing.T) {
+	t.Run("Create public dashboard with template variables, expiry and IP allow list", func(t *testing.T) {
 		sqlStore := db.InitTestDB(t)
 		quotaService := quotatest.New(false, nil)
 		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotaService)
 		require.NoError(t, err)
 		publicdashboardStore := database.ProvideStore(sqlStore)
-		templateVars := make([]map[string]interface{}, 1)
+		templateVars := []map[string]interface{}{{"name": "job", "type": "query"}}
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, templateVars, nil)
+
+		service := &PublicDashboardServiceImpl{
+			log:   log.New("test.logger"),
+			store: publicdashboardStore,
+		}
+
+		expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		dto := &SavePublicDashboardDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:         true,
+				TemplateVariables: TemplateVariables{"job": {"api", "web"}},
+				ExpiresAt:         &expiresAt,
+				IPAllowList:       IPAllowList{"10.0.0.0/8", "192.168.1.1"},
+			},
+		}
+
+		_, err = service.Create(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		pubdash, err := service.FindByDashboardUid(context.Background(), dashboard.OrgId, dashboard.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, dto.PublicDashboard.TemplateVariables, pubdash.TemplateVariables)
+		require.NotNil(t, pubdash.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*pubdash.ExpiresAt))
+		assert.Equal(t, dto.PublicDashboard.IPAllowList, pubdash.IPAllowList)
+	})
+
+	t.Run("Validate pubdash whose dashboard has unsupported template variables returns error", func(t *testing.T) {
+		sqlStore := db.InitTestDB(t)
+		quotaService := quotatest.New(false, nil)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotaService)
+		require.NoError(t, err)
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		templateVars := []map[string]interface{}{{"name": "filters", "type": "adhoc"}}
 		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, templateVars, nil)
 
 		service := &PublicDashboardServiceImpl{
@@ -230,7 +281,7 @@ func TestCreatePublicDashboard(t *testing.T) {
 		}
 
 		_, err = service.Create(context.Background(), SignedInUser, dto)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrPublicDashboardHasTemplateVariables)
 	})
 
 	t.Run("Throws an error when pubdash with generated access token already exists", func(t *testing.T) {
@@ -436,6 +487,59 @@ func TestUpdatePublicDashboard(t *testing.T) {
 	})
 }
 
+func TestFindQueryUsage(t *testing.T) {
+	usage := []QueryUsage{{PublicDashboardUid: "pubdash", Day: "2022-10-02", RequestCount: 3, QueryCount: 6}}
+
+	testCases := []struct {
+		Name      string
+		Query     QueryUsageQuery
+		Pubdash   *PublicDashboard
+		ErrResp   error
+		UsageResp []QueryUsage
+	}{
+		{
+			Name:      "returns the usage of the public dashboard",
+			Query:     QueryUsageQuery{OrgId: 1, PublicDashboardUid: "pubdash", From: "2022-10-01", To: "2022-10-31"},
+			Pubdash:   &PublicDashboard{Uid: "pubdash", OrgId: 1},
+			UsageResp: usage,
+		},
+		{
+			Name:    "returns ErrPublicDashboardNotFound for public dashboards of other orgs",
+			Query:   QueryUsageQuery{OrgId: 2, PublicDashboardUid: "pubdash"},
+			Pubdash: &PublicDashboard{Uid: "pubdash", OrgId: 1},
+			ErrResp: ErrPublicDashboardNotFound,
+		},
+		{
+			Name:    "returns ErrInvalidDay for invalid days",
+			Query:   QueryUsageQuery{OrgId: 1, PublicDashboardUid: "pubdash", From: "yesterday"},
+			Pubdash: &PublicDashboard{Uid: "pubdash", OrgId: 1},
+			ErrResp: ErrInvalidDay,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			fakeStore := FakePublicDashboardStore{}
+			service := &PublicDashboardServiceImpl{
+				log:   log.New("test.logger"),
+				store: &fakeStore,
+			}
+
+			fakeStore.On("Find", mock.Anything, test.Query.PublicDashboardUid).Return(test.Pubdash, nil).Maybe()
+			fakeStore.On("FindQueryUsage", mock.Anything, test.Query).Return(usage, nil).Maybe()
+
+			res, err := service.FindQueryUsage(context.Background(), test.Query)
+			if test.ErrResp != nil {
+				require.ErrorIs(t, err, test.ErrResp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.UsageResp, res)
+		})
+	}
+}
+
 func TestDeletePublicDashboard(t *testing.T) {
 	testCases := []struct {
 		Name             string
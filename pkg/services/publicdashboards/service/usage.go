@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// queryUsageFlushInterval is how often the query usage counted in memory is added to the database
+const queryUsageFlushInterval = 30 * time.Second
+
+type queryUsageKey struct {
+	orgId              int64
+	publicDashboardUid string
+	day                string
+}
+
+// queryUsageCounter counts the query requests of public dashboards in memory, so that busy public dashboards do not
+// update their usage row in the database on every request. A nil counter does not count requests.
+type queryUsageCounter struct {
+	mu    sync.Mutex
+	usage map[queryUsageKey]*models.RecordQueryUsageCommand
+}
+
+func newQueryUsageCounter() *queryUsageCounter {
+	return &queryUsageCounter{usage: make(map[queryUsageKey]*models.RecordQueryUsageCommand)}
+}
+
+// Add counts a query request made at the given time
+func (c *queryUsageCounter) Add(orgId int64, publicDashboardUid string, now time.Time, queries int64, failed bool) {
+	if c == nil {
+		return
+	}
+
+	key := queryUsageKey{orgId: orgId, publicDashboardUid: publicDashboardUid, day: now.UTC().Format(models.QueryUsageDayFormat)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cmd, ok := c.usage[key]
+	if !ok {
+		cmd = &models.RecordQueryUsageCommand{OrgId: orgId, PublicDashboardUid: publicDashboardUid, Day: key.day}
+		c.usage[key] = cmd
+	}
+	cmd.Requests++
+	cmd.Queries += queries
+	if failed {
+		cmd.Errors++
+	}
+}
+
+// Take returns the usage counted since the last call and resets the counter
+func (c *queryUsageCounter) Take() []models.RecordQueryUsageCommand {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	usage := c.usage
+	c.usage = make(map[queryUsageKey]*models.RecordQueryUsageCommand)
+	c.mu.Unlock()
+
+	cmds := make([]models.RecordQueryUsageCommand, 0, len(usage))
+	for _, cmd := range usage {
+		cmds = append(cmds, *cmd)
+	}
+	return cmds
+}
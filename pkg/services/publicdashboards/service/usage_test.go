@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func TestQueryUsageCounter(t *testing.T) {
+	t.Run("does not count requests when nil", func(t *testing.T) {
+		var counter *queryUsageCounter
+		counter.Add(1, "abc", time.Now(), 2, false)
+		assert.Empty(t, counter.Take())
+	})
+
+	t.Run("counts the requests of each public dashboard by day", func(t *testing.T) {
+		counter := newQueryUsageCounter()
+		counter.Add(1, "abc", time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC), 2, false)
+		counter.Add(1, "abc", time.Date(2022, 10, 1, 23, 0, 0, 0, time.UTC), 3, true)
+		counter.Add(1, "abc", time.Date(2022, 10, 2, 1, 0, 0, 0, time.UTC), 1, false)
+		counter.Add(1, "def", time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC), 1, false)
+
+		assert.ElementsMatch(t, []models.RecordQueryUsageCommand{
+			{OrgId: 1, PublicDashboardUid: "abc", Day: "2022-10-01", Requests: 2, Queries: 5, Errors: 1},
+			{OrgId: 1, PublicDashboardUid: "abc", Day: "2022-10-02", Requests: 1, Queries: 1},
+			{OrgId: 1, PublicDashboardUid: "def", Day: "2022-10-01", Requests: 1, Queries: 1},
+		}, counter.Take())
+		assert.Empty(t, counter.Take())
+	})
+}
+
+func TestRunAddsQueryUsageOnShutdown(t *testing.T) {
+	fakeStore := &publicdashboards.FakePublicDashboardStore{}
+	fakeStore.On("RecordQueryUsage", mock.Anything, mock.Anything).Return(nil)
+	service := &PublicDashboardServiceImpl{
+		log:        log.New("test.logger"),
+		store:      fakeStore,
+		queryUsage: newQueryUsageCounter(),
+	}
+
+	pubdash := &models.PublicDashboard{OrgId: 1, Uid: "abc"}
+	service.recordQueryUsage(pubdash, 2, false)
+	service.recordQueryUsage(pubdash, 3, true)
+	fakeStore.AssertNotCalled(t, "RecordQueryUsage", mock.Anything, mock.Anything)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, service.Run(ctx), context.Canceled)
+
+	fakeStore.AssertNumberOfCalls(t, "RecordQueryUsage", 1)
+	fakeStore.AssertCalled(t, "RecordQueryUsage", mock.Anything, models.RecordQueryUsageCommand{
+		OrgId:              1,
+		PublicDashboardUid: "abc",
+		Day:                time.Now().UTC().Format(models.QueryUsageDayFormat),
+		Requests:           2,
+		Queries:            5,
+		Errors:             1,
+	})
+}
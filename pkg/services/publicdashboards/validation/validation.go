@@ -1,23 +1,69 @@
 package validation
 
 import (
+	"net"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 )
 
+// unsupportedVariableTypes are the types of template variables which cannot be interpolated on the server
+var unsupportedVariableTypes = map[string]bool{"datasource": true, "adhoc": true}
+
 func ValidatePublicDashboard(dto *SavePublicDashboardDTO, dashboard *models.Dashboard) error {
-	if hasTemplateVariables(dashboard) {
-		return ErrPublicDashboardHasTemplateVariables.Errorf("ValidateSavePublicDashboard: public dashboard has template variables")
+	var templateVariables TemplateVariables
+	var ipAllowList IPAllowList
+	if dto.PublicDashboard != nil {
+		templateVariables = dto.PublicDashboard.TemplateVariables
+		ipAllowList = dto.PublicDashboard.IPAllowList
+	}
+
+	if err := validateTemplateVariables(templateVariables, dashboard); err != nil {
+		return err
+	}
+
+	return validateIPAllowList(ipAllowList)
+}
+
+// validateTemplateVariables checks that the dashboard only has template variables which can be interpolated on the
+// server and that values are only allowed for variables of the dashboard. Variables without allowed values keep the
+// value saved with the dashboard.
+func validateTemplateVariables(templateVariables TemplateVariables, dashboard *models.Dashboard) error {
+	dashboardVariables := make(map[string]bool)
+	for _, variableObj := range dashboard.Data.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name, variableType := variable.Get("name").MustString(), variable.Get("type").MustString()
+		if unsupportedVariableTypes[variableType] {
+			return ErrPublicDashboardHasTemplateVariables.Errorf("ValidateSavePublicDashboard: template variable %s has unsupported type %s", name, variableType)
+		}
+		dashboardVariables[name] = variableType != "constant"
+	}
+
+	for name, values := range templateVariables {
+		if !dashboardVariables[name] {
+			return ErrInvalidTemplateVariables.Errorf("ValidateSavePublicDashboard: dashboard has no template variable %s that can be selected", name)
+		}
+		if len(values) == 0 {
+			return ErrInvalidTemplateVariables.Errorf("ValidateSavePublicDashboard: no values allowed for template variable %s", name)
+		}
 	}
 
 	return nil
 }
 
-func hasTemplateVariables(dashboard *models.Dashboard) bool {
-	templateVariables := dashboard.Data.Get("templating").Get("list").MustArray()
+func validateIPAllowList(ipAllowList IPAllowList) error {
+	for _, entry := range ipAllowList {
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil {
+			return ErrInvalidIPAllowList.Errorf("ValidateSavePublicDashboard: %s is neither an IP address nor a CIDR range", entry)
+		}
+	}
 
-	return len(templateVariables) > 0
+	return nil
 }
 
 func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
@@ -29,6 +75,17 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 		return ErrInvalidMaxDataPoints.Errorf("ValidateQueryPublicDashboardRequest: maxDataPoints should be greater than 0")
 	}
 
+	for name, values := range req.Variables {
+		if _, ok := pd.TemplateVariables[name]; !ok {
+			return ErrTemplateVariableValueNotAllowed.Errorf("ValidateQueryPublicDashboardRequest: template variable %s cannot be selected", name)
+		}
+		for _, value := range values {
+			if !pd.TemplateVariables.IsAllowed(name, value) {
+				return ErrTemplateVariableValueNotAllowed.Errorf("ValidateQueryPublicDashboardRequest: value %s is not allowed for template variable %s", value, name)
+			}
+		}
+	}
+
 	if pd.TimeSelectionEnabled {
 		timeRange := legacydata.NewDataTimeRange(req.TimeRange.From, req.TimeRange.To)
 
@@ -10,12 +10,13 @@ import (
 )
 
 func TestValidatePublicDashboard(t *testing.T) {
-	t.Run("Returns validation error when dashboard has template variables", func(t *testing.T) {
+	t.Run("Returns validation error when dashboard has unsupported template variables", func(t *testing.T) {
 		templateVars := []byte(`{
 			"templating": {
 				 "list": [
 				   {
-					  "name": "templateVariableName"
+					  "name": "templateVariableName",
+					  "type": "datasource"
 				   }
 				]
 			}
@@ -28,6 +29,57 @@ func TestValidatePublicDashboard(t *testing.T) {
 		require.ErrorContains(t, err, ErrPublicDashboardHasTemplateVariables.Error())
 	})
 
+	t.Run("Returns no validation error when template variables have allowed values", func(t *testing.T) {
+		templateVars := []byte(`{
+			"templating": {
+				 "list": [
+				   {"name": "job", "type": "query"},
+				   {"name": "env", "type": "custom"}
+				]
+			}
+		}`)
+		dashboardData, _ := simplejson.NewJson(templateVars)
+		dashboard := models.NewDashboardFromJson(dashboardData)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{
+			TemplateVariables: TemplateVariables{"job": {"api", "web"}},
+		}}
+
+		err := ValidatePublicDashboard(dto, dashboard)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns validation error when values are allowed for unknown or constant template variables", func(t *testing.T) {
+		templateVars := []byte(`{
+			"templating": {
+				 "list": [
+				   {"name": "job", "type": "query"},
+				   {"name": "cluster", "type": "constant"}
+				]
+			}
+		}`)
+		dashboardData, _ := simplejson.NewJson(templateVars)
+		dashboard := models.NewDashboardFromJson(dashboardData)
+
+		for _, templateVariables := range []TemplateVariables{{"unknown": {"a"}}, {"cluster": {"a"}}, {"job": {}}} {
+			dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{
+				TemplateVariables: templateVariables,
+			}}
+
+			err := ValidatePublicDashboard(dto, dashboard)
+			require.ErrorIs(t, err, ErrInvalidTemplateVariables)
+		}
+	})
+
+	t.Run("Validates the IP allow list", func(t *testing.T) {
+		dashboard := models.NewDashboardFromJson(simplejson.New())
+
+		dto := &SavePublicDashboardDTO{PublicDashboard: &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.1", "192.168.0.0/16", "2001:db8::/32"}}}
+		require.NoError(t, ValidatePublicDashboard(dto, dashboard))
+
+		dto = &SavePublicDashboardDTO{PublicDashboard: &PublicDashboard{IPAllowList: IPAllowList{"10.0.0.1", "example.com"}}}
+		require.ErrorIs(t, ValidatePublicDashboard(dto, dashboard), ErrInvalidIPAllowList)
+	})
+
 	t.Run("Returns no validation error when dashboard has no template variables", func(t *testing.T) {
 		templateVars := []byte(`{
 			"templating": {
@@ -148,6 +200,42 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns no error when template variable values are allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"job": {"api", "web"}},
+				},
+				pd: &PublicDashboard{
+					TemplateVariables: TemplateVariables{"job": {"api", "web", "db"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when template variable value is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"job": {"api", "admin"}},
+				},
+				pd: &PublicDashboard{
+					TemplateVariables: TemplateVariables{"job": {"api", "web"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns validation error when template variable cannot be selected",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {"prod"}},
+				},
+				pd: &PublicDashboard{
+					TemplateVariables: TemplateVariables{"job": {"api"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
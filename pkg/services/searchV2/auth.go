@@ -6,17 +6,21 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
-// ResourceFilter checks if a given a uid (resource identifier) check if we have the requested permission
-type ResourceFilter func(uid string) bool
+// ResourceFilter checks if we have the requested permission for the entity of the given kind and uid (resource identifier).
+// The location is the parent path of the entity, ie. the folder uid of alert rules and library panels
+type ResourceFilter func(kind entityKind, uid string, location string) bool
 
 // FutureAuthService eventually implemented by the security service
 type FutureAuthService interface {
-	GetDashboardReadFilter(user *user.SignedInUser) (ResourceFilter, error)
+	GetReadFilter(user *user.SignedInUser) (ResourceFilter, error)
 }
 
 var _ FutureAuthService = (*simpleSQLAuthService)(nil)
@@ -44,7 +48,7 @@ func (a *simpleSQLAuthService) getDashboardTableAuthFilter(user *user.SignedInUs
 	return permissions.NewAccessControlDashboardPermissionFilter(user, models.PERMISSION_VIEW, searchstore.TypeDashboard)
 }
 
-func (a *simpleSQLAuthService) GetDashboardReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
+func (a *simpleSQLAuthService) GetReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
 	filter := a.getDashboardTableAuthFilter(user)
 	rows := make([]*dashIdQueryResult, 0)
 
@@ -72,7 +76,73 @@ func (a *simpleSQLAuthService) GetDashboardReadFilter(user *user.SignedInUser) (
 		uids[rows[i].UID] = true
 	}
 
-	return func(uid string) bool {
-		return uids[uid]
-	}, err
+	canReadFolder := func(folderUID string) bool {
+		return folderUID == "general" || uids[folderUID]
+	}
+
+	correlationSources, err := a.getCorrelationSources(user.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(kind entityKind, uid string, location string) bool {
+		switch kind {
+		case entityKindAlertRule:
+			if !canReadFolder(location) {
+				return false
+			}
+			return a.ac.IsDisabled() || a.hasPermission(user, accesscontrol.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(location))
+		case entityKindLibraryPanel:
+			return canReadFolder(location)
+		case entityKindDatasource:
+			return a.canReadDatasource(user, uid)
+		case entityKindCorrelation:
+			sourceUID, ok := correlationSources[uid]
+			return ok && a.canReadDatasource(user, sourceUID)
+		case entityKindPlaylist:
+			// playlists are visible to every member of the organization
+			return true
+		default:
+			return uids[uid]
+		}
+	}, nil
+}
+
+func (a *simpleSQLAuthService) canReadDatasource(user *user.SignedInUser, uid string) bool {
+	if a.ac.IsDisabled() {
+		return user.HasRole(org.RoleAdmin)
+	}
+	return a.hasPermission(user, datasources.ActionRead, datasources.ScopeProvider.GetResourceScopeUID(uid))
+}
+
+func (a *simpleSQLAuthService) hasPermission(user *user.SignedInUser, action string, scope string) bool {
+	permissions, ok := user.Permissions[user.OrgID]
+	if !ok {
+		return false
+	}
+	return accesscontrol.EvalPermission(action, scope).Evaluate(permissions)
+}
+
+type correlationSourceQueryResult struct {
+	UID       string `xorm:"uid"`
+	SourceUID string `xorm:"source_uid"`
+}
+
+// getCorrelationSources returns the source datasource uid of every correlation in the organization
+func (a *simpleSQLAuthService) getCorrelationSources(orgID int64) (map[string]string, error) {
+	rows := make([]*correlationSourceQueryResult, 0)
+	err := a.sql.WithDbSession(context.Background(), func(sess *db.Session) error {
+		return sess.SQL("SELECT correlation.uid, correlation.source_uid FROM correlation "+
+			"INNER JOIN data_source ON correlation.source_uid = data_source.uid AND data_source.org_id = ?", orgID).
+			Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string, len(rows))
+	for _, row := range rows {
+		sources[row.UID] = row.SourceUID
+	}
+	return sources, nil
 }
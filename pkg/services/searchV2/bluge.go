@@ -30,16 +30,23 @@ const (
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
+	documentFieldLabel       = "label" // key=value
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
-	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
+func initOrgIndex(config bluge.Config, dashboards []dashboard, entities []indexedEntity, logger log.Logger, extendDoc ExtendDashboardFunc) (_ *orgIndex, err error) {
+	dashboardWriter, err := bluge.OpenWriter(config)
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
 	}
 	// Not closing Writer here since we use it later while processing dashboard change events.
+	// On failure it is closed to release the index directory lock of on-disk indexes.
+	defer func() {
+		if err != nil {
+			_ = dashboardWriter.Close()
+		}
+	}()
 
 	start := time.Now()
 	label := start
@@ -119,6 +126,14 @@ func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDas
 		}
 	}
 
+	// Then alert rules, datasources, library panels, playlists and correlations.
+	for _, e := range entities {
+		batch.Insert(getEntityDoc(e))
+		if err := flushIfRequired(false); err != nil {
+			return nil, err
+		}
+	}
+
 	// Flush docs in batch with force as we are in the end.
 	if err := flushIfRequired(true); err != nil {
 		return nil, err
@@ -426,6 +441,22 @@ func doSearchQuery(
 		hasConstraints = true
 	}
 
+	// Datasource type
+	if q.DatasourceType != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.DatasourceType).SetField(documentFieldDSType))
+		hasConstraints = true
+	}
+
+	// Labels (key=value)
+	if len(q.Labels) > 0 {
+		bq := bluge.NewBooleanQuery()
+		for _, v := range q.Labels {
+			bq.AddMust(bluge.NewTermQuery(v).SetField(documentFieldLabel))
+		}
+		fullQuery.AddMust(bq)
+		hasConstraints = true
+	}
+
 	// Folder
 	if q.Location != "" {
 		fullQuery.AddMust(bluge.NewTermQuery(q.Location).SetField(documentFieldLocation))
@@ -571,6 +602,11 @@ func doSearchQuery(
 			return response
 		}
 
+		// entity documents are identified by `kind/uid`
+		if entityKind(kind).isEntity() {
+			uid = strings.TrimPrefix(uid, kind+"/")
+		}
+
 		fKind.Append(kind)
 		fUID.Append(uid)
 		fPType.Append(ptype)
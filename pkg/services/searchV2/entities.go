@@ -0,0 +1,405 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/store"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
+)
+
+// Datasource UID used by server side expressions in alert rule queries
+const expressionDatasourceUID = "__expr__"
+
+// Library elements of kind 1 are panels
+const libraryElementKindPanel = 1
+
+type entityLoader interface {
+	// LoadEntities returns the non-dashboard entities of the given kind. If kind is empty – then
+	// implementation must return entities of all supported kinds to build an entire index for an
+	// organization. If uid is not empty – then only return the entity with specified UID or empty
+	// slice if not found (this is required to apply partial update).
+	LoadEntities(ctx context.Context, orgID int64, kind entityKind, uid string) ([]indexedEntity, error)
+}
+
+// indexedEntity is an alert rule, datasource, library panel, playlist or correlation
+type indexedEntity struct {
+	kind        entityKind
+	uid         string
+	name        string
+	description string
+	url         string
+	location    string            // folder uid
+	labels      map[string]string // indexed as `key=value`
+	dsUIDs      []string
+	dsTypes     []string
+	panelType   string
+	created     time.Time
+	updated     time.Time
+}
+
+// entityKindFromEntityType maps the entity events types to the indexed kinds
+func entityKindFromEntityType(t store.EntityType) (entityKind, bool) {
+	switch t {
+	case store.EntityTypeAlertRule:
+		return entityKindAlertRule, true
+	case store.EntityTypeDatasource:
+		return entityKindDatasource, true
+	case store.EntityTypeLibraryPanel:
+		return entityKindLibraryPanel, true
+	case store.EntityTypePlaylist:
+		return entityKindPlaylist, true
+	case store.EntityTypeCorrelation:
+		return entityKindCorrelation, true
+	}
+	return "", false
+}
+
+// Entities share the index with dashboards, so the kind is part of the document ID to avoid uid collisions
+func entityDocumentID(kind entityKind, uid string) string {
+	return string(kind) + "/" + uid
+}
+
+func getEntityDoc(e indexedEntity) *bluge.Document {
+	doc := newSearchDocument(entityDocumentID(e.kind, e.uid), e.name, e.description, e.url).
+		AddField(bluge.NewKeywordField(documentFieldKind, string(e.kind)).Aggregatable().StoreValue()).
+		AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, e.created).Sortable().StoreValue()).
+		AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, e.updated).Sortable().StoreValue())
+
+	if e.location != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldLocation, e.location).Aggregatable().StoreValue())
+	}
+	if e.panelType != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldPanelType, e.panelType).Aggregatable().StoreValue())
+	}
+
+	keys := make([]string, 0, len(e.labels))
+	for k := range e.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		doc.AddField(bluge.NewKeywordField(documentFieldLabel, k+"="+e.labels[k]).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+
+	for _, dsType := range e.dsTypes {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSType, dsType).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	for _, dsUID := range e.dsUIDs {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSUID, dsUID).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	return doc
+}
+
+type sqlEntityLoader struct {
+	sql    db.DB
+	logger log.Logger
+	tracer tracing.Tracer
+}
+
+func newSQLEntityLoader(sql db.DB, tracer tracing.Tracer) *sqlEntityLoader {
+	return &sqlEntityLoader{sql: sql, logger: log.New("sqlEntityLoader"), tracer: tracer}
+}
+
+func (l sqlEntityLoader) LoadEntities(ctx context.Context, orgID int64, kind entityKind, uid string) ([]indexedEntity, error) {
+	ctx, span := l.tracer.Start(ctx, "sqlEntityLoader LoadEntities")
+	span.SetAttributes("orgID", orgID, attribute.Key("orgID").Int64(orgID))
+	span.SetAttributes("kind", string(kind), attribute.Key("kind").String(string(kind)))
+	defer span.End()
+
+	lookup, err := kdash.LoadDatasourceLookup(ctx, orgID, l.sql)
+	if err != nil {
+		return nil, err
+	}
+
+	loaders := map[entityKind]func(context.Context, int64, string, kdash.DatasourceLookup) ([]indexedEntity, error){
+		entityKindAlertRule:    l.loadAlertRules,
+		entityKindDatasource:   l.loadDatasources,
+		entityKindLibraryPanel: l.loadLibraryPanels,
+		entityKindPlaylist:     l.loadPlaylists,
+		entityKindCorrelation:  l.loadCorrelations,
+	}
+
+	if kind != "" {
+		load, ok := loaders[kind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported entity kind: %s", kind)
+		}
+		return load(ctx, orgID, uid, lookup)
+	}
+
+	var entities []indexedEntity
+	for _, k := range []entityKind{entityKindAlertRule, entityKindDatasource, entityKindLibraryPanel, entityKindPlaylist, entityKindCorrelation} {
+		loaded, err := loaders[k](ctx, orgID, uid, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", k, err)
+		}
+		entities = append(entities, loaded...)
+	}
+	return entities, nil
+}
+
+type alertRuleQueryResult struct {
+	UID          string `xorm:"uid"`
+	Title        string `xorm:"title"`
+	NamespaceUID string `xorm:"namespace_uid"`
+	RuleGroup    string `xorm:"rule_group"`
+	Data         string `xorm:"data"`
+	Labels       string `xorm:"labels"`
+	Updated      time.Time
+}
+
+func (l sqlEntityLoader) loadAlertRules(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]indexedEntity, error) {
+	rows := make([]*alertRuleQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("alert_rule").Where("org_id = ?", orgID)
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		return sess.Cols("uid", "title", "namespace_uid", "rule_group", "data", "labels", "updated").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		e := indexedEntity{
+			kind:        entityKindAlertRule,
+			uid:         row.UID,
+			name:        row.Title,
+			description: row.RuleGroup,
+			url:         fmt.Sprintf("/alerting/grafana/%s/view", row.UID),
+			location:    row.NamespaceUID,
+			created:     row.Updated,
+			updated:     row.Updated,
+		}
+
+		if row.Labels != "" {
+			if err := json.Unmarshal([]byte(row.Labels), &e.labels); err != nil {
+				l.logger.Warn("Error reading alert rule labels", "error", err, "uid", row.UID)
+			}
+		}
+
+		var queries []struct {
+			DatasourceUID string `json:"datasourceUid"`
+		}
+		if err := json.Unmarshal([]byte(row.Data), &queries); err != nil {
+			l.logger.Warn("Error reading alert rule queries", "error", err, "uid", row.UID)
+		}
+		for _, q := range queries {
+			if q.DatasourceUID == "" || q.DatasourceUID == expressionDatasourceUID {
+				continue
+			}
+			e.addDatasource(lookup.ByRef(&kdash.DataSourceRef{UID: q.DatasourceUID}))
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+type datasourceQueryResult struct {
+	UID     string `xorm:"uid"`
+	Name    string `xorm:"name"`
+	Type    string `xorm:"type"`
+	Created time.Time
+	Updated time.Time
+}
+
+func (l sqlEntityLoader) loadDatasources(ctx context.Context, orgID int64, uid string, _ kdash.DatasourceLookup) ([]indexedEntity, error) {
+	rows := make([]*datasourceQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("data_source").Where("org_id = ?", orgID)
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		return sess.Cols("uid", "name", "type", "created", "updated").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, indexedEntity{
+			kind:    entityKindDatasource,
+			uid:     row.UID,
+			name:    row.Name,
+			url:     fmt.Sprintf("/datasources/edit/%s", row.UID),
+			dsUIDs:  []string{row.UID},
+			dsTypes: []string{row.Type},
+			created: row.Created,
+			updated: row.Updated,
+		})
+	}
+	return entities, nil
+}
+
+type libraryPanelQueryResult struct {
+	UID         string `xorm:"uid"`
+	Name        string `xorm:"name"`
+	Description string `xorm:"description"`
+	Type        string `xorm:"type"`
+	Model       []byte `xorm:"model"`
+	FolderID    int64  `xorm:"folder_id"`
+	FolderUID   string `xorm:"folder_uid"`
+	Created     time.Time
+	Updated     time.Time
+}
+
+func (l sqlEntityLoader) loadLibraryPanels(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]indexedEntity, error) {
+	rows := make([]*libraryPanelQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := "SELECT le.uid, le.name, le.description, le.type, le.model, le.folder_id, COALESCE(d.uid, '') AS folder_uid, le.created, le.updated " +
+			"FROM library_element AS le LEFT JOIN dashboard AS d ON d.id = le.folder_id " +
+			"WHERE le.org_id = ? AND le.kind = ?"
+		params := []interface{}{orgID, libraryElementKindPanel}
+		if uid != "" {
+			sql += " AND le.uid = ?"
+			params = append(params, uid)
+		}
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		location := row.FolderUID
+		if row.FolderID == 0 {
+			location = "general"
+		}
+		e := indexedEntity{
+			kind:        entityKindLibraryPanel,
+			uid:         row.UID,
+			name:        row.Name,
+			description: row.Description,
+			url:         "/library-panels",
+			location:    location,
+			panelType:   row.Type,
+			created:     row.Created,
+			updated:     row.Updated,
+		}
+
+		var model struct {
+			Datasource json.RawMessage `json:"datasource"`
+		}
+		if err := json.Unmarshal(row.Model, &model); err != nil {
+			l.logger.Warn("Error reading library panel model", "error", err, "uid", row.UID)
+		} else if len(model.Datasource) > 0 {
+			ref := &kdash.DataSourceRef{}
+			// older panels reference the datasource by name
+			if err := json.Unmarshal(model.Datasource, ref); err != nil {
+				ref = &kdash.DataSourceRef{}
+				_ = json.Unmarshal(model.Datasource, &ref.UID)
+			}
+			e.addDatasource(lookup.ByRef(ref))
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+type playlistQueryResult struct {
+	UID  string `xorm:"uid"`
+	Name string `xorm:"name"`
+}
+
+func (l sqlEntityLoader) loadPlaylists(ctx context.Context, orgID int64, uid string, _ kdash.DatasourceLookup) ([]indexedEntity, error) {
+	rows := make([]*playlistQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("playlist").Where("org_id = ?", orgID)
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		return sess.Cols("uid", "name").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, indexedEntity{
+			kind: entityKindPlaylist,
+			uid:  row.UID,
+			name: row.Name,
+			url:  fmt.Sprintf("/playlists/edit/%s", row.UID),
+		})
+	}
+	return entities, nil
+}
+
+type correlationQueryResult struct {
+	UID         string `xorm:"uid"`
+	Label       string `xorm:"label"`
+	Description string `xorm:"description"`
+	SourceUID   string `xorm:"source_uid"`
+	TargetUID   string `xorm:"target_uid"`
+}
+
+func (l sqlEntityLoader) loadCorrelations(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]indexedEntity, error) {
+	rows := make([]*correlationQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		// correlations belong to the organization of their source datasource
+		sql := "SELECT correlation.uid, correlation.label, correlation.description, correlation.source_uid, COALESCE(correlation.target_uid, '') AS target_uid " +
+			"FROM correlation INNER JOIN data_source ON correlation.source_uid = data_source.uid AND data_source.org_id = ?"
+		params := []interface{}{orgID}
+		if uid != "" {
+			sql += " WHERE correlation.uid = ?"
+			params = append(params, uid)
+		}
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		e := indexedEntity{
+			kind:        entityKindCorrelation,
+			uid:         row.UID,
+			name:        row.Label,
+			description: row.Description,
+			url:         "/datasources/correlations",
+		}
+		e.addDatasource(lookup.ByRef(&kdash.DataSourceRef{UID: row.SourceUID}))
+		if row.TargetUID != "" {
+			e.addDatasource(lookup.ByRef(&kdash.DataSourceRef{UID: row.TargetUID}))
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func (e *indexedEntity) addDatasource(ref *kdash.DataSourceRef) {
+	if ref == nil || ref.UID == "" {
+		return
+	}
+	if !stringInSlice(ref.UID, e.dsUIDs) {
+		e.dsUIDs = append(e.dsUIDs, ref.UID)
+	}
+	if ref.Type != "" && !stringInSlice(ref.Type, e.dsTypes) {
+		e.dsTypes = append(e.dsTypes, ref.Type)
+	}
+}
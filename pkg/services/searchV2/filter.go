@@ -2,6 +2,7 @@ package searchV2
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/search"
@@ -19,23 +20,36 @@ type PermissionFilter struct {
 type entityKind string
 
 const (
-	entityKindPanel      entityKind = models.StandardKindPanel
-	entityKindDashboard  entityKind = models.StandardKindDashboard
-	entityKindFolder     entityKind = models.StandardKindFolder
-	entityKindDatasource entityKind = models.StandardKindDataSource
-	entityKindQuery      entityKind = models.StandardKindQuery
+	entityKindPanel        entityKind = models.StandardKindPanel
+	entityKindDashboard    entityKind = models.StandardKindDashboard
+	entityKindFolder       entityKind = models.StandardKindFolder
+	entityKindDatasource   entityKind = models.StandardKindDataSource
+	entityKindQuery        entityKind = models.StandardKindQuery
+	entityKindAlertRule    entityKind = models.StandardKindAlertRule
+	entityKindLibraryPanel entityKind = models.StandardKindLibraryPanel
+	entityKindPlaylist     entityKind = models.StandardKindPlaylist
+	entityKindCorrelation  entityKind = models.StandardKindCorrelation
 )
 
 func (r entityKind) IsValid() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isEntity()
 }
 
 func (r entityKind) supportsAuthzCheck() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isEntity()
+}
+
+// isEntity returns true for the kinds loaded by the entityLoader rather than from the dashboard table
+func (r entityKind) isEntity() bool {
+	switch r {
+	case entityKindAlertRule, entityKindDatasource, entityKindLibraryPanel, entityKindPlaylist, entityKindCorrelation:
+		return true
+	}
+	return false
 }
 
 var (
-	permissionFilterFields                 = []string{documentFieldUID, documentFieldKind}
+	permissionFilterFields                 = []string{documentFieldUID, documentFieldKind, documentFieldLocation}
 	panelIdFieldRegex                      = regexp.MustCompile(`^(.*)#([0-9]{1,4})$`)
 	panelIdFieldDashboardUidSubmatchIndex  = 1
 	panelIdFieldPanelIdSubmatchIndex       = 2
@@ -64,14 +78,12 @@ func (q *PermissionFilter) logAccessDecision(decision bool, kind interface{}, id
 	}
 }
 
-func (q *PermissionFilter) canAccess(kind entityKind, id string) bool {
+func (q *PermissionFilter) canAccess(kind entityKind, id string, location string) bool {
 	if !kind.supportsAuthzCheck() {
 		q.logAccessDecision(false, kind, id, "entityDoesNotSupportAuthz")
 		return false
 	}
 
-	// TODO add `kind` to the `ResourceFilter` interface so that we can move the switch out of here
-	//
 	switch kind {
 	case entityKindFolder:
 		if id == "" {
@@ -80,7 +92,7 @@ func (q *PermissionFilter) canAccess(kind entityKind, id string) bool {
 		}
 		fallthrough
 	case entityKindDashboard:
-		decision := q.filter(id)
+		decision := q.filter(kind, id, location)
 		q.logAccessDecision(decision, kind, id, "resourceFilter")
 		return decision
 	case entityKindPanel:
@@ -93,10 +105,16 @@ func (q *PermissionFilter) canAccess(kind entityKind, id string) bool {
 		}
 
 		dashboardUid := matches[panelIdFieldDashboardUidSubmatchIndex]
-		decision := q.filter(dashboardUid)
+		decision := q.filter(entityKindDashboard, dashboardUid, location)
 
 		q.logAccessDecision(decision, kind, id, "resourceFilter", "dashboardUid", dashboardUid, "panelId", matches[panelIdFieldPanelIdSubmatchIndex])
 		return decision
+	case entityKindAlertRule, entityKindDatasource, entityKindLibraryPanel, entityKindPlaylist, entityKindCorrelation:
+		// entity documents are identified by `kind/uid`
+		uid := strings.TrimPrefix(id, string(kind)+"/")
+		decision := q.filter(kind, uid, location)
+		q.logAccessDecision(decision, kind, id, "resourceFilter", "location", location)
+		return decision
 	default:
 		q.logAccessDecision(false, kind, id, "reason", "unknownKind")
 		return false
@@ -111,12 +129,15 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 
 	s, err := searcher.NewMatchAllSearcher(i, 1, similarity.ConstantScorer(1), options)
 	return searcher.NewFilteringSearcher(s, func(d *search.DocumentMatch) bool {
-		var kind, id string
+		var kind, id, location string
 		err := dvReader.VisitDocumentValues(d.Number, func(field string, term []byte) {
-			if field == documentFieldKind {
+			switch field {
+			case documentFieldKind:
 				kind = string(term)
-			} else if field == documentFieldUID {
+			case documentFieldUID:
 				id = string(term)
+			case documentFieldLocation:
+				location = string(term)
 			}
 		})
 		if err != nil {
@@ -130,6 +151,6 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 			return false
 		}
 
-		return q.canAccess(e, id)
+		return q.canAccess(e, id, location)
 	}), err
 }
@@ -66,6 +66,8 @@ type buildSignal struct {
 
 type orgIndex struct {
 	writers map[indexType]*bluge.Writer
+	// path is the directory of an on-disk index, empty for in-memory indexes.
+	path string
 }
 
 type indexType string
@@ -86,9 +88,16 @@ func (i *orgIndex) readerForIndex(idxType indexType) (*bluge.Reader, func(), err
 	return reader, func() { _ = reader.Close() }, nil
 }
 
+func (i *orgIndex) close() {
+	for _, w := range i.writers {
+		_ = w.Close()
+	}
+}
+
 type searchIndex struct {
 	mu                      sync.RWMutex
 	loader                  dashboardLoader
+	entityLoader            entityLoader
 	perOrgIndex             map[int64]*orgIndex
 	initializedOrgs         map[int64]bool
 	initialIndexingComplete bool
@@ -104,9 +113,10 @@ type searchIndex struct {
 	settings                setting.SearchSettings
 }
 
-func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
+func newSearchIndex(dashLoader dashboardLoader, entLoader entityLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
 	return &searchIndex{
 		loader:          dashLoader,
+		entityLoader:    entLoader,
 		eventStore:      evStore,
 		perOrgIndex:     map[int64]*orgIndex{},
 		initializedOrgs: map[int64]bool{},
@@ -202,12 +212,24 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 		lastEventID = lastEvent.Id
 	}
 
-	err = i.buildInitialIndexes(initialSetupCtx, orgIDs)
+	pendingOrgIDs := orgIDs
+	catchUpEventID := lastEventID
+	if i.isPersistent() {
+		pendingOrgIDs, catchUpEventID = i.openPersistedIndexes(initialSetupCtx, orgIDs, lastEventID)
+	}
+
+	err = i.buildInitialIndexes(initialSetupCtx, pendingOrgIDs)
 	if err != nil {
 		initialSetupSpan.End()
 		return err
 	}
 
+	if catchUpEventID < lastEventID {
+		// Indexes reopened from disk miss changes made while Grafana was stopped. Re-applying
+		// events on indexes built above is harmless since updates are loaded from the database.
+		lastEventID = i.applyIndexUpdates(initialSetupCtx, catchUpEventID)
+	}
+
 	// This semaphore channel allows limiting concurrent async re-indexing routines to 1.
 	asyncReIndexSemaphore := make(chan struct{}, 1)
 
@@ -300,11 +322,38 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			}
 			fullReIndexTimer.Reset(reIndexInterval)
 		case <-ctx.Done():
+			if i.isPersistent() {
+				// Wait for asynchronous re-indexing to finish, it's cancelled together with ctx.
+				lastEventID = waitForReIndex(asyncReIndexSemaphore, reIndexDoneCh, lastEventID)
+				i.persistIndexes(lastEventID)
+			}
 			return ctx.Err()
 		}
 	}
 }
 
+// waitForReIndex blocks until no asynchronous re-indexing is running and returns the lowest of
+// lastEventID and event IDs of re-indexing not handled by the run loop yet.
+func waitForReIndex(asyncReIndexSemaphore chan struct{}, reIndexDoneCh chan int64, lastEventID int64) int64 {
+	for {
+		select {
+		case asyncReIndexSemaphore <- struct{}{}:
+			select {
+			case lastIndexedEventID := <-reIndexDoneCh:
+				if lastIndexedEventID < lastEventID {
+					lastEventID = lastIndexedEventID
+				}
+			default:
+			}
+			return lastEventID
+		case lastIndexedEventID := <-reIndexDoneCh:
+			if lastIndexedEventID < lastEventID {
+				lastEventID = lastIndexedEventID
+			}
+		}
+	}
+}
+
 func (i *searchIndex) buildInitialIndexes(ctx context.Context, orgIDs []int64) error {
 	started := time.Now()
 	i.logger.Info("Start building in-memory indexes")
@@ -486,19 +535,35 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 	}
 	i.logger.Info("Finish loading org dashboards", "elapsed", orgSearchIndexLoadTime, "orgId", orgID)
 
+	entities, err := i.entityLoader.LoadEntities(ctx, orgID, "", "")
+	if err != nil {
+		return 0, fmt.Errorf("error loading entities: %w", err)
+	}
+	orgSearchIndexLoadTime = time.Since(started)
+	i.logger.Info("Finish loading org entities", "elapsed", orgSearchIndexLoadTime, "orgId", orgID, "numEntities", len(entities))
+
 	dashboardExtender := i.extender.GetDashboardExtender(orgID)
 
 	_, initOrgIndexSpan := i.tracer.Start(ctx, "searchV2 buildOrgIndex init org index")
 	initOrgIndexSpan.SetAttributes("org_id", orgID, attribute.Key("org_id").Int64(orgID))
 	initOrgIndexSpan.SetAttributes("dashboardCount", len(dashboards), attribute.Key("dashboardCount").Int(len(dashboards)))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	config, path, err := i.newIndexConfig(orgID)
+	if err != nil {
+		initOrgIndexSpan.End()
+		return 0, err
+	}
+	index, err := initOrgIndex(config, dashboards, entities, i.logger, dashboardExtender)
 
 	initOrgIndexSpan.End()
 
 	if err != nil {
+		if path != "" {
+			_ = os.RemoveAll(path)
+		}
 		return 0, fmt.Errorf("error initializing index: %w", err)
 	}
+	index.path = path
 	orgSearchIndexTotalTime := time.Since(started)
 	orgSearchIndexBuildTime := orgSearchIndexTotalTime - orgSearchIndexLoadTime
 
@@ -507,16 +572,19 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchIndexLoadTime", orgSearchIndexLoadTime,
 			"orgSearchIndexBuildTime", orgSearchIndexBuildTime,
 			"orgSearchIndexTotalTime", orgSearchIndexTotalTime,
-			"orgSearchDashboardCount", len(dashboards))...)
+			"orgSearchDashboardCount", len(dashboards),
+			"orgSearchEntityCount", len(entities))...)
 
 	i.mu.Lock()
-	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
-		for _, w := range oldIndex.writers {
-			_ = w.Close()
-		}
+	oldIndex, ok := i.perOrgIndex[orgID]
+	if ok {
+		oldIndex.close()
 	}
 	i.perOrgIndex[orgID] = index
 	i.mu.Unlock()
+	if ok {
+		i.removeIndexGeneration(oldIndex)
+	}
 
 	i.initializationMutex.Lock()
 	i.initializedOrgs[orgID] = true
@@ -654,6 +722,10 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	}
 	i.mu.Unlock()
 
+	if entKind, ok := entityKindFromEntityType(kind); ok {
+		return i.applyEntityEvent(ctx, orgID, entKind, uid)
+	}
+
 	// Both dashboard and folder share same DB table.
 	dbDashboards, err := i.loader.LoadDashboards(ctx, orgID, uid)
 	if err != nil {
@@ -688,6 +760,29 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	return nil
 }
 
+func (i *searchIndex) applyEntityEvent(ctx context.Context, orgID int64, kind entityKind, uid string) error {
+	entities, err := i.entityLoader.LoadEntities(ctx, orgID, kind, uid)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	index, ok := i.perOrgIndex[orgID]
+	if !ok {
+		// Skip event for org not yet fully indexed.
+		return nil
+	}
+
+	writer := index.writerForIndex(indexTypeDashboard)
+	if len(entities) == 0 {
+		return writer.Delete(bluge.NewDocument(entityDocumentID(kind, uid)).ID())
+	}
+	doc := getEntityDoc(entities[0])
+	return writer.Update(doc.ID(), doc)
+}
+
 func (i *searchIndex) removeDashboard(_ context.Context, index *orgIndex, dashboardUID string) error {
 	dashboardLocation, ok, err := getDashboardLocation(index, dashboardUID)
 	if err != nil {
@@ -2,9 +2,12 @@ package searchV2
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -29,13 +32,30 @@ func (t *testDashboardLoader) LoadDashboards(_ context.Context, _ int64, _ strin
 	return t.dashboards, nil
 }
 
+type testEntityLoader struct {
+	entities []indexedEntity
+}
+
+func (t *testEntityLoader) LoadEntities(_ context.Context, _ int64, kind entityKind, uid string) ([]indexedEntity, error) {
+	if kind == "" && uid == "" {
+		return t.entities, nil
+	}
+	var entities []indexedEntity
+	for _, e := range t.entities {
+		if e.kind == kind && e.uid == uid {
+			entities = append(entities, e)
+		}
+	}
+	return entities, nil
+}
+
 var testLogger = log.New("index-test-logger")
 
-var testAllowAllFilter = func(uid string) bool {
+var testAllowAllFilter = func(kind entityKind, uid string, location string) bool {
 	return true
 }
 
-var testDisallowAllFilter = func(uid string) bool {
+var testDisallowAllFilter = func(kind entityKind, uid string, location string) bool {
 	return false
 }
 
@@ -63,7 +83,7 @@ func initTestIndexFromDashesExtended(t *testing.T, dashboards []dashboard, exten
 	dashboardLoader := &testDashboardLoader{
 		dashboards: dashboards,
 	}
-	index := newSearchIndex(dashboardLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
+	index := newSearchIndex(dashboardLoader, &testEntityLoader{}, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
 	require.NotNil(t, index)
 	numDashboards, err := index.buildOrgIndex(context.Background(), testOrgID)
 	require.NoError(t, err)
@@ -732,3 +752,323 @@ func TestDashboardIndex_MultiTermPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+var testEntities = []indexedEntity{
+	{
+		kind:     entityKindAlertRule,
+		uid:      "rule1",
+		name:     "High CPU",
+		url:      "/alerting/grafana/rule1/view",
+		location: "1",
+		labels:   map[string]string{"severity": "critical", "team": "infra"},
+		dsUIDs:   []string{"prom1"},
+		dsTypes:  []string{"prometheus"},
+	},
+	{
+		kind:     entityKindAlertRule,
+		uid:      "rule2",
+		name:     "High memory",
+		url:      "/alerting/grafana/rule2/view",
+		location: "2",
+		labels:   map[string]string{"severity": "warning"},
+		dsUIDs:   []string{"loki1"},
+		dsTypes:  []string{"loki"},
+	},
+	{
+		kind:    entityKindDatasource,
+		uid:     "prom1",
+		name:    "Prometheus",
+		url:     "/datasources/edit/prom1",
+		dsUIDs:  []string{"prom1"},
+		dsTypes: []string{"prometheus"},
+	},
+	{
+		kind:      entityKindLibraryPanel,
+		uid:       "1", // same uid as a dashboard
+		name:      "CPU panel",
+		url:       "/library-panels",
+		location:  "general",
+		panelType: "timeseries",
+		dsUIDs:    []string{"prom1"},
+		dsTypes:   []string{"prometheus"},
+	},
+	{
+		kind: entityKindPlaylist,
+		uid:  "playlist1",
+		name: "CPU playlist",
+		url:  "/playlists/edit/playlist1",
+	},
+	{
+		kind:    entityKindCorrelation,
+		uid:     "correlation1",
+		name:    "CPU logs",
+		url:     "/datasources/correlations",
+		dsUIDs:  []string{"prom1", "loki1"},
+		dsTypes: []string{"prometheus", "loki"},
+	},
+}
+
+func initTestIndexFromEntities(t *testing.T, dashboards []dashboard, loader *testEntityLoader) *searchIndex {
+	t.Helper()
+	index := newSearchIndex(&testDashboardLoader{dashboards: dashboards}, loader, &store.MockEntityEventsService{}, &NoopDocumentExtender{}, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
+	require.NotNil(t, index)
+	_, err := index.buildOrgIndex(context.Background(), testOrgID)
+	require.NoError(t, err)
+	return index
+}
+
+func searchEntityUIDs(t *testing.T, index *orgIndex, filter ResourceFilter, query DashboardQuery) []string {
+	t.Helper()
+	resp := doSearchQuery(context.Background(), testLogger, index, filter, query, &NoopQueryExtender{}, "")
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	kindField, _ := frame.FieldByName("kind")
+	uidField, _ := frame.FieldByName("uid")
+	uids := make([]string, 0, uidField.Len())
+	for i := 0; i < uidField.Len(); i++ {
+		uids = append(uids, fmt.Sprintf("%s/%s", kindField.At(i), uidField.At(i)))
+	}
+	return uids
+}
+
+func TestDashboardIndex_Entities(t *testing.T) {
+	t.Run("entities-indexed", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithFolders, &testEntityLoader{entities: testEntities})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		checkSearchResponse(t, filepath.Base(t.Name()), orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "cpu", Sort: "name_sort"},
+		)
+	})
+
+	t.Run("entities-facets", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, nil, &testEntityLoader{entities: testEntities})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		checkSearchResponse(t, filepath.Base(t.Name()), orgIdx, testAllowAllFilter,
+			DashboardQuery{
+				Kind:  []string{string(entityKindAlertRule), string(entityKindCorrelation)},
+				Sort:  "name_sort",
+				Facet: []FacetField{{Field: documentFieldLabel}, {Field: documentFieldDSType}, {Field: documentFieldLocation}},
+			},
+		)
+	})
+
+	t.Run("entities-filtered-by-label-and-datasource-type", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, nil, &testEntityLoader{entities: testEntities})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		uids := searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{Labels: []string{"severity=critical"}})
+		require.Equal(t, []string{"alertrule/rule1"}, uids)
+
+		uids = searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{DatasourceType: "loki", Sort: "name_sort"})
+		require.Equal(t, []string{"correlation/correlation1", "alertrule/rule2"}, uids)
+	})
+
+	t.Run("entities-filtered-by-permissions", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, nil, &testEntityLoader{entities: testEntities})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		var checked []string
+		filter := func(kind entityKind, uid string, location string) bool {
+			checked = append(checked, fmt.Sprintf("%s/%s@%s", kind, uid, location))
+			return (kind == entityKindAlertRule && location == "1") || kind == entityKindPlaylist
+		}
+
+		uids := searchEntityUIDs(t, orgIdx, filter, DashboardQuery{Sort: "name_sort"})
+		require.Equal(t, []string{"playlist/playlist1", "alertrule/rule1"}, uids)
+		require.Contains(t, checked, "alertrule/rule2@2")
+		require.Contains(t, checked, "librarypanel/1@general")
+		require.Contains(t, checked, "ds/prom1@")
+	})
+
+	t.Run("entities-updated-on-event", func(t *testing.T) {
+		loader := &testEntityLoader{entities: testEntities}
+		index := initTestIndexFromEntities(t, testDashboards, loader)
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		loader.entities = []indexedEntity{{kind: entityKindPlaylist, uid: "playlist1", name: "Renamed playlist"}}
+		err := index.applyEvent(context.Background(), testOrgID, store.EntityTypePlaylist, "playlist1", store.EntityEventTypeUpdate)
+		require.NoError(t, err)
+
+		uids := searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{Query: "renamed"})
+		require.Equal(t, []string{"playlist/playlist1"}, uids)
+		uids = searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{Kind: []string{string(entityKindPlaylist)}})
+		require.Equal(t, []string{"playlist/playlist1"}, uids)
+	})
+
+	t.Run("entities-removed-on-event", func(t *testing.T) {
+		loader := &testEntityLoader{entities: testEntities}
+		index := initTestIndexFromEntities(t, testDashboards, loader)
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		loader.entities = nil
+		err := index.applyEvent(context.Background(), testOrgID, store.EntityTypeLibraryPanel, "1", store.EntityEventTypeDelete)
+		require.NoError(t, err)
+
+		// the dashboard with the same uid is kept
+		uids := searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{UIDs: []string{"1"}})
+		require.Equal(t, []string{"dashboard/1"}, uids)
+		uids = searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{Kind: []string{string(entityKindLibraryPanel)}})
+		require.Empty(t, uids)
+	})
+}
+
+func initTestPersistentIndex(t *testing.T, indexPath string, dashboards []dashboard) *searchIndex {
+	t.Helper()
+	index := newSearchIndex(&testDashboardLoader{dashboards: dashboards}, &testEntityLoader{}, &store.MockEntityEventsService{}, &NoopDocumentExtender{}, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{IndexPath: indexPath})
+	require.NotNil(t, index)
+	return index
+}
+
+func TestDashboardIndex_Persistence(t *testing.T) {
+	ctx := context.Background()
+
+	persistTestIndex := func(t *testing.T, indexPath string, lastEventID int64) []string {
+		t.Helper()
+		index := initTestPersistentIndex(t, indexPath, testDashboards)
+		_, err := index.buildOrgIndex(ctx, testOrgID)
+		require.NoError(t, err)
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		uids := searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{})
+		require.NotEmpty(t, uids)
+		index.persistIndexes(lastEventID)
+		require.FileExists(t, filepath.Join(indexPath, "1", persistedIndexMetaFile))
+		return uids
+	}
+
+	t.Run("reopens persisted index", func(t *testing.T) {
+		indexPath := t.TempDir()
+		expected := persistTestIndex(t, indexPath, 5)
+
+		// Nothing is loaded from the database for reopened indexes.
+		index := initTestPersistentIndex(t, indexPath, nil)
+		pending, fromEventID := index.openPersistedIndexes(ctx, []int64{testOrgID}, 7)
+		require.Empty(t, pending)
+		require.Equal(t, int64(5), fromEventID)
+		require.True(t, index.initializedOrgs[testOrgID])
+		require.NoFileExists(t, filepath.Join(indexPath, "1", persistedIndexMetaFile))
+
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		require.Equal(t, expected, searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{}))
+
+		err := index.updateDashboard(ctx, testOrgID, orgIdx, dashboard{
+			id:  3,
+			uid: "3",
+			summary: &models.EntitySummary{
+				Name: "created",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"dashboard/3"}, searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{Query: "created"}))
+
+		// Changes applied after reopening are persisted as well.
+		index.persistIndexes(8)
+		index = initTestPersistentIndex(t, indexPath, nil)
+		pending, fromEventID = index.openPersistedIndexes(ctx, []int64{testOrgID}, 8)
+		require.Empty(t, pending)
+		require.Equal(t, int64(8), fromEventID)
+		orgIdx, ok = index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		require.Equal(t, []string{"dashboard/3"}, searchEntityUIDs(t, orgIdx, testAllowAllFilter, DashboardQuery{Query: "created"}))
+		orgIdx.close()
+	})
+
+	t.Run("rebuilds index which was not persisted", func(t *testing.T) {
+		indexPath := t.TempDir()
+		index := initTestPersistentIndex(t, indexPath, testDashboards)
+		_, err := index.buildOrgIndex(ctx, testOrgID)
+		require.NoError(t, err)
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		orgIdx.close()
+
+		index = initTestPersistentIndex(t, indexPath, testDashboards)
+		pending, fromEventID := index.openPersistedIndexes(ctx, []int64{testOrgID}, 7)
+		require.Equal(t, []int64{testOrgID}, pending)
+		require.Equal(t, int64(7), fromEventID)
+		require.NoDirExists(t, filepath.Join(indexPath, "1"))
+	})
+
+	t.Run("rebuilds corrupted index", func(t *testing.T) {
+		indexPath := t.TempDir()
+		persistTestIndex(t, indexPath, 5)
+
+		segments, err := filepath.Glob(filepath.Join(indexPath, "1", "*", "*.seg"))
+		require.NoError(t, err)
+		require.NotEmpty(t, segments)
+		for _, segment := range segments {
+			require.NoError(t, os.Truncate(segment, 10))
+		}
+
+		index := initTestPersistentIndex(t, indexPath, testDashboards)
+		pending, _ := index.openPersistedIndexes(ctx, []int64{testOrgID}, 7)
+		require.Equal(t, []int64{testOrgID}, pending)
+		require.NoDirExists(t, filepath.Join(indexPath, "1"))
+	})
+
+	t.Run("rebuilds index with mismatching meta", func(t *testing.T) {
+		for name, modify := range map[string]func(meta *persistedIndexMeta){
+			"version":         func(meta *persistedIndexMeta) { meta.Version = persistedIndexVersion + 1 },
+			"num docs":        func(meta *persistedIndexMeta) { meta.NumDocs++ },
+			"event ahead":     func(meta *persistedIndexMeta) { meta.LastEventID = 100 },
+			"expired":         func(meta *persistedIndexMeta) { meta.Persisted = time.Now().Add(-48 * time.Hour).UnixMilli() },
+			"generation":      func(meta *persistedIndexMeta) { meta.Generation = "../1" },
+			"grafana version": func(meta *persistedIndexMeta) { meta.GrafanaVersion = "0.0.1" },
+		} {
+			t.Run(name, func(t *testing.T) {
+				indexPath := t.TempDir()
+				persistTestIndex(t, indexPath, 5)
+
+				metaPath := filepath.Join(indexPath, "1", persistedIndexMetaFile)
+				data, err := os.ReadFile(metaPath)
+				require.NoError(t, err)
+				var meta persistedIndexMeta
+				require.NoError(t, json.Unmarshal(data, &meta))
+				modify(&meta)
+				data, err = json.Marshal(meta)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(metaPath, data, 0640))
+
+				index := initTestPersistentIndex(t, indexPath, testDashboards)
+				pending, _ := index.openPersistedIndexes(ctx, []int64{testOrgID}, 7)
+				require.Equal(t, []int64{testOrgID}, pending)
+				require.NoDirExists(t, filepath.Join(indexPath, "1"))
+			})
+		}
+	})
+
+	t.Run("removes indexes of unknown orgs", func(t *testing.T) {
+		indexPath := t.TempDir()
+		persistTestIndex(t, indexPath, 5)
+
+		index := initTestPersistentIndex(t, indexPath, testDashboards)
+		pending, _ := index.openPersistedIndexes(ctx, []int64{2}, 7)
+		require.Equal(t, []int64{2}, pending)
+		require.NoDirExists(t, filepath.Join(indexPath, "1"))
+	})
+
+	t.Run("removes replaced index on re-index", func(t *testing.T) {
+		indexPath := t.TempDir()
+		index := initTestPersistentIndex(t, indexPath, testDashboards)
+		_, err := index.buildOrgIndex(ctx, testOrgID)
+		require.NoError(t, err)
+		orgIdx, _ := index.getOrgIndex(testOrgID)
+		oldPath := orgIdx.path
+		require.DirExists(t, oldPath)
+
+		_, err = index.buildOrgIndex(ctx, testOrgID)
+		require.NoError(t, err)
+		orgIdx, _ = index.getOrgIndex(testOrgID)
+		require.NotEqual(t, oldPath, orgIdx.path)
+		require.NoDirExists(t, oldPath)
+		require.DirExists(t, orgIdx.path)
+		orgIdx.close()
+	})
+}
@@ -0,0 +1,317 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// persistedIndexVersion must be incremented whenever the documents written by initOrgIndex
+// change, so that indexes persisted by an older version are rebuilt instead of reopened.
+const persistedIndexVersion = 1
+
+const (
+	persistedIndexMetaFile = "meta.json"
+	persistIndexTimeout    = 30 * time.Second
+)
+
+var errNoPersistedIndex = errors.New("no persisted index")
+
+// persistedIndexMeta is written next to an on-disk org index on shutdown. The index
+// is only reopened if the meta file exists, so an index that was not cleanly persisted
+// (e.g. after a crash) is always rebuilt from the database.
+type persistedIndexMeta struct {
+	Version        int    `json:"version"`
+	GrafanaVersion string `json:"grafanaVersion"`
+	Generation     string `json:"generation"`
+	LastEventID    int64  `json:"lastEventId"`
+	NumDocs        uint64 `json:"numDocs"`
+	Persisted      int64  `json:"persisted"` // unix milliseconds
+}
+
+func (i *searchIndex) isPersistent() bool {
+	return i.settings.IndexPath != ""
+}
+
+func (i *searchIndex) orgIndexDir(orgID int64) string {
+	return filepath.Join(i.settings.IndexPath, strconv.FormatInt(orgID, 10))
+}
+
+// newIndexConfig returns the config for a new org index. On-disk indexes get a new
+// generation directory each time, so that a full re-index can be built while the
+// previous index is still used.
+func (i *searchIndex) newIndexConfig(orgID int64) (bluge.Config, string, error) {
+	if !i.isPersistent() {
+		return bluge.InMemoryOnlyConfig(), "", nil
+	}
+	dir := filepath.Join(i.orgIndexDir(orgID), strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return bluge.Config{}, "", fmt.Errorf("can't create index directory: %w", err)
+	}
+	return bluge.DefaultConfig(dir), dir, nil
+}
+
+// openPersistedIndexes reopens org indexes persisted on the previous shutdown. It returns
+// the IDs of orgs which still need to be indexed from scratch, and the event ID to apply
+// index updates from in order to catch up reopened indexes.
+func (i *searchIndex) openPersistedIndexes(ctx context.Context, orgIDs []int64, lastEventID int64) ([]int64, int64) {
+	i.removeUnknownOrgIndexes(orgIDs)
+
+	started := time.Now()
+	fromEventID := lastEventID
+	pending := make([]int64, 0, len(orgIDs))
+	for _, orgID := range orgIDs {
+		if ctx.Err() != nil {
+			pending = append(pending, orgID)
+			continue
+		}
+		index, persistedEventID, err := i.openPersistedOrgIndex(orgID, lastEventID)
+		if err != nil {
+			if errors.Is(err, errNoPersistedIndex) {
+				i.logger.Info("No persisted index found for org", "orgId", orgID)
+			} else {
+				i.logger.Warn("Discarding persisted index for org, it will be rebuilt", "orgId", orgID, "error", err)
+			}
+			if err := os.RemoveAll(i.orgIndexDir(orgID)); err != nil {
+				i.logger.Error("Can't remove persisted index", "orgId", orgID, "error", err)
+			}
+			pending = append(pending, orgID)
+			continue
+		}
+
+		i.mu.Lock()
+		i.perOrgIndex[orgID] = index
+		i.mu.Unlock()
+
+		i.initializationMutex.Lock()
+		i.initializedOrgs[orgID] = true
+		i.initializationMutex.Unlock()
+
+		if persistedEventID < fromEventID {
+			fromEventID = persistedEventID
+		}
+	}
+	i.logger.Info("Finish opening persisted indexes", "elapsed", time.Since(started), "numOpened", len(orgIDs)-len(pending), "numPending", len(pending))
+	return pending, fromEventID
+}
+
+func (i *searchIndex) openPersistedOrgIndex(orgID int64, lastEventID int64) (*orgIndex, int64, error) {
+	orgDir := i.orgIndexDir(orgID)
+	metaPath := filepath.Join(orgDir, persistedIndexMetaFile)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, errNoPersistedIndex
+		}
+		return nil, 0, fmt.Errorf("can't read index meta: %w", err)
+	}
+	// The index is modified as soon as it is reopened, the meta file is only valid
+	// until the next shutdown writes it again.
+	if err := os.Remove(metaPath); err != nil {
+		return nil, 0, fmt.Errorf("can't remove index meta: %w", err)
+	}
+
+	var meta persistedIndexMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, 0, fmt.Errorf("can't parse index meta: %w", err)
+	}
+	switch {
+	case meta.Version != persistedIndexVersion:
+		return nil, 0, fmt.Errorf("index version %d does not match %d", meta.Version, persistedIndexVersion)
+	case meta.GrafanaVersion != setting.BuildVersion:
+		return nil, 0, fmt.Errorf("index was persisted by Grafana %s", meta.GrafanaVersion)
+	case meta.LastEventID > lastEventID:
+		return nil, 0, fmt.Errorf("index last event ID %d is ahead of the database (%d)", meta.LastEventID, lastEventID)
+	case time.Since(time.UnixMilli(meta.Persisted)) > store.EntityEventsRetention:
+		// Events which happened after the index was persisted may have been cleaned up already.
+		return nil, 0, fmt.Errorf("index is older than entity events retention")
+	case meta.Generation == "" || filepath.Base(meta.Generation) != meta.Generation:
+		return nil, 0, fmt.Errorf("invalid index generation %q", meta.Generation)
+	}
+
+	dir := filepath.Join(orgDir, meta.Generation)
+	writer, err := bluge.OpenWriter(bluge.DefaultConfig(dir))
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't open index: %w", err)
+	}
+	index := &orgIndex{
+		writers: map[indexType]*bluge.Writer{
+			indexTypeDashboard: writer,
+		},
+		path: dir,
+	}
+	if err := validateOrgIndex(index, meta.NumDocs); err != nil {
+		index.close()
+		return nil, 0, err
+	}
+
+	// Remove leftovers of generations which were not cleaned up.
+	entries, err := os.ReadDir(orgDir)
+	if err != nil {
+		index.close()
+		return nil, 0, fmt.Errorf("can't list index directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == meta.Generation {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(orgDir, entry.Name())); err != nil {
+			i.logger.Warn("Can't remove stale index generation", "orgId", orgID, "path", entry.Name(), "error", err)
+		}
+	}
+	return index, meta.LastEventID, nil
+}
+
+// validateOrgIndex reads all stored documents to make sure the index is not corrupted
+// and contains the expected number of documents.
+func validateOrgIndex(index *orgIndex, expectedNumDocs uint64) error {
+	reader, cancel, err := index.readerForIndex(indexTypeDashboard)
+	if err != nil {
+		return fmt.Errorf("can't open index reader: %w", err)
+	}
+	defer cancel()
+
+	documentMatchIterator, err := reader.Search(context.Background(), bluge.NewAllMatches(bluge.NewMatchAllQuery()))
+	if err != nil {
+		return fmt.Errorf("can't search index: %w", err)
+	}
+	var numDocs uint64
+	match, err := documentMatchIterator.Next()
+	for err == nil && match != nil {
+		err = match.VisitStoredFields(func(field string, value []byte) bool {
+			return true
+		})
+		if err != nil {
+			break
+		}
+		numDocs++
+		match, err = documentMatchIterator.Next()
+	}
+	if err != nil {
+		return fmt.Errorf("can't read index documents: %w", err)
+	}
+	if numDocs != expectedNumDocs {
+		return fmt.Errorf("index contains %d documents, expected %d", numDocs, expectedNumDocs)
+	}
+	return nil
+}
+
+// removeUnknownOrgIndexes removes persisted indexes of organizations which no longer exist.
+func (i *searchIndex) removeUnknownOrgIndexes(orgIDs []int64) {
+	entries, err := os.ReadDir(i.settings.IndexPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			i.logger.Warn("Can't list index directory", "error", err)
+		}
+		return
+	}
+	known := make(map[string]bool, len(orgIDs))
+	for _, orgID := range orgIDs {
+		known[strconv.FormatInt(orgID, 10)] = true
+	}
+	for _, entry := range entries {
+		if known[entry.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(i.settings.IndexPath, entry.Name())); err != nil {
+			i.logger.Warn("Can't remove index of unknown org", "path", entry.Name(), "error", err)
+		}
+	}
+}
+
+// persistIndexes flushes on-disk org indexes and records the last applied event ID next
+// to them, so that they can be reopened and caught up on the next start. Indexes are
+// closed and can't be used afterwards.
+func (i *searchIndex) persistIndexes(lastEventID int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for orgID, index := range i.perOrgIndex {
+		if index.path == "" {
+			continue
+		}
+		if err := persistOrgIndex(index, lastEventID); err != nil {
+			i.logger.Warn("Can't persist index, it will be rebuilt on next start", "orgId", orgID, "error", err)
+		} else {
+			i.logger.Info("Persisted index", "orgId", orgID, "lastEventId", lastEventID)
+		}
+		delete(i.perOrgIndex, orgID)
+
+		i.initializationMutex.Lock()
+		delete(i.initializedOrgs, orgID)
+		i.initializationMutex.Unlock()
+	}
+}
+
+func persistOrgIndex(index *orgIndex, lastEventID int64) error {
+	defer index.close()
+
+	writer := index.writerForIndex(indexTypeDashboard)
+	reader, err := writer.Reader()
+	if err != nil {
+		return fmt.Errorf("can't open index reader: %w", err)
+	}
+	numDocs, err := reader.Count()
+	_ = reader.Close()
+	if err != nil {
+		return fmt.Errorf("can't count documents: %w", err)
+	}
+
+	// Segments are persisted asynchronously. An empty batch gets its callback invoked
+	// once everything introduced before it has been written to disk.
+	persisted := make(chan error, 1)
+	batch := bluge.NewBatch()
+	batch.SetPersistedCallback(func(err error) {
+		persisted <- err
+	})
+	if err := writer.Batch(batch); err != nil {
+		return fmt.Errorf("can't flush index: %w", err)
+	}
+	select {
+	case err := <-persisted:
+		if err != nil {
+			return fmt.Errorf("can't flush index: %w", err)
+		}
+	case <-time.After(persistIndexTimeout):
+		return fmt.Errorf("timeout flushing index")
+	}
+	index.close()
+
+	data, err := json.Marshal(persistedIndexMeta{
+		Version:        persistedIndexVersion,
+		GrafanaVersion: setting.BuildVersion,
+		Generation:     filepath.Base(index.path),
+		LastEventID:    lastEventID,
+		NumDocs:        numDocs,
+		Persisted:      time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+	orgDir := filepath.Dir(index.path)
+	tmpPath := filepath.Join(orgDir, persistedIndexMetaFile+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0640); err != nil {
+		return fmt.Errorf("can't write index meta: %w", err)
+	}
+	return os.Rename(tmpPath, filepath.Join(orgDir, persistedIndexMetaFile))
+}
+
+// removeIndexGeneration removes the directory of an on-disk index once it is closed.
+func (i *searchIndex) removeIndexGeneration(index *orgIndex) {
+	if index.path == "" {
+		return
+	}
+	if err := os.RemoveAll(index.path); err != nil {
+		i.logger.Warn("Can't remove replaced index", "path", index.path, "error", err)
+	}
+}
@@ -97,6 +97,7 @@ func ProvideService(cfg *setting.Cfg, sql db.DB, entityEventStore store.EntityEv
 		},
 		dashboardIndex: newSearchIndex(
 			newSQLDashboardLoader(sql, tracer, cfg.Search),
+			newSQLEntityLoader(sql, tracer),
 			entityEventStore,
 			extender.GetDocumentExtender(),
 			newFolderIDLookup(sql),
@@ -245,7 +246,7 @@ func (s *StandardSearchService) doDashboardQuery(ctx context.Context, signedInUs
 
 	rsp := &backend.DataResponse{}
 
-	filter, err := s.auth.GetDashboardReadFilter(signedInUser)
+	filter, err := s.auth.GetReadFilter(signedInUser)
 	if err != nil {
 		dashboardSearchFailureRequestsCounter.With(prometheus.Labels{
 			"reason": "get_dashboard_filter_error",
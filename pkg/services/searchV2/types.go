@@ -21,6 +21,7 @@ type DashboardQuery struct {
 	Datasource         string       `json:"ds_uid,omitempty"`   // "datasource" collides with the JSON value at the same leel :()
 	DatasourceType     string       `json:"ds_type,omitempty"`
 	Tags               []string     `json:"tags,omitempty"`
+	Labels             []string     `json:"labels,omitempty"` // key=value
 	Kind               []string     `json:"kind,omitempty"`
 	PanelType          string       `json:"panel_type,omitempty"`
 	UIDs               []string     `json:"uid,omitempty"`
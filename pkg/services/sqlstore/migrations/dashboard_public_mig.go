@@ -84,4 +84,36 @@ func addPublicDashboardMigration(mg *Migrator) {
 
 	mg.AddMigration("delete orphaned public dashboards", NewRawSQLMigration(
 		"DELETE FROM dashboard_public WHERE dashboard_uid NOT IN (SELECT uid FROM dashboard)"))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add ip_allow_list column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "ip_allow_list",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	var dashboardPublicUsageV1 = Table{
+		Name: "dashboard_public_usage",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "day", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "request_count", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "query_count", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "error_count", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"public_dashboard_uid", "day"}, Type: UniqueIndex},
+			{Cols: []string{"org_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public usage table v1", NewAddTableMigration(dashboardPublicUsageV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicUsageV1)
 }
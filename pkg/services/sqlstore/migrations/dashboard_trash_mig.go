@@ -0,0 +1,36 @@
+package migrations
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardTrashMigrations(mg *migrator.Migrator) {
+	dashboardTrash := migrator.Table{
+		Name: "dashboard_trash",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "is_folder", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "data", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "deleted", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "dashboard_uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"deleted"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard trash table", migrator.NewAddTableMigration(dashboardTrash))
+	mg.AddMigration("add unique index dashboard_trash.org_id_dashboard_uid", migrator.NewAddIndexMigration(dashboardTrash, dashboardTrash.Indices[0]))
+	mg.AddMigration("add index dashboard_trash.deleted", migrator.NewAddIndexMigration(dashboardTrash, dashboardTrash.Indices[1]))
+
+	mg.AddMigration("add trashed_with column to dashboard_trash", migrator.NewAddColumnMigration(dashboardTrash, &migrator.Column{
+		Name:     "trashed_with",
+		Type:     migrator.DB_NVarchar,
+		Length:   40,
+		Nullable: false,
+		Default:  "''",
+	}))
+}
@@ -143,6 +143,32 @@ func addEntityStoreMigrations(mg *migrator.Migrator) {
 		},
 	})
 
+	// Change log used to watch entities -- the resource version is the auto incrementing id
+	tables = append(tables, migrator.Table{
+		Name: "entity_change",
+		Columns: []*migrator.Column{
+			{Name: "resource_version", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "grn", Type: migrator.DB_NVarchar, Length: grnLength, Nullable: false},
+			{Name: "tenant_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "version", Type: migrator.DB_NVarchar, Length: 128, Nullable: false},
+			{Name: "action", Type: migrator.DB_Int, Nullable: false}, // created, updated, deleted
+
+			// Who changed what when
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+
+			// When the change was recorded, used to prune old changes
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"tenant_id", "resource_version"}, Type: migrator.IndexType},
+			{Cols: []string{"created_at"}, Type: migrator.IndexType},
+		},
+	})
+
 	// !!! This should not run in production!
 	// The object store SQL schema is still in active development and this
 	// will only be called when the feature toggle is enabled
@@ -154,7 +180,7 @@ func addEntityStoreMigrations(mg *migrator.Migrator) {
 	// Migration cleanups: given that this is a complex setup
 	// that requires a lot of testing before we are ready to push out of dev
 	// this script lets us easy wipe previous changes and initialize clean tables
-	suffix := " (v8)" // change this when we want to wipe and reset the object tables
+	suffix := " (v9)" // change this when we want to wipe and reset the object tables
 	mg.AddMigration("EntityStore init: cleanup"+suffix, migrator.NewRawSQLMigration(strings.TrimSpace(`
 		DELETE FROM migration_log WHERE migration_id LIKE 'EntityStore init%';
 	`)))
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
@@ -105,6 +106,8 @@ type RootStorageConfig struct {
 	Name             string `json:"name"`
 	Description      string `json:"description"`
 	Disabled         bool   `json:"disabled,omitempty"`
+	// ReadOnly storages can be browsed but files can not be uploaded
+	ReadOnly bool `json:"readOnly,omitempty"`
 
 	// Depending on type, these will be configured
 	Disk *StorageLocalDiskConfig `json:"disk,omitempty"`
@@ -141,10 +144,14 @@ type StorageS3Config struct {
 	Bucket string `json:"bucket"`
 	Folder string `json:"folder"`
 
-	// SECURE!!!
+	// Secure values, use a reference to an environment variable like "$AWS_SECRET_ACCESS_KEY".
+	// When not set the default AWS credential chain is used.
 	AccessKey string `json:"accessKey"`
 	SecretKey string `json:"secretKey"`
 	Region    string `json:"region"`
+
+	// Endpoint of an S3 compatible service
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 type StorageGCSConfig struct {
@@ -152,14 +159,42 @@ type StorageGCSConfig struct {
 	Folder string `json:"folder"`
 
 	CredentialsFile string `json:"credentialsFile"`
+	// Secure value, use a reference to an environment variable like "$GCS_CREDENTIALS".
+	// When neither file nor json are set the application default credentials are used.
+	CredentialsJSON string `json:"credentialsJSON,omitempty"`
+}
+
+// secureValue resolves a secret configured as a reference to an environment variable, e.g. "$AWS_SECRET_ACCESS_KEY".
+func secureValue(value string) (string, error) {
+	if !strings.HasPrefix(value, "$") {
+		return value, nil
+	}
+	resolved := os.Getenv(value[1:])
+	if resolved == "" {
+		return "", fmt.Errorf("unable to find environment variable: %s", value)
+	}
+	return resolved, nil
+}
+
+// redactSecureValue hides a secret unless it is a reference to an environment variable.
+func redactSecureValue(value string) string {
+	if value == "" || strings.HasPrefix(value, "$") {
+		return value
+	}
+	return "********"
 }
 
 func newStorage(cfg RootStorageConfig, localWorkCache string) (storageRuntime, error) {
+	meta := RootStorageMeta{ReadOnly: cfg.ReadOnly}
 	switch cfg.Type {
 	case rootStorageTypeDisk:
-		return newDiskStorage(RootStorageMeta{}, cfg), nil
+		return newDiskStorage(meta, cfg), nil
 	case rootStorageTypeGit:
-		return newGitStorage(RootStorageMeta{}, cfg, localWorkCache), nil
+		return newGitStorage(meta, cfg, localWorkCache), nil
+	case rootStorageTypeS3:
+		return newS3Storage(meta, cfg), nil
+	case rootStorageTypeGCS:
+		return newGCSStorage(meta, cfg), nil
 	}
 
 	return nil, fmt.Errorf("unsupported store: " + cfg.Type)
@@ -44,3 +44,7 @@ func (i fakeEntityStore) History(ctx context.Context, r *entity.EntityHistoryReq
 func (i fakeEntityStore) Search(ctx context.Context, r *entity.EntitySearchRequest) (*entity.EntitySearchResponse, error) {
 	return nil, fmt.Errorf("unimplemented")
 }
+
+func (i fakeEntityStore) Watch(r *entity.EntityWatchRequest, w entity.EntityStore_WatchServer) error {
+	return fmt.Errorf("unimplemented")
+}
@@ -73,6 +73,59 @@ func (WriteEntityResponse_Status) EnumDescriptor() ([]byte, []int) {
 	return file_entity_proto_rawDescGZIP(), []int{10, 0}
 }
 
+// Action enumeration
+type EntityWatchResponse_Action int32
+
+const (
+	EntityWatchResponse_UNKNOWN EntityWatchResponse_Action = 0
+	EntityWatchResponse_CREATED EntityWatchResponse_Action = 1
+	EntityWatchResponse_UPDATED EntityWatchResponse_Action = 2
+	EntityWatchResponse_DELETED EntityWatchResponse_Action = 3
+)
+
+// Enum value maps for EntityWatchResponse_Action.
+var (
+	EntityWatchResponse_Action_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "CREATED",
+		2: "UPDATED",
+		3: "DELETED",
+	}
+	EntityWatchResponse_Action_value = map[string]int32{
+		"UNKNOWN": 0,
+		"CREATED": 1,
+		"UPDATED": 2,
+		"DELETED": 3,
+	}
+)
+
+func (x EntityWatchResponse_Action) Enum() *EntityWatchResponse_Action {
+	p := new(EntityWatchResponse_Action)
+	*p = x
+	return p
+}
+
+func (x EntityWatchResponse_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EntityWatchResponse_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_entity_proto_enumTypes[1].Descriptor()
+}
+
+func (EntityWatchResponse_Action) Type() protoreflect.EnumType {
+	return &file_entity_proto_enumTypes[1]
+}
+
+func (x EntityWatchResponse_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EntityWatchResponse_Action.Descriptor instead.
+func (EntityWatchResponse_Action) EnumDescriptor() ([]byte, []int) {
+	return file_entity_proto_rawDescGZIP(), []int{19, 0}
+}
+
 type GRN struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1583,6 +1636,175 @@ func (x *EntitySearchResponse) GetNextPageToken() string {
 	return ""
 }
 
+type EntityWatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Resource version to resume from.  Only changes after this version will be returned.
+	// When empty, only changes that happen after the watch started are returned
+	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	// limit to a specific kind (empty is all)
+	Kind []string `protobuf:"bytes,2,rep,name=kind,proto3" json:"kind,omitempty"`
+	// Limit results to items in a specific folder
+	Folder string `protobuf:"bytes,3,opt,name=folder,proto3" json:"folder,omitempty"`
+	// Limit results to items where the GRN string starts with this prefix
+	GrnPrefix string `protobuf:"bytes,4,opt,name=grn_prefix,json=grnPrefix,proto3" json:"grn_prefix,omitempty"`
+	// Return the full body in each payload
+	WithBody bool `protobuf:"varint,5,opt,name=with_body,json=withBody,proto3" json:"with_body,omitempty"`
+	// Include derived summary metadata
+	WithSummary bool `protobuf:"varint,6,opt,name=with_summary,json=withSummary,proto3" json:"with_summary,omitempty"`
+}
+
+func (x *EntityWatchRequest) Reset() {
+	*x = EntityWatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_entity_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EntityWatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityWatchRequest) ProtoMessage() {}
+
+func (x *EntityWatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_entity_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityWatchRequest.ProtoReflect.Descriptor instead.
+func (*EntityWatchRequest) Descriptor() ([]byte, []int) {
+	return file_entity_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EntityWatchRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *EntityWatchRequest) GetKind() []string {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *EntityWatchRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *EntityWatchRequest) GetGrnPrefix() string {
+	if x != nil {
+		return x.GrnPrefix
+	}
+	return ""
+}
+
+func (x *EntityWatchRequest) GetWithBody() bool {
+	if x != nil {
+		return x.WithBody
+	}
+	return false
+}
+
+func (x *EntityWatchRequest) GetWithSummary() bool {
+	if x != nil {
+		return x.WithSummary
+	}
+	return false
+}
+
+type EntityWatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Resource version of the change.  Pass this as `since` to resume watching
+	ResourceVersion int64 `protobuf:"varint,1,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	// The changed entity.  Deleted entities only include the identifier and version
+	Entity *Entity `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
+	// Folder UID
+	Folder string `protobuf:"bytes,3,opt,name=folder,proto3" json:"folder,omitempty"`
+	// What happened to the entity
+	Action EntityWatchResponse_Action `protobuf:"varint,4,opt,name=action,proto3,enum=entity.EntityWatchResponse_Action" json:"action,omitempty"`
+}
+
+func (x *EntityWatchResponse) Reset() {
+	*x = EntityWatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_entity_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EntityWatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityWatchResponse) ProtoMessage() {}
+
+func (x *EntityWatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_entity_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityWatchResponse.ProtoReflect.Descriptor instead.
+func (*EntityWatchResponse) Descriptor() ([]byte, []int) {
+	return file_entity_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EntityWatchResponse) GetResourceVersion() int64 {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return 0
+}
+
+func (x *EntityWatchResponse) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+func (x *EntityWatchResponse) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *EntityWatchResponse) GetAction() EntityWatchResponse_Action {
+	if x != nil {
+		return x.Action
+	}
+	return EntityWatchResponse_UNKNOWN
+}
+
 var File_entity_proto protoreflect.FileDescriptor
 
 var file_entity_proto_rawDesc = []byte{
@@ -1795,45 +2017,77 @@ var file_entity_proto_rawDesc = []byte{
 	0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65,
 	0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
-	0x65, 0x6e, 0x32, 0xee, 0x03, 0x0a, 0x0b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x6f,
-	0x72, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x52, 0x65, 0x61, 0x64, 0x12, 0x19, 0x2e, 0x65, 0x6e, 0x74,
-	0x69, 0x74, 0x79, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x4c, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
-	0x61, 0x64, 0x12, 0x1e, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x42, 0x61, 0x74, 0x63,
-	0x68, 0x52, 0x65, 0x61, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x42, 0x61, 0x74, 0x63,
-	0x68, 0x52, 0x65, 0x61, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12,
-	0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x07, 0x48, 0x69,
-	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74,
-	0x69, 0x74, 0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x43, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x1b, 0x2e, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x65, 0x61, 0x72,
-	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x65, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x41,
-	0x64, 0x6d, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x32, 0x5e, 0x0a, 0x10, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x6f,
-	0x72, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x4a, 0x0a, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x41,
-	0x64, 0x6d, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x0b, 0x5a, 0x09, 0x2e, 0x2f, 0x3b, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x6e, 0x22, 0xb5, 0x01, 0x0a, 0x12, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x67,
+	0x72, 0x6e, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x67, 0x72, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x69,
+	0x74, 0x68, 0x5f, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x77,
+	0x69, 0x74, 0x68, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x5f,
+	0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x77,
+	0x69, 0x74, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0xfa, 0x01, 0x0a, 0x13, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a,
+	0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x3a, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3c, 0x0a, 0x06, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a,
+	0x07, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x32, 0xb2, 0x04, 0x0a, 0x0b, 0x45, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x52, 0x65, 0x61, 0x64, 0x12,
+	0x19, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x45, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x4c, 0x0a, 0x09, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x12, 0x1e, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x57, 0x72, 0x69, 0x74,
+	0x65, 0x12, 0x1a, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x06, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1c, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x46, 0x0a, 0x07, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x2e, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x12, 0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x05,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1a, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01,
+	0x12, 0x4a, 0x0a, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1f,
+	0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x57, 0x72, 0x69,
+	0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x5e, 0x0a, 0x10,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x12, 0x4a, 0x0a, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1f,
+	0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x57, 0x72, 0x69,
+	0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0b, 0x5a, 0x09,
+	0x2e, 0x2f, 0x3b, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -1848,73 +2102,80 @@ func file_entity_proto_rawDescGZIP() []byte {
 	return file_entity_proto_rawDescData
 }
 
-var file_entity_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_entity_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_entity_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_entity_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_entity_proto_goTypes = []interface{}{
 	(WriteEntityResponse_Status)(0), // 0: entity.WriteEntityResponse.Status
-	(*GRN)(nil),                     // 1: entity.GRN
-	(*Entity)(nil),                  // 2: entity.Entity
-	(*EntityOriginInfo)(nil),        // 3: entity.EntityOriginInfo
-	(*EntityErrorInfo)(nil),         // 4: entity.EntityErrorInfo
-	(*EntityVersionInfo)(nil),       // 5: entity.EntityVersionInfo
-	(*ReadEntityRequest)(nil),       // 6: entity.ReadEntityRequest
-	(*BatchReadEntityRequest)(nil),  // 7: entity.BatchReadEntityRequest
-	(*BatchReadEntityResponse)(nil), // 8: entity.BatchReadEntityResponse
-	(*WriteEntityRequest)(nil),      // 9: entity.WriteEntityRequest
-	(*AdminWriteEntityRequest)(nil), // 10: entity.AdminWriteEntityRequest
-	(*WriteEntityResponse)(nil),     // 11: entity.WriteEntityResponse
-	(*DeleteEntityRequest)(nil),     // 12: entity.DeleteEntityRequest
-	(*DeleteEntityResponse)(nil),    // 13: entity.DeleteEntityResponse
-	(*EntityHistoryRequest)(nil),    // 14: entity.EntityHistoryRequest
-	(*EntityHistoryResponse)(nil),   // 15: entity.EntityHistoryResponse
-	(*EntitySearchRequest)(nil),     // 16: entity.EntitySearchRequest
-	(*EntitySearchResult)(nil),      // 17: entity.EntitySearchResult
-	(*EntitySearchResponse)(nil),    // 18: entity.EntitySearchResponse
-	nil,                             // 19: entity.EntitySearchRequest.LabelsEntry
-	nil,                             // 20: entity.EntitySearchResult.LabelsEntry
+	(EntityWatchResponse_Action)(0), // 1: entity.EntityWatchResponse.Action
+	(*GRN)(nil),                     // 2: entity.GRN
+	(*Entity)(nil),                  // 3: entity.Entity
+	(*EntityOriginInfo)(nil),        // 4: entity.EntityOriginInfo
+	(*EntityErrorInfo)(nil),         // 5: entity.EntityErrorInfo
+	(*EntityVersionInfo)(nil),       // 6: entity.EntityVersionInfo
+	(*ReadEntityRequest)(nil),       // 7: entity.ReadEntityRequest
+	(*BatchReadEntityRequest)(nil),  // 8: entity.BatchReadEntityRequest
+	(*BatchReadEntityResponse)(nil), // 9: entity.BatchReadEntityResponse
+	(*WriteEntityRequest)(nil),      // 10: entity.WriteEntityRequest
+	(*AdminWriteEntityRequest)(nil), // 11: entity.AdminWriteEntityRequest
+	(*WriteEntityResponse)(nil),     // 12: entity.WriteEntityResponse
+	(*DeleteEntityRequest)(nil),     // 13: entity.DeleteEntityRequest
+	(*DeleteEntityResponse)(nil),    // 14: entity.DeleteEntityResponse
+	(*EntityHistoryRequest)(nil),    // 15: entity.EntityHistoryRequest
+	(*EntityHistoryResponse)(nil),   // 16: entity.EntityHistoryResponse
+	(*EntitySearchRequest)(nil),     // 17: entity.EntitySearchRequest
+	(*EntitySearchResult)(nil),      // 18: entity.EntitySearchResult
+	(*EntitySearchResponse)(nil),    // 19: entity.EntitySearchResponse
+	(*EntityWatchRequest)(nil),      // 20: entity.EntityWatchRequest
+	(*EntityWatchResponse)(nil),     // 21: entity.EntityWatchResponse
+	nil,                             // 22: entity.EntitySearchRequest.LabelsEntry
+	nil,                             // 23: entity.EntitySearchResult.LabelsEntry
 }
 var file_entity_proto_depIdxs = []int32{
-	1,  // 0: entity.Entity.GRN:type_name -> entity.GRN
-	3,  // 1: entity.Entity.origin:type_name -> entity.EntityOriginInfo
-	1,  // 2: entity.ReadEntityRequest.GRN:type_name -> entity.GRN
-	6,  // 3: entity.BatchReadEntityRequest.batch:type_name -> entity.ReadEntityRequest
-	2,  // 4: entity.BatchReadEntityResponse.results:type_name -> entity.Entity
-	1,  // 5: entity.WriteEntityRequest.GRN:type_name -> entity.GRN
-	1,  // 6: entity.AdminWriteEntityRequest.GRN:type_name -> entity.GRN
-	3,  // 7: entity.AdminWriteEntityRequest.origin:type_name -> entity.EntityOriginInfo
-	4,  // 8: entity.WriteEntityResponse.error:type_name -> entity.EntityErrorInfo
-	1,  // 9: entity.WriteEntityResponse.GRN:type_name -> entity.GRN
-	5,  // 10: entity.WriteEntityResponse.entity:type_name -> entity.EntityVersionInfo
+	2,  // 0: entity.Entity.GRN:type_name -> entity.GRN
+	4,  // 1: entity.Entity.origin:type_name -> entity.EntityOriginInfo
+	2,  // 2: entity.ReadEntityRequest.GRN:type_name -> entity.GRN
+	7,  // 3: entity.BatchReadEntityRequest.batch:type_name -> entity.ReadEntityRequest
+	3,  // 4: entity.BatchReadEntityResponse.results:type_name -> entity.Entity
+	2,  // 5: entity.WriteEntityRequest.GRN:type_name -> entity.GRN
+	2,  // 6: entity.AdminWriteEntityRequest.GRN:type_name -> entity.GRN
+	4,  // 7: entity.AdminWriteEntityRequest.origin:type_name -> entity.EntityOriginInfo
+	5,  // 8: entity.WriteEntityResponse.error:type_name -> entity.EntityErrorInfo
+	2,  // 9: entity.WriteEntityResponse.GRN:type_name -> entity.GRN
+	6,  // 10: entity.WriteEntityResponse.entity:type_name -> entity.EntityVersionInfo
 	0,  // 11: entity.WriteEntityResponse.status:type_name -> entity.WriteEntityResponse.Status
-	1,  // 12: entity.DeleteEntityRequest.GRN:type_name -> entity.GRN
-	1,  // 13: entity.EntityHistoryRequest.GRN:type_name -> entity.GRN
-	1,  // 14: entity.EntityHistoryResponse.GRN:type_name -> entity.GRN
-	5,  // 15: entity.EntityHistoryResponse.versions:type_name -> entity.EntityVersionInfo
-	19, // 16: entity.EntitySearchRequest.labels:type_name -> entity.EntitySearchRequest.LabelsEntry
-	1,  // 17: entity.EntitySearchResult.GRN:type_name -> entity.GRN
-	20, // 18: entity.EntitySearchResult.labels:type_name -> entity.EntitySearchResult.LabelsEntry
-	17, // 19: entity.EntitySearchResponse.results:type_name -> entity.EntitySearchResult
-	6,  // 20: entity.EntityStore.Read:input_type -> entity.ReadEntityRequest
-	7,  // 21: entity.EntityStore.BatchRead:input_type -> entity.BatchReadEntityRequest
-	9,  // 22: entity.EntityStore.Write:input_type -> entity.WriteEntityRequest
-	12, // 23: entity.EntityStore.Delete:input_type -> entity.DeleteEntityRequest
-	14, // 24: entity.EntityStore.History:input_type -> entity.EntityHistoryRequest
-	16, // 25: entity.EntityStore.Search:input_type -> entity.EntitySearchRequest
-	10, // 26: entity.EntityStore.AdminWrite:input_type -> entity.AdminWriteEntityRequest
-	10, // 27: entity.EntityStoreAdmin.AdminWrite:input_type -> entity.AdminWriteEntityRequest
-	2,  // 28: entity.EntityStore.Read:output_type -> entity.Entity
-	8,  // 29: entity.EntityStore.BatchRead:output_type -> entity.BatchReadEntityResponse
-	11, // 30: entity.EntityStore.Write:output_type -> entity.WriteEntityResponse
-	13, // 31: entity.EntityStore.Delete:output_type -> entity.DeleteEntityResponse
-	15, // 32: entity.EntityStore.History:output_type -> entity.EntityHistoryResponse
-	18, // 33: entity.EntityStore.Search:output_type -> entity.EntitySearchResponse
-	11, // 34: entity.EntityStore.AdminWrite:output_type -> entity.WriteEntityResponse
-	11, // 35: entity.EntityStoreAdmin.AdminWrite:output_type -> entity.WriteEntityResponse
-	28, // [28:36] is the sub-list for method output_type
-	20, // [20:28] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	2,  // 12: entity.DeleteEntityRequest.GRN:type_name -> entity.GRN
+	2,  // 13: entity.EntityHistoryRequest.GRN:type_name -> entity.GRN
+	2,  // 14: entity.EntityHistoryResponse.GRN:type_name -> entity.GRN
+	6,  // 15: entity.EntityHistoryResponse.versions:type_name -> entity.EntityVersionInfo
+	22, // 16: entity.EntitySearchRequest.labels:type_name -> entity.EntitySearchRequest.LabelsEntry
+	2,  // 17: entity.EntitySearchResult.GRN:type_name -> entity.GRN
+	23, // 18: entity.EntitySearchResult.labels:type_name -> entity.EntitySearchResult.LabelsEntry
+	18, // 19: entity.EntitySearchResponse.results:type_name -> entity.EntitySearchResult
+	3,  // 20: entity.EntityWatchResponse.entity:type_name -> entity.Entity
+	1,  // 21: entity.EntityWatchResponse.action:type_name -> entity.EntityWatchResponse.Action
+	7,  // 22: entity.EntityStore.Read:input_type -> entity.ReadEntityRequest
+	8,  // 23: entity.EntityStore.BatchRead:input_type -> entity.BatchReadEntityRequest
+	10, // 24: entity.EntityStore.Write:input_type -> entity.WriteEntityRequest
+	13, // 25: entity.EntityStore.Delete:input_type -> entity.DeleteEntityRequest
+	15, // 26: entity.EntityStore.History:input_type -> entity.EntityHistoryRequest
+	17, // 27: entity.EntityStore.Search:input_type -> entity.EntitySearchRequest
+	20, // 28: entity.EntityStore.Watch:input_type -> entity.EntityWatchRequest
+	11, // 29: entity.EntityStore.AdminWrite:input_type -> entity.AdminWriteEntityRequest
+	11, // 30: entity.EntityStoreAdmin.AdminWrite:input_type -> entity.AdminWriteEntityRequest
+	3,  // 31: entity.EntityStore.Read:output_type -> entity.Entity
+	9,  // 32: entity.EntityStore.BatchRead:output_type -> entity.BatchReadEntityResponse
+	12, // 33: entity.EntityStore.Write:output_type -> entity.WriteEntityResponse
+	14, // 34: entity.EntityStore.Delete:output_type -> entity.DeleteEntityResponse
+	16, // 35: entity.EntityStore.History:output_type -> entity.EntityHistoryResponse
+	19, // 36: entity.EntityStore.Search:output_type -> entity.EntitySearchResponse
+	21, // 37: entity.EntityStore.Watch:output_type -> entity.EntityWatchResponse
+	12, // 38: entity.EntityStore.AdminWrite:output_type -> entity.WriteEntityResponse
+	12, // 39: entity.EntityStoreAdmin.AdminWrite:output_type -> entity.WriteEntityResponse
+	31, // [31:40] is the sub-list for method output_type
+	22, // [22:31] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_entity_proto_init() }
@@ -2139,14 +2400,38 @@ func file_entity_proto_init() {
 				return nil
 			}
 		}
+		file_entity_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EntityWatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_entity_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EntityWatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_entity_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   20,
+			NumEnums:      2,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
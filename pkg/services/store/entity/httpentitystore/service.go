@@ -1,6 +1,8 @@
 package httpentitystore
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/store/kind"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
+	"google.golang.org/grpc"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -52,6 +55,7 @@ func (s *httpEntityStore) RegisterHTTPRoutes(route routing.RouteRegister) {
 	route.Get("/history/:kind/:uid", reqGrafanaAdmin, routing.Wrap(s.doGetHistory))
 	route.Get("/list/:uid", reqGrafanaAdmin, routing.Wrap(s.doListFolder)) // Simplified version of search -- path is prefix
 	route.Get("/search", reqGrafanaAdmin, routing.Wrap(s.doSearch))
+	route.Get("/watch", reqGrafanaAdmin, s.doWatch) // streams newline delimited JSON
 
 	// File upload
 	route.Post("/upload", reqGrafanaAdmin, routing.Wrap(s.doUpload))
@@ -333,6 +337,61 @@ func (s *httpEntityStore) doSearch(c *models.ReqContext) response.Response {
 	return response.JSON(200, rsp)
 }
 
+// Writes each watch event as a line of JSON
+type httpWatchServer struct {
+	grpc.ServerStream
+	ctx context.Context
+	rw  web.ResponseWriter
+}
+
+func (w *httpWatchServer) Context() context.Context {
+	return w.ctx
+}
+
+func (w *httpWatchServer) Send(rsp *entity.EntityWatchResponse) error {
+	b, err := json.Marshal(rsp)
+	if err != nil {
+		return err
+	}
+	if _, err := w.rw.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	w.rw.Flush()
+	return nil
+}
+
+func (s *httpEntityStore) doWatch(c *models.ReqContext) {
+	vals := c.Req.URL.Query()
+
+	req := &entity.EntityWatchRequest{
+		WithBody:    asBoolean("body", vals, false),
+		WithSummary: asBoolean("summary", vals, false),
+		Kind:        vals["kind"],
+		Folder:      vals.Get("folder"),
+		GrnPrefix:   vals.Get("prefix"),
+	}
+	if vals.Has("since") {
+		since, err := strconv.ParseInt(vals.Get("since"), 10, 64)
+		if err != nil {
+			c.JsonApiErr(400, "bad since", err)
+			return
+		}
+		req.Since = since
+	}
+
+	c.Resp.Header().Set("Content-Type", "application/x-ndjson")
+	c.Resp.Header().Set("Cache-Control", "no-cache")
+	c.Resp.WriteHeader(200)
+	c.Resp.Flush()
+
+	err := s.store.Watch(req, &httpWatchServer{ctx: c.Req.Context(), rw: c.Resp})
+	if err != nil {
+		s.log.Error("error watching entities", "error", err)
+		b, _ := json.Marshal(map[string]string{"error": err.Error()})
+		_, _ = c.Resp.Write(append(b, '\n'))
+	}
+}
+
 func asBoolean(key string, vals url.Values, defaultValue bool) bool {
 	v, ok := vals[key]
 	if !ok {
@@ -12,6 +12,7 @@ import (
 func init() { //nolint:gochecknoinits
 	jsoniter.RegisterTypeEncoder("entity.EntitySearchResult", &searchResultCodec{})
 	jsoniter.RegisterTypeEncoder("entity.WriteEntityResponse", &writeResponseCodec{})
+	jsoniter.RegisterTypeEncoder("entity.EntityWatchResponse", &watchResponseCodec{})
 
 	jsoniter.RegisterTypeEncoder("entity.Entity", &rawEntityCodec{})
 	jsoniter.RegisterTypeDecoder("entity.Entity", &rawEntityCodec{})
@@ -292,3 +293,37 @@ func (codec *writeResponseCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Str
 	}
 	stream.WriteObjectEnd()
 }
+
+type watchResponseCodec struct{}
+
+func (obj *EntityWatchResponse) MarshalJSON() ([]byte, error) {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.Marshal(obj)
+}
+
+func (codec *watchResponseCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	f := (*EntityWatchResponse)(ptr)
+	return f == nil
+}
+
+func (codec *watchResponseCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	obj := (*EntityWatchResponse)(ptr)
+	stream.WriteObjectStart()
+	stream.WriteObjectField("resourceVersion")
+	stream.WriteInt64(obj.ResourceVersion)
+	stream.WriteMore()
+	stream.WriteObjectField("action")
+	stream.WriteString(obj.Action.String())
+
+	if obj.Folder != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("folder")
+		stream.WriteString(obj.Folder)
+	}
+	if obj.Entity != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("entity")
+		stream.WriteVal(obj.Entity)
+	}
+	stream.WriteObjectEnd()
+}
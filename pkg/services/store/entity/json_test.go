@@ -46,3 +46,33 @@ func TestRawEncoders(t *testing.T) {
 	err = json.Unmarshal(b, copy)
 	require.NoError(t, err)
 }
+
+func TestWatchResponseEncoder(t *testing.T) {
+	rsp := &EntityWatchResponse{
+		ResourceVersion: 12,
+		Action:          EntityWatchResponse_DELETED,
+		Folder:          "f",
+		Entity: &Entity{
+			GRN: &GRN{
+				UID:  "a",
+				Kind: "b",
+			},
+			Version: "3",
+		},
+	}
+
+	b, err := json.Marshal(rsp)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"resourceVersion": 12,
+		"action": "DELETED",
+		"folder": "f",
+		"entity": {
+		  "GRN": {
+		    "kind": "b",
+		    "UID": "a"
+		  },
+		  "version": "3"
+		}
+	  }`, string(b))
+}
@@ -7,6 +7,7 @@ type selectQuery struct {
 	from     string   // FROM object
 	limit    int
 	oneExtra bool
+	orderBy  []string // ORDER BY xyz
 
 	where []string
 	args  []interface{}
@@ -17,6 +18,17 @@ func (q *selectQuery) addWhere(f string, val interface{}) {
 	q.where = append(q.where, f+"=?")
 }
 
+func (q *selectQuery) addWhereGreater(f string, val interface{}) {
+	q.args = append(q.args, val)
+	q.where = append(q.where, f+">?")
+}
+
+// The LIKE wildcards in the prefix are not escaped, so results may include more than the prefix
+func (q *selectQuery) addWherePrefix(f string, prefix string) {
+	q.args = append(q.args, prefix+"%")
+	q.where = append(q.where, f+" LIKE ?")
+}
+
 func (q *selectQuery) addWhereInSubquery(f string, subquery string, subqueryArgs []interface{}) {
 	q.args = append(q.args, subqueryArgs...)
 	q.where = append(q.where, f+" IN ("+subquery+")")
@@ -62,6 +74,11 @@ func (q *selectQuery) toQuery() (string, []interface{}) {
 		}
 	}
 
+	if len(q.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(q.orderBy, ","))
+	}
+
 	if q.limit > 0 || q.oneExtra {
 		limit := q.limit
 		if limit < 1 {
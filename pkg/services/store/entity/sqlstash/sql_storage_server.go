@@ -42,6 +42,11 @@ type sqlEntityServer struct {
 	sess     *session.SessionDB
 	kinds    kind.KindRegistry
 	resolver resolver.EntityReferenceResolver
+
+	// How often watchers check for new events
+	watchInterval time.Duration
+	// How long watchers look for events which are committed late
+	watchGracePeriod time.Duration
 }
 
 func getReadSelect(r *entity.ReadEntityRequest) string {
@@ -445,7 +450,10 @@ func (s *sqlEntityServer) AdminWrite(ctx context.Context, r *entity.AdminWriteEn
 				origin.Source, origin.Key, timestamp,
 				oid,
 			)
-			return err
+			if err != nil {
+				return err
+			}
+			return addEvent(ctx, tx, oid, entity.EntityWatchResponse_UPDATED, updatedAt, versionInfo.UpdatedBy)
 		}
 
 		if createdAt < 1000 {
@@ -475,7 +483,10 @@ func (s *sqlEntityServer) AdminWrite(ctx context.Context, r *entity.AdminWriteEn
 			summary.labels, summary.fields, summary.errors,
 			origin.Source, origin.Key, origin.Time,
 		)
-		return err
+		if err != nil {
+			return err
+		}
+		return addEvent(ctx, tx, oid, entity.EntityWatchResponse_CREATED, updatedAt, versionInfo.UpdatedBy)
 	})
 	rsp.SummaryJson = summary.marshaled
 	if err != nil {
@@ -560,9 +571,20 @@ func (s *sqlEntityServer) Delete(ctx context.Context, r *entity.DeleteEntityRequ
 		return nil, err
 	}
 
+	modifier, err := appcontext.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oid := grn.ToGRNString()
+
 	rsp := &entity.DeleteEntityResponse{}
 	err = s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
-		rsp.OK, err = doDelete(ctx, tx, grn.ToGRNString())
+		// Record the event first -- it needs the folder and version of the deleted entity
+		err = addEvent(ctx, tx, oid, entity.EntityWatchResponse_DELETED, time.Now().UnixMilli(), store.GetUserIDString(modifier))
+		if err != nil {
+			return err
+		}
+		rsp.OK, err = doDelete(ctx, tx, oid)
 		return err
 	})
 	return rsp, err
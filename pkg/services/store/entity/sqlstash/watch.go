@@ -0,0 +1,270 @@
+package sqlstash
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/sqlstore/session"
+	"github.com/grafana/grafana/pkg/services/store/entity"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	watchBatchSize       = 100
+
+	// defaultWatchGracePeriod is how long watchers keep looking for events with a lower resource version than the
+	// ones they already sent. Resource versions are allocated on insert, so a transaction which commits late adds
+	// an event below the latest one.
+	defaultWatchGracePeriod = 10 * time.Second
+
+	// EventRetention is how long changes are kept in the `entity_change` table. Watchers can't resume from a
+	// resource version which is older.
+	EventRetention = 24 * time.Hour
+)
+
+type entityEvent struct {
+	resourceVersion int64
+	grn             *entity.GRN
+	oid             string
+	folder          string
+	version         string
+	action          entity.EntityWatchResponse_Action
+	updatedAt       int64
+	updatedBy       string
+	createdAt       int64
+}
+
+// addEvent records a change to the entity in the `entity_change` table so it can be watched.
+// Must be called while the entity exists in the `entity` table
+func addEvent(ctx context.Context, tx *session.SessionTx, grn string, action entity.EntityWatchResponse_Action, updatedAt int64, updatedBy string) error {
+	rows, err := tx.Query(ctx, "SELECT tenant_id,kind,uid,folder,version FROM entity WHERE grn=?", grn)
+	if err != nil {
+		return err
+	}
+	if !rows.Next() {
+		return rows.Close() // nothing to record
+	}
+
+	e := &entityEvent{grn: &entity.GRN{}}
+	err = rows.Scan(&e.grn.TenantId, &e.grn.Kind, &e.grn.UID, &e.folder, &e.version)
+	_ = rows.Close()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "INSERT INTO entity_change ("+
+		"grn, tenant_id, kind, uid, folder, version, action, updated_at, updated_by, created_at) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		grn, e.grn.TenantId, e.grn.Kind, e.grn.UID, e.folder, e.version, int64(action), updatedAt, updatedBy, time.Now().UnixMilli(),
+	)
+	return err
+}
+
+// DeleteOldEvents deletes changes which were recorded before olderThan from the `entity_change` table
+func DeleteOldEvents(ctx context.Context, sess *session.SessionDB, olderThan time.Time) (int64, error) {
+	res, err := sess.Exec(ctx, "DELETE FROM entity_change WHERE created_at < ?", olderThan.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Watch streams entity changes until the client disconnects.  Changes are polled from the `entity_change` table
+func (s *sqlEntityServer) Watch(r *entity.EntityWatchRequest, w entity.EntityStore_WatchServer) error {
+	ctx := w.Context()
+	user, err := appcontext.User(ctx)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("missing user in context")
+	}
+
+	interval := s.watchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	gracePeriod := s.watchGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = defaultWatchGracePeriod
+	}
+
+	// The cursor only moves past events which were recorded before the grace period, newer events are kept in sent
+	// so they are not sent again while events below them are looked for.
+	since := r.Since
+	sent := map[int64]bool{}
+	// Without a resource version the watch starts after the existing events, the ones within the grace period are
+	// only marked as sent.
+	skip := since < 1
+	if skip {
+		since, err = s.settledResourceVersion(ctx, user.OrgID, time.Now().Add(-gracePeriod).UnixMilli())
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		settled := time.Now().Add(-gracePeriod).UnixMilli()
+		cursor := since
+		holding := false
+		for {
+			events, err := s.pollEvents(ctx, user.OrgID, cursor, r)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range events {
+				cursor = e.resourceVersion
+				isSettled := !holding && e.createdAt < settled
+				if isSettled {
+					since = e.resourceVersion
+				} else {
+					holding = true
+				}
+				if sent[e.resourceVersion] {
+					if isSettled {
+						delete(sent, e.resourceVersion)
+					}
+					continue
+				}
+				if !isSettled {
+					sent[e.resourceVersion] = true
+				}
+				if skip {
+					continue
+				}
+				if r.GrnPrefix != "" && !strings.HasPrefix(e.oid, r.GrnPrefix) {
+					continue // LIKE matches more than the prefix
+				}
+
+				rsp, err := s.toWatchResponse(ctx, e, r)
+				if err != nil {
+					return err
+				}
+				if err := w.Send(rsp); err != nil {
+					return err
+				}
+			}
+
+			// More events are waiting
+			if len(events) < watchBatchSize {
+				break
+			}
+		}
+		skip = false
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *sqlEntityServer) currentResourceVersion(ctx context.Context, tenantID int64) (int64, error) {
+	return s.queryResourceVersion(ctx, "SELECT MAX(resource_version) FROM entity_change WHERE tenant_id=?", tenantID)
+}
+
+// settledResourceVersion returns the latest resource version of the events recorded before settled
+func (s *sqlEntityServer) settledResourceVersion(ctx context.Context, tenantID int64, settled int64) (int64, error) {
+	return s.queryResourceVersion(ctx, "SELECT MAX(resource_version) FROM entity_change WHERE tenant_id=? AND created_at<?", tenantID, settled)
+}
+
+func (s *sqlEntityServer) queryResourceVersion(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	rows, err := s.sess.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	rv := sql.NullInt64{}
+	if rows.Next() {
+		if err := rows.Scan(&rv); err != nil {
+			return 0, err
+		}
+	}
+	return rv.Int64, nil
+}
+
+func (s *sqlEntityServer) pollEvents(ctx context.Context, tenantID int64, since int64, r *entity.EntityWatchRequest) ([]*entityEvent, error) {
+	eventQuery := selectQuery{
+		fields: []string{
+			"resource_version", "grn", "tenant_id", "kind", "uid",
+			"folder", "version", "action", "updated_at", "updated_by", "created_at",
+		},
+		from:    "entity_change",
+		args:    []interface{}{},
+		limit:   watchBatchSize,
+		orderBy: []string{"resource_version ASC"},
+	}
+	eventQuery.addWhere("tenant_id", tenantID)
+	eventQuery.addWhereGreater("resource_version", since)
+
+	if len(r.Kind) > 0 {
+		eventQuery.addWhereIn("kind", r.Kind)
+	}
+	if r.Folder != "" {
+		eventQuery.addWhere("folder", r.Folder)
+	}
+	if r.GrnPrefix != "" {
+		eventQuery.addWherePrefix("grn", r.GrnPrefix)
+	}
+
+	query, args := eventQuery.toQuery()
+	rows, err := s.sess.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []*entityEvent
+	for rows.Next() {
+		e := &entityEvent{grn: &entity.GRN{}}
+		action := int64(0)
+		err = rows.Scan(&e.resourceVersion, &e.oid, &e.grn.TenantId, &e.grn.Kind, &e.grn.UID,
+			&e.folder, &e.version, &action, &e.updatedAt, &e.updatedBy, &e.createdAt)
+		if err != nil {
+			return nil, err
+		}
+		e.action = entity.EntityWatchResponse_Action(action)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (s *sqlEntityServer) toWatchResponse(ctx context.Context, e *entityEvent, r *entity.EntityWatchRequest) (*entity.EntityWatchResponse, error) {
+	rsp := &entity.EntityWatchResponse{
+		ResourceVersion: e.resourceVersion,
+		Folder:          e.folder,
+		Action:          e.action,
+	}
+
+	// The history is removed with the entity, so deleted entities only include the identifier
+	if e.action != entity.EntityWatchResponse_DELETED && (r.WithBody || r.WithSummary) {
+		found, err := s.readFromHistory(ctx, &entity.ReadEntityRequest{
+			GRN:         e.grn,
+			Version:     e.version,
+			WithBody:    r.WithBody,
+			WithSummary: r.WithSummary,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found.GRN != nil {
+			rsp.Entity = found
+			return rsp, nil
+		}
+	}
+
+	rsp.Entity = &entity.Entity{
+		GRN:       e.grn,
+		Version:   e.version,
+		UpdatedAt: e.updatedAt,
+		UpdatedBy: e.updatedBy,
+	}
+	return rsp, nil
+}
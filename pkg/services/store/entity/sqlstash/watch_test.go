@@ -0,0 +1,190 @@
+package sqlstash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/store/entity"
+	"github.com/grafana/grafana/pkg/services/store/kind"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeWatchServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *entity.EntityWatchResponse
+}
+
+func (f *fakeWatchServer) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchServer) Send(rsp *entity.EntityWatchResponse) error {
+	f.events <- rsp
+	return nil
+}
+
+func startWatch(t *testing.T, ctx context.Context, s *sqlEntityServer, r *entity.EntityWatchRequest) chan *entity.EntityWatchResponse {
+	t.Helper()
+	ctx, cancel := context.WithCancel(ctx)
+	w := &fakeWatchServer{ctx: ctx, events: make(chan *entity.EntityWatchResponse, 100)}
+	done := make(chan error)
+	go func() {
+		done <- s.Watch(r, w)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return w.events
+}
+
+func nextEvent(t *testing.T, events chan *entity.EntityWatchResponse) *entity.EntityWatchResponse {
+	t.Helper()
+	select {
+	case rsp := <-events:
+		return rsp
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return nil
+}
+
+func TestIntegrationEntityWatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	s := &sqlEntityServer{
+		sess:          db.InitTestDB(t).GetSqlxSession(),
+		log:           log.New("sql-entity-server"),
+		kinds:         kind.NewKindRegistry(),
+		watchInterval: 10 * time.Millisecond,
+	}
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{UserID: 1, OrgID: 1})
+
+	write := func(t *testing.T, uid string, folder string, body string) *entity.WriteEntityResponse {
+		t.Helper()
+		rsp, err := s.Write(ctx, &entity.WriteEntityRequest{
+			GRN:    &entity.GRN{Kind: models.StandardKindJSONObj, UID: uid},
+			Folder: folder,
+			Body:   []byte(body),
+		})
+		require.NoError(t, err)
+		return rsp
+	}
+
+	t.Run("should stream changes after the resource version", func(t *testing.T) {
+		write(t, "before", "", `{"a":1}`)
+		since, err := s.currentResourceVersion(ctx, 1)
+		require.NoError(t, err)
+		require.Greater(t, since, int64(0))
+
+		events := startWatch(t, ctx, s, &entity.EntityWatchRequest{Since: since, WithBody: true})
+
+		write(t, "watched", "", `{"a":1}`)
+		created := nextEvent(t, events)
+		require.Equal(t, entity.EntityWatchResponse_CREATED, created.Action)
+		require.Equal(t, "watched", created.Entity.GRN.UID)
+		require.Equal(t, "1", created.Entity.Version)
+		require.JSONEq(t, `{"a":1}`, string(created.Entity.Body))
+
+		write(t, "watched", "", `{"a":2}`)
+		updated := nextEvent(t, events)
+		require.Equal(t, entity.EntityWatchResponse_UPDATED, updated.Action)
+		require.Equal(t, "2", updated.Entity.Version)
+		require.JSONEq(t, `{"a":2}`, string(updated.Entity.Body))
+		require.Greater(t, updated.ResourceVersion, created.ResourceVersion)
+
+		_, err = s.Delete(ctx, &entity.DeleteEntityRequest{
+			GRN: &entity.GRN{Kind: models.StandardKindJSONObj, UID: "watched"},
+		})
+		require.NoError(t, err)
+
+		// history is removed with the entity
+		deleted := nextEvent(t, events)
+		require.Equal(t, entity.EntityWatchResponse_DELETED, deleted.Action)
+		require.Equal(t, "watched", deleted.Entity.GRN.UID)
+		require.Equal(t, "2", deleted.Entity.Version)
+		require.Nil(t, deleted.Entity.Body)
+	})
+
+	t.Run("should resume from a resource version and filter events", func(t *testing.T) {
+		first := write(t, "resume-a", "folder-a", `{"b":1}`)
+		require.Equal(t, entity.WriteEntityResponse_CREATED, first.Status)
+		write(t, "resume-b", "folder-b", `{"b":1}`)
+		write(t, "other", "folder-a", `{"b":1}`)
+
+		since, err := s.currentResourceVersion(ctx, 1)
+		require.NoError(t, err)
+		since -= 3
+
+		events := startWatch(t, ctx, s, &entity.EntityWatchRequest{Since: since, Folder: "folder-a"})
+		rsp := nextEvent(t, events)
+		require.Equal(t, "resume-a", rsp.Entity.GRN.UID)
+		require.Equal(t, "folder-a", rsp.Folder)
+		require.Nil(t, rsp.Entity.Body)
+		require.Equal(t, "other", nextEvent(t, events).Entity.GRN.UID)
+
+		events = startWatch(t, ctx, s, &entity.EntityWatchRequest{Since: since, GrnPrefix: "grn:1/jsonobj/resume-"})
+		require.Equal(t, "resume-a", nextEvent(t, events).Entity.GRN.UID)
+		require.Equal(t, "resume-b", nextEvent(t, events).Entity.GRN.UID)
+
+		events = startWatch(t, ctx, s, &entity.EntityWatchRequest{Since: since, Kind: []string{models.StandardKindDashboard}})
+		select {
+		case rsp := <-events:
+			t.Fatalf("unexpected event: %v", rsp)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+	insertEvent := func(t *testing.T, rv int64, uid string, createdAt time.Time) {
+		t.Helper()
+		_, err := s.sess.Exec(ctx, "INSERT INTO entity_change ("+
+			"resource_version, grn, tenant_id, kind, uid, folder, version, action, updated_at, updated_by, created_at) "+
+			"VALUES (?, ?, 1, ?, ?, '', '1', ?, 0, '', ?)",
+			rv, "grn:1/jsonobj/"+uid, models.StandardKindJSONObj, uid, int64(entity.EntityWatchResponse_CREATED), createdAt.UnixMilli())
+		require.NoError(t, err)
+	}
+
+	t.Run("should stream events which are committed after events with a higher resource version", func(t *testing.T) {
+		since, err := s.currentResourceVersion(ctx, 1)
+		require.NoError(t, err)
+
+		events := startWatch(t, ctx, s, &entity.EntityWatchRequest{Since: since})
+		insertEvent(t, since+10, "late-b", time.Now())
+		require.Equal(t, "late-b", nextEvent(t, events).Entity.GRN.UID)
+
+		insertEvent(t, since+5, "late-a", time.Now())
+		rsp := nextEvent(t, events)
+		require.Equal(t, "late-a", rsp.Entity.GRN.UID)
+		require.Equal(t, since+5, rsp.ResourceVersion)
+
+		// events are not sent twice
+		select {
+		case rsp := <-events:
+			t.Fatalf("unexpected event: %v", rsp)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("should delete old events", func(t *testing.T) {
+		since, err := s.currentResourceVersion(ctx, 1)
+		require.NoError(t, err)
+		insertEvent(t, since+1, "old", time.Now().Add(-2*EventRetention))
+
+		deleted, err := DeleteOldEvents(ctx, s.sess, time.Now().Add(-EventRetention))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+		current, err := s.currentResourceVersion(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, since, current)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/sqlstore/session"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -23,12 +24,20 @@ const (
 type EntityType string
 
 const (
-	EntityTypeDashboard EntityType = "dashboard"
-	EntityTypeFolder    EntityType = "folder"
-	EntityTypeImage     EntityType = "image"
-	EntityTypeJSON      EntityType = "json"
+	EntityTypeDashboard    EntityType = "dashboard"
+	EntityTypeFolder       EntityType = "folder"
+	EntityTypeImage        EntityType = "image"
+	EntityTypeJSON         EntityType = "json"
+	EntityTypeAlertRule    EntityType = "alertrule"
+	EntityTypeDatasource   EntityType = "datasource"
+	EntityTypeLibraryPanel EntityType = "librarypanel"
+	EntityTypePlaylist     EntityType = "playlist"
+	EntityTypeCorrelation  EntityType = "correlation"
 )
 
+// EntityEventsRetention is how long entity events are kept before being cleaned up.
+const EntityEventsRetention = 24 * time.Hour
+
 // CreateDatabaseEntityId creates entityId for entities stored in the existing SQL tables
 func CreateDatabaseEntityId(internalId interface{}, orgId int64, entityType EntityType) string {
 	var internalIdAsString string
@@ -42,6 +51,49 @@ func CreateDatabaseEntityId(internalId interface{}, orgId int64, entityType Enti
 	return fmt.Sprintf("database/%d/%s/%s", orgId, entityType, internalIdAsString)
 }
 
+// NewDatabaseEntityEvent creates an event for a change of an entity stored in the existing SQL tables
+func NewDatabaseEntityEvent(internalId interface{}, orgId int64, entityType EntityType, eventType EntityEventType) *EntityEvent {
+	return &EntityEvent{
+		EventType: eventType,
+		EntityId:  CreateDatabaseEntityId(internalId, orgId, entityType),
+		Created:   time.Now().Unix(),
+	}
+}
+
+// EntityEventsEnabled returns whether changes of the entities stored in the existing SQL tables should be recorded.
+// The events are only consumed (and cleaned up) when the search index is enabled
+func EntityEventsEnabled(features featuremgmt.FeatureToggles) bool {
+	return features != nil && features.IsEnabled(featuremgmt.FlagPanelTitleSearch)
+}
+
+// InsertEntityEvents records the changes of entities stored in the existing SQL tables within the session of the store
+// making them, so the search index can be kept in sync. Nothing is recorded unless entity events are enabled
+func InsertEntityEvents(sess *db.Session, features featuremgmt.FeatureToggles, orgID int64, entityType EntityType, eventType EntityEventType, internalIds ...string) error {
+	if !EntityEventsEnabled(features) {
+		return nil
+	}
+	for _, id := range internalIds {
+		if _, err := sess.Insert(NewDatabaseEntityEvent(id, orgID, entityType, eventType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertEntityEventsTx is InsertEntityEvents for stores using a sqlx transaction
+func InsertEntityEventsTx(ctx context.Context, tx *session.SessionTx, features featuremgmt.FeatureToggles, orgID int64, entityType EntityType, eventType EntityEventType, internalIds ...string) error {
+	if !EntityEventsEnabled(features) {
+		return nil
+	}
+	for _, id := range internalIds {
+		e := NewDatabaseEntityEvent(id, orgID, entityType, eventType)
+		if _, err := tx.Exec(ctx, "INSERT INTO entity_event (entity_id, event_type, created) VALUES (?, ?, ?)", e.EntityId, e.EventType, e.Created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type EntityEvent struct {
 	Id        int64
 	EventType EntityEventType
@@ -132,7 +184,7 @@ func (e *entityEventService) Run(ctx context.Context) error {
 		select {
 		case <-clean.C:
 			go func() {
-				err := e.deleteEventsOlderThan(context.Background(), 24*time.Hour)
+				err := e.deleteEventsOlderThan(context.Background(), EntityEventsRetention)
 				if err != nil {
 					e.log.Info("failed to delete old entity events", "error", err)
 				}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/sqlstore/session"
 )
 
 func saveEvent(ctx context.Context, sql db.DB, cmd SaveEventCmd) error {
@@ -146,6 +148,45 @@ func TestIntegrationEntityEventsService(t *testing.T) {
 	})
 }
 
+func TestIntegrationInsertEntityEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	service := &entityEventService{sql: sqlStore, log: log.New("entity-event-test")}
+
+	t.Run("Should not insert events if they are disabled", func(t *testing.T) {
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			return InsertEntityEvents(sess, featuremgmt.WithFeatures(), 1, EntityTypePlaylist, EntityEventTypeCreate, "a")
+		})
+		require.NoError(t, err)
+		ev, err := service.GetLastEvent(ctx)
+		require.NoError(t, err)
+		require.Nil(t, ev)
+	})
+
+	t.Run("Should insert an event per entity", func(t *testing.T) {
+		features := featuremgmt.WithFeatures(featuremgmt.FlagPanelTitleSearch)
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			return InsertEntityEvents(sess, features, 1, EntityTypePlaylist, EntityEventTypeCreate, "a", "b")
+		})
+		require.NoError(t, err)
+		err = sqlStore.GetSqlxSession().WithTransaction(ctx, func(tx *session.SessionTx) error {
+			return InsertEntityEventsTx(ctx, tx, features, 1, EntityTypePlaylist, EntityEventTypeDelete, "a")
+		})
+		require.NoError(t, err)
+
+		evs, err := service.GetAllEventsAfter(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, evs, 3)
+		require.Equal(t, "database/1/playlist/a", evs[0].EntityId)
+		require.Equal(t, "database/1/playlist/b", evs[1].EntityId)
+		require.Equal(t, "database/1/playlist/a", evs[2].EntityId)
+		require.Equal(t, EntityEventTypeDelete, evs[2].EventType)
+	})
+}
+
 func TestIntegrationCreateDatabaseEntityId(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -351,6 +351,19 @@ func (s *standardStorageService) Read(ctx context.Context, user *user.SignedInUs
 func (s *standardStorageService) Usage(ctx context.Context, ScopeParameters *quota.ScopeParameters) (*quota.Map, error) {
 	u := &quota.Map{}
 
+	// Files uploaded to cloud storages count towards the same quota
+	var count int64
+	for _, root := range s.tree.getRoots(ac.GlobalOrgID) {
+		if reporter, ok := root.(storageUsageReporter); ok {
+			c, err := reporter.countFiles(ctx)
+			if err != nil {
+				grafanaStorageLogger.Warn("failed to count the files of a storage", "prefix", root.Meta().Config.Prefix, "error", err)
+				continue
+			}
+			count += c
+		}
+	}
+
 	err := s.sql.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		type result struct {
 			Count int64
@@ -366,7 +379,7 @@ func (s *standardStorageService) Usage(ctx context.Context, ScopeParameters *quo
 		if err != nil {
 			return err
 		}
-		u.Set(tag, r.Count)
+		u.Set(tag, count+r.Count)
 
 		return nil
 	})
@@ -439,7 +452,7 @@ func (s *standardStorageService) Upload(ctx context.Context, user *user.SignedIn
 }
 
 func (s *standardStorageService) checkFileQuota(ctx context.Context, path string) error {
-	// assumes we are only uploading to the SQL database - TODO: refactor once we introduce object stores
+	// the quota is shared by the SQL database and the cloud storages, see Usage
 	quotaReached, err := s.quotaService.CheckQuotaReached(ctx, QuotaTargetSrv, nil)
 	if err != nil {
 		grafanaStorageLogger.Error("failed while checking upload quota", "path", path, "error", err)
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"gocloud.dev/blob"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+)
+
+var (
+	_ storageRuntime       = &rootStorageBlob{}
+	_ storageUsageReporter = &rootStorageBlob{}
+)
+
+// blobFileCountTTL is how long the number of files in a bucket is cached.
+// Counting lists the whole bucket, so the file quota may lag behind files
+// written by other instances by that long.
+const blobFileCountTTL = 5 * time.Minute
+
+// rootStorageBlob is a storage backed by a cloud blob bucket.
+type rootStorageBlob struct {
+	meta  RootStorageMeta
+	store filestorage.FileStorage
+
+	countMu      sync.Mutex
+	count        int64
+	countExpires time.Time
+	now          func() time.Time
+}
+
+type blobBucketOpener = func(ctx context.Context) (*blob.Bucket, error)
+
+// newBlobStorage creates a storage from a bucket. Notices already set on meta prevent opening the bucket.
+func newBlobStorage(meta RootStorageMeta, scfg RootStorageConfig, folder string, openBucket blobBucketOpener) *rootStorageBlob {
+	meta.Config = scfg
+	if scfg.Prefix == "" {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityError,
+			Text:     "Missing prefix",
+		})
+	}
+	if scfg.Disabled {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     "folder is disabled (in configuration)",
+		})
+	}
+
+	s := &rootStorageBlob{now: time.Now}
+	if meta.Notice == nil {
+		bucket, err := openBucket(context.Background())
+		if err != nil {
+			grafanaStorageLogger.Warn("error loading storage", "prefix", scfg.Prefix, "err", err)
+			meta.Notice = append(meta.Notice, data.Notice{
+				Severity: data.NoticeSeverityError,
+				Text:     "Failed to initialize storage",
+			})
+		} else {
+			s.store = filestorage.NewCdkBlobStorage(grafanaStorageLogger, bucket, blobRootFolder(folder), nil)
+			meta.Ready = true
+		}
+	}
+
+	s.meta = meta
+	return s
+}
+
+// blobRootFolder converts a folder within a bucket to the root folder format of the blob file storage.
+func blobRootFolder(folder string) string {
+	folder = strings.Trim(folder, filestorage.Delimiter)
+	if folder == "" {
+		return ""
+	}
+	return folder + filestorage.Delimiter
+}
+
+func (s *rootStorageBlob) Meta() RootStorageMeta {
+	return s.meta
+}
+
+func (s *rootStorageBlob) Store() filestorage.FileStorage {
+	if s.store == nil {
+		return nil
+	}
+	return &blobFileStorage{FileStorage: s.store, root: s}
+}
+
+func (s *rootStorageBlob) Sync() error {
+	return nil // already in sync
+}
+
+func (s *rootStorageBlob) Write(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error) {
+	if s.meta.ReadOnly {
+		return nil, ErrUnsupportedStorage
+	}
+
+	path := cmd.Path
+	if !strings.HasPrefix(path, filestorage.Delimiter) {
+		path = filestorage.Delimiter + path
+	}
+	err := s.Store().Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     path,
+		Contents: cmd.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &WriteValueResponse{Code: 200}, nil
+}
+
+// countFiles counts the files stored in the bucket folder. The count is
+// cached for blobFileCountTTL.
+func (s *rootStorageBlob) countFiles(ctx context.Context) (int64, error) {
+	if s.store == nil || s.meta.ReadOnly {
+		return 0, nil
+	}
+
+	s.countMu.Lock()
+	defer s.countMu.Unlock()
+	if s.now().Before(s.countExpires) {
+		return s.count, nil
+	}
+
+	count, err := s.listFiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.count = count
+	s.countExpires = s.now().Add(blobFileCountTTL)
+	return count, nil
+}
+
+// invalidateFileCount makes the next countFiles call list the bucket again.
+func (s *rootStorageBlob) invalidateFileCount() {
+	s.countMu.Lock()
+	defer s.countMu.Unlock()
+	s.countExpires = time.Time{}
+}
+
+func (s *rootStorageBlob) listFiles(ctx context.Context) (int64, error) {
+	var count int64
+	paging := &filestorage.Paging{First: 1000}
+	for {
+		resp, err := s.store.List(ctx, filestorage.Delimiter, paging, &filestorage.ListOptions{Recursive: true, WithFiles: true})
+		if err != nil {
+			return 0, err
+		}
+		count += int64(len(resp.Files))
+		if !resp.HasMore {
+			return count, nil
+		}
+		paging.After = resp.LastPath
+	}
+}
+
+// blobFileStorage invalidates the cached number of files of the storage
+// after every change made through it.
+type blobFileStorage struct {
+	filestorage.FileStorage
+	root *rootStorageBlob
+}
+
+func (b *blobFileStorage) Upsert(ctx context.Context, command *filestorage.UpsertFileCommand) error {
+	if err := b.FileStorage.Upsert(ctx, command); err != nil {
+		return err
+	}
+	b.root.invalidateFileCount()
+	return nil
+}
+
+func (b *blobFileStorage) Delete(ctx context.Context, path string) error {
+	if err := b.FileStorage.Delete(ctx, path); err != nil {
+		return err
+	}
+	b.root.invalidateFileCount()
+	return nil
+}
+
+func (b *blobFileStorage) DeleteFolder(ctx context.Context, path string, options *filestorage.DeleteFolderOptions) error {
+	if err := b.FileStorage.DeleteFolder(ctx, path, options); err != nil {
+		return err
+	}
+	b.root.invalidateFileCount()
+	return nil
+}
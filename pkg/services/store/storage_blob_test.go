@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+)
+
+func setupBlobStore(t *testing.T, readOnly bool, bucketURL string, folder string) (*standardStorageService, *rootStorageBlob) {
+	t.Helper()
+	root := newBlobStorage(RootStorageMeta{ReadOnly: readOnly}, RootStorageConfig{Prefix: "cloud"}, folder, func(ctx context.Context) (*blob.Bucket, error) {
+		return blob.OpenBucket(ctx, bucketURL)
+	})
+	require.True(t, root.Meta().Ready)
+
+	store := newStandardStorageService(db.InitTestDB(t), []storageRuntime{root}, func(orgId int64) []storageRuntime {
+		return make([]storageRuntime, 0)
+	}, allowAllAuthService, cfg, nil)
+	store.cfg = &GlobalStorageConfig{}
+	store.quotaService = quotatest.New(false, nil)
+	return store, root
+}
+
+func TestBlobStorage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should upload files and count them towards the file quota", func(t *testing.T) {
+		store, _ := setupBlobStore(t, false, "mem://", "")
+
+		err := store.Upload(ctx, dummyUser, &UploadRequest{
+			EntityType: EntityTypeImage,
+			Contents:   jpgBytes,
+			Path:       "cloud/folder/image.jpg",
+		})
+		require.NoError(t, err)
+
+		file, err := store.Read(ctx, dummyUser, "cloud/folder/image.jpg")
+		require.NoError(t, err)
+		require.NotNil(t, file)
+		require.Equal(t, jpgBytes, file.Contents)
+
+		usage, err := store.Usage(ctx, nil)
+		require.NoError(t, err)
+		tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.GlobalScope)
+		require.NoError(t, err)
+		count, ok := usage.Get(tag)
+		require.True(t, ok)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("Should cache the number of files in the bucket", func(t *testing.T) {
+		_, root := setupBlobStore(t, false, "mem://", "")
+		now := time.Now()
+		root.now = func() time.Time { return now }
+
+		// files written by other instances bypass the storage runtime
+		upsert := func(path string) {
+			t.Helper()
+			require.NoError(t, root.store.Upsert(ctx, &filestorage.UpsertFileCommand{Path: path, Contents: jpgBytes}))
+		}
+		upsert("/a.jpg")
+		count, err := root.countFiles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		upsert("/b.jpg")
+		count, err = root.countFiles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		now = now.Add(blobFileCountTTL)
+		count, err = root.countFiles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+	})
+
+	t.Run("Should count files written and deleted by this instance right away", func(t *testing.T) {
+		store, root := setupBlobStore(t, false, "mem://", "")
+		now := time.Now()
+		root.now = func() time.Time { return now }
+
+		count, err := root.countFiles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+
+		err = store.Upload(ctx, dummyUser, &UploadRequest{
+			EntityType: EntityTypeImage,
+			Contents:   jpgBytes,
+			Path:       "cloud/image.jpg",
+		})
+		require.NoError(t, err)
+		_, err = root.Write(ctx, &WriteValueRequest{Path: "dash.json", Body: []byte("{}")})
+		require.NoError(t, err)
+		count, err = root.countFiles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+
+		require.NoError(t, store.Delete(ctx, dummyUser, "cloud/image.jpg"))
+		count, err = root.countFiles(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("Should skip storages whose files cannot be counted", func(t *testing.T) {
+		store, _ := setupBlobStore(t, false, "mem://", "")
+		require.NoError(t, store.Upload(ctx, dummyUser, &UploadRequest{
+			EntityType: EntityTypeImage,
+			Contents:   jpgBytes,
+			Path:       "cloud/image.jpg",
+		}))
+
+		var bucket *blob.Bucket
+		broken := newBlobStorage(RootStorageMeta{}, RootStorageConfig{Prefix: "broken"}, "", func(ctx context.Context) (*blob.Bucket, error) {
+			var err error
+			bucket, err = blob.OpenBucket(ctx, "mem://")
+			return bucket, err
+		})
+		require.NoError(t, bucket.Close())
+		store.tree.rootsByOrgId[accesscontrol.GlobalOrgID] = append(store.tree.rootsByOrgId[accesscontrol.GlobalOrgID], broken)
+
+		usage, err := store.Usage(ctx, nil)
+		require.NoError(t, err)
+		tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.GlobalScope)
+		require.NoError(t, err)
+		count, ok := usage.Get(tag)
+		require.True(t, ok)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("Should store files in the configured folder of the bucket", func(t *testing.T) {
+		dir := t.TempDir()
+		store, _ := setupBlobStore(t, false, "file://"+dir, "/grafana/files/")
+
+		err := store.Upload(ctx, dummyUser, &UploadRequest{
+			EntityType: EntityTypeImage,
+			Contents:   jpgBytes,
+			Path:       "cloud/image.jpg",
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(filepath.Join(dir, "grafana", "files", "image.jpg"))
+		require.NoError(t, err)
+		require.Equal(t, jpgBytes, body)
+	})
+
+	t.Run("Should not upload files to read only storages", func(t *testing.T) {
+		store, root := setupBlobStore(t, true, "mem://", "")
+
+		err := store.Upload(ctx, dummyUser, &UploadRequest{
+			EntityType: EntityTypeImage,
+			Contents:   jpgBytes,
+			Path:       "cloud/image.jpg",
+		})
+		require.ErrorIs(t, err, ErrUnsupportedStorage)
+
+		_, err = root.Write(ctx, &WriteValueRequest{Path: "dash.json", Body: []byte("{}")})
+		require.ErrorIs(t, err, ErrUnsupportedStorage)
+	})
+}
+
+func TestCloudStorageConfig(t *testing.T) {
+	t.Run("S3 storage resolves and redacts secrets", func(t *testing.T) {
+		t.Setenv("TEST_S3_SECRET_KEY", "secret")
+		s, err := newStorage(RootStorageConfig{
+			Type:     rootStorageTypeS3,
+			Prefix:   "s3",
+			ReadOnly: true,
+			S3: &StorageS3Config{
+				Bucket:    "bucket",
+				Region:    "us-east-1",
+				AccessKey: "access",
+				SecretKey: "$TEST_S3_SECRET_KEY",
+			},
+		}, "")
+		require.NoError(t, err)
+
+		meta := s.Meta()
+		require.True(t, meta.Ready)
+		require.True(t, meta.ReadOnly)
+		require.Empty(t, meta.Notice)
+		require.Equal(t, "********", meta.Config.S3.AccessKey)
+		require.Equal(t, "$TEST_S3_SECRET_KEY", meta.Config.S3.SecretKey)
+	})
+
+	t.Run("S3 storage requires a bucket and existing secrets", func(t *testing.T) {
+		s, err := newStorage(RootStorageConfig{
+			Type:   rootStorageTypeS3,
+			Prefix: "s3",
+			S3:     &StorageS3Config{SecretKey: "$TEST_S3_MISSING_SECRET"},
+		}, "")
+		require.NoError(t, err)
+		require.False(t, s.Meta().Ready)
+		require.Len(t, s.Meta().Notice, 2)
+	})
+
+	t.Run("GCS storage requires a bucket and redacts credentials", func(t *testing.T) {
+		s, err := newStorage(RootStorageConfig{
+			Type:   rootStorageTypeGCS,
+			Prefix: "gcs",
+			GCS:    &StorageGCSConfig{CredentialsJSON: `{"type": "service_account"}`},
+		}, "")
+		require.NoError(t, err)
+		require.False(t, s.Meta().Ready)
+		require.Len(t, s.Meta().Notice, 1)
+		require.Equal(t, "********", s.Meta().Config.GCS.CredentialsJSON)
+	})
+
+	t.Run("Unknown storage types are not supported", func(t *testing.T) {
+		_, err := newStorage(RootStorageConfig{Type: "ftp", Prefix: "ftp"}, "")
+		require.Error(t, err)
+	})
+}
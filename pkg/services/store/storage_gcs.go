@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"os"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/gcsblob"
+	"gocloud.dev/gcp"
+	"golang.org/x/oauth2/google"
+)
+
+const rootStorageTypeGCS = "gcs"
+
+func newGCSStorage(meta RootStorageMeta, scfg RootStorageConfig) *rootStorageBlob {
+	cfg := scfg.GCS
+	if cfg == nil {
+		cfg = &StorageGCSConfig{}
+	}
+	scfg.Type = rootStorageTypeGCS
+	scfg.Disk = nil
+	scfg.Git = nil
+	scfg.SQL = nil
+	scfg.S3 = nil
+
+	// Secrets are never exposed with the storage configuration.
+	redacted := *cfg
+	redacted.CredentialsJSON = redactSecureValue(cfg.CredentialsJSON)
+	scfg.GCS = &redacted
+
+	if cfg.Bucket == "" {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityError,
+			Text:     "Missing bucket configuration",
+		})
+	}
+	credentialsJSON, err := secureValue(cfg.CredentialsJSON)
+	if err != nil {
+		meta.Notice = append(meta.Notice, data.Notice{Severity: data.NoticeSeverityError, Text: err.Error()})
+	}
+
+	return newBlobStorage(meta, scfg, cfg.Folder, func(ctx context.Context) (*blob.Bucket, error) {
+		creds, err := gcsCredentials(ctx, cfg.CredentialsFile, credentialsJSON)
+		if err != nil {
+			return nil, err
+		}
+		client, err := gcp.NewHTTPClient(gcp.DefaultTransport(), gcp.CredentialsTokenSource(creds))
+		if err != nil {
+			return nil, err
+		}
+		return gcsblob.OpenBucket(ctx, client, cfg.Bucket, nil)
+	})
+}
+
+// gcsCredentials reads the service account credentials, falling back to the application default credentials.
+func gcsCredentials(ctx context.Context, credentialsFile string, credentialsJSON string) (*google.Credentials, error) {
+	if credentialsJSON == "" && credentialsFile != "" {
+		// nolint:gosec
+		// The path comes from the storage configuration managed by server admins.
+		body, err := os.ReadFile(credentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		credentialsJSON = string(body)
+	}
+	if credentialsJSON == "" {
+		return gcp.DefaultCredentials(ctx)
+	}
+	return google.CredentialsFromJSON(ctx, []byte(credentialsJSON), "https://www.googleapis.com/auth/devstorage.read_write")
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
@@ -130,15 +129,12 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 				meta.Ready = true // exists!
 				s.root = p
 
-				token := cfg.AccessToken
-				if strings.HasPrefix(token, "$") {
-					token = os.Getenv(token[1:])
-					if token == "" {
-						meta.Notice = append(meta.Notice, data.Notice{
-							Severity: data.NoticeSeverityError,
-							Text:     "Unable to find token environment variable: " + cfg.AccessToken,
-						})
-					}
+				token, err := secureValue(cfg.AccessToken)
+				if err != nil {
+					meta.Notice = append(meta.Notice, data.Notice{
+						Severity: data.NoticeSeverityError,
+						Text:     "Unable to find token environment variable: " + cfg.AccessToken,
+					})
 				}
 
 				if token != "" {
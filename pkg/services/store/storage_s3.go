@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/s3blob"
+)
+
+const rootStorageTypeS3 = "s3"
+
+func newS3Storage(meta RootStorageMeta, scfg RootStorageConfig) *rootStorageBlob {
+	cfg := scfg.S3
+	if cfg == nil {
+		cfg = &StorageS3Config{}
+	}
+	scfg.Type = rootStorageTypeS3
+	scfg.Disk = nil
+	scfg.Git = nil
+	scfg.SQL = nil
+	scfg.GCS = nil
+
+	// Secrets are never exposed with the storage configuration.
+	redacted := *cfg
+	redacted.AccessKey = redactSecureValue(cfg.AccessKey)
+	redacted.SecretKey = redactSecureValue(cfg.SecretKey)
+	scfg.S3 = &redacted
+
+	if cfg.Bucket == "" {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityError,
+			Text:     "Missing bucket configuration",
+		})
+	}
+	accessKey, err := secureValue(cfg.AccessKey)
+	if err != nil {
+		meta.Notice = append(meta.Notice, data.Notice{Severity: data.NoticeSeverityError, Text: err.Error()})
+	}
+	secretKey, err := secureValue(cfg.SecretKey)
+	if err != nil {
+		meta.Notice = append(meta.Notice, data.Notice{Severity: data.NoticeSeverityError, Text: err.Error()})
+	}
+
+	return newBlobStorage(meta, scfg, cfg.Folder, func(ctx context.Context) (*blob.Bucket, error) {
+		awsCfg := aws.NewConfig()
+		if cfg.Region != "" {
+			awsCfg = awsCfg.WithRegion(cfg.Region)
+		}
+		if cfg.Endpoint != "" {
+			// S3 compatible services usually do not support virtual hosted buckets
+			awsCfg = awsCfg.WithEndpoint(cfg.Endpoint).WithS3ForcePathStyle(true)
+		}
+		// Without static credentials the default AWS credential chain is used.
+		if accessKey != "" || secretKey != "" {
+			awsCfg = awsCfg.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, ""))
+		}
+		sess, err := session.NewSession(awsCfg)
+		if err != nil {
+			return nil, err
+		}
+		return s3blob.OpenBucket(ctx, sess, cfg.Bucket, nil)
+	})
+}
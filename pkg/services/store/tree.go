@@ -57,6 +57,12 @@ func (t *nestedTree) assureOrgIsInitialized(orgId int64) {
 	}
 }
 
+func (t *nestedTree) getRoots(orgId int64) []storageRuntime {
+	t.orgInitMutex.Lock()
+	defer t.orgInitMutex.Unlock()
+	return append([]storageRuntime(nil), t.rootsByOrgId[orgId]...)
+}
+
 func (t *nestedTree) getRoot(orgId int64, path string) (storageRuntime, string) {
 	t.assureOrgIsInitialized(orgId)
 
@@ -57,6 +57,11 @@ type storageRuntime interface {
 	Write(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error)
 }
 
+// storageUsageReporter is implemented by storages whose files count towards the file quota.
+type storageUsageReporter interface {
+	countFiles(ctx context.Context) (int64, error)
+}
+
 type RootStorageMeta struct {
 	ReadOnly bool          `json:"editable,omitempty"`
 	Builtin  bool          `json:"builtin,omitempty"`
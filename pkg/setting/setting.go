@@ -294,6 +294,13 @@ type Cfg struct {
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	// DashboardTrashRetention is how long deleted dashboards and folders are
+	// kept in trash and can be restored. Zero deletes them immediately.
+	DashboardTrashRetention time.Duration
+	// DashboardLintOnSave and DashboardLintOnProvisioning control whether dashboards
+	// are linted when saved by users or provisioned.
+	DashboardLintOnSave         DashboardLintMode
+	DashboardLintOnProvisioning DashboardLintMode
 
 	// Auth
 	LoginCookieName              string
@@ -394,6 +401,7 @@ type Cfg struct {
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
+	AnnotationStores                   AnnotationStoreSettings
 
 	// Sentry config
 	Sentry Sentry
@@ -403,9 +411,15 @@ type Cfg struct {
 
 	// Data sources
 	DataSourceLimit int
+	// Maximum number of concurrent sub-range requests when splitting queries
+	DataSourceQuerySplitConcurrency int
 
 	// Snapshots
 	SnapshotPublicMode bool
+	// SnapshotPublicModeToken is required from unauthenticated requests in public mode when set.
+	SnapshotPublicModeToken string
+	// ExternalSnapshotToken is sent to the external snapshot server.
+	ExternalSnapshotToken string
 
 	ErrTemplateName string
 
@@ -455,6 +469,26 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LivePipelineStorage is a storage type for Live pipeline channel rules and
+	// write configs: "file" (default) or "database".
+	LivePipelineStorage string
+	// LiveHistoryMaxRows is a maximum number of rows kept in managed stream
+	// channel history. Zero disables the row limit.
+	LiveHistoryMaxRows int
+	// LiveHistoryMaxAge is a maximum age of frames kept in managed stream
+	// channel history. Zero disables the age limit. History is only kept
+	// if at least one limit is set.
+	LiveHistoryMaxAge time.Duration
+	// LivePushMaxMessageSize is a maximum size in bytes of a message pushed
+	// over Live push endpoints. Zero means no limit.
+	LivePushMaxMessageSize int
+	// LivePushOrgRateLimit is a maximum number of messages per minute an
+	// organization can push. Zero means no limit. Can be overridden per
+	// organization with quotas when they are enabled.
+	LivePushOrgRateLimit int64
+	// LivePushStreamRateLimit is a maximum number of messages per minute which
+	// can be pushed to one stream or pipeline channel. Zero means no limit.
+	LivePushStreamRateLimit int64
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -479,6 +513,10 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	PublicDashboards PublicDashboardsSettings
+
+	GitSync GitSyncSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// Access Control
@@ -668,7 +706,9 @@ func (cfg *Cfg) readAnnotationSettings() error {
 	cfg.DashboardAnnotationCleanupSettings = newAnnotationCleanupSettings(dashboardAnnotation, "max_age")
 	cfg.APIAnnotationCleanupSettings = newAnnotationCleanupSettings(apiIAnnotation, "max_age")
 
-	return nil
+	var err error
+	cfg.AnnotationStores, err = readAnnotationStoreSettings(cfg.Raw)
+	return err
 }
 
 func (cfg *Cfg) readExpressionsSettings() {
@@ -988,6 +1028,20 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
 
+	cfg.DashboardTrashRetention, err = gtime.ParseDuration(valueAsString(dashboards, "trash_retention", "0"))
+	if err != nil {
+		return err
+	}
+	if cfg.DashboardTrashRetention < 0 {
+		return fmt.Errorf("unexpected value %s for [dashboards] trash_retention", cfg.DashboardTrashRetention)
+	}
+	if cfg.DashboardLintOnSave, err = readDashboardLintMode(dashboards, "lint_on_save"); err != nil {
+		return err
+	}
+	if cfg.DashboardLintOnProvisioning, err = readDashboardLintMode(dashboards, "lint_on_provisioning"); err != nil {
+		return err
+	}
+
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
 	}
@@ -1088,7 +1142,11 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.DashboardPreviews = readDashboardPreviewsSettings(iniFile)
 	cfg.Storage = readStorageSettings(iniFile)
-	cfg.Search = readSearchSettings(iniFile)
+	cfg.Search = readSearchSettings(iniFile, cfg.DataPath)
+	cfg.PublicDashboards = readPublicDashboardsSettings(iniFile)
+	if cfg.GitSync, err = readGitSyncSettings(iniFile); err != nil {
+		return err
+	}
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -1647,6 +1705,8 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 	ExternalEnabled = snapshots.Key("external_enabled").MustBool(true)
 	SnapShotRemoveExpired = snapshots.Key("snapshot_remove_expired").MustBool(true)
 	cfg.SnapshotPublicMode = snapshots.Key("public_mode").MustBool(false)
+	cfg.SnapshotPublicModeToken = valueAsString(snapshots, "public_mode_token", "")
+	cfg.ExternalSnapshotToken = valueAsString(snapshots, "external_snapshot_token", "")
 
 	return nil
 }
@@ -1736,6 +1796,7 @@ func (cfg *Cfg) GetContentDeliveryURL(prefix string) string {
 func (cfg *Cfg) readDataSourcesSettings() {
 	datasources := cfg.Raw.Section("datasources")
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
+	cfg.DataSourceQuerySplitConcurrency = datasources.Key("query_split_concurrency").MustInt(4)
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
@@ -1779,5 +1840,34 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 		return err
 	}
 	cfg.LiveAllowedOrigins = originPatterns
+
+	cfg.LivePipelineStorage = section.Key("pipeline_storage").MustString("file")
+	switch cfg.LivePipelineStorage {
+	case "file", "database":
+	default:
+		return fmt.Errorf("unsupported live pipeline storage type: %s", cfg.LivePipelineStorage)
+	}
+
+	cfg.LiveHistoryMaxRows = section.Key("history_max_rows").MustInt(0)
+	if cfg.LiveHistoryMaxRows < 0 {
+		return fmt.Errorf("unexpected value %d for [live] history_max_rows", cfg.LiveHistoryMaxRows)
+	}
+	cfg.LiveHistoryMaxAge = section.Key("history_max_age").MustDuration(0)
+	if cfg.LiveHistoryMaxAge < 0 {
+		return fmt.Errorf("unexpected value %s for [live] history_max_age", cfg.LiveHistoryMaxAge)
+	}
+
+	cfg.LivePushMaxMessageSize = section.Key("push_max_message_size").MustInt(0)
+	if cfg.LivePushMaxMessageSize < 0 {
+		return fmt.Errorf("unexpected value %d for [live] push_max_message_size", cfg.LivePushMaxMessageSize)
+	}
+	cfg.LivePushOrgRateLimit = section.Key("push_org_rate_limit").MustInt64(0)
+	if cfg.LivePushOrgRateLimit < 0 {
+		return fmt.Errorf("unexpected value %d for [live] push_org_rate_limit", cfg.LivePushOrgRateLimit)
+	}
+	cfg.LivePushStreamRateLimit = section.Key("push_stream_rate_limit").MustInt64(0)
+	if cfg.LivePushStreamRateLimit < 0 {
+		return fmt.Errorf("unexpected value %d for [live] push_stream_rate_limit", cfg.LivePushStreamRateLimit)
+	}
 	return nil
 }
@@ -0,0 +1,92 @@
+package setting
+
+import (
+	"fmt"
+
+	"gopkg.in/ini.v1"
+)
+
+// AnnotationStore is the backend keeping annotations of a type.
+type AnnotationStore string
+
+const (
+	// AnnotationStoreSQL keeps annotations in the Grafana database.
+	AnnotationStoreSQL AnnotationStore = "sql"
+	// AnnotationStoreLoki keeps annotations in Loki.
+	AnnotationStoreLoki AnnotationStore = "loki"
+	// AnnotationStoreElasticsearch keeps annotations in an Elasticsearch index.
+	AnnotationStoreElasticsearch AnnotationStore = "elasticsearch"
+)
+
+// AnnotationStoreSettings select where annotations are kept. Annotations created on dashboards are always
+// kept in the Grafana database.
+type AnnotationStoreSettings struct {
+	// AlertStore keeps the annotations created by alert state changes.
+	AlertStore AnnotationStore
+	// APIStore keeps the annotations created with the HTTP API without association to a dashboard.
+	APIStore AnnotationStore
+
+	Loki          AnnotationLokiSettings
+	Elasticsearch AnnotationElasticsearchSettings
+}
+
+type AnnotationLokiSettings struct {
+	URL               string
+	TenantID          string
+	BasicAuthUser     string
+	BasicAuthPassword string
+}
+
+type AnnotationElasticsearchSettings struct {
+	URL      string
+	Index    string
+	Username string
+	Password string
+}
+
+func readAnnotationStoreSettings(iniFile *ini.File) (AnnotationStoreSettings, error) {
+	s := AnnotationStoreSettings{}
+
+	section := iniFile.Section("annotations")
+	var err error
+	if s.AlertStore, err = readAnnotationStore(section, "alert_store"); err != nil {
+		return s, err
+	}
+	if s.APIStore, err = readAnnotationStore(section, "api_store"); err != nil {
+		return s, err
+	}
+
+	loki := iniFile.Section("annotations.loki")
+	s.Loki.URL = valueAsString(loki, "url", "")
+	s.Loki.TenantID = valueAsString(loki, "tenant_id", "")
+	s.Loki.BasicAuthUser = valueAsString(loki, "basic_auth_user", "")
+	s.Loki.BasicAuthPassword = valueAsString(loki, "basic_auth_password", "")
+
+	elasticsearch := iniFile.Section("annotations.elasticsearch")
+	s.Elasticsearch.URL = valueAsString(elasticsearch, "url", "")
+	s.Elasticsearch.Index = valueAsString(elasticsearch, "index", "grafana-annotations")
+	s.Elasticsearch.Username = valueAsString(elasticsearch, "username", "")
+	s.Elasticsearch.Password = valueAsString(elasticsearch, "password", "")
+
+	if s.uses(AnnotationStoreLoki) && s.Loki.URL == "" {
+		return s, fmt.Errorf("[annotations.loki] url is required when annotations are stored in Loki")
+	}
+	if s.uses(AnnotationStoreElasticsearch) && s.Elasticsearch.URL == "" {
+		return s, fmt.Errorf("[annotations.elasticsearch] url is required when annotations are stored in Elasticsearch")
+	}
+
+	return s, nil
+}
+
+func (s AnnotationStoreSettings) uses(store AnnotationStore) bool {
+	return s.AlertStore == store || s.APIStore == store
+}
+
+func readAnnotationStore(section *ini.Section, key string) (AnnotationStore, error) {
+	store := AnnotationStore(valueAsString(section, key, string(AnnotationStoreSQL)))
+	switch store {
+	case AnnotationStoreSQL, AnnotationStoreLoki, AnnotationStoreElasticsearch:
+		return store, nil
+	}
+	return "", fmt.Errorf("unexpected value %q for [%s] %s, expected one of sql, loki, elasticsearch", store, section.Name(), key)
+}
@@ -0,0 +1,28 @@
+package setting
+
+import (
+	"fmt"
+
+	"gopkg.in/ini.v1"
+)
+
+// DashboardLintMode controls what happens with dashboards failing lint rules.
+type DashboardLintMode string
+
+const (
+	// DashboardLintOff disables linting.
+	DashboardLintOff DashboardLintMode = "off"
+	// DashboardLintWarn logs problems found by the linter.
+	DashboardLintWarn DashboardLintMode = "warn"
+	// DashboardLintReject logs problems and rejects dashboards with error severity problems.
+	DashboardLintReject DashboardLintMode = "reject"
+)
+
+func readDashboardLintMode(section *ini.Section, key string) (DashboardLintMode, error) {
+	mode := DashboardLintMode(valueAsString(section, key, string(DashboardLintOff)))
+	switch mode {
+	case DashboardLintOff, DashboardLintWarn, DashboardLintReject:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unexpected value %q for [%s] %s, expected one of off, warn, reject", mode, section.Name(), key)
+}
@@ -0,0 +1,53 @@
+package setting
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// GitSyncUIChangesReadOnly blocks changes of synchronized dashboards in the UI.
+	GitSyncUIChangesReadOnly = "readonly"
+	// GitSyncUIChangesCommit commits changes made in the UI to the synchronized branch.
+	GitSyncUIChangesCommit = "commit"
+	// GitSyncUIChangesBranch pushes changes made in the UI to a new branch.
+	GitSyncUIChangesBranch = "branch"
+)
+
+type GitSyncSettings struct {
+	Enabled    bool
+	Repository string
+	Branch     string
+	Path       string
+	Username   string
+	Password   string
+	OrgID      int64
+	Interval   time.Duration
+	UIChanges  string
+}
+
+func readGitSyncSettings(iniFile *ini.File) (GitSyncSettings, error) {
+	s := GitSyncSettings{}
+
+	section := iniFile.Section("git_sync")
+	s.Enabled = section.Key("enabled").MustBool(false)
+	s.Repository = section.Key("repository").MustString("")
+	s.Branch = section.Key("branch").MustString("main")
+	s.Path = section.Key("path").MustString("")
+	s.Username = section.Key("username").MustString("")
+	s.Password = section.Key("password").MustString("")
+	s.OrgID = section.Key("org_id").MustInt64(1)
+	s.Interval = section.Key("interval").MustDuration(time.Minute)
+	s.UIChanges = section.Key("ui_changes").In(GitSyncUIChangesReadOnly,
+		[]string{GitSyncUIChangesReadOnly, GitSyncUIChangesCommit, GitSyncUIChangesBranch})
+
+	if s.Enabled && s.Repository == "" {
+		return s, fmt.Errorf("[git_sync] repository must be set when git sync is enabled")
+	}
+	if s.Interval < time.Second {
+		s.Interval = time.Second
+	}
+	return s, nil
+}
@@ -0,0 +1,34 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type PublicDashboardsSettings struct {
+	// QueryRateLimit is the number of queries per second allowed for each public dashboard access token.
+	// Queries are not rate limited when zero.
+	QueryRateLimit float64
+	// QueryRateLimitBurst is the number of queries allowed at once above the rate limit, one second of queries when zero.
+	QueryRateLimitBurst int
+	// QueryCacheTTL is how long query responses are shared between the viewers of a public dashboard.
+	// Responses are not cached when zero.
+	QueryCacheTTL time.Duration
+	// TrustedProxies are the IP addresses and CIDR ranges of the reverse proxies whose X-Forwarded-For and
+	// X-Real-IP headers are used to check the IP allow lists of public dashboards.
+	TrustedProxies []string
+}
+
+func readPublicDashboardsSettings(iniFile *ini.File) PublicDashboardsSettings {
+	s := PublicDashboardsSettings{}
+
+	section := iniFile.Section("public_dashboards")
+	s.QueryRateLimit = section.Key("query_rate_limit").MustFloat64(0)
+	s.QueryRateLimitBurst = section.Key("query_rate_limit_burst").MustInt(0)
+	s.QueryCacheTTL = section.Key("query_cache_ttl").MustDuration(0)
+	s.TrustedProxies = util.SplitString(section.Key("trusted_proxies").MustString(""))
+	return s
+}
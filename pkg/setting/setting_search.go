@@ -10,14 +10,20 @@ type SearchSettings struct {
 	FullReindexInterval       time.Duration
 	IndexUpdateInterval       time.Duration
 	DashboardLoadingBatchSize int
+	// IndexPath is the directory where search indexes are persisted between restarts.
+	// Indexes are kept in memory only when empty.
+	IndexPath string
 }
 
-func readSearchSettings(iniFile *ini.File) SearchSettings {
+func readSearchSettings(iniFile *ini.File, dataPath string) SearchSettings {
 	s := SearchSettings{}
 
 	searchSection := iniFile.Section("search")
 	s.DashboardLoadingBatchSize = searchSection.Key("dashboard_loading_batch_size").MustInt(200)
 	s.FullReindexInterval = searchSection.Key("full_reindex_interval").MustDuration(5 * time.Minute)
 	s.IndexUpdateInterval = searchSection.Key("index_update_interval").MustDuration(10 * time.Second)
+	if indexPath := valueAsString(searchSection, "index_path", ""); indexPath != "" {
+		s.IndexPath = makeAbsolute(indexPath, dataPath)
+	}
 	return s
 }
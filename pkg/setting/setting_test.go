@@ -768,3 +768,64 @@ func TestAlertingEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotationStoreSettings(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		settings    map[string]map[string]string
+		expected    AnnotationStoreSettings
+		expectedErr string
+	}{
+		{
+			desc:     "should keep annotations in the database by default",
+			settings: map[string]map[string]string{},
+			expected: AnnotationStoreSettings{AlertStore: AnnotationStoreSQL, APIStore: AnnotationStoreSQL, Elasticsearch: AnnotationElasticsearchSettings{Index: "grafana-annotations"}},
+		},
+		{
+			desc: "should read the configured stores",
+			settings: map[string]map[string]string{
+				"annotations":               {"alert_store": "loki", "api_store": "elasticsearch"},
+				"annotations.loki":          {"url": "http://loki:3100", "tenant_id": "grafana"},
+				"annotations.elasticsearch": {"url": "http://elasticsearch:9200", "index": "annotations"},
+			},
+			expected: AnnotationStoreSettings{
+				AlertStore:    AnnotationStoreLoki,
+				APIStore:      AnnotationStoreElasticsearch,
+				Loki:          AnnotationLokiSettings{URL: "http://loki:3100", TenantID: "grafana"},
+				Elasticsearch: AnnotationElasticsearchSettings{URL: "http://elasticsearch:9200", Index: "annotations"},
+			},
+		},
+		{
+			desc:        "should fail for unknown stores",
+			settings:    map[string]map[string]string{"annotations": {"alert_store": "cassandra"}},
+			expectedErr: `unexpected value "cassandra" for [annotations] alert_store, expected one of sql, loki, elasticsearch`,
+		},
+		{
+			desc:        "should require the url of the store",
+			settings:    map[string]map[string]string{"annotations": {"api_store": "loki"}},
+			expectedErr: "[annotations.loki] url is required when annotations are stored in Loki",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := ini.Empty()
+			for section, keys := range tc.settings {
+				sec, err := f.NewSection(section)
+				require.NoError(t, err)
+				for key, value := range keys {
+					_, err := sec.NewKey(key, value)
+					require.NoError(t, err)
+				}
+			}
+
+			s, err := readAnnotationStoreSettings(f)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, s)
+		})
+	}
+}
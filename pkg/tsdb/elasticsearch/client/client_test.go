@@ -123,3 +123,104 @@ func createMultisearchForTest(t *testing.T, c Client) (*MultiSearchRequest, erro
 	})
 	return msb.Build()
 }
+
+func TestClient_ExecuteTextQuery(t *testing.T) {
+	version, err := semver.NewVersion("8.0.0")
+	require.NoError(t, err)
+	timeRange := backend.TimeRange{
+		From: time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC),
+		To:   time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		flavor   string
+		language TextQueryLanguage
+		path     string
+		response string
+	}{
+		{name: "Elasticsearch SQL", flavor: FlavorElasticsearch, language: TextQueryLanguageSQL, path: "/_sql",
+			response: `{"columns":[{"name":"host","type":"keyword"},{"name":"c","type":"long"}],"rows":[["a",1]]}`},
+		{name: "ES|QL", flavor: FlavorElasticsearch, language: TextQueryLanguageESQL, path: "/_query",
+			response: `{"columns":[{"name":"host","type":"keyword"},{"name":"c","type":"long"}],"values":[["a",1]]}`},
+		{name: "OpenSearch SQL", flavor: FlavorOpenSearch, language: TextQueryLanguageSQL, path: "/_plugins/_sql",
+			response: `{"schema":[{"name":"host","type":"keyword"},{"name":"c","type":"long"}],"datarows":[["a",1]],"status":200}`},
+		{name: "OpenSearch PPL", flavor: FlavorOpenSearch, language: TextQueryLanguagePPL, path: "/_plugins/_ppl",
+			response: `{"schema":[{"name":"host","type":"string"},{"name":"c","type":"integer"}],"datarows":[["a",1]],"status":200}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request *http.Request
+			var body []byte
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				request = r
+				var err error
+				body, err = io.ReadAll(r.Body)
+				require.NoError(t, err)
+				_, err = rw.Write([]byte(tt.response))
+				require.NoError(t, err)
+			}))
+			t.Cleanup(ts.Close)
+
+			c, err := NewClient(context.Background(), &DatasourceInfo{
+				URL:        ts.URL,
+				HTTPClient: ts.Client(),
+				ESVersion:  version,
+				TimeField:  "@timestamp",
+				Flavor:     tt.flavor,
+			}, timeRange)
+			require.NoError(t, err)
+
+			res, err := c.ExecuteTextQuery(&TextQueryRequest{Language: tt.language, Query: "the query"})
+			require.NoError(t, err)
+
+			require.Equal(t, tt.path, request.URL.Path)
+			require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+			jBody, err := simplejson.NewJson(body)
+			require.NoError(t, err)
+			require.Equal(t, "the query", jBody.Get("query").MustString())
+
+			require.Len(t, res.Columns, 2)
+			require.Equal(t, "host", res.Columns[0].Name)
+			require.Len(t, res.Rows, 1)
+			require.Equal(t, "a", res.Rows[0][0])
+		})
+	}
+
+	t.Run("Unsupported language for flavor", func(t *testing.T) {
+		c, err := NewClient(context.Background(), &DatasourceInfo{ESVersion: version, Flavor: FlavorElasticsearch}, timeRange)
+		require.NoError(t, err)
+		_, err = c.ExecuteTextQuery(&TextQueryRequest{Language: TextQueryLanguagePPL, Query: "source=logs"})
+		require.Error(t, err)
+	})
+
+	t.Run("Error response", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err := rw.Write([]byte(`{"error":{"type":"parsing_exception","reason":"line 1:1: mismatched input"},"status":400}`))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(ts.Close)
+
+		c, err := NewClient(context.Background(), &DatasourceInfo{URL: ts.URL, HTTPClient: ts.Client(), ESVersion: version}, timeRange)
+		require.NoError(t, err)
+		_, err = c.ExecuteTextQuery(&TextQueryRequest{Language: TextQueryLanguageSQL, Query: "SELEC"})
+		require.EqualError(t, err, "parsing_exception: line 1:1: mismatched input")
+	})
+
+	t.Run("Non-JSON error response", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "text/html")
+			rw.WriteHeader(http.StatusBadGateway)
+			_, err := rw.Write([]byte("<html><body>502 Bad Gateway</body></html>\n"))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(ts.Close)
+
+		c, err := NewClient(context.Background(), &DatasourceInfo{URL: ts.URL, HTTPClient: ts.Client(), ESVersion: version}, timeRange)
+		require.NoError(t, err)
+		_, err = c.ExecuteTextQuery(&TextQueryRequest{Language: TextQueryLanguageSQL, Query: "SELECT 1"})
+		require.EqualError(t, err, "text query failed with status code 502: <html><body>502 Bad Gateway</body></html>")
+	})
+}
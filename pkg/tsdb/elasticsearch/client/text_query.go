@@ -0,0 +1,191 @@
+package es
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TextQueryLanguage is the language of a text query
+type TextQueryLanguage string
+
+const (
+	// TextQueryLanguageSQL is Elasticsearch SQL or OpenSearch SQL, depending on the flavor
+	TextQueryLanguageSQL TextQueryLanguage = "sql"
+	// TextQueryLanguagePPL is the OpenSearch Piped Processing Language
+	TextQueryLanguagePPL TextQueryLanguage = "ppl"
+	// TextQueryLanguageESQL is the Elasticsearch Query Language
+	TextQueryLanguageESQL TextQueryLanguage = "esql"
+)
+
+const (
+	// FlavorElasticsearch is the default flavor of the datasource
+	FlavorElasticsearch = "elasticsearch"
+	// FlavorOpenSearch is used for OpenSearch clusters
+	FlavorOpenSearch = "opensearch"
+)
+
+// TextQueryRequest represents a SQL, PPL or ES|QL query request
+type TextQueryRequest struct {
+	Language  TextQueryLanguage
+	Query     string
+	FetchSize int
+}
+
+// TextQueryColumn represents a column of a text query response
+type TextQueryColumn struct {
+	Name string
+	Type string
+}
+
+// TextQueryResponse represents the tabular response of a text query
+type TextQueryResponse struct {
+	Columns []TextQueryColumn
+	Rows    [][]interface{}
+}
+
+// textQueryRawResponse covers the response formats of Elasticsearch SQL
+// (columns/rows), ES|QL (columns/values) and the OpenSearch SQL and PPL
+// plugins (schema/datarows).
+type textQueryRawResponse struct {
+	Columns  []TextQueryColumn `json:"columns"`
+	Rows     [][]interface{}   `json:"rows"`
+	Values   [][]interface{}   `json:"values"`
+	Schema   []TextQueryColumn `json:"schema"`
+	DataRows [][]interface{}   `json:"datarows"`
+	Error    json.RawMessage   `json:"error"`
+}
+
+func (c *baseClientImpl) textQueryEndpoint(language TextQueryLanguage) (string, string, error) {
+	flavor := c.ds.Flavor
+	if flavor == "" {
+		flavor = FlavorElasticsearch
+	}
+
+	switch {
+	case language == TextQueryLanguageSQL && flavor == FlavorElasticsearch:
+		return "_sql", "format=json", nil
+	case language == TextQueryLanguageSQL && flavor == FlavorOpenSearch:
+		return "_plugins/_sql", "format=jdbc", nil
+	case language == TextQueryLanguagePPL && flavor == FlavorOpenSearch:
+		return "_plugins/_ppl", "format=jdbc", nil
+	case language == TextQueryLanguageESQL && flavor == FlavorElasticsearch:
+		return "_query", "format=json", nil
+	}
+
+	return "", "", fmt.Errorf("%s queries are not supported by %s", language, flavor)
+}
+
+func (c *baseClientImpl) ExecuteTextQuery(r *TextQueryRequest) (*TextQueryResponse, error) {
+	uriPath, uriQuery, err := c.textQueryEndpoint(r.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"query": r.Query,
+	}
+	if r.FetchSize > 0 && r.Language == TextQueryLanguageSQL {
+		body["fetch_size"] = r.FetchSize
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Executing text query", "language", r.Language)
+
+	clientRes, err := c.executeRequest(http.MethodPost, uriPath, uriQuery, "application/json", payload)
+	if err != nil {
+		return nil, err
+	}
+	res := clientRes
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// error responses are not necessarily JSON, e.g. when returned by a proxy in front of the cluster
+	if res.StatusCode/100 != 2 {
+		return nil, textQueryStatusError(res.StatusCode, resBody)
+	}
+
+	start := time.Now()
+	var raw textQueryRawResponse
+	dec := json.NewDecoder(bytes.NewReader(resBody))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", r.Language, err)
+	}
+	c.logger.Debug("Decoded text query response", "took", time.Since(start))
+
+	if len(raw.Error) > 0 {
+		return nil, textQueryError(res.StatusCode, raw.Error)
+	}
+
+	tr := &TextQueryResponse{}
+	switch {
+	case raw.Schema != nil:
+		tr.Columns, tr.Rows = raw.Schema, raw.DataRows
+	case raw.Values != nil:
+		tr.Columns, tr.Rows = raw.Columns, raw.Values
+	default:
+		tr.Columns, tr.Rows = raw.Columns, raw.Rows
+	}
+
+	return tr, nil
+}
+
+// maxTextQueryErrorLength is the maximum length of a non-JSON error body included in errors
+const maxTextQueryErrorLength = 512
+
+// textQueryStatusError returns the error message of a non-2xx response, which is either a JSON error returned by the
+// cluster or any text body.
+func textQueryStatusError(status int, body []byte) error {
+	var raw struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &raw); err == nil && len(raw.Error) > 0 {
+		return textQueryError(status, raw.Error)
+	}
+
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		return fmt.Errorf("text query failed with status code %d", status)
+	}
+	if len(message) > maxTextQueryErrorLength {
+		message = message[:maxTextQueryErrorLength] + "..."
+	}
+	return fmt.Errorf("text query failed with status code %d: %s", status, message)
+}
+
+func textQueryError(status int, raw json.RawMessage) error {
+	var structured struct {
+		Type    string `json:"type"`
+		Reason  string `json:"reason"`
+		Details string `json:"details"`
+	}
+	if err := json.Unmarshal(raw, &structured); err == nil && structured.Reason != "" {
+		if structured.Details != "" {
+			return fmt.Errorf("%s: %s", structured.Reason, structured.Details)
+		}
+		return fmt.Errorf("%s: %s", structured.Type, structured.Reason)
+	}
+
+	var message string
+	if err := json.Unmarshal(raw, &message); err == nil && message != "" {
+		return fmt.Errorf("%s", message)
+	}
+
+	return fmt.Errorf("text query failed with status code %d", status)
+}
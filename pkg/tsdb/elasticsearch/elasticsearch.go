@@ -58,8 +58,33 @@ func queryData(ctx context.Context, queries []backend.DataQuery, dsInfo *es.Data
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
-	query := newTimeSeriesQuery(client, queries)
-	return query.execute()
+
+	// SQL, PPL and ES|QL queries are sent one by one to their own endpoints,
+	// everything else is batched into a single multisearch request.
+	aggQueries := make([]backend.DataQuery, 0, len(queries))
+	textResponses := backend.Responses{}
+	for _, q := range queries {
+		if isTextQuery(q) {
+			textResponses[q.RefID] = newTextQuery(client, q, dsInfo.Flavor).execute()
+			continue
+		}
+		aggQueries = append(aggQueries, q)
+	}
+
+	if len(aggQueries) == 0 {
+		return &backend.QueryDataResponse{Responses: textResponses}, nil
+	}
+
+	query := newTimeSeriesQuery(client, aggQueries)
+	result, err := query.execute()
+	if err != nil {
+		return result, err
+	}
+
+	for refID, res := range textResponses {
+		result.Responses[refID] = res
+	}
+	return result, nil
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -132,6 +157,11 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			xpack = false
 		}
 
+		flavor, ok := jsonData["flavor"].(string)
+		if !ok || flavor == "" {
+			flavor = es.FlavorElasticsearch
+		}
+
 		model := es.DatasourceInfo{
 			ID:                         settings.ID,
 			URL:                        settings.URL,
@@ -144,6 +174,7 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			TimeInterval:               timeInterval,
 			IncludeFrozen:              includeFrozen,
 			XPack:                      xpack,
+			Flavor:                     flavor,
 		}
 		return model, nil
 	}
@@ -0,0 +1,328 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const (
+	sqlQueryType  = "sql"
+	pplQueryType  = "ppl"
+	esqlQueryType = "esql"
+
+	defaultTextQueryFetchSize = 10000
+)
+
+var textQueryTypes = map[string]es.TextQueryLanguage{
+	sqlQueryType:  es.TextQueryLanguageSQL,
+	pplQueryType:  es.TextQueryLanguagePPL,
+	esqlQueryType: es.TextQueryLanguageESQL,
+}
+
+// isTextQuery returns true if the query is a SQL, PPL or ES|QL query rather
+// than a query built from bucket and metric aggregations.
+func isTextQuery(q backend.DataQuery) bool {
+	_, ok := textQueryTypes[q.QueryType]
+	return ok
+}
+
+type textQuery struct {
+	client    es.Client
+	dataQuery backend.DataQuery
+	flavor    string
+}
+
+var newTextQuery = func(client es.Client, dataQuery backend.DataQuery, flavor string) *textQuery {
+	return &textQuery{
+		client:    client,
+		dataQuery: dataQuery,
+		flavor:    flavor,
+	}
+}
+
+func (e *textQuery) execute() backend.DataResponse {
+	language := textQueryTypes[e.dataQuery.QueryType]
+
+	model, err := simplejson.NewJson(e.dataQuery.JSON)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	rawQuery := strings.TrimSpace(model.Get("query").MustString())
+	if rawQuery == "" {
+		return backend.DataResponse{Error: fmt.Errorf("invalid %s query, query text is empty", language)}
+	}
+
+	timeField := model.Get("timeField").MustString(e.client.GetTimeField())
+	interpolated := interpolateTextQuery(rawQuery, language, e.flavor, timeField, e.dataQuery.TimeRange)
+
+	res, err := e.client.ExecuteTextQuery(&es.TextQueryRequest{
+		Language:  language,
+		Query:     interpolated,
+		FetchSize: model.Get("size").MustInt(defaultTextQueryFetchSize),
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	frame, err := textQueryResponseToFrame(res, timeField)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	frame.RefID = e.dataQuery.RefID
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	frame.Meta.ExecutedQueryString = interpolated
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+var textQueryMacroRegexp = regexp.MustCompile(`\$__(timeFilter|timeFrom|timeTo)(\(([^)]*)\))?`)
+
+// interpolateTextQuery replaces the $__timeFilter, $__timeFrom and $__timeTo
+// macros with literals in the syntax of the query language and flavor. $__timeFilter
+// uses the datasource time field unless a column is given, e.g.
+// $__timeFilter(event.created).
+func interpolateTextQuery(query string, language es.TextQueryLanguage, flavor, timeField string, tr backend.TimeRange) string {
+	return textQueryMacroRegexp.ReplaceAllStringFunc(query, func(match string) string {
+		parts := textQueryMacroRegexp.FindStringSubmatch(match)
+		column := timeField
+		if arg := strings.TrimSpace(parts[3]); arg != "" {
+			column = arg
+		}
+
+		from := textQueryTimeLiteral(language, flavor, tr.From)
+		to := textQueryTimeLiteral(language, flavor, tr.To)
+
+		switch parts[1] {
+		case "timeFrom":
+			return from
+		case "timeTo":
+			return to
+		}
+
+		col := textQueryIdentifier(language, flavor, column)
+		return fmt.Sprintf("%s >= %s AND %s <= %s", col, from, col, to)
+	})
+}
+
+func textQueryTimeLiteral(language es.TextQueryLanguage, flavor string, t time.Time) string {
+	switch {
+	case language == es.TextQueryLanguageESQL:
+		return fmt.Sprintf(`TO_DATETIME("%s")`, t.UTC().Format("2006-01-02T15:04:05.000Z"))
+	case language == es.TextQueryLanguageSQL && flavor != es.FlavorOpenSearch:
+		return fmt.Sprintf(`CAST('%s' AS DATETIME)`, t.UTC().Format("2006-01-02T15:04:05.000Z"))
+	default:
+		return fmt.Sprintf(`'%s'`, t.UTC().Format("2006-01-02 15:04:05.000"))
+	}
+}
+
+func textQueryIdentifier(language es.TextQueryLanguage, flavor string, name string) string {
+	if language == es.TextQueryLanguageSQL && flavor != es.FlavorOpenSearch {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+var textQueryTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func isTextQueryTimeType(t string) bool {
+	switch strings.ToLower(t) {
+	case "date", "datetime", "date_nanos", "timestamp":
+		return true
+	}
+	return false
+}
+
+func isTextQueryNumberType(t string) bool {
+	switch strings.ToLower(t) {
+	case "long", "integer", "short", "byte", "double", "float", "half_float", "scaled_float", "unsigned_long", "counter_long", "counter_integer", "counter_double":
+		return true
+	}
+	return false
+}
+
+// textQueryResponseToFrame converts a tabular text query response into a
+// frame. If the response has a time column and numeric columns, the frame is
+// returned as a time series, where any string columns are treated as group by
+// dimensions and converted to labels.
+func textQueryResponseToFrame(res *es.TextQueryResponse, timeField string) (*data.Frame, error) {
+	timeIndex := -1
+	for i, c := range res.Columns {
+		if !isTextQueryTimeType(c.Type) {
+			continue
+		}
+		if timeIndex == -1 || c.Name == timeField {
+			timeIndex = i
+		}
+	}
+
+	rows := res.Rows
+	hasValues := false
+	for _, c := range res.Columns {
+		if isTextQueryNumberType(c.Type) {
+			hasValues = true
+		}
+	}
+	isTimeSeries := timeIndex >= 0 && hasValues
+
+	times := make([]*time.Time, len(rows))
+	if timeIndex >= 0 {
+		for i, row := range rows {
+			if timeIndex < len(row) {
+				times[i] = parseTextQueryTime(row[timeIndex])
+			}
+		}
+	}
+
+	if isTimeSeries {
+		order := make([]int, 0, len(rows))
+		for i := range rows {
+			if times[i] != nil {
+				order = append(order, i)
+			}
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return times[order[a]].Before(*times[order[b]])
+		})
+		sortedRows := make([][]interface{}, len(order))
+		sortedTimes := make([]*time.Time, len(order))
+		for i, idx := range order {
+			sortedRows[i] = rows[idx]
+			sortedTimes[i] = times[idx]
+		}
+		rows, times = sortedRows, sortedTimes
+	}
+
+	frame := data.NewFrame("")
+	for ci, c := range res.Columns {
+		var field *data.Field
+		switch {
+		case ci == timeIndex && isTimeSeries:
+			values := make([]time.Time, len(rows))
+			for i := range rows {
+				values[i] = *times[i]
+			}
+			field = data.NewField(c.Name, nil, values)
+		case isTextQueryTimeType(c.Type):
+			values := make([]*time.Time, len(rows))
+			for i, row := range rows {
+				if ci < len(row) {
+					values[i] = parseTextQueryTime(row[ci])
+				}
+			}
+			field = data.NewField(c.Name, nil, values)
+		case isTextQueryNumberType(c.Type):
+			values := make([]*float64, len(rows))
+			for i, row := range rows {
+				if ci < len(row) {
+					values[i] = parseTextQueryNumber(row[ci])
+				}
+			}
+			field = data.NewField(c.Name, nil, values)
+		case strings.ToLower(c.Type) == "boolean":
+			values := make([]*bool, len(rows))
+			for i, row := range rows {
+				if ci < len(row) {
+					if b, ok := row[ci].(bool); ok {
+						values[i] = &b
+					}
+				}
+			}
+			field = data.NewField(c.Name, nil, values)
+		default:
+			values := make([]*string, len(rows))
+			for i, row := range rows {
+				if ci < len(row) {
+					values[i] = stringifyTextQueryValue(row[ci])
+				}
+			}
+			field = data.NewField(c.Name, nil, values)
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+
+	if !isTimeSeries {
+		frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+		return frame, nil
+	}
+
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong && len(rows) > 0 {
+		wide, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return nil, err
+		}
+		frame = wide
+	}
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeGraph}
+
+	return frame, nil
+}
+
+func parseTextQueryTime(v interface{}) *time.Time {
+	switch value := v.(type) {
+	case string:
+		for _, layout := range textQueryTimeLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return &t
+			}
+		}
+	case json.Number:
+		if ms, err := value.Int64(); err == nil {
+			t := time.UnixMilli(ms).UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+func parseTextQueryNumber(v interface{}) *float64 {
+	switch value := v.(type) {
+	case json.Number:
+		if f, err := value.Float64(); err == nil {
+			return &f
+		}
+	case float64:
+		return &value
+	case string:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return &f
+		}
+	}
+	return nil
+}
+
+func stringifyTextQueryValue(v interface{}) *string {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return &value
+	case json.Number:
+		s := value.String()
+		return &s
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		s := string(b)
+		return &s
+	}
+}
@@ -0,0 +1,128 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolateTextQuery(t *testing.T) {
+	tr := backend.TimeRange{
+		From: time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC),
+		To:   time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC),
+	}
+
+	t.Run("Elasticsearch SQL", func(t *testing.T) {
+		q := interpolateTextQuery(`SELECT * FROM logs WHERE $__timeFilter`, es.TextQueryLanguageSQL, es.FlavorElasticsearch, "@timestamp", tr)
+		require.Equal(t, `SELECT * FROM logs WHERE "@timestamp" >= CAST('2018-05-15T17:50:00.000Z' AS DATETIME) AND "@timestamp" <= CAST('2018-05-15T17:55:00.000Z' AS DATETIME)`, q)
+	})
+
+	t.Run("ES|QL with explicit column", func(t *testing.T) {
+		q := interpolateTextQuery(`FROM logs | WHERE $__timeFilter(event.created)`, es.TextQueryLanguageESQL, es.FlavorElasticsearch, "@timestamp", tr)
+		require.Equal(t, "FROM logs | WHERE `event.created` >= TO_DATETIME(\"2018-05-15T17:50:00.000Z\") AND `event.created` <= TO_DATETIME(\"2018-05-15T17:55:00.000Z\")", q)
+	})
+
+	t.Run("OpenSearch PPL with from and to", func(t *testing.T) {
+		q := interpolateTextQuery(`source=logs | where ts > $__timeFrom and ts < $__timeTo`, es.TextQueryLanguagePPL, es.FlavorOpenSearch, "@timestamp", tr)
+		require.Equal(t, `source=logs | where ts > '2018-05-15 17:50:00.000' and ts < '2018-05-15 17:55:00.000'`, q)
+	})
+}
+
+func TestTextQueryResponseToFrame(t *testing.T) {
+	t.Run("Table without time column", func(t *testing.T) {
+		frame, err := textQueryResponseToFrame(&es.TextQueryResponse{
+			Columns: []es.TextQueryColumn{{Name: "host", Type: "keyword"}, {Name: "count", Type: "long"}},
+			Rows:    [][]interface{}{{"a", json.Number("2")}, {"b", nil}},
+		}, "@timestamp")
+		require.NoError(t, err)
+		require.Equal(t, data.VisType(data.VisTypeTable), frame.Meta.PreferredVisualization)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+		require.Nil(t, frame.Fields[1].At(1))
+	})
+
+	t.Run("Time series with group by", func(t *testing.T) {
+		frame, err := textQueryResponseToFrame(&es.TextQueryResponse{
+			Columns: []es.TextQueryColumn{{Name: "@timestamp", Type: "datetime"}, {Name: "host", Type: "keyword"}, {Name: "value", Type: "double"}},
+			Rows: [][]interface{}{
+				{"2018-05-15T17:51:00.000Z", "a", json.Number("2")},
+				{"2018-05-15T17:50:00.000Z", "b", json.Number("3")},
+				{"2018-05-15T17:50:00.000Z", "a", json.Number("1")},
+				{"2018-05-15T17:51:00.000Z", "b", json.Number("4")},
+			},
+		}, "@timestamp")
+		require.NoError(t, err)
+		require.Equal(t, data.VisTypeGraph, frame.Meta.PreferredVisualization)
+		require.Equal(t, data.TimeSeriesTypeWide, frame.TimeSeriesSchema().Type)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+		require.Equal(t, 1.0, *frame.Fields[1].At(0).(*float64))
+		require.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+		require.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("OpenSearch timestamps", func(t *testing.T) {
+		frame, err := textQueryResponseToFrame(&es.TextQueryResponse{
+			Columns: []es.TextQueryColumn{{Name: "ts", Type: "timestamp"}, {Name: "value", Type: "integer"}},
+			Rows:    [][]interface{}{{"2018-05-15 17:50:00.123", json.Number("1")}},
+		}, "@timestamp")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2018, 5, 15, 17, 50, 0, 123000000, time.UTC), frame.Fields[0].At(0))
+	})
+}
+
+func TestExecuteTextQuery(t *testing.T) {
+	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
+	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
+
+	t.Run("Sends the interpolated query and sets the ref id", func(t *testing.T) {
+		c := newFakeClient()
+		c.textQueryResponse = &es.TextQueryResponse{
+			Columns: []es.TextQueryColumn{{Name: "@timestamp", Type: "date"}, {Name: "value", Type: "long"}},
+			Rows:    [][]interface{}{{"2018-05-15T17:50:00.000Z", json.Number("1")}},
+		}
+		res := newTextQuery(c, backend.DataQuery{
+			RefID:     "A",
+			QueryType: esqlQueryType,
+			TimeRange: backend.TimeRange{From: from, To: to},
+			JSON:      json.RawMessage(`{"query": "FROM logs | WHERE $__timeFilter | STATS value = COUNT(*) BY @timestamp"}`),
+		}, es.FlavorElasticsearch).execute()
+		require.NoError(t, res.Error)
+		require.Len(t, c.textQueryRequests, 1)
+		require.Equal(t, es.TextQueryLanguageESQL, c.textQueryRequests[0].Language)
+		require.NotContains(t, c.textQueryRequests[0].Query, "$__timeFilter")
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, "A", res.Frames[0].RefID)
+		require.Equal(t, c.textQueryRequests[0].Query, res.Frames[0].Meta.ExecutedQueryString)
+	})
+
+	t.Run("Reports errors on the query", func(t *testing.T) {
+		c := newFakeClient()
+		c.textQueryError = errors.New("parsing_exception")
+		res := newTextQuery(c, backend.DataQuery{
+			RefID:     "A",
+			QueryType: sqlQueryType,
+			JSON:      json.RawMessage(`{"query": "SELECT 1"}`),
+		}, es.FlavorElasticsearch).execute()
+		require.EqualError(t, res.Error, "parsing_exception")
+	})
+
+	t.Run("Rejects empty queries", func(t *testing.T) {
+		c := newFakeClient()
+		res := newTextQuery(c, backend.DataQuery{
+			RefID:     "A",
+			QueryType: pplQueryType,
+			JSON:      json.RawMessage(`{"query": ""}`),
+		}, es.FlavorOpenSearch).execute()
+		require.Error(t, res.Error)
+		require.Len(t, c.textQueryRequests, 0)
+	})
+}
@@ -1754,6 +1754,9 @@ type fakeClient struct {
 	multiSearchError    error
 	builder             *es.MultiSearchRequestBuilder
 	multisearchRequests []*es.MultiSearchRequest
+	textQueryResponse   *es.TextQueryResponse
+	textQueryError      error
+	textQueryRequests   []*es.TextQueryRequest
 }
 
 func newFakeClient() *fakeClient {
@@ -1777,6 +1780,11 @@ func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSear
 	return c.multiSearchResponse, c.multiSearchError
 }
 
+func (c *fakeClient) ExecuteTextQuery(r *es.TextQueryRequest) (*es.TextQueryResponse, error) {
+	c.textQueryRequests = append(c.textQueryRequests, r)
+	return c.textQueryResponse, c.textQueryError
+}
+
 func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	c.builder = es.NewMultiSearchRequestBuilder()
 	return c.builder
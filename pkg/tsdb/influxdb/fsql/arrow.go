@@ -0,0 +1,209 @@
+package fsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// newFrameFromSchema creates an empty frame with one field per column of the
+// Arrow schema.
+func newFrameFromSchema(schema *arrow.Schema) *data.Frame {
+	fields := make([]*data.Field, len(schema.Fields()))
+	for i, f := range schema.Fields() {
+		fields[i] = data.NewFieldFromFieldType(fieldTypeForArrow(f.Type), 0)
+		fields[i].Name = f.Name
+	}
+	return data.NewFrame("", fields...)
+}
+
+// fieldTypeForArrow maps an Arrow data type to a nullable frame field type.
+// Dictionary encoded columns, which InfluxDB uses for tags, are mapped to the
+// type of their values.
+func fieldTypeForArrow(t arrow.DataType) data.FieldType {
+	switch t.ID() {
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		return data.FieldTypeNullableTime
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		return data.FieldTypeNullableInt64
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return data.FieldTypeNullableUint64
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return data.FieldTypeNullableFloat64
+	case arrow.BOOL:
+		return data.FieldTypeNullableBool
+	case arrow.DICTIONARY:
+		return fieldTypeForArrow(t.(*arrow.DictionaryType).ValueType)
+	default:
+		return data.FieldTypeNullableString
+	}
+}
+
+// appendRecord appends the rows of an Arrow record batch to the frame, which
+// must have been created from the same schema.
+func appendRecord(frame *data.Frame, record arrow.Record) error {
+	if int(record.NumCols()) != len(frame.Fields) {
+		return fmt.Errorf("record has %d columns, expected %d", record.NumCols(), len(frame.Fields))
+	}
+
+	for i, col := range record.Columns() {
+		field := frame.Fields[i]
+		for row := 0; row < col.Len(); row++ {
+			v, err := arrowValue(col, row)
+			if err != nil {
+				return fmt.Errorf("column %q: %w", field.Name, err)
+			}
+			field.Append(v)
+		}
+	}
+	return nil
+}
+
+// arrowValue returns the value at row as a pointer of the frame field type
+// returned by fieldTypeForArrow, or a nil pointer for null values.
+func arrowValue(col arrow.Array, row int) (interface{}, error) {
+	ft := fieldTypeForArrow(col.DataType())
+	if col.IsNull(row) {
+		return data.NewFieldFromFieldType(ft, 1).At(0), nil
+	}
+
+	switch a := col.(type) {
+	case *array.Dictionary:
+		return arrowValue(a.Dictionary(), a.GetValueIndex(row))
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		t := a.Value(row).ToTime(unit)
+		return &t, nil
+	case *array.Date32:
+		t := a.Value(row).ToTime()
+		return &t, nil
+	case *array.Date64:
+		t := a.Value(row).ToTime()
+		return &t, nil
+	case *array.Int8:
+		v := int64(a.Value(row))
+		return &v, nil
+	case *array.Int16:
+		v := int64(a.Value(row))
+		return &v, nil
+	case *array.Int32:
+		v := int64(a.Value(row))
+		return &v, nil
+	case *array.Int64:
+		v := a.Value(row)
+		return &v, nil
+	case *array.Uint8:
+		v := uint64(a.Value(row))
+		return &v, nil
+	case *array.Uint16:
+		v := uint64(a.Value(row))
+		return &v, nil
+	case *array.Uint32:
+		v := uint64(a.Value(row))
+		return &v, nil
+	case *array.Uint64:
+		v := a.Value(row)
+		return &v, nil
+	case *array.Float16:
+		v := float64(a.Value(row).Float32())
+		return &v, nil
+	case *array.Float32:
+		v := float64(a.Value(row))
+		return &v, nil
+	case *array.Float64:
+		v := a.Value(row)
+		return &v, nil
+	case *array.Boolean:
+		v := a.Value(row)
+		return &v, nil
+	case *array.String:
+		v := a.Value(row)
+		return &v, nil
+	case *array.LargeString:
+		v := a.Value(row)
+		return &v, nil
+	}
+
+	if ft != data.FieldTypeNullableString {
+		return nil, fmt.Errorf("unsupported arrow type %s", col.DataType())
+	}
+
+	// Anything else, such as lists and structs, is returned as JSON.
+	slice := array.NewSlice(col, int64(row), int64(row+1))
+	defer slice.Release()
+	b, err := slice.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected JSON value of arrow type %s", col.DataType())
+	}
+	v := string(values[0])
+	return &v, nil
+}
+
+// timeSeriesFrame converts a frame with a time column into a time series:
+// rows are sorted by time and long frames, where string columns hold the
+// dimensions, are converted to wide frames with one field per series.
+func timeSeriesFrame(frame *data.Frame) (*data.Frame, error) {
+	tsSchema := frame.TimeSeriesSchema()
+	if tsSchema.Type == data.TimeSeriesTypeNot {
+		return nil, fmt.Errorf("time series format requires a time column and at least one numeric column")
+	}
+
+	timeField := frame.Fields[tsSchema.TimeIndex]
+	for i := 0; i < timeField.Len(); i++ {
+		if timeField.At(i).(*time.Time) == nil {
+			return nil, fmt.Errorf("time column %q must not contain null values", timeField.Name)
+		}
+	}
+
+	// Sorting and converting require a non-nullable time field.
+	nonNullable := data.NewFieldFromFieldType(data.FieldTypeTime, timeField.Len())
+	nonNullable.Name = timeField.Name
+	for i := 0; i < timeField.Len(); i++ {
+		nonNullable.Set(i, *timeField.At(i).(*time.Time))
+	}
+	frame.Fields[tsSchema.TimeIndex] = nonNullable
+
+	sortFrameByTime(frame, tsSchema.TimeIndex)
+
+	if tsSchema.Type == data.TimeSeriesTypeLong && frame.Rows() > 0 {
+		return data.LongToWide(frame, nil)
+	}
+	return frame, nil
+}
+
+// sortFrameByTime sorts the rows of the frame by the non-nullable time field
+// at timeIndex. InfluxDB does not guarantee ordering unless the query has an
+// ORDER BY clause.
+func sortFrameByTime(frame *data.Frame, timeIndex int) {
+	timeField := frame.Fields[timeIndex]
+	order := make([]int, timeField.Len())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return timeField.At(order[a]).(time.Time).Before(timeField.At(order[b]).(time.Time))
+	})
+
+	for fi, field := range frame.Fields {
+		sorted := data.NewFieldFromFieldType(field.Type(), field.Len())
+		sorted.Name = field.Name
+		sorted.Labels = field.Labels
+		sorted.Config = field.Config
+		for i, idx := range order {
+			sorted.Set(i, field.CopyAt(idx))
+		}
+		frame.Fields[fi] = sorted
+	}
+}
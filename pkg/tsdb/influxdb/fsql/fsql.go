@@ -0,0 +1,181 @@
+package fsql
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+var (
+	glog = log.New("tsdb.influx_fsql")
+)
+
+// Query executes SQL queries over Arrow Flight SQL, as supported by InfluxDB 3,
+// and returns the results.
+func Query(ctx context.Context, dsInfo *models.DatasourceInfo, req backend.QueryDataRequest) (
+	*backend.QueryDataResponse, error) {
+	logger := glog.FromContext(ctx)
+	tRes := backend.NewQueryDataResponse()
+
+	r, err := runnerFromDataSource(dsInfo)
+	if err != nil {
+		return &backend.QueryDataResponse{}, err
+	}
+
+	for _, query := range req.Queries {
+		qm, err := getQueryModel(query)
+		if err != nil {
+			tRes.Responses[query.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
+
+		tRes.Responses[query.RefID] = executeQuery(ctx, logger, *qm, r, dsInfo.TimeInterval)
+	}
+	return tRes, nil
+}
+
+// runner is a Flight SQL client with the metadata sent with every call.
+type runner struct {
+	client *flightsql.Client
+	md     metadata.MD
+}
+
+// NewClient creates the Flight SQL client of a datasource instance. The underlying gRPC connection is established
+// lazily and shared by all queries of the instance.
+func NewClient(dsInfo *models.DatasourceInfo) (*flightsql.Client, error) {
+	if dsInfo.URL == "" {
+		return nil, fmt.Errorf("missing URL from datasource configuration")
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL in datasource configuration: %w", err)
+	}
+
+	addr := u.Host
+	secure := u.Scheme == "https" || u.Scheme == "grpc+tls"
+	if u.Port() == "" {
+		if secure {
+			addr = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	dialOpts := []grpc.DialOption{}
+	if secure && !dsInfo.InsecureGrpc {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(pool, "")))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	return flightsql.NewClient(addr, nil, nil, dialOpts...)
+}
+
+// runnerFromDataSource creates a runner from the datasource model (the datasource instance's configuration).
+func runnerFromDataSource(dsInfo *models.DatasourceInfo) (*runner, error) {
+	if dsInfo.FlightSQLClient == nil {
+		return nil, fmt.Errorf("flight sql client is not configured")
+	}
+
+	md := metadata.MD{}
+	if dsInfo.Token != "" {
+		md.Set("authorization", "Bearer "+dsInfo.Token)
+	}
+	database := dsInfo.Database
+	if database == "" {
+		database = dsInfo.DefaultBucket
+	}
+	if database != "" {
+		md.Set("database", database)
+	}
+
+	return &runner{
+		client: dsInfo.FlightSQLClient,
+		md:     md,
+	}, nil
+}
+
+func executeQuery(ctx context.Context, logger log.Logger, query queryModel, r *runner, timeInterval string) backend.DataResponse {
+	sql, err := interpolate(query, timeInterval)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	logger.Debug("Executing Flight SQL query", "refId", query.RefID)
+
+	frame, err := r.runQuery(ctx, sql)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	if query.Format == formatTimeSeries {
+		frame, err = timeSeriesFrame(frame)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+	}
+
+	frame.RefID = query.RefID
+	frame.Meta = &data.FrameMeta{
+		ExecutedQueryString: sql,
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// runQuery executes the SQL query and reads the record batches of every
+// endpoint into a single frame.
+func (r *runner) runQuery(ctx context.Context, sql string) (*data.Frame, error) {
+	ctx = metadata.NewOutgoingContext(ctx, r.md)
+
+	info, err := r.client.Execute(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("flight sql error: %w", err)
+	}
+
+	var frame *data.Frame
+	for _, endpoint := range info.Endpoint {
+		reader, err := r.client.DoGet(ctx, endpoint.Ticket)
+		if err != nil {
+			return nil, fmt.Errorf("flight sql error: %w", err)
+		}
+
+		if frame == nil {
+			frame = newFrameFromSchema(reader.Schema())
+		}
+
+		for reader.Next() {
+			if err := appendRecord(frame, reader.Record()); err != nil {
+				reader.Release()
+				return nil, err
+			}
+		}
+		err = reader.Err()
+		reader.Release()
+		if err != nil {
+			return nil, fmt.Errorf("flight sql error: %w", err)
+		}
+	}
+
+	if frame == nil {
+		return data.NewFrame(""), nil
+	}
+	return frame, nil
+}
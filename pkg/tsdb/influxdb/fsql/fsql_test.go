@@ -0,0 +1,210 @@
+package fsql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/flight"
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+// stubFlightSQLServer is an in-process Flight SQL server that returns the
+// same record for every query.
+type stubFlightSQLServer struct {
+	flightsql.BaseServer
+
+	mu       sync.Mutex
+	queries  []string
+	metadata metadata.MD
+	record   func() arrow.Record
+}
+
+func (s *stubFlightSQLServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	s.mu.Lock()
+	s.queries = append(s.queries, cmd.GetQuery())
+	s.metadata, _ = metadata.FromIncomingContext(ctx)
+	s.mu.Unlock()
+
+	ticket, err := flightsql.CreateStatementQueryTicket([]byte(cmd.GetQuery()))
+	if err != nil {
+		return nil, err
+	}
+	return &flight.FlightInfo{
+		FlightDescriptor: desc,
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+func (s *stubFlightSQLServer) DoGetStatement(ctx context.Context, ticket flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	rec := s.record()
+	ch := make(chan flight.StreamChunk, 1)
+	ch <- flight.StreamChunk{Data: rec}
+	close(ch)
+	return rec.Schema(), ch, nil
+}
+
+func startStubServer(t *testing.T, stub *stubFlightSQLServer) string {
+	t.Helper()
+
+	srv := flight.NewServerWithMiddleware(nil)
+	require.NoError(t, srv.Init("127.0.0.1:0"))
+	srv.RegisterFlightService(flightsql.NewFlightServer(stub))
+	go func() {
+		_ = srv.Serve()
+	}()
+	t.Cleanup(srv.Shutdown)
+
+	return "http://" + srv.Addr().String()
+}
+
+func cpuRecord() arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "time", Type: &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}},
+		{Name: "host", Type: &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}, Nullable: true},
+		{Name: "usage", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []struct {
+		offset time.Duration
+		host   string
+		usage  float64
+	}{
+		{time.Minute, "a", 2},
+		{0, "a", 1},
+		{0, "b", 3},
+		{time.Minute, "b", 4},
+	}
+	for _, r := range rows {
+		b.Field(0).(*array.TimestampBuilder).Append(arrow.Timestamp(start.Add(r.offset).UnixNano()))
+		if err := b.Field(1).(*array.BinaryDictionaryBuilder).AppendString(r.host); err != nil {
+			panic(err)
+		}
+		b.Field(2).(*array.Float64Builder).Append(r.usage)
+	}
+	return b.NewRecord()
+}
+
+func TestQuery(t *testing.T) {
+	stub := &stubFlightSQLServer{record: cpuRecord}
+	dsInfo := &models.DatasourceInfo{
+		URL:      startStubServer(t, stub),
+		Database: "telegraf",
+		Token:    "secret",
+	}
+	client, err := NewClient(dsInfo)
+	require.NoError(t, err)
+	dsInfo.FlightSQLClient = client
+	t.Cleanup(dsInfo.Dispose)
+
+	timeRange := backend.TimeRange{
+		From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC),
+	}
+	newQuery := func(refID string, model map[string]interface{}) backend.DataQuery {
+		b, err := json.Marshal(model)
+		require.NoError(t, err)
+		return backend.DataQuery{RefID: refID, JSON: b, TimeRange: timeRange, Interval: time.Minute, MaxDataPoints: 100}
+	}
+
+	t.Run("table format", func(t *testing.T) {
+		res, err := Query(context.Background(), dsInfo, backend.QueryDataRequest{
+			Queries: []backend.DataQuery{newQuery("A", map[string]interface{}{
+				"rawSql": "SELECT time, host, usage FROM cpu WHERE $__timeFilter(time)",
+			})},
+		})
+		require.NoError(t, err)
+		dr := res.Responses["A"]
+		require.NoError(t, dr.Error)
+		require.Len(t, dr.Frames, 1)
+
+		frame := dr.Frames[0]
+		require.Equal(t, "A", frame.RefID)
+		require.Equal(t, "SELECT time, host, usage FROM cpu WHERE time >= '2023-01-01T00:00:00Z' AND time <= '2023-01-01T01:00:00Z'", frame.Meta.ExecutedQueryString)
+		require.Equal(t, 4, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, "a", *frame.Fields[1].At(0).(*string))
+		require.Equal(t, 2.0, *frame.Fields[2].At(0).(*float64))
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		require.Equal(t, []string{"telegraf"}, stub.metadata.Get("database"))
+		require.Equal(t, []string{"Bearer secret"}, stub.metadata.Get("authorization"))
+	})
+
+	t.Run("time series format", func(t *testing.T) {
+		res, err := Query(context.Background(), dsInfo, backend.QueryDataRequest{
+			Queries: []backend.DataQuery{newQuery("B", map[string]interface{}{
+				"rawSql": "SELECT $__dateBinAlias(time), host, avg(usage) AS usage FROM cpu GROUP BY 1, host",
+				"format": "time_series",
+			})},
+		})
+		require.NoError(t, err)
+		dr := res.Responses["B"]
+		require.NoError(t, dr.Error)
+		require.Len(t, dr.Frames, 1)
+
+		frame := dr.Frames[0]
+		require.Equal(t, data.TimeSeriesTypeWide, frame.TimeSeriesSchema().Type)
+		require.Equal(t, 2, frame.Rows())
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+		require.Equal(t, 1.0, *frame.Fields[1].At(0).(*float64))
+		require.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+		require.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("query errors are reported per query", func(t *testing.T) {
+		res, err := Query(context.Background(), dsInfo, backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				newQuery("A", map[string]interface{}{"rawSql": ""}),
+				newQuery("B", map[string]interface{}{"rawSql": "SELECT $__unknown(time) FROM cpu"}),
+				newQuery("C", map[string]interface{}{"rawSql": "SELECT 1"}),
+			},
+		})
+		require.NoError(t, err)
+		require.Error(t, res.Responses["A"].Error)
+		require.Error(t, res.Responses["B"].Error)
+		require.NoError(t, res.Responses["C"].Error)
+	})
+
+	t.Run("requires the client of the instance", func(t *testing.T) {
+		_, err := Query(context.Background(), &models.DatasourceInfo{URL: dsInfo.URL}, backend.QueryDataRequest{
+			Queries: []backend.DataQuery{newQuery("A", map[string]interface{}{"rawSql": "SELECT 1"})},
+		})
+		require.EqualError(t, err, "flight sql client is not configured")
+	})
+}
+
+func TestArrowValue(t *testing.T) {
+	b := array.NewListBuilder(memory.DefaultAllocator, arrow.PrimitiveTypes.Int64)
+	defer b.Release()
+	values := b.ValueBuilder().(*array.Int64Builder)
+	b.Append(true)
+	values.AppendValues([]int64{1, 2}, nil)
+	b.Append(true)
+	values.Append(3)
+	list := b.NewArray()
+	defer list.Release()
+
+	v, err := arrowValue(list, 1)
+	require.NoError(t, err)
+	require.Equal(t, "[3]", *v.(*string))
+}
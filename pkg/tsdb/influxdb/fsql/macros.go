@@ -0,0 +1,110 @@
+package fsql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+const rsIdentifier = `([_a-zA-Z0-9]+)`
+const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
+
+var macroExp = regexp.MustCompile(sExpr)
+
+// interpolate replaces the global SQL macros ($__interval, $__interval_ms,
+// $__unixEpochFrom() and $__unixEpochTo()) and the InfluxDB SQL specific
+// macros with their values.
+func interpolate(query queryModel, timeInterval string) (string, error) {
+	dq := backend.DataQuery{
+		RefID:         query.RefID,
+		TimeRange:     query.TimeRange,
+		MaxDataPoints: query.MaxDataPoints,
+		Interval:      query.Interval,
+	}
+
+	sql, err := sqleng.Interpolate(dq, query.TimeRange, timeInterval, query.RawSQL)
+	if err != nil {
+		return "", err
+	}
+
+	var macroError error
+	sql = sqleng.NewSQLMacroEngineBase().ReplaceAllStringSubmatchFunc(macroExp, sql, func(groups []string) string {
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, " ")
+		}
+		res, err := evaluateMacro(query, groups[1], args)
+		if err != nil && macroError == nil {
+			macroError = err
+			return "macro_error()"
+		}
+		return res
+	})
+
+	if macroError != nil {
+		return "", macroError
+	}
+
+	return sql, nil
+}
+
+func evaluateMacro(query queryModel, name string, args []string) (string, error) {
+	timeRange := query.TimeRange
+	switch name {
+	case "__timeFilter":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= '%s' AND %s <= '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), args[0], timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeFrom":
+		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeTo":
+		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__dateBin":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return dateBin(args[0], query.Interval), nil
+	case "__dateBinAlias":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return dateBin(args[0], query.Interval) + ` AS "time"`, nil
+	case "__timeGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		return dateBin(args[0], interval), nil
+	case "__timeGroupAlias":
+		tg, err := evaluateMacro(query, "__timeGroup", args)
+		if err == nil {
+			return tg + ` AS "time"`, nil
+		}
+		return "", err
+	case "__unixEpochNanoFrom":
+		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+	case "__unixEpochNanoTo":
+		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+	default:
+		return "", fmt.Errorf("unknown macro %q", name)
+	}
+}
+
+// dateBin groups the timestamps of column into buckets of the given interval,
+// aligned to the Unix epoch.
+func dateBin(column string, interval time.Duration) string {
+	if interval < time.Second {
+		return fmt.Sprintf("date_bin(interval '%d millisecond', %s, timestamp '1970-01-01T00:00:00Z')", interval.Milliseconds(), column)
+	}
+	return fmt.Sprintf("date_bin(interval '%d second', %s, timestamp '1970-01-01T00:00:00Z')", int64(interval.Seconds()), column)
+}
@@ -0,0 +1,73 @@
+package fsql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	query := queryModel{
+		RefID: "A",
+		TimeRange: backend.TimeRange{
+			From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2023, 1, 1, 6, 0, 0, 0, time.UTC),
+		},
+		MaxDataPoints: 360,
+		Interval:      time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			name:     "time filter",
+			sql:      "SELECT * FROM cpu WHERE $__timeFilter(time)",
+			expected: "SELECT * FROM cpu WHERE time >= '2023-01-01T00:00:00Z' AND time <= '2023-01-01T06:00:00Z'",
+		},
+		{
+			name:     "time from and to",
+			sql:      "WHERE time > $__timeFrom() AND time < $__timeTo()",
+			expected: "WHERE time > '2023-01-01T00:00:00Z' AND time < '2023-01-01T06:00:00Z'",
+		},
+		{
+			name:     "date bin",
+			sql:      "SELECT $__dateBin(time)",
+			expected: "SELECT date_bin(interval '60 second', time, timestamp '1970-01-01T00:00:00Z')",
+		},
+		{
+			name:     "date bin alias",
+			sql:      "SELECT $__dateBinAlias(time)",
+			expected: `SELECT date_bin(interval '60 second', time, timestamp '1970-01-01T00:00:00Z') AS "time"`,
+		},
+		{
+			name:     "time group with global interval",
+			sql:      "SELECT $__timeGroupAlias(time, $__interval)",
+			expected: `SELECT date_bin(interval '60 second', time, timestamp '1970-01-01T00:00:00Z') AS "time"`,
+		},
+		{
+			name:     "unix epoch",
+			sql:      "WHERE ts >= $__unixEpochFrom() AND ts <= $__unixEpochNanoTo()",
+			expected: "WHERE ts >= 1672531200 AND ts <= 1672552800000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query.RawSQL = tt.sql
+			sql, err := interpolate(query, "")
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, sql)
+		})
+	}
+
+	t.Run("missing column", func(t *testing.T) {
+		query.RawSQL = "SELECT $__dateBin()"
+		_, err := interpolate(query, "")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,49 @@
+package fsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	formatTimeSeries = "time_series"
+	formatTable      = "table"
+)
+
+// queryModel represents a query.
+type queryModel struct {
+	RawSQL string `json:"rawSql"`
+	Format string `json:"format"`
+
+	// Not from JSON
+	RefID         string            `json:"-"`
+	TimeRange     backend.TimeRange `json:"-"`
+	MaxDataPoints int64             `json:"-"`
+	Interval      time.Duration     `json:"-"`
+}
+
+func getQueryModel(query backend.DataQuery) (*queryModel, error) {
+	model := &queryModel{}
+	if err := json.Unmarshal(query.JSON, model); err != nil {
+		return nil, fmt.Errorf("error reading query: %w", err)
+	}
+	if model.RawSQL == "" {
+		return nil, fmt.Errorf("query is empty")
+	}
+	if model.Format == "" {
+		model.Format = formatTable
+	}
+
+	// Copy directly from the well typed query
+	model.RefID = query.RefID
+	model.TimeRange = query.TimeRange
+	model.MaxDataPoints = query.MaxDataPoints
+	model.Interval = query.Interval
+	if model.Interval.Milliseconds() == 0 {
+		model.Interval = time.Millisecond // 1ms
+	}
+	return model, nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/flux"
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/fsql"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
 )
 
@@ -80,6 +81,13 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			Organization:  jsonData.Organization,
 			MaxSeries:     maxSeries,
 			Token:         settings.DecryptedSecureJSONData["token"],
+			InsecureGrpc:  jsonData.InsecureGrpc,
+		}
+		if version == influxVersionSQL {
+			model.FlightSQLClient, err = fsql.NewClient(model)
+			if err != nil {
+				return nil, err
+			}
 		}
 		return model, nil
 	}
@@ -94,9 +102,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return nil, err
 	}
 	version := dsInfo.Version
-	if version == "Flux" {
+	if version == influxVersionFlux {
 		return flux.Query(ctx, dsInfo, *req)
 	}
+	if version == influxVersionSQL {
+		return fsql.Query(ctx, dsInfo, *req)
+	}
 
 	logger.Debug("Making a non-Flux type query")
 
@@ -2,12 +2,18 @@ package models
 
 import (
 	"net/http"
+
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 )
 
 type DatasourceInfo struct {
 	HTTPClient *http.Client
 	Token      string
 	URL        string
+	// FlightSQLClient is used by SQL queries, it is only set when the version is SQL.
+	FlightSQLClient *flightsql.Client
 
 	Database      string `json:"database"`
 	Version       string `json:"version"`
@@ -16,4 +22,15 @@ type DatasourceInfo struct {
 	DefaultBucket string `json:"defaultBucket"`
 	Organization  string `json:"organization"`
 	MaxSeries     int    `json:"maxSeries"`
+	InsecureGrpc  bool   `json:"insecureGrpc"`
+}
+
+// Dispose closes the connections of the instance when its settings change.
+func (d *DatasourceInfo) Dispose() {
+	if d.FlightSQLClient == nil {
+		return
+	}
+	if err := d.FlightSQLClient.Close(); err != nil {
+		log.New("tsdb.influxdb").Warn("Failed to close Flight SQL client", "err", err)
+	}
 }
@@ -3,4 +3,5 @@ package influxdb
 const (
 	influxVersionFlux     = "Flux"
 	influxVersionInfluxQL = "InfluxQL"
+	influxVersionSQL      = "SQL"
 )
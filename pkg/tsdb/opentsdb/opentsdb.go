@@ -1,6 +1,7 @@
 package opentsdb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,14 +9,17 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
@@ -63,19 +67,135 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 	}
 }
 
-func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	var tsdbQuery OpenTsdbQuery
+const (
+	// expressionQueryType is the query type of OpenTSDB 2.3+ expression queries,
+	// sent to /api/query/exp.
+	expressionQueryType = "expression"
+
+	defaultFillPolicy = "none"
 
+	// maxConcurrentRequests is the maximum number of requests sent to OpenTSDB at the same time for one request.
+	maxConcurrentRequests = 4
+)
+
+// fillPolicies are the fill policies supported by OpenTSDB downsamplers.
+// The scalar policy is only supported by expression queries.
+var fillPolicies = map[string]bool{
+	"none":   true,
+	"nan":    true,
+	"null":   true,
+	"zero":   true,
+	"scalar": true,
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
 
-	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
-	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
+	result := backend.NewQueryDataResponse()
+	var mu sync.Mutex
+	setResponse := func(refID string, frames data.Frames, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			result.Responses[refID] = backend.DataResponse{Error: err}
+			return
+		}
+		for _, frame := range frames {
+			frame.RefID = refID
+		}
+		result.Responses[refID] = backend.DataResponse{Frames: frames}
+	}
 
+	// Metric queries with the same time range are sent in one request, expression queries are sent one by one.
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentRequests)
+	var batches [][]backend.DataQuery
+	batchIndex := map[backend.TimeRange]int{}
 	for _, query := range req.Queries {
-		metric := s.buildMetric(query)
-		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
+		if query.QueryType == expressionQueryType {
+			query := query
+			g.Go(func() error {
+				frames, err := s.executeExpressionQuery(ctx, logger, dsInfo, query)
+				setResponse(query.RefID, frames, err)
+				return nil
+			})
+			continue
+		}
+
+		i, ok := batchIndex[query.TimeRange]
+		if !ok {
+			i = len(batches)
+			batchIndex[query.TimeRange] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], query)
+	}
+	for _, batch := range batches {
+		batch := batch
+		g.Go(func() error {
+			s.executeMetricQueries(ctx, logger, dsInfo, batch, setResponse)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return result, nil
+}
+
+// executeMetricQueries sends metric queries with the same time range in one request. OpenTSDB fails the whole
+// request when one of the queries fails, the queries are then sent one by one to report the error of each query.
+func (s *Service) executeMetricQueries(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, queries []backend.DataQuery,
+	setResponse func(refID string, frames data.Frames, err error)) {
+	built := make([]backend.DataQuery, 0, len(queries))
+	metrics := make([]map[string]interface{}, 0, len(queries))
+	for _, query := range queries {
+		metric, err := s.buildMetric(query)
+		if err != nil {
+			setResponse(query.RefID, nil, err)
+			continue
+		}
+		built = append(built, query)
+		metrics = append(metrics, metric)
+	}
+	if len(built) == 0 {
+		return
+	}
+
+	frames, err := s.executeMetricRequest(ctx, logger, dsInfo, built[0].TimeRange, metrics)
+	if err == nil || len(built) == 1 {
+		for i, query := range built {
+			if err != nil {
+				setResponse(query.RefID, nil, err)
+				continue
+			}
+			setResponse(query.RefID, frames[i], nil)
+		}
+		return
+	}
+
+	logger.Debug("Batched OpenTSDB request failed, sending queries one by one", "error", err)
+	for i, query := range built {
+		frames, err := s.executeMetricRequest(ctx, logger, dsInfo, query.TimeRange, metrics[i:i+1])
+		if err != nil {
+			setResponse(query.RefID, nil, err)
+			continue
+		}
+		setResponse(query.RefID, frames[0], nil)
+	}
+}
+
+// executeMetricRequest sends metric queries to /api/query and returns the frames of each query.
+func (s *Service) executeMetricRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, timeRange backend.TimeRange, metrics []map[string]interface{}) ([]data.Frames, error) {
+	tsdbQuery := OpenTsdbQuery{
+		Start:     timeRange.From.UnixNano() / int64(time.Millisecond),
+		End:       timeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries:   metrics,
+		ShowQuery: len(metrics) > 1,
 	}
 
 	// TODO: Don't use global variable
@@ -83,35 +203,49 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(req.PluginContext)
+	request, err := s.createRequest(ctx, logger, dsInfo, "api/query", tsdbQuery)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	res, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
 	}
 
-	res, err := dsInfo.HTTPClient.Do(request)
+	return s.parseResponse(logger, res, len(metrics))
+}
+
+func (s *Service) executeExpressionQuery(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) (data.Frames, error) {
+	expQuery, err := s.buildExpressionQuery(query)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
+	}
+
+	// TODO: Don't use global variable
+	if setting.Env == setting.Dev {
+		logger.Debug("OpenTsdb expression request", "params", expQuery)
 	}
 
-	result, err := s.parseResponse(logger, res)
+	request, err := s.createRequest(ctx, logger, dsInfo, "api/query/exp", expQuery)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
 	}
 
-	return result, nil
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.parseExpressionResponse(logger, res)
 }
 
-func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
+func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, apiPath string, data interface{}) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, "api/query")
+	u.Path = path.Join(u.Path, apiPath)
 
 	postData, err := json.Marshal(data)
 	if err != nil {
@@ -129,9 +263,9 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 	return req, nil
 }
 
-func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend.QueryDataResponse, error) {
-	resp := backend.NewQueryDataResponse()
-
+// readResponse reads the response body, and returns the error reported by
+// OpenTSDB if the request failed.
+func readResponse(logger log.Logger, res *http.Response) ([]byte, error) {
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
@@ -144,22 +278,92 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend
 
 	if res.StatusCode/100 != 2 {
 		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		var errResponse OpenTsdbErrorResponse
+		if err := json.Unmarshal(body, &errResponse); err == nil && errResponse.Error.Message != "" {
+			return nil, fmt.Errorf("request failed, status: %s, error: %s", res.Status, errResponse.Error.Message)
+		}
 		return nil, fmt.Errorf("request failed, status: %s", res.Status)
 	}
 
+	return body, nil
+}
+
+// nonFiniteNumbers are the tokens OpenTSDB writes for values which are not valid JSON numbers.
+var nonFiniteNumbers = []string{"NaN", "-Infinity", "Infinity"}
+
+// quoteNonFiniteNumbers quotes NaN and infinite values outside of strings, so the body can be decoded into
+// OpenTsdbValue.
+func quoteNonFiniteNumbers(body []byte) []byte {
+	var out []byte
+	inString, escaped := false, false
+	last := 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			continue
+		}
+		for _, token := range nonFiniteNumbers {
+			if bytes.HasPrefix(body[i:], []byte(token)) {
+				out = append(out, body[last:i]...)
+				out = append(out, '"')
+				out = append(out, token...)
+				out = append(out, '"')
+				i += len(token) - 1
+				last = i + 1
+				break
+			}
+		}
+	}
+	if out == nil {
+		return body
+	}
+	return append(out, body[last:]...)
+}
+
+// parseResponse converts every series of an /api/query response into a frame, grouped by the index of the sub query
+// which returned the series.
+func (s *Service) parseResponse(logger log.Logger, res *http.Response, queries int) ([]data.Frames, error) {
+	body, err := readResponse(logger, res)
+	if err != nil {
+		return nil, err
+	}
+
 	var responseData []OpenTsdbResponse
-	err = json.Unmarshal(body, &responseData)
+	err = json.Unmarshal(quoteNonFiniteNumbers(body), &responseData)
 	if err != nil {
 		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
 		return nil, err
 	}
 
-	frames := data.Frames{}
+	frames := make([]data.Frames, queries)
+	for i := range frames {
+		frames[i] = data.Frames{}
+	}
 	for _, val := range responseData {
-		timeVector := make([]time.Time, 0, len(val.DataPoints))
-		values := make([]float64, 0, len(val.DataPoints))
-		name := val.Metric
-		tags := val.Tags
+		index := 0
+		if val.Query != nil && val.Query.Index != nil {
+			index = *val.Query.Index
+		} else if queries > 1 {
+			return nil, fmt.Errorf("response of metric %q does not include the index of its query", val.Metric)
+		}
+		if index < 0 || index >= queries {
+			return nil, fmt.Errorf("response of metric %q has an invalid query index %d", val.Metric, index)
+		}
+
+		timestamps := make([]int64, 0, len(val.DataPoints))
+		values := make(map[int64]float64, len(val.DataPoints))
 
 		for timeString, value := range val.DataPoints {
 			timestamp, err := strconv.ParseInt(timeString, 10, 64)
@@ -167,25 +371,82 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend
 				logger.Info("Failed to unmarshal opentsdb timestamp", "timestamp", timeString)
 				return nil, err
 			}
+			timestamps = append(timestamps, timestamp)
+			values[timestamp] = float64(value)
+		}
+		sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+		timeVector := make([]time.Time, 0, len(timestamps))
+		valueVector := make([]float64, 0, len(timestamps))
+		for _, timestamp := range timestamps {
 			timeVector = append(timeVector, time.Unix(timestamp, 0).UTC())
-			values = append(values, value)
+			valueVector = append(valueVector, values[timestamp])
 		}
-		frames = append(frames, data.NewFrame(name,
+
+		frames[index] = append(frames[index], data.NewFrame(val.Metric,
 			data.NewField("time", nil, timeVector),
-			data.NewField("value", tags, values)))
+			data.NewField("value", val.Tags, valueVector)))
+	}
+
+	return frames, nil
+}
+
+// parseExpressionResponse converts every series of every output of an
+// expression query into a frame.
+func (s *Service) parseExpressionResponse(logger log.Logger, res *http.Response) (data.Frames, error) {
+	body, err := readResponse(logger, res)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData OpenTsdbExpResponse
+	err = json.Unmarshal(quoteNonFiniteNumbers(body), &responseData)
+	if err != nil {
+		logger.Info("Failed to unmarshal opentsdb expression response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	frames := data.Frames{}
+	for _, output := range responseData.Outputs {
+		rows := output.DataPoints
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+		name := output.Alias
+		if name == "" {
+			name = output.ID
+		}
+
+		for _, series := range output.Meta {
+			// index 0 describes the timestamp column
+			if series.Index == 0 {
+				continue
+			}
+
+			timeVector := make([]time.Time, 0, len(rows))
+			valueVector := make([]float64, 0, len(rows))
+			for _, row := range rows {
+				if series.Index >= len(row) {
+					continue
+				}
+				timeVector = append(timeVector, time.UnixMilli(int64(row[0])).UTC())
+				valueVector = append(valueVector, float64(row[series.Index]))
+			}
+
+			frames = append(frames, data.NewFrame(name,
+				data.NewField("time", nil, timeVector),
+				data.NewField("value", series.CommonTags, valueVector)))
+		}
 	}
-	result := resp.Responses["A"]
-	result.Frames = frames
-	resp.Responses["A"] = result
-	return resp, nil
+
+	return frames, nil
 }
 
-func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
+func (s *Service) buildMetric(query backend.DataQuery) (map[string]interface{}, error) {
 	metric := make(map[string]interface{})
 
 	model, err := simplejson.NewJson(query.JSON)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	// Setting metric and aggregator
@@ -200,10 +461,17 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 			downsampleInterval = "1m" // default value for blank
 		}
 		downsample := downsampleInterval + "-" + model.Get("downsampleAggregator").MustString()
-		if model.Get("downsampleFillPolicy").MustString() != "none" {
-			metric["downsample"] = downsample + "-" + model.Get("downsampleFillPolicy").MustString()
-		} else {
+		fillPolicy, err := getFillPolicy(model)
+		if err != nil {
+			return nil, err
+		}
+		switch fillPolicy {
+		case "scalar":
+			return nil, fmt.Errorf("fill policy %q is only supported by expression queries", fillPolicy)
+		case defaultFillPolicy:
 			metric["downsample"] = downsample
+		default:
+			metric["downsample"] = downsample + "-" + fillPolicy
 		}
 	}
 
@@ -242,7 +510,90 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 		metric["filters"] = filters.MustArray()
 	}
 
-	return metric
+	return metric, nil
+}
+
+// getFillPolicy returns the downsample fill policy of the query, which
+// defaults to none.
+func getFillPolicy(model *simplejson.Json) (string, error) {
+	fillPolicy := model.Get("downsampleFillPolicy").MustString(defaultFillPolicy)
+	if fillPolicy == "" {
+		fillPolicy = defaultFillPolicy
+	}
+	if !fillPolicies[fillPolicy] {
+		return "", fmt.Errorf("unsupported fill policy %q", fillPolicy)
+	}
+	return fillPolicy, nil
+}
+
+// buildExpressionQuery builds the body of an /api/query/exp request. The
+// filters, metrics, expressions and outputs of the query model are passed
+// through as is, while the time section is built from the time range and the
+// aggregation and downsampling options of the query.
+func (s *Service) buildExpressionQuery(query backend.DataQuery) (map[string]interface{}, error) {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return nil, err
+	}
+
+	expressions := model.Get("expressions").MustArray()
+	if len(expressions) == 0 {
+		return nil, fmt.Errorf("expression query must have at least one expression")
+	}
+
+	timeSection := map[string]interface{}{
+		"start":      query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		"end":        query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		"aggregator": model.Get("aggregator").MustString("sum"),
+	}
+
+	fillPolicy, err := getFillPolicy(model)
+	if err != nil {
+		return nil, err
+	}
+	policy := map[string]interface{}{"policy": fillPolicy}
+	if fillPolicy == "scalar" {
+		fillValue, ok := model.CheckGet("downsampleFillValue")
+		if !ok {
+			return nil, fmt.Errorf("fill policy %q requires a fill value", fillPolicy)
+		}
+		policy["value"] = fillValue.MustFloat64()
+	}
+
+	if !model.Get("disableDownsampling").MustBool() {
+		downsampleInterval := model.Get("downsampleInterval").MustString()
+		if downsampleInterval == "" {
+			downsampleInterval = "1m" // default value for blank
+		}
+		timeSection["downsampler"] = map[string]interface{}{
+			"interval":   downsampleInterval,
+			"aggregator": model.Get("downsampleAggregator").MustString("avg"),
+			"fillPolicy": policy,
+		}
+	}
+
+	metrics := model.Get("metrics").MustArray()
+	for _, m := range metrics {
+		metric, ok := m.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expression query metrics must be objects, got %T", m)
+		}
+		if _, ok := metric["fillPolicy"]; !ok {
+			metric["fillPolicy"] = policy
+		}
+	}
+
+	expQuery := map[string]interface{}{
+		"time":        timeSection,
+		"filters":     model.Get("filters").MustArray([]interface{}{}),
+		"metrics":     metrics,
+		"expressions": expressions,
+	}
+	if outputs := model.Get("outputs").MustArray(); len(outputs) > 0 {
+		expQuery["outputs"] = outputs
+	}
+
+	return expQuery, nil
 }
 
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, error) {
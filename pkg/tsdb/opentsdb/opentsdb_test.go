@@ -2,9 +2,14 @@ package opentsdb
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,16 +18,19 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 )
 
 func TestOpenTsdbExecutor(t *testing.T) {
 	service := &Service{}
 
 	t.Run("create request", func(t *testing.T) {
-		req, err := service.createRequest(context.Background(), logger, &datasourceInfo{}, OpenTsdbQuery{})
+		req, err := service.createRequest(context.Background(), logger, &datasourceInfo{}, "api/query", OpenTsdbQuery{})
 		require.NoError(t, err)
 
 		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "api/query", req.URL.Path)
 		body, err := io.ReadAll(req.Body)
 		require.NoError(t, err)
 
@@ -33,7 +41,7 @@ func TestOpenTsdbExecutor(t *testing.T) {
 	t.Run("Parse response should handle invalid JSON", func(t *testing.T) {
 		response := `{ invalid }`
 
-		result, err := service.parseResponse(logger, &http.Response{Body: io.NopCloser(strings.NewReader(response))})
+		result, err := service.parseResponse(logger, &http.Response{Body: io.NopCloser(strings.NewReader(response))}, 1)
 		require.Nil(t, result)
 		require.Error(t, err)
 	})
@@ -63,16 +71,143 @@ func TestOpenTsdbExecutor(t *testing.T) {
 
 		resp := http.Response{Body: io.NopCloser(strings.NewReader(response))}
 		resp.StatusCode = 200
-		result, err := service.parseResponse(logger, &resp)
+		frames, err := service.parseResponse(logger, &resp, 1)
 		require.NoError(t, err)
 
-		frame := result.Responses["A"]
+		if diff := cmp.Diff(testFrame, frames[0][0], data.FrameTestCompareOptions()...); diff != "" {
+			t.Errorf("Result mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Parse response should sort data points by time", func(t *testing.T) {
+		response := `
+		[
+			{
+				"metric": "test",
+				"dps": {
+					"1405544206": 52.0,
+					"1405544146": 50.0,
+					"1405544176": 51.0
+				}
+			}
+		]`
+
+		resp := http.Response{Body: io.NopCloser(strings.NewReader(response)), StatusCode: 200}
+		frames, err := service.parseResponse(logger, &resp, 1)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		timeField := frames[0][0].Fields[0]
+		require.Equal(t, 3, timeField.Len())
+		for i := 1; i < timeField.Len(); i++ {
+			require.True(t, timeField.At(i-1).(time.Time).Before(timeField.At(i).(time.Time)))
+		}
+		require.Equal(t, []float64{50, 51, 52}, []float64{
+			frames[0][0].Fields[1].At(0).(float64),
+			frames[0][0].Fields[1].At(1).(float64),
+			frames[0][0].Fields[1].At(2).(float64),
+		})
+	})
+
+	t.Run("Parse response should return the OpenTSDB error message", func(t *testing.T) {
+		response := `{"error": {"code": 400, "message": "No such name for 'metrics': 'nope'"}}`
 
-		if diff := cmp.Diff(testFrame, frame.Frames[0], data.FrameTestCompareOptions()...); diff != "" {
+		resp := http.Response{Body: io.NopCloser(strings.NewReader(response)), StatusCode: 400, Status: "400 Bad Request"}
+		_, err := service.parseResponse(logger, &resp, 1)
+		require.EqualError(t, err, "request failed, status: 400 Bad Request, error: No such name for 'metrics': 'nope'")
+	})
+
+	t.Run("Parse expression response", func(t *testing.T) {
+		response := `
+		{
+			"outputs": [
+				{
+					"id": "e",
+					"alias": "cpu total",
+					"dps": [[1431561660000, 3, 30], [1431561600000, 1, 10]],
+					"meta": [
+						{"index": 0, "metrics": ["timestamp"]},
+						{"index": 1, "metrics": ["sys.cpu.user", "sys.cpu.sys"], "commonTags": {"host": "web01"}},
+						{"index": 2, "metrics": ["sys.cpu.user", "sys.cpu.sys"], "commonTags": {"host": "web02"}}
+					]
+				}
+			]
+		}`
+
+		resp := http.Response{Body: io.NopCloser(strings.NewReader(response)), StatusCode: 200}
+		frames, err := service.parseExpressionResponse(logger, &resp)
+		require.NoError(t, err)
+		require.Len(t, frames, 2)
+
+		expected := data.NewFrame("cpu total",
+			data.NewField("time", nil, []time.Time{
+				time.UnixMilli(1431561600000).UTC(),
+				time.UnixMilli(1431561660000).UTC(),
+			}),
+			data.NewField("value", data.Labels{"host": "web02"}, []float64{10, 30}),
+		)
+		if diff := cmp.Diff(expected, frames[1], data.FrameTestCompareOptions()...); diff != "" {
 			t.Errorf("Result mismatch (-want +got):\n%s", diff)
 		}
 	})
 
+	t.Run("Build expression query", func(t *testing.T) {
+		query := backend.DataQuery{
+			TimeRange: backend.TimeRange{From: time.UnixMilli(1000), To: time.UnixMilli(2000)},
+			JSON: []byte(`
+					{
+						"aggregator": "sum",
+						"downsampleInterval": "5m",
+						"downsampleAggregator": "max",
+						"downsampleFillPolicy": "scalar",
+						"downsampleFillValue": 0,
+						"filters": [{"id": "f1", "tags": [{"type": "wildcard", "tagk": "host", "filter": "*", "groupBy": true}]}],
+						"metrics": [
+							{"id": "a", "metric": "sys.cpu.user", "filter": "f1"},
+							{"id": "b", "metric": "sys.cpu.sys", "filter": "f1", "fillPolicy": {"policy": "nan"}}
+						],
+						"expressions": [{"id": "e", "expr": "a + b"}]
+					}`,
+			),
+		}
+
+		expQuery, err := service.buildExpressionQuery(query)
+		require.NoError(t, err)
+
+		timeSection := expQuery["time"].(map[string]interface{})
+		require.Equal(t, int64(1000), timeSection["start"])
+		require.Equal(t, int64(2000), timeSection["end"])
+		require.Equal(t, "sum", timeSection["aggregator"])
+		require.Equal(t, map[string]interface{}{
+			"interval":   "5m",
+			"aggregator": "max",
+			"fillPolicy": map[string]interface{}{"policy": "scalar", "value": float64(0)},
+		}, timeSection["downsampler"])
+
+		metrics := expQuery["metrics"].([]interface{})
+		require.Equal(t, map[string]interface{}{"policy": "scalar", "value": float64(0)}, metrics[0].(map[string]interface{})["fillPolicy"])
+		require.Equal(t, map[string]interface{}{"policy": "nan"}, metrics[1].(map[string]interface{})["fillPolicy"])
+		require.Nil(t, expQuery["outputs"])
+	})
+
+	t.Run("Build expression query without expressions should fail", func(t *testing.T) {
+		_, err := service.buildExpressionQuery(backend.DataQuery{JSON: []byte(`{"metrics": [{"id": "a", "metric": "sys.cpu.user"}]}`)})
+		require.Error(t, err)
+	})
+
+	t.Run("Build metric with unsupported fill policy should fail", func(t *testing.T) {
+		for _, policy := range []string{"previous", "scalar"} {
+			_, err := service.buildMetric(backend.DataQuery{JSON: []byte(`{"metric": "cpu", "aggregator": "avg", "downsampleFillPolicy": "` + policy + `"}`)})
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("Build metric without fill policy", func(t *testing.T) {
+		metric, err := service.buildMetric(backend.DataQuery{JSON: []byte(`{"metric": "cpu", "aggregator": "avg", "downsampleAggregator": "avg"}`)})
+		require.NoError(t, err)
+		require.Equal(t, "1m-avg", metric["downsample"])
+	})
+
 	t.Run("Build metric with downsampling enabled", func(t *testing.T) {
 		query := backend.DataQuery{
 			JSON: []byte(`
@@ -87,7 +222,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 3)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -109,7 +245,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 2)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -130,7 +267,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 3)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -156,7 +294,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 3)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -187,7 +326,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 5)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -222,7 +362,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 5)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -242,3 +383,95 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
 }
+
+func TestQueryData(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/api/query/exp":
+			_, _ = w.Write([]byte(`{"outputs": [{"id": "e", "dps": [[1405544146000, 5], [1405544176000, NaN]], "meta": [{"index": 0}, {"index": 1, "commonTags": {}}]}]}`))
+		case strings.Contains(string(body), "missing.metric"):
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "No such name for 'metrics': 'missing.metric'"}}`))
+		default:
+			var q OpenTsdbQuery
+			require.NoError(t, json.Unmarshal(body, &q))
+			series := make([]string, 0, len(q.Queries))
+			for i, query := range q.Queries {
+				series = append(series, fmt.Sprintf(`{"metric": %q, "dps": {"1405544146": 1}, "query": {"index": %d}}`, query["metric"], i))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(series, ",") + "]"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	service := ProvideService(httpclient.NewProvider())
+	tr := backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)}
+	queryData := func(t *testing.T, queries ...backend.DataQuery) *backend.QueryDataResponse {
+		t.Helper()
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: srv.URL},
+			},
+			Queries: queries,
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("sends metric queries with the same time range in one request", func(t *testing.T) {
+		res := queryData(t,
+			backend.DataQuery{RefID: "A", TimeRange: tr, JSON: []byte(`{"metric": "cpu", "aggregator": "avg", "disableDownsampling": true}`)},
+			backend.DataQuery{RefID: "B", TimeRange: tr, JSON: []byte(`{"metric": "mem", "aggregator": "avg", "disableDownsampling": true}`)},
+		)
+		require.Len(t, res.Responses, 2)
+		require.Equal(t, []string{"/api/query"}, requests)
+
+		require.NoError(t, res.Responses["A"].Error)
+		require.Equal(t, "cpu", res.Responses["A"].Frames[0].Name)
+		require.Equal(t, "A", res.Responses["A"].Frames[0].RefID)
+		require.NoError(t, res.Responses["B"].Error)
+		require.Equal(t, "mem", res.Responses["B"].Frames[0].Name)
+		require.Equal(t, "B", res.Responses["B"].Frames[0].RefID)
+	})
+
+	t.Run("retries metric queries one by one when the request fails", func(t *testing.T) {
+		res := queryData(t,
+			backend.DataQuery{RefID: "A", TimeRange: tr, JSON: []byte(`{"metric": "cpu", "aggregator": "avg", "disableDownsampling": true}`)},
+			backend.DataQuery{RefID: "B", TimeRange: tr, JSON: []byte(`{"metric": "missing.metric", "aggregator": "avg", "disableDownsampling": true}`)},
+			backend.DataQuery{RefID: "C", TimeRange: tr, QueryType: expressionQueryType, JSON: []byte(`{"metrics": [{"id": "a", "metric": "cpu"}], "expressions": [{"id": "e", "expr": "a * 5"}]}`)},
+			backend.DataQuery{RefID: "D", TimeRange: tr, JSON: []byte(`{"metric": "mem", "aggregator": "avg", "disableDownsampling": true}`)},
+		)
+		require.Len(t, res.Responses, 4)
+		require.ElementsMatch(t, []string{"/api/query", "/api/query", "/api/query", "/api/query", "/api/query/exp"}, requests)
+
+		require.NoError(t, res.Responses["A"].Error)
+		require.Equal(t, "cpu", res.Responses["A"].Frames[0].Name)
+		require.Equal(t, "A", res.Responses["A"].Frames[0].RefID)
+
+		require.ErrorContains(t, res.Responses["B"].Error, "No such name for 'metrics': 'missing.metric'")
+
+		require.NoError(t, res.Responses["C"].Error)
+		require.Equal(t, "e", res.Responses["C"].Frames[0].Name)
+		require.Equal(t, 5.0, res.Responses["C"].Frames[0].Fields[1].At(0))
+		require.True(t, math.IsNaN(res.Responses["C"].Frames[0].Fields[1].At(1).(float64)))
+
+		require.NoError(t, res.Responses["D"].Error)
+		require.Equal(t, "mem", res.Responses["D"].Frames[0].Name)
+	})
+}
+
+func TestQuoteNonFiniteNumbers(t *testing.T) {
+	body := `{"dps": [[1, NaN], [2, -Infinity], [3, Infinity]], "tags": {"host": "NaN [Infinity]"}}`
+	require.Equal(t, `{"dps": [[1, "NaN"], [2, "-Infinity"], [3, "Infinity"]], "tags": {"host": "NaN [Infinity]"}}`, string(quoteNonFiniteNumbers([]byte(body))))
+}
@@ -1,13 +1,86 @@
 package opentsdb
 
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
 type OpenTsdbQuery struct {
 	Start   int64                    `json:"start"`
 	End     int64                    `json:"end"`
 	Queries []map[string]interface{} `json:"queries"`
+	// ShowQuery makes OpenTSDB include the query, with its index, in every series of the response.
+	ShowQuery bool `json:"showQuery,omitempty"`
 }
 
 type OpenTsdbResponse struct {
-	Metric     string             `json:"metric"`
-	Tags       map[string]string  `json:"tags"`
-	DataPoints map[string]float64 `json:"dps"`
+	Metric     string                   `json:"metric"`
+	Tags       map[string]string        `json:"tags"`
+	DataPoints map[string]OpenTsdbValue `json:"dps"`
+	Query      *OpenTsdbResponseQuery   `json:"query"`
+}
+
+// OpenTsdbResponseQuery is the query of a series, returned when showQuery is set.
+type OpenTsdbResponseQuery struct {
+	Index *int `json:"index"`
+}
+
+// OpenTsdbValue is a data point value. OpenTSDB writes NaN and infinite
+// values as bare tokens, which are quoted before decoding, and null values
+// for the null fill policy, which are decoded as NaN.
+type OpenTsdbValue float64
+
+func (v *OpenTsdbValue) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*v = OpenTsdbValue(math.NaN())
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var str string
+		if err := json.Unmarshal(b, &str); err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return err
+		}
+		*v = OpenTsdbValue(f)
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	*v = OpenTsdbValue(f)
+	return nil
+}
+
+// OpenTsdbExpResponse is the response of the /api/query/exp endpoint.
+type OpenTsdbExpResponse struct {
+	Outputs []OpenTsdbExpOutput `json:"outputs"`
+}
+
+// OpenTsdbExpOutput is a single output of an expression query. Every row of
+// DataPoints holds the timestamp in milliseconds followed by one value per
+// series, and Meta describes each column.
+type OpenTsdbExpOutput struct {
+	ID         string              `json:"id"`
+	Alias      string              `json:"alias"`
+	DataPoints [][]OpenTsdbValue   `json:"dps"`
+	Meta       []OpenTsdbExpSeries `json:"meta"`
+}
+
+type OpenTsdbExpSeries struct {
+	Index      int               `json:"index"`
+	Metrics    []string          `json:"metrics"`
+	CommonTags map[string]string `json:"commonTags"`
+}
+
+// OpenTsdbErrorResponse is the body OpenTSDB returns for failed requests.
+type OpenTsdbErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
@@ -35,6 +35,10 @@ func (s *QueryData) parseResponse(ctx context.Context, q *models.Query, res *htt
 
 	// The ExecutedQueryString can be viewed in QueryInspector in UI
 	for _, frame := range r.Frames {
+		if converter.IsHistogramFrame(frame) {
+			addMetadataToHistogramFrame(q, frame)
+			continue
+		}
 		if s.enableWideSeries {
 			addMetadataToWideFrame(q, frame)
 		} else {
@@ -126,6 +130,18 @@ func addMetadataToWideFrame(q *models.Query, frame *data.Frame) {
 	}
 }
 
+// addMetadataToHistogramFrame sets the metadata of a native histogram frame.
+// The fields of these frames describe heatmap cells (xMax, yMin, yMax, count,
+// yLayout) and keep their names, so the legend is used as the frame name.
+func addMetadataToHistogramFrame(q *models.Query, frame *data.Frame) {
+	frame.Meta.ExecutedQueryString = executedQueryString(q)
+	if len(frame.Fields) < 2 {
+		return
+	}
+	frame.Name = getName(q, frame.Fields[1])
+	frame.Fields[0].Config = &data.FieldConfig{Interval: float64(q.Step.Milliseconds())}
+}
+
 // this is based on the logic from the String() function in github.com/prometheus/common/model.go
 func metricNameFromLabels(f *data.Field) string {
 	labels := f.Labels
@@ -18,6 +18,16 @@ func logf(format string, a ...interface{}) {
 	//fmt.Printf(format, a...)
 }
 
+// FrameTypeHeatmapCells is the frame type of native histograms. Each row is a
+// bucket at a point in time: xMax (time), yMin, yMax, count and yLayout, where
+// yLayout holds the Prometheus bucket boundary rule.
+const FrameTypeHeatmapCells data.FrameType = "heatmap-cells"
+
+// IsHistogramFrame returns true when the frame holds the buckets of a native histogram.
+func IsHistogramFrame(frame *data.Frame) bool {
+	return frame.Meta != nil && frame.Meta.Type == FrameTypeHeatmapCells
+}
+
 type Options struct {
 	MatrixWideSeries bool
 	VectorWideSeries bool
@@ -426,10 +436,14 @@ func readMatrixOrVectorWide(iter *jsoniter.Iterator, resultType string) *backend
 		}
 
 		if histogram != nil {
+			// the series is returned as its own heatmap frame, so the (empty)
+			// value field should not end up in the wide frame
+			frame.Fields = frame.Fields[:len(frame.Fields)-1]
+
 			histogram.yMin.Labels = valueField.Labels
 			frame := data.NewFrame(valueField.Name, histogram.time, histogram.yMin, histogram.yMax, histogram.count, histogram.yLayout)
 			frame.Meta = &data.FrameMeta{
-				Type: "heatmap-cells",
+				Type: FrameTypeHeatmapCells,
 			}
 			if frame.Name == data.TimeSeriesValueFieldName {
 				frame.Name = "" // only set the name if useful
@@ -438,7 +452,8 @@ func readMatrixOrVectorWide(iter *jsoniter.Iterator, resultType string) *backend
 		}
 	}
 
-	if len(rsp.Frames) == 0 {
+	// float series are kept when the result mixes them with histograms
+	if len(rsp.Frames) == 0 || len(frame.Fields) > 1 {
 		sorter := experimental.NewFrameSorter(frame, frame.Fields[0])
 		sort.Sort(sorter)
 		rsp.Frames = append(rsp.Frames, frame)
@@ -535,7 +550,7 @@ func readMatrixOrVectorMulti(iter *jsoniter.Iterator, resultType string) *backen
 			histogram.yMin.Labels = valueField.Labels
 			frame := data.NewFrame(valueField.Name, histogram.time, histogram.yMin, histogram.yMax, histogram.count, histogram.yLayout)
 			frame.Meta = &data.FrameMeta{
-				Type: "heatmap-cells",
+				Type: FrameTypeHeatmapCells,
 			}
 			if frame.Name == data.TimeSeriesValueFieldName {
 				frame.Name = "" // only set the name if useful
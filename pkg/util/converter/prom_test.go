@@ -87,3 +87,39 @@ func TestTimeConversions(t *testing.T) {
 		time.Date(2033, time.May, 18, 3, 33, 20, 0, time.UTC),
 		timeFromLokiString("2000000000000000000"))
 }
+
+func TestReadMixedFloatAndHistogramSeries(t *testing.T) {
+	res := `{
+		"status": "success",
+		"data": {
+			"resultType": "vector",
+			"result": [
+				{"metric": {"job": "a"}, "value": [1649967668.042, "1"]},
+				{"metric": {"job": "b"}, "histogram": [1649967668.042, {"count": "3", "sum": "1.5", "buckets": [[0, "0.25", "0.5", "1"], [0, "0.5", "1", "2"]]}]}
+			]
+		}
+	}`
+
+	for _, opts := range []Options{{}, {MatrixWideSeries: true, VectorWideSeries: true}} {
+		iter := jsoniter.ParseString(jsoniter.ConfigDefault, res)
+		rsp := ReadPrometheusStyleResult(iter, opts)
+		require.NoError(t, rsp.Error)
+		require.Len(t, rsp.Frames, 2)
+
+		var histogram, series int
+		for _, frame := range rsp.Frames {
+			if IsHistogramFrame(frame) {
+				histogram++
+				require.Len(t, frame.Fields, 5)
+				require.Equal(t, 2, frame.Rows())
+				require.Equal(t, "b", frame.Fields[1].Labels["job"])
+				continue
+			}
+			series++
+			require.Len(t, frame.Fields, 2)
+			require.Equal(t, "a", frame.Fields[1].Labels["job"])
+		}
+		require.Equal(t, 1, histogram)
+		require.Equal(t, 1, series)
+	}
+}
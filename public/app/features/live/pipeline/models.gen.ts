@@ -4,6 +4,8 @@ import { FieldConfig } from '@grafana/data';
 
 export interface ChannelAuthCheckConfig {
   role?: string;
+  action?: string;
+  scope?: string;
 }
 export interface ChannelAuthConfig {
   subscribe?: ChannelAuthCheckConfig;
@@ -66,13 +68,26 @@ export interface KeepFieldsFrameProcessorConfig {
 export interface DropFieldsFrameProcessorConfig {
   fieldNames: string[];
 }
+export interface AggregateFrameProcessorConfig {
+  window: string;
+  slide?: string;
+  functions: string[];
+  percentile?: number;
+  fieldNames?: string[];
+  groupBy?: string[];
+}
 export interface FrameProcessorConfig {
   type: Omit<keyof FrameProcessorConfig, 'type'>;
   dropFields?: DropFieldsFrameProcessorConfig;
   keepFields?: KeepFieldsFrameProcessorConfig;
   multiple?: MultipleFrameProcessorConfig;
+  aggregate?: AggregateFrameProcessorConfig;
 }
 export interface JsonFrameConverterConfig {}
+export interface PrometheusConverterConfig {}
+export interface OtlpConverterConfig {
+  resourceAttributes?: string[];
+}
 export interface AutoInfluxConverterConfig {
   frameFormat: string;
 }
@@ -99,6 +114,8 @@ export interface ConverterConfig {
   jsonExact?: ExactJsonConverterConfig;
   influxAuto?: AutoInfluxConverterConfig;
   jsonFrame?: JsonFrameConverterConfig;
+  prometheus?: PrometheusConverterConfig;
+  otlp?: OtlpConverterConfig;
 }
 export interface LokiOutputConfig {
   uid: string;
@@ -118,6 +135,23 @@ export interface SubscriberConfig {
   type: Omit<keyof SubscriberConfig, 'type'>;
   multiple?: MultipleSubscriberConfig;
 }
+export interface MQTTConnectorConfig {
+  uid: string;
+  topics: string[];
+  clientId?: string;
+  qos?: number;
+}
+export interface KafkaConnectorConfig {
+  uid: string;
+  topic: string;
+  groupId?: string;
+  startOffset?: string;
+}
+export interface ConnectorConfig {
+  type: Omit<keyof ConnectorConfig, 'type'>;
+  mqtt?: MQTTConnectorConfig;
+  kafka?: KafkaConnectorConfig;
+}
 export interface ChannelRuleSettings {
   auth?: ChannelAuthConfig;
   subscribers?: SubscriberConfig[];
@@ -125,8 +159,10 @@ export interface ChannelRuleSettings {
   converter?: ConverterConfig;
   frameProcessors?: FrameProcessorConfig[];
   frameOutputs?: FrameOutputterConfig[];
+  connectors?: ConnectorConfig[];
 }
 export interface ChannelRule {
   pattern: string;
   settings: ChannelRuleSettings;
+  version?: number;
 }